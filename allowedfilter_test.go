@@ -0,0 +1,84 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAllowedFilterFields(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 0)
+	computed := map[string]string{"kode_status": "kode || '-' || status"}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		rows  int
+		err   error
+	}{
+		{"nil allows any column", url.Values{"filter[gudang_id]": {"1"}}, Options{}, 2, nil},
+		{"listed", url.Values{"filter[status]": {"aktif"}}, Options{AllowedFilterFields: []string{"status"}}, 2, nil},
+		{"existing column not listed", url.Values{"filter[gudang_id]": {"1"}}, Options{AllowedFilterFields: []string{"status"}}, 0, ErrUnknownFilterField},
+		{"empty rejects every filter", url.Values{"filter[status]": {"aktif"}}, Options{AllowedFilterFields: []string{}}, 0, ErrUnknownFilterField},
+		{"empty allows no filter", url.Values{}, Options{AllowedFilterFields: []string{}}, 4, nil},
+		{"computed alias listed", url.Values{"filter[kode_status]": {"ORD-01-aktif"}},
+			Options{ComputedColumns: computed, AllowedFilterFields: []string{"kode_status"}}, 1, nil},
+		{"computed alias not listed", url.Values{"filter[kode_status]": {"ORD-01-aktif"}},
+			Options{ComputedColumns: computed, AllowedFilterFields: []string{"status"}}, 0, ErrUnknownFilterField},
+		{"independent of StrictQuery", url.Values{"filter[gudang_id]": {"1"}}, Options{StrictQuery: true, AllowedFilterFields: []string{"status"}}, 0, ErrUnknownFilterField},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil && (StatusForError(err) != 400 || errorCode(err) != "unknown_filter_field") {
+				t.Fatalf("status %d, code %q", StatusForError(err), errorCode(err))
+			}
+			if len(res.Data) != tc.rows {
+				t.Fatalf("rows = %d, want %d", len(res.Data), tc.rows)
+			}
+		})
+	}
+
+	// PageRequest.Filter lewat parser yang sama
+	req := PageRequest{Filter: map[string]string{"gudang_id": "1"}}
+	opts := Options{OrderBy: "id", AllowedFilterFields: []string{"status"}}
+	if _, err := ReadPaginatedSource[Order](context.Background(), req.Source(), db.Model(&Order{}), &Order{}, opts); !errors.Is(err, ErrUnknownFilterField) {
+		t.Fatalf("PageRequest: err = %v, want ErrUnknownFilterField", err)
+	}
+}
+
+func TestAllowedFilterFieldsConfig(t *testing.T) {
+	o, err := NewOptions(WithAllowedFilterFields())
+	if err != nil || o.AllowedFilterFields == nil || len(o.AllowedFilterFields) != 0 {
+		t.Fatalf("no args: %#v, err %v", o.AllowedFilterFields, err)
+	}
+	if _, err := NewOptions(WithAllowedFilterFields("status", " ")); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("empty name: err = %v, want ErrInvalidOption", err)
+	}
+
+	err = Options{AllowedFilterFields: []string{"status", "alamat"}}.Validate(&Order{})
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), `AllowedFilterFields: unknown column "alamat" on Order`) {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	db := newTestDB(t)
+	d, err := Describe[Order](db, Options{AllowedFilterFields: []string{"status", "kode"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, f := range d.Filters {
+		fields = append(fields, f.Field)
+	}
+	if fmt.Sprint(fields) != "[kode status]" {
+		t.Fatalf("described filters %v", fields)
+	}
+}
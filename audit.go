@@ -0,0 +1,116 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrMissingActor: kolom audit NOT NULL tapi context tidak membawa actor (WriteOptions.ActorFromContext)
+var ErrMissingActor = errors.New("missing actor")
+
+// ActorFunc mengambil id user yang sedang login dari context (biasanya diisi middleware autentikasi)
+type ActorFunc func(ctx context.Context) (uuid.UUID, bool)
+
+// auditColumns: kolom created_by / updated_by model beserta actor dari context.
+// Field nil bila model tidak punya kolomnya; zero value = fitur tidak aktif.
+type auditColumns struct {
+	createdBy *schema.Field
+	updatedBy *schema.Field
+	actor     uuid.UUID
+	ok        bool // context membawa actor
+}
+
+// auditFields me-resolve WriteOptions.CreatedByColumn / UpdatedByColumn (default created_by / updated_by)
+func auditFields(sch *schema.Schema, opts WriteOptions) (createdBy, updatedBy *schema.Field) {
+	if opts.ActorFromContext == nil {
+		return nil, nil
+	}
+	lookUp := func(name, def string) *schema.Field {
+		if name == "" {
+			name = def
+		}
+		if f := sch.LookUpField(name); isColumnField(f) {
+			return f
+		}
+		return nil
+	}
+	return lookUp(opts.CreatedByColumn, "created_by"), lookUp(opts.UpdatedByColumn, "updated_by")
+}
+
+// newAuditColumns membaca actor dari context. Tanpa actor, kolom audit yang NOT NULL (tanpa default)
+// langsung ditolak dengan ErrMissingActor, bukan constraint violation dari database.
+func newAuditColumns(ctx context.Context, sch *schema.Schema, opts WriteOptions, creating bool) (auditColumns, error) {
+	a := auditColumns{}
+	a.createdBy, a.updatedBy = auditFields(sch, opts)
+	if !creating {
+		a.createdBy = nil
+	}
+	if a.createdBy == nil && a.updatedBy == nil {
+		return a, nil
+	}
+	a.actor, a.ok = opts.ActorFromContext(ctx)
+	if a.ok {
+		return a, nil
+	}
+	for _, f := range []*schema.Field{a.createdBy, a.updatedBy} {
+		if f != nil && f.NotNull && !f.HasDefaultValue {
+			return a, fmt.Errorf("%w: %s.%s is NOT NULL but the context has no actor", ErrMissingActor, sch.Name, f.DBName)
+		}
+	}
+	return a, nil
+}
+
+// fields: kolom audit yang diisi (kosong bila context tidak membawa actor)
+func (a auditColumns) fields() []*schema.Field {
+	var out []*schema.Field
+	if a.ok {
+		for _, f := range []*schema.Field{a.createdBy, a.updatedBy} {
+			if f != nil {
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
+
+// set mengisi kolom audit di item (rv = struct) dan mengembalikan nama kolom DB-nya
+func (a auditColumns) set(ctx context.Context, rv reflect.Value) ([]string, error) {
+	var cols []string
+	for _, f := range a.fields() {
+		if err := f.Set(ctx, rv, a.actor); err != nil {
+			return nil, err
+		}
+		cols = append(cols, f.DBName)
+	}
+	return cols, nil
+}
+
+// assign menambahkan kolom audit ke map updates (PatchGeneric, BulkUpdateByQuery)
+func (a auditColumns) assign(updates map[string]interface{}) {
+	for _, f := range a.fields() {
+		updates[f.DBName] = a.actor
+	}
+}
+
+// setAuditOnCreate mengisi created_by / updated_by di item yang akan di-insert
+func setAuditOnCreate(ctx context.Context, db *gorm.DB, item interface{}, opts WriteOptions) error {
+	if opts.ActorFromContext == nil {
+		return nil
+	}
+	sch, err := parseSchema(db, item)
+	if err != nil {
+		return err
+	}
+	audit, err := newAuditColumns(ctx, sch, opts, true)
+	if err != nil {
+		return err
+	}
+	_, err = audit.set(ctx, reflect.ValueOf(item).Elem())
+	return err
+}
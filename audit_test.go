@@ -0,0 +1,218 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Dokumen: created_by wajib, updated_by boleh kosong
+type Dokumen struct {
+	ID        uint       `json:"id"`
+	Judul     string     `json:"judul"`
+	CreatedBy uuid.UUID  `json:"created_by" gorm:"type:text;not null"`
+	UpdatedBy *uuid.UUID `json:"updated_by" gorm:"type:text"`
+}
+
+// Catatan: kolom audit dengan nama lain (WriteOptions.CreatedByColumn / UpdatedByColumn)
+type Catatan struct {
+	ID         uint      `json:"id"`
+	Isi        string    `json:"isi"`
+	DibuatOleh uuid.UUID `json:"dibuat_oleh" gorm:"type:text"`
+	DiubahOleh uuid.UUID `json:"diubah_oleh" gorm:"type:text"`
+}
+
+type actorKey struct{}
+
+// withActor / fakeActor: pengganti middleware autentikasi
+func withActor(id uuid.UUID) context.Context {
+	return context.WithValue(context.Background(), actorKey{}, id)
+}
+
+func fakeActor(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(actorKey{}).(uuid.UUID)
+	return id, ok
+}
+
+var (
+	budi = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	sari = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+)
+
+func newAuditDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Dokumen{}, &Catatan{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCreateGenericAudit(t *testing.T) {
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		payload Dokumen
+		opts    WriteOptions
+		want    error
+		created uuid.UUID
+	}{
+		{"actor", withActor(budi), Dokumen{Judul: "a"}, WriteOptions{ActorFromContext: fakeActor}, nil, budi},
+		{"payload cannot spoof", withActor(budi), Dokumen{Judul: "a", CreatedBy: sari}, WriteOptions{ActorFromContext: fakeActor}, nil, budi},
+		{"no actor on NOT NULL", context.Background(), Dokumen{Judul: "a"}, WriteOptions{ActorFromContext: fakeActor}, ErrMissingActor, uuid.Nil},
+		{"feature off", context.Background(), Dokumen{Judul: "a", CreatedBy: sari}, WriteOptions{}, nil, sari},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newAuditDB(t)
+			out, err := CreateGeneric(tc.ctx, db, &tc.payload, tc.opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if tc.want != nil {
+				var count int64
+				db.Model(&Dokumen{}).Count(&count)
+				if count != 0 {
+					t.Fatalf("%d rows stored after %v", count, err)
+				}
+				if code := errorCode(err); code != "missing_actor" {
+					t.Fatalf("code = %q", code)
+				}
+				return
+			}
+			var stored Dokumen
+			db.First(&stored, out.ID)
+			if stored.CreatedBy != tc.created {
+				t.Fatalf("created_by = %s, want %s", stored.CreatedBy, tc.created)
+			}
+			if tc.opts.ActorFromContext != nil && (stored.UpdatedBy == nil || *stored.UpdatedBy != budi) {
+				t.Fatalf("updated_by = %v, want %s", stored.UpdatedBy, budi)
+			}
+		})
+	}
+}
+
+func TestWriteGenericAuditOnUpdate(t *testing.T) {
+	opts := WriteOptions{ActorFromContext: fakeActor}
+	cases := []struct {
+		name  string
+		write func(ctx context.Context, db *gorm.DB, id uint) error
+	}{
+		{"update", func(ctx context.Context, db *gorm.DB, id uint) error {
+			_, err := UpdateGeneric(ctx, db, id, &Dokumen{Judul: "baru", CreatedBy: sari}, opts)
+			return err
+		}},
+		{"patch", func(ctx context.Context, db *gorm.DB, id uint) error {
+			_, err := PatchGeneric[Dokumen](ctx, db, id, map[string]interface{}{"judul": "baru"}, opts)
+			return err
+		}},
+		{"bulk update by query", func(ctx context.Context, db *gorm.DB, id uint) error {
+			_, err := BulkUpdateByQuery(ctx, url.Values{"filter[id]": {"1"}}, db.Model(&Dokumen{}), &Dokumen{},
+				Options{}, map[string]interface{}{"judul": "baru"}, opts)
+			return err
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newAuditDB(t)
+			doc, err := CreateGeneric(withActor(budi), db, &Dokumen{Judul: "lama"}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tc.write(withActor(sari), db, doc.ID); err != nil {
+				t.Fatal(err)
+			}
+			var stored Dokumen
+			db.First(&stored, doc.ID)
+			if stored.Judul != "baru" || stored.CreatedBy != budi || stored.UpdatedBy == nil || *stored.UpdatedBy != sari {
+				t.Fatalf("stored %+v (updated_by %v): want created_by budi, updated_by sari", stored, stored.UpdatedBy)
+			}
+
+			// updated_by nullable: tanpa actor write tetap jalan dan kolom tidak disentuh
+			if err := tc.write(context.Background(), db, doc.ID); err != nil {
+				t.Fatalf("without actor: %v", err)
+			}
+			db.First(&stored, doc.ID)
+			if stored.UpdatedBy == nil || *stored.UpdatedBy != sari {
+				t.Fatalf("updated_by = %v after a write without actor", stored.UpdatedBy)
+			}
+		})
+	}
+}
+
+func TestPatchGenericRejectsAuditColumns(t *testing.T) {
+	db := newAuditDB(t)
+	opts := WriteOptions{ActorFromContext: fakeActor}
+	doc, err := CreateGeneric(withActor(budi), db, &Dokumen{Judul: "lama"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = PatchGeneric[Dokumen](withActor(sari), db, doc.ID, map[string]interface{}{"created_by": sari.String()}, opts)
+	if !errors.Is(err, ErrInvalidPatch) {
+		t.Fatalf("err = %v, want ErrInvalidPatch", err)
+	}
+}
+
+func TestBulkCreateGenericAudit(t *testing.T) {
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"actor", withActor(budi), nil},
+		{"no actor", context.Background(), ErrMissingActor},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newAuditDB(t)
+			docs := []Dokumen{{Judul: "a"}, {Judul: "b", CreatedBy: sari}, {Judul: "c"}}
+			out, err := BulkCreateGeneric(tc.ctx, db, docs, WriteOptions{ActorFromContext: fakeActor})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			var stored []Dokumen
+			db.Order("id").Find(&stored)
+			if tc.want != nil {
+				if len(stored) != 0 {
+					t.Fatalf("%d rows stored", len(stored))
+				}
+				return
+			}
+			if len(stored) != 3 || len(out) != 3 {
+				t.Fatalf("%d rows stored, %d returned", len(stored), len(out))
+			}
+			for i, d := range stored {
+				if d.CreatedBy != budi || d.UpdatedBy == nil || *d.UpdatedBy != budi {
+					t.Fatalf("row %d: %+v", i, d)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditCustomColumns(t *testing.T) {
+	db := newAuditDB(t)
+	opts := WriteOptions{ActorFromContext: fakeActor, CreatedByColumn: "dibuat_oleh", UpdatedByColumn: "DiubahOleh"}
+	c, err := CreateGeneric(withActor(budi), db, &Catatan{Isi: "a"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PatchGeneric[Catatan](withActor(sari), db, c.ID, map[string]interface{}{"isi": "b"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	var stored Catatan
+	db.First(&stored, c.ID)
+	if stored.DibuatOleh != budi || stored.DiubahOleh != sari {
+		t.Fatalf("stored %+v", stored)
+	}
+
+	// kolom NOT NULL tidak ada di Catatan: tanpa actor tidak ada error, kolom dibiarkan kosong
+	c, err = CreateGeneric(context.Background(), db, &Catatan{Isi: "c"}, opts)
+	if err != nil || c.DibuatOleh != uuid.Nil {
+		t.Fatalf("err %v, row %+v", err, c)
+	}
+}
@@ -0,0 +1,265 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// defaultBatchSize dipakai bila WriteOptions.BatchSize tidak di-set
+const defaultBatchSize = 100
+
+// ErrMissingConditions: operasi bulk by query tanpa filter/search dan tanpa WriteOptions.AllowDeleteAll
+var ErrMissingConditions = errors.New("bulk operation without conditions")
+
+// ErrTooManyAffected: jumlah row yang cocok melebihi WriteOptions.MaxAffected
+var ErrTooManyAffected = errors.New("too many rows affected")
+
+// BulkPolicy menentukan perlakuan item yang gagal di helper bulk
+type BulkPolicy int
+
+const (
+	// BulkAllOrNothing: semua item dalam satu transaksi, satu gagal = semua batal (default)
+	BulkAllOrNothing BulkPolicy = iota
+	// BulkBestEffort: item di-insert satu per satu, yang gagal dilaporkan di *BulkError
+	BulkBestEffort
+)
+
+// BulkItemError: error untuk satu item (Index = posisi di slice input)
+type BulkItemError struct {
+	Index int
+	Err   error
+}
+
+// BulkError: kumpulan error per item dari helper bulk. errors.Is/As menjangkau error tiap item.
+type BulkError struct {
+	Items []BulkItemError
+}
+
+func (e *BulkError) Error() string {
+	parts := make([]string, len(e.Items))
+	for i, it := range e.Items {
+		parts[i] = fmt.Sprintf("[%d] %v", it.Index, it.Err)
+	}
+	return fmt.Sprintf("bulk: %d item(s) failed: %s", len(e.Items), strings.Join(parts, "; "))
+}
+
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Items))
+	for i, it := range e.Items {
+		errs[i] = it.Err
+	}
+	return errs
+}
+
+// BulkCreateGeneric mengisi kolom audit dan memvalidasi semua item dulu (semua error per index dikumpulkan dalam *BulkError),
+// lalu meng-insert dengan CreateInBatches (WriteOptions.BatchSize, default 100) dalam satu transaksi.
+// Dengan WriteOptions.BulkPolicy = BulkBestEffort item di-insert satu per satu: yang berhasil
+// dikembalikan (ID terisi) dan yang gagal dilaporkan per index di *BulkError.
+func BulkCreateGeneric[T any](ctx context.Context, db *gorm.DB, items []T, opts WriteOptions) (out []T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpBulkCreate)
+	defer func() { op.end(len(out), err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	var failed []BulkItemError
+	for i := range items {
+		if err := setAuditOnCreate(ctx, db, &items[i], opts); err != nil {
+			return nil, err
+		}
+		if err := runValidation(ctx, db, &items[i], opts); err != nil {
+			failed = append(failed, BulkItemError{Index: i, Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return nil, &BulkError{Items: failed}
+	}
+	if len(items) == 0 {
+		return []T{}, nil
+	}
+
+	if opts.BulkPolicy == BulkBestEffort {
+		created := make([]T, 0, len(items))
+		var failed []BulkItemError
+		for i := range items {
+			if err := db.Create(&items[i]).Error; err != nil {
+				failed = append(failed, BulkItemError{Index: i, Err: translateWriteError(err)})
+				continue
+			}
+			created = append(created, items[i])
+		}
+		if len(created) > 0 {
+			invalidateItems(ctx, db, opts, created)
+		}
+		if len(failed) > 0 {
+			return created, &BulkError{Items: failed}
+		}
+		return created, nil
+	}
+
+	batch := opts.BatchSize
+	if batch <= 0 {
+		batch = defaultBatchSize
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&items, batch).Error
+	})
+	if err != nil {
+		return nil, translateWriteError(err)
+	}
+	invalidateItems(ctx, db, opts, items)
+	return items, nil
+}
+
+// bulkDetails: ValidationDetail per item, Field diawali "items[i]"
+func bulkDetails(e *BulkError, locale string) []ValidationDetail {
+	var out []ValidationDetail
+	for _, it := range e.Items {
+		prefix := "items[" + strconv.Itoa(it.Index) + "]"
+		details := DetailsFromErrorLocale(it.Err, locale)
+		if len(details) == 0 {
+			details = []ValidationDetail{{Code: "invalid_item", Message: it.Err.Error()}}
+		}
+		for _, d := range details {
+			if d.Field == "" {
+				d.Field = prefix
+			} else {
+				d.Field = prefix + "." + d.Field
+			}
+			d.Message = renderMessage(locale, d, d.Message)
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// bulkQuery menyusun query bulk dari pipeline BuildQuery yang sama dengan ReadPaginated (filter, search,
+// Scopes) lalu menerapkan pengaman: tanpa filter/search yang benar-benar diterapkan (?search= tanpa
+// SearchField/SearchFields tidak dihitung) -> ErrMissingConditions kecuali AllowDeleteAll,
+// jumlah row dihitung dulu dan ditolak bila melebihi MaxAffected. Mengembalikan query dan jumlah row.
+func bulkQuery[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, write WriteOptions) (*gorm.DB, int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db = db.WithContext(ctx)
+	if write.HardDelete {
+		db = db.Unscoped()
+	}
+	q, info, err := BuildQuery(query, db, modelPtr, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := checkBulkRelations(info, opts); err != nil {
+		return nil, 0, err
+	}
+	if len(info.Filters) == 0 && !searchApplied(info) {
+		if !write.AllowDeleteAll {
+			return nil, 0, ErrMissingConditions
+		}
+		q = q.Session(&gorm.Session{AllowGlobalUpdate: true})
+	}
+
+	var total int64
+	if err := countQuery(q).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if write.MaxAffected > 0 && total > write.MaxAffected {
+		return nil, total, fmt.Errorf("%w: %d rows match, max %d", ErrTooManyAffected, total, write.MaxAffected)
+	}
+	return q, total, nil
+}
+
+// checkBulkRelations: UPDATE / DELETE gorm tidak membawa JOIN, jadi filter dan search "Relasi.kolom" akan
+// merujuk tabel yang tidak di-join -> ErrInvalidFilter sebelum query apa pun dijalankan
+func checkBulkRelations(info QueryInfo, opts Options) error {
+	for _, f := range info.Params.Filters {
+		if _, computed := opts.ComputedColumns[f.Field]; !computed && strings.Contains(f.Field, ".") {
+			return newQueryError(ErrInvalidFilter, "filter["+f.Field+"]", "", "relation filters are not supported by bulk operations")
+		}
+	}
+	if searchApplied(info) {
+		for _, f := range info.SearchFields {
+			if strings.Contains(f, ".") {
+				return newQueryError(ErrInvalidFilter, "search", "", "searching relation "+f+" is not supported by bulk operations")
+			}
+		}
+	}
+	return nil
+}
+
+// searchApplied: ?search= menambah kondisi ILIKE (BuildQuery hanya memakainya bila ada kolom search)
+func searchApplied(info QueryInfo) bool {
+	return info.Search != "" && len(info.SearchFields) > 0
+}
+
+// BulkDeleteByQuery menghapus semua row yang cocok dengan filter/search query — pipeline yang sama dengan
+// ReadPaginated sehingga yang terhapus persis yang tampil di list. Soft delete bila model punya
+// gorm.DeletedAt (WriteOptions.HardDelete untuk permanen). Pengaman: tanpa kondisi -> ErrMissingConditions
+// (kecuali WriteOptions.AllowDeleteAll), lebih dari WriteOptions.MaxAffected row -> ErrTooManyAffected,
+// WriteOptions.DryRun hanya menghitung. Mengembalikan jumlah row (yang akan) terhapus.
+func BulkDeleteByQuery[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, write WriteOptions) (n int64, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](write.Metrics, OpBulkDelete)
+	defer func() { op.end(int(n), err) }()
+	q, total, err := bulkQuery(ctx, query, db, modelPtr, opts, write)
+	if err != nil || write.DryRun || total == 0 {
+		return total, err
+	}
+	res := q.Delete(new(T))
+	if res.Error == nil && res.RowsAffected > 0 {
+		invalidateWrite[T](ctx, db, write)
+	}
+	return res.RowsAffected, res.Error
+}
+
+// BulkUpdateByQuery mengisi kolom di set untuk semua row yang cocok dengan filter/search query (pipeline
+// ReadPaginated), e.g. set status=archived untuk filter[status]=done. set divalidasi seperti PatchGeneric
+// (kolom yang boleh ditulis, nilai ber-tipe); pengaman AllowDeleteAll, MaxAffected dan DryRun sama dengan
+// BulkDeleteByQuery. Mengembalikan jumlah row (yang akan) ter-update.
+func BulkUpdateByQuery[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, set map[string]interface{}, write WriteOptions) (n int64, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](write.Metrics, OpBulkUpdate)
+	defer func() { op.end(int(n), err) }()
+	sch, err := parseSchema(db, modelPtr)
+	if err != nil {
+		return 0, err
+	}
+	updates, _, err := patchColumns(sch, set, write)
+	if err != nil {
+		return 0, err
+	}
+	if len(updates) == 0 {
+		return 0, fmt.Errorf("%w: empty set", ErrInvalidPatch)
+	}
+	audit, err := newAuditColumns(ctx, sch, write, false)
+	if err != nil {
+		return 0, err
+	}
+	audit.assign(updates)
+
+	write.HardDelete = false
+	q, total, err := bulkQuery(ctx, query, db, modelPtr, opts, write)
+	if err != nil || write.DryRun || total == 0 {
+		return total, err
+	}
+	cols := make([]string, 0, len(updates))
+	for c := range updates {
+		cols = append(cols, c)
+	}
+	// Select mengganti proyeksi dari BuildQuery agar hanya kolom di set (dan updated_at) yang ditulis
+	res := q.Select(cols).Updates(updates)
+	if res.Error == nil && res.RowsAffected > 0 {
+		invalidateWrite[T](ctx, db, write)
+	}
+	return res.RowsAffected, translateWriteError(res.Error)
+}
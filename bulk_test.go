@@ -0,0 +1,194 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// bulkOrders: lima order baru; item ke-3 (index 2) memakai kode yang sama dengan item pertama
+func bulkOrders() []Order {
+	out := make([]Order, 5)
+	for i := range out {
+		out[i] = Order{Kode: fmt.Sprintf("BLK-%d", i+1), Status: "aktif", GudangID: 1}
+	}
+	out[2].Kode = "BLK-1"
+	return out
+}
+
+func TestBulkCreateGenericUniqueViolation(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    WriteOptions
+		created int // row yang tersimpan
+	}{
+		{"all or nothing", WriteOptions{}, 0},
+		{"all or nothing small batches", WriteOptions{BatchSize: 2}, 0},
+		{"best effort", WriteOptions{BulkPolicy: BulkBestEffort}, 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			out, err := BulkCreateGeneric(context.Background(), db, bulkOrders(), tc.opts)
+			if !errors.Is(err, ErrConflict) {
+				t.Fatalf("err = %v, want ErrConflict", err)
+			}
+			var count int64
+			db.Model(&Order{}).Count(&count)
+			if int(count) != tc.created || len(out) != tc.created {
+				t.Fatalf("%d rows stored, %d returned, want %d", count, len(out), tc.created)
+			}
+			if tc.opts.BulkPolicy != BulkBestEffort {
+				return
+			}
+			var be *BulkError
+			if !errors.As(err, &be) || len(be.Items) != 1 || be.Items[0].Index != 2 {
+				t.Fatalf("BulkError = %+v, want index 2 only", be)
+			}
+			for _, o := range out {
+				if o.ID == 0 {
+					t.Fatalf("created row without ID: %+v", o)
+				}
+			}
+			if d := DetailsFromError(err); len(d) != 1 || d[0].Field != "items[2]" || d[0].Code != "conflict" {
+				t.Fatalf("details = %+v", d)
+			}
+		})
+	}
+}
+
+func TestBulkCreateGenericSuccess(t *testing.T) {
+	db := newTestDB(t)
+	items := bulkOrders()
+	items[2].Kode = "BLK-3"
+	out, err := BulkCreateGeneric(context.Background(), db, items, WriteOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 5 {
+		t.Fatalf("%d rows returned", len(out))
+	}
+	for i, o := range out {
+		if o.ID == 0 || o.Kode != items[i].Kode {
+			t.Fatalf("row %d: %+v", i, o)
+		}
+	}
+	if out, err := BulkCreateGeneric(context.Background(), db, []Order{}, WriteOptions{}); err != nil || len(out) != 0 {
+		t.Fatalf("empty input: %v, %v", out, err)
+	}
+}
+
+func TestBulkCreateGenericValidatesEveryItem(t *testing.T) {
+	db := newTestDB(t)
+	items := bulkOrders()
+	items[1].Status, items[3].Status = "", ""
+	opts := WriteOptions{Validate: func(_ context.Context, _ *gorm.DB, item any) error {
+		if item.(*Order).Status == "" {
+			return newQueryError(ErrValidation, "status", "", "is required")
+		}
+		return nil
+	}}
+	_, err := BulkCreateGeneric(context.Background(), db, items, opts)
+	var be *BulkError
+	if !errors.As(err, &be) || len(be.Items) != 2 || be.Items[0].Index != 1 || be.Items[1].Index != 3 {
+		t.Fatalf("err = %v, want BulkError for items 1 and 3", err)
+	}
+	// validasi gagal: tidak ada insert sama sekali, termasuk duplikat kode di index 2
+	var count int64
+	db.Model(&Order{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d rows stored after validation errors", count)
+	}
+}
+
+func TestBulkByQueryRequiresConditions(t *testing.T) {
+	cases := []struct {
+		name    string
+		query   url.Values
+		opts    Options
+		write   WriteOptions
+		want    error
+		deleted int64
+	}{
+		{"no conditions", url.Values{}, Options{}, WriteOptions{}, ErrMissingConditions, 0},
+		{"search without search field", url.Values{"search": {"ORD"}}, Options{}, WriteOptions{}, ErrMissingConditions, 0},
+		{"filter", url.Values{"filter[status]": {"aktif"}}, Options{}, WriteOptions{}, nil, 2},
+		{"allow delete all", url.Values{"search": {"x"}}, Options{}, WriteOptions{AllowDeleteAll: true}, nil, 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			seedOrders(t, db, 4, 0)
+			n, err := BulkDeleteByQuery(context.Background(), tc.query, db.Model(&Order{}), &Order{}, tc.opts, tc.write)
+			if !errors.Is(err, tc.want) || n != tc.deleted {
+				t.Fatalf("delete: %d rows, err %v; want %d, %v", n, err, tc.deleted, tc.want)
+			}
+			var left int64
+			db.Model(&Order{}).Count(&left)
+			if left != 4-tc.deleted {
+				t.Fatalf("%d rows left", left)
+			}
+
+			set := map[string]interface{}{"status": "arsip"}
+			n, err = BulkUpdateByQuery(context.Background(), tc.query, db.Unscoped().Model(&Order{}), &Order{}, tc.opts, set, tc.write)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("update: err %v, want %v", err, tc.want)
+			}
+			var archived int64
+			db.Unscoped().Model(&Order{}).Where("status = ?", "arsip").Count(&archived)
+			if archived != n || tc.want != nil && archived != 0 {
+				t.Fatalf("update: %d rows reported, %d archived", n, archived)
+			}
+		})
+	}
+}
+
+func TestBulkDeleteByQuerySearchCondition(t *testing.T) {
+	db := newTestDB(t)
+	// SQLite tidak punya ILIKE: cukup pastikan pengaman lolos dan kondisi search ada di SQL
+	rdb, rec := recordSQL(db.Session(&gorm.Session{DryRun: true}))
+	_, err := BulkDeleteByQuery(context.Background(), url.Values{"search": {"ORD"}}, rdb.Model(&Order{}), &Order{},
+		Options{SearchField: "kode"}, WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmts := rec.matching("ILIKE"); len(stmts) != 1 {
+		t.Fatalf("statements %v, want the count with ILIKE", rec.statements())
+	}
+}
+
+func TestBulkByQueryRejectsRelationFilters(t *testing.T) {
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		param string
+	}{
+		{"relation filter", url.Values{"filter[Gudang.kode]": {"GD-01"}}, Options{}, "filter[Gudang.kode]"},
+		{"relation filter with other filter", url.Values{"filter[Gudang.kode]": {"GD-01"}, "filter[status]": {"aktif"}}, Options{}, "filter[Gudang.kode]"},
+		{"relation search", url.Values{"search": {"Pusat"}}, Options{SearchFields: []string{"kode", "Gudang.nama"}}, "search"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			seedOrders(t, db, 4, 0)
+			rdb, rec := recordSQL(db)
+			set := map[string]interface{}{"status": "arsip"}
+			_, errUpdate := BulkUpdateByQuery(context.Background(), tc.query, rdb.Model(&Order{}), &Order{}, tc.opts, set, WriteOptions{})
+			_, errDelete := BulkDeleteByQuery(context.Background(), tc.query, rdb.Model(&Order{}), &Order{}, tc.opts, WriteOptions{})
+			for _, err := range []error{errUpdate, errDelete} {
+				var qe *QueryError
+				if !errors.Is(err, ErrInvalidFilter) || !errors.As(err, &qe) || qe.Param != tc.param {
+					t.Fatalf("err = %v, want ErrInvalidFilter for %s", err, tc.param)
+				}
+			}
+			if stmts := rec.statements(); len(stmts) > 0 {
+				t.Fatalf("SQL executed: %v", stmts)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package magicrest
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultResultCacheTTL: umur entry Options.ResultCache bila ResultCacheTTL 0
+const defaultResultCacheTTL = time.Minute
+
+// ResultCache: penyimpanan hasil list ter-serialisasi (Options.ResultCache), e.g. NewMemoryCache atau Redis
+type ResultCache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration)
+}
+
+// CacheKeyFunc: bagian key ResultCache dari context request (tenant, role), e.g. "tenant-7:kasir"
+type CacheKeyFunc func(ctx context.Context) string
+
+// PrefixInvalidator: cache yang bisa menghapus semua key berawalan prefix (InvalidatePrefix)
+type PrefixInvalidator interface {
+	InvalidatePrefix(ctx context.Context, prefix string) error
+}
+
+// CacheCodec: serialisasi entry ResultCache. Cache yang juga mengimplementasikan CodecCache memakai codec-nya
+// (e.g. msgpack di rediscache), selain itu GobCodec. Codec harus menyimpan semua field exported model, termasuk
+// yang `json:"-"` (e.g. DeletedAt): TransformItem, AfterQuery dan handler masih bisa membacanya setelah cache hit.
+type CacheCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// CodecCache: ResultCache dengan codec sendiri
+type CodecCache interface {
+	Codec() CacheCodec
+}
+
+// GobCodec: CacheCodec default (encoding/gob) — semua field exported, tanpa memandang tag json
+var GobCodec CacheCodec = gobCodec{}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// JSONCodec: CacheCodec encoding/json. Field `json:"-"` dan field dengan MarshalJSON kustom tidak kembali utuh
+// saat cache hit; pakai hanya bila model tidak punya field seperti itu.
+var JSONCodec CacheCodec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// cacheCodec: codec cache (CodecCache) atau GobCodec
+func cacheCodec(cache ResultCache) CacheCodec {
+	if cc, ok := cache.(CodecCache); ok && cc.Codec() != nil {
+		return cc.Codec()
+	}
+	return GobCodec
+}
+
+// CacheKeyPrefix: awalan key ResultCache untuk model T ("<package path>.<nama model>:", e.g.
+// "example.com/toko/model.Barang:"), untuk InvalidatePrefix. Package path ikut agar dua model bernama sama
+// dari package berbeda tidak berbagi entry.
+func CacheKeyPrefix[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return t.PkgPath() + "." + t.Name() + ":"
+}
+
+// modelName: nama tipe model T, e.g. "Barang"
+func modelName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().Name()
+}
+
+// InvalidatePrefix menghapus entry cache berawalan prefix (e.g. CacheKeyPrefix[Barang]() setelah write).
+// Cache yang tidak mengimplementasikan PrefixInvalidator -> error (entry baru hilang setelah TTL).
+func InvalidatePrefix(ctx context.Context, cache ResultCache, prefix string) error {
+	inv, ok := cache.(PrefixInvalidator)
+	if !ok {
+		return fmt.Errorf("magicrest: %T does not implement InvalidatePrefix", cache)
+	}
+	return inv.InvalidatePrefix(ctx, prefix)
+}
+
+// cachedList: isi entry ResultCache — data dan meta sebelum TransformResult; pagination disusun ulang
+// dari total agar tipenya sama dengan hasil query (int / int64, bukan float64 dari JSON). Meta disimpan
+// sebagai JSON: nilainya interface{} yang tidak bisa di-encode gob tanpa gob.Register.
+type cachedList[T any] struct {
+	Data  []T    `json:"data"`
+	Total int64  `json:"total"`
+	Meta  []byte `json:"meta,omitempty"`
+}
+
+// checkResultCacheKey: Scopes, MaskedColumns, MaskFields, BeforeQuery dan ReadDBResolver biasanya bergantung
+// context (tenant, role, otorisasi) sementara entry disimpan setelah semuanya diterapkan — tanpa ResultCacheKey
+// satu pemanggil bisa menerima data pemanggil lain
+func checkResultCacheKey(opts Options) error {
+	if opts.ResultCache == nil || opts.ResultCacheKey != nil {
+		return nil
+	}
+	for _, o := range []struct {
+		name string
+		set  bool
+	}{
+		{"Scopes", len(opts.Scopes) > 0},
+		{"MaskedColumns", len(opts.MaskedColumns) > 0},
+		{"MaskFields", opts.MaskFields != nil},
+		{"BeforeQuery", opts.BeforeQuery != nil},
+		{"ReadDBResolver", opts.ReadDBResolver != nil},
+	} {
+		if o.set {
+			return fmt.Errorf("%w: ResultCache with %s requires ResultCacheKey", ErrInvalidConfig, o.name)
+		}
+	}
+	return nil
+}
+
+// listCacheKey: "<model>:[<ResultCacheKey>:]<sha256 query kanonik (key terurut) dan Options yang membentuk
+// response (etagConfig)>", "" bila halaman tidak di-cache (melewati ResultCacheDepth atau ?debug=sql yang butuh
+// SQL sebenarnya). Dua endpoint model yang sama dengan SelectableColumns, preload, mask atau computed column
+// berbeda tidak berbagi entry.
+func listCacheKey[T any](ctx context.Context, query QuerySource, page, pageSize int, opts Options) string {
+	if opts.ResultCacheDepth > 0 && page > opts.ResultCacheDepth {
+		return ""
+	}
+	if opts.Debug || (opts.AllowDebugSQL && query.Get("debug") == "sql") {
+		return ""
+	}
+	key := CacheKeyPrefix[T]()
+	if opts.ResultCacheKey != nil {
+		key += opts.ResultCacheKey(ctx) + ":"
+	}
+	// page/pageSize efektif, jadi "?page=1" dan tanpa page berbagi entry
+	canonical := url.Values{}
+	for k, v := range query.Values() {
+		canonical[k] = v
+	}
+	canonical.Set("page", strconv.Itoa(page))
+	canonical.Set("pageSize", strconv.Itoa(pageSize))
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", canonical.Encode())
+	etagConfig(h, opts)
+	return key + hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// cachedResult: Result dari entry cache (Meta["cached"] = true); ok false bila tidak ada / rusak
+func cachedResult[T any](ctx context.Context, cache ResultCache, key string, page, pageSize int) (Result[T], bool) {
+	raw, ok := cache.Get(ctx, key)
+	if !ok {
+		return Result[T]{}, false
+	}
+	var c cachedList[T]
+	if err := cacheCodec(cache).Unmarshal(raw, &c); err != nil {
+		return Result[T]{}, false
+	}
+	meta := map[string]interface{}{}
+	if len(c.Meta) > 0 {
+		if err := json.Unmarshal(c.Meta, &meta); err != nil {
+			return Result[T]{}, false
+		}
+	}
+	meta["pagination"] = paginationMeta(c.Total, page, pageSize)
+	meta["cached"] = true
+	if c.Data == nil {
+		c.Data = []T{}
+	}
+	return Result[T]{Data: c.Data, Meta: meta}, true
+}
+
+// storeResult menyimpan data dan meta (tanpa pagination) ke cache; gagal encode = tidak di-cache
+func storeResult[T any](ctx context.Context, opts Options, key string, data []T, meta map[string]interface{}) {
+	c := cachedList[T]{Data: data}
+	rest := map[string]interface{}{}
+	for k, v := range meta {
+		if k == "pagination" {
+			c.Total, _ = v.(map[string]interface{})["total"].(int64)
+			continue
+		}
+		rest[k] = v
+	}
+	var err error
+	if len(rest) > 0 {
+		if c.Meta, err = json.Marshal(rest); err != nil {
+			return
+		}
+	}
+	raw, err := cacheCodec(opts.ResultCache).Marshal(c)
+	if err != nil {
+		return
+	}
+	ttl := opts.ResultCacheTTL
+	if ttl <= 0 {
+		ttl = defaultResultCacheTTL
+	}
+	opts.ResultCache.Set(ctx, key, raw, ttl)
+}
+
+// MemoryCache: ResultCache in-memory dengan TTL per entry dan batas jumlah entry (LRU), aman untuk goroutine
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // depan = paling baru dipakai
+	entries    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+// NewMemoryCache: MemoryCache dengan maksimal maxEntries entry (<= 0 = 1000)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryCache{maxEntries: maxEntries, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get: nilai key bila ada dan belum kedaluwarsa
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.val, true
+}
+
+// Set menyimpan val selama ttl; entry paling lama tidak dipakai dibuang bila melebihi maxEntries
+func (c *MemoryCache) Set(_ context.Context, key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &memoryEntry{key: key, val: val, expires: time.Now().Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// InvalidatePrefix menghapus semua entry berawalan prefix
+func (c *MemoryCache) InvalidatePrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+// Len: jumlah entry (termasuk yang kedaluwarsa tapi belum dibuang)
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemoryCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*memoryEntry).key)
+}
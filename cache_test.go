@@ -0,0 +1,182 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Akun: model dengan field yang tidak pernah dikirim ke klien
+type Akun struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Sandi string `json:"-"`
+}
+
+func TestResultCacheKeepsHiddenFields(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		codec CacheCodec // nil = default (GobCodec)
+	}{
+		{"default", nil},
+		{"gob", GobCodec},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if err := db.AutoMigrate(&Akun{}); err != nil {
+				t.Fatal(err)
+			}
+			db.Create(&[]Akun{{Nama: "budi", Sandi: "rahasia"}, {Nama: "sari", Sandi: "kunci"}})
+			var cache ResultCache = NewMemoryCache(10)
+			if tc.codec != nil {
+				cache = codecMemoryCache{MemoryCache: NewMemoryCache(10), codec: tc.codec}
+			}
+			opts := Options{OrderBy: "id", ResultCache: cache}
+			miss, err := ReadPaginated(url.Values{}, db.Model(&Akun{}), &Akun{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rdb, rec := recordSQL(db)
+			hit, err := ReadPaginated(url.Values{}, rdb.Model(&Akun{}), &Akun{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hit.Meta["cached"] != true || len(rec.statements()) > 0 {
+				t.Fatalf("meta %v, SQL %v: want a cache hit", hit.Meta, rec.statements())
+			}
+			for i := range miss.Data {
+				if hit.Data[i] != miss.Data[i] {
+					t.Fatalf("row %d: hit %+v, miss %+v", i, hit.Data[i], miss.Data[i])
+				}
+			}
+			p := hit.Meta["pagination"].(map[string]interface{})
+			if total, ok := p["total"].(int64); !ok || total != 2 {
+				t.Fatalf("pagination %v", p)
+			}
+		})
+	}
+}
+
+// codecMemoryCache: MemoryCache dengan CodecCache
+type codecMemoryCache struct {
+	*MemoryCache
+	codec CacheCodec
+}
+
+func (c codecMemoryCache) Codec() CacheCodec { return c.codec }
+
+func TestResultCacheKeepsMeta(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	opts := Options{OrderBy: "id", ResultCache: NewMemoryCache(10)}
+	query := url.Values{"fields": {"kode"}}
+	miss, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hit, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit.Meta["cached"] != true {
+		t.Fatalf("meta %v: want a cache hit", hit.Meta)
+	}
+	if miss.Meta["implicitFields"] == nil || hit.Meta["implicitFields"] == nil {
+		t.Fatalf("implicitFields: miss %v, hit %v", miss.Meta, hit.Meta)
+	}
+}
+
+func TestResultCacheRequiresKeyWithScopes(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", 1) }
+	cases := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"scopes", Options{Scopes: []Scope{tenant}}, ErrInvalidConfig},
+		{"masks", Options{MaskedColumns: map[string][]string{"kasir": {"telepon"}}}, ErrInvalidConfig},
+		{"mask func", Options{MaskFields: func(context.Context, any) {}}, ErrInvalidConfig},
+		{"before query", Options{BeforeQuery: func(_ context.Context, db *gorm.DB, _ QueryParams) (*gorm.DB, error) { return db, nil }}, ErrInvalidConfig},
+		{"read db resolver", Options{ReadDBResolver: func(context.Context) *gorm.DB { return nil }}, ErrInvalidConfig},
+		{"before query with key", Options{
+			BeforeQuery:    func(_ context.Context, db *gorm.DB, _ QueryParams) (*gorm.DB, error) { return db, nil },
+			ResultCacheKey: func(context.Context) string { return "u1" },
+		}, nil},
+		{"scopes with key", Options{Scopes: []Scope{tenant}, ResultCacheKey: func(context.Context) string { return "t1" }}, nil},
+		{"no scopes", Options{}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			tc.opts.ResultCache = NewMemoryCache(10)
+			rdb, rec := recordSQL(db)
+			_, err := ReadPaginated(url.Values{}, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if tc.want != nil && len(rec.statements()) > 0 {
+				t.Fatalf("SQL executed: %v", rec.statements())
+			}
+			if err := tc.opts.Validate(&Order{}); !errors.Is(err, tc.want) {
+				t.Fatalf("Validate = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestResultCacheKeyIncludesOptions(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	cache := NewMemoryCache(10)
+	cases := []struct {
+		name string
+		opts Options
+		hit  bool
+	}{
+		{"full", Options{}, false},
+		{"full again", Options{}, true},
+		{"selectable", Options{SelectableColumns: []string{"id", "kode"}}, false},
+		{"preload", Options{PreloadFields: []string{"Items"}}, false},
+		{"masked", Options{MaskedColumns: map[string][]string{"kasir": {"telepon"}}}, false},
+		{"computed", Options{ComputedColumns: map[string]string{"dua": "2"}}, false},
+		{"selectable again", Options{SelectableColumns: []string{"id", "kode"}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// key pemanggil sama untuk semua endpoint: hanya Options yang membedakan entry
+			tc.opts.OrderBy, tc.opts.ResultCache = "id", cache
+			tc.opts.ResultCacheKey = func(context.Context) string { return "kasir" }
+			tc.opts.MaskRole = func(context.Context) string { return "kasir" }
+			res, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hit := res.Meta["cached"] == true; hit != tc.hit {
+				t.Fatalf("cached = %v, want %v", hit, tc.hit)
+			}
+			o := res.Data[0]
+			if restricted := tc.opts.SelectableColumns != nil || tc.opts.MaskedColumns != nil; restricted && o.Telepon != "" {
+				t.Fatalf("telepon served by a restricted endpoint: %+v", o)
+			}
+			if preload := tc.opts.PreloadFields != nil; preload != (len(o.Items) > 0) {
+				t.Fatalf("items %+v, preload %v", o.Items, preload)
+			}
+		})
+	}
+}
+
+func TestCacheKeyPrefixQualified(t *testing.T) {
+	if got, want := CacheKeyPrefix[Order](), "github.com/Jupriadi/magic-rest.Order:"; got != want {
+		t.Fatalf("CacheKeyPrefix = %q, want %q", got, want)
+	}
+	key := listCacheKey[Order](context.Background(), FromURLValues(url.Values{}), 1, 10, Options{})
+	if !strings.HasPrefix(key, CacheKeyPrefix[Order]()) {
+		t.Fatalf("key %q", key)
+	}
+}
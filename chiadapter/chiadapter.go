@@ -0,0 +1,107 @@
+// Package chiadapter memasang resource magicrest ke router chi. Modul terpisah (go.mod sendiri) agar chi
+// tetap dependency opsional. chi memakai net/http, jadi handler magicrest (ListHandlerHTTP, ...) dipakai
+// langsung; id diambil dari chi.URLParam.
+package chiadapter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// ResourceConfig: konfigurasi Mount
+type ResourceConfig struct {
+	Verbs        []magicrest.Verb                                     // verb yang dipasang (nil = magicrest.AllVerbs)
+	Middleware   map[magicrest.Verb][]func(http.Handler) http.Handler // middleware per verb, e.g. VerbDelete: {adminOnly}
+	Options      magicrest.Options                                    // list dan get
+	WriteOptions magicrest.WriteOptions                               // create, update, patch, delete
+	Describe     bool                                                 // OPTIONS path dan GET path/_meta: magicrest.Describe
+}
+
+// withID meneruskan chi.URLParam(r, "id") sebagai r.PathValue("id") untuk handler net/http magicrest
+func withID(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.SetPathValue("id", chi.URLParam(r, "id"))
+		h(w, r)
+	}
+}
+
+// ListHandler: GET list untuk model T (magicrest.ListHandlerHTTP)
+func ListHandler[T any](db *gorm.DB, opts magicrest.Options) http.HandlerFunc {
+	return magicrest.ListHandlerHTTP[T](db, opts)
+}
+
+// GetHandler: GET /{id} via magicrest.ReadOne, id dari chi.URLParam
+func GetHandler[T any](db *gorm.DB, opts magicrest.Options) http.HandlerFunc {
+	return withID(magicrest.GetHandlerHTTP[T](db, opts))
+}
+
+// CreateHandler: POST via magicrest.CreateGeneric, response 201 dengan Location
+func CreateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) http.HandlerFunc {
+	return magicrest.CreateHandlerHTTP[T](db, opts)
+}
+
+// UpdateHandler: PUT /{id} via magicrest.UpdateGeneric
+func UpdateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) http.HandlerFunc {
+	return withID(magicrest.UpdateHandlerHTTP[T](db, opts))
+}
+
+// PatchHandler: PATCH /{id} via magicrest.PatchGeneric
+func PatchHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) http.HandlerFunc {
+	return withID(magicrest.PatchHandlerHTTP[T](db, opts))
+}
+
+// DeleteHandler: DELETE /{id} via magicrest.DeleteGeneric, response 204
+func DeleteHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) http.HandlerFunc {
+	return withID(magicrest.DeleteHandlerHTTP[T](db, opts))
+}
+
+// Mount memasang GET path (dan HEAD path), GET path/{id}, POST path, PUT/PATCH/DELETE path/{id} untuk model T di r — padanan
+// ginrest.RegisterCRUD. Verb yang tidak dipilih tidak didaftarkan; middleware per verb lewat cfg.Middleware.
+// Envelope WriteOptions mengikuti Options.Envelope bila tidak di-set. ResourceConfig.Describe menambah OPTIONS path
+// dan GET path/_meta (magicrest.DescribeHandlerHTTP).
+func Mount[T any](r chi.Router, path string, db *gorm.DB, cfg ResourceConfig) {
+	verbs := cfg.Verbs
+	if verbs == nil {
+		verbs = magicrest.AllVerbs
+	}
+	write := cfg.WriteOptions
+	if write.Envelope == (magicrest.EnvelopeConfig{}) {
+		write.Envelope = cfg.Options.Envelope
+	}
+	path = "/" + strings.Trim(path, "/")
+	for _, v := range verbs {
+		var method, pattern string
+		var h http.HandlerFunc
+		switch v {
+		case magicrest.VerbList:
+			method, pattern, h = http.MethodGet, path, ListHandler[T](db, cfg.Options)
+		case magicrest.VerbGet:
+			method, pattern, h = http.MethodGet, path+"/{id}", GetHandler[T](db, cfg.Options)
+		case magicrest.VerbCreate:
+			method, pattern, h = http.MethodPost, path, CreateHandler[T](db, write)
+		case magicrest.VerbUpdate:
+			method, pattern, h = http.MethodPut, path+"/{id}", UpdateHandler[T](db, write)
+		case magicrest.VerbPatch:
+			method, pattern, h = http.MethodPatch, path+"/{id}", PatchHandler[T](db, write)
+		case magicrest.VerbDelete:
+			method, pattern, h = http.MethodDelete, path+"/{id}", DeleteHandler[T](db, write)
+		default:
+			panic(fmt.Sprintf("chiadapter: unknown verb %q", v))
+		}
+		r.With(cfg.Middleware[v]...).Method(method, pattern, h)
+		if v == magicrest.VerbList {
+			// HEAD path = hanya count di header (X-Total-Count, X-Page-Count, Link)
+			r.With(cfg.Middleware[v]...).Method(http.MethodHead, pattern, h)
+		}
+	}
+	if cfg.Describe {
+		h := magicrest.DescribeHandlerHTTP[T](db, cfg.Options, verbs)
+		r.Options(path, h)
+		r.Get(path+"/_meta", h)
+	}
+}
@@ -0,0 +1,109 @@
+package chiadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Barang struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Harga int    `json:"harga"`
+}
+
+func newBarangDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Barang{{Nama: "Semen", Harga: 50}, {Nama: "Pasir", Harga: 20}})
+	return db
+}
+
+func TestMount(t *testing.T) {
+	db := newBarangDB(t)
+	r := chi.NewRouter()
+	adminOnly := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Role") != "admin" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	Mount[Barang](r, "/api/barang/", db, ResourceConfig{
+		Middleware: map[magicrest.Verb][]func(http.Handler) http.Handler{magicrest.VerbDelete: {adminOnly}},
+		Options:    magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}},
+	})
+	Mount[Barang](r, "katalog", db, ResourceConfig{Verbs: []magicrest.Verb{magicrest.VerbList, magicrest.VerbGet}})
+
+	cases := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		role     string
+		status   int
+		want     string // potongan body
+		location string
+	}{
+		{"list", http.MethodGet, "/api/barang?filter[harga]=20", "", "", http.StatusOK, `"nama":"Pasir"`, ""},
+		{"list invalid filter", http.MethodGet, "/api/barang?filter[harga]=mahal", "", "", http.StatusBadRequest, `"code":"invalid_int"`, ""},
+		{"get", http.MethodGet, "/api/barang/1", "", "", http.StatusOK, `"nama":"Semen"`, ""},
+		{"get not found", http.MethodGet, "/api/barang/99", "", "", http.StatusNotFound, `"error"`, ""},
+		{"create", http.MethodPost, "/api/barang", `{"nama":"Bata","harga":3}`, "", http.StatusCreated, `"id":3`, "/api/barang/3"},
+		{"update", http.MethodPut, "/api/barang/3", `{"nama":"Bata Merah","harga":4}`, "", http.StatusOK, `"nama":"Bata Merah"`, ""},
+		{"patch", http.MethodPatch, "/api/barang/3", `{"harga":5}`, "", http.StatusOK, `"harga":5`, ""},
+		{"delete without role", http.MethodDelete, "/api/barang/3", "", "", http.StatusForbidden, "", ""},
+		{"delete", http.MethodDelete, "/api/barang/3", "", "admin", http.StatusNoContent, "", ""},
+		{"get deleted", http.MethodGet, "/api/barang/3", "", "", http.StatusNotFound, `"error"`, ""},
+		{"read-only get", http.MethodGet, "/katalog/2", "", "", http.StatusOK, `"nama":"Pasir"`, ""},
+		{"disabled create", http.MethodPost, "/katalog", `{"nama":"Bata"}`, "", http.StatusMethodNotAllowed, "", ""},
+		{"disabled delete", http.MethodDelete, "/katalog/1", "", "admin", http.StatusMethodNotAllowed, "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.role != "" {
+				req.Header.Set("X-Role", tc.role)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tc.status || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, body %s; want %d with %s", rec.Code, rec.Body.String(), tc.status, tc.want)
+			}
+			if got := rec.Header().Get("Location"); got != tc.location {
+				t.Fatalf("Location %q, want %q", got, tc.location)
+			}
+		})
+	}
+	var n int64
+	db.Model(&Barang{}).Count(&n)
+	if n != 2 {
+		t.Fatalf("%d rows after delete, want 2", n)
+	}
+}
+
+func TestMountUnknownVerb(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic for unknown verb")
+		}
+	}()
+	Mount[Barang](chi.NewRouter(), "/barang", newBarangDB(t), ResourceConfig{Verbs: []magicrest.Verb{"archive"}})
+}
@@ -0,0 +1,71 @@
+package magicrest
+
+import (
+	"reflect"
+)
+
+// CompiledOptions: Options yang sudah divalidasi dan di-resolve sekali untuk model T (CompileOptions) —
+// tipe filter final (DefaultFieldTypes + AutoFieldTypes + LegacyFieldTypes), whitelist preload
+// (AutoAllowPreloads) dan SearchFields dari tag (TagDrivenConfig) — sehingga request tidak menyusunnya ulang.
+type CompiledOptions struct {
+	opts  Options
+	model reflect.Type
+}
+
+// CompileOptions memvalidasi opts terhadap model T (Options.Validate, semua masalah sekaligus) lalu
+// me-resolve semua yang bergantung pada model. Panggil sekali saat setup:
+//
+//	compiled, err := magicrest.CompileOptions[Barang](opts)
+//	r.GET("/barang", ginrest.ListHandler[Barang](db, compiled.Options()))
+func CompileOptions[T any](opts Options) (*CompiledOptions, error) {
+	if err := opts.Validate(new(T)); err != nil {
+		return nil, err
+	}
+	c := &CompiledOptions{model: reflect.TypeOf((*T)(nil)).Elem()}
+	c.opts = resolveOptions[T](opts)
+	c.opts.compiled = c
+	return c, nil
+}
+
+// Options: Options hasil compile, diterima semua fungsi yang menerima Options (ReadPaginated, ReadOne,
+// handler, adapter). Field hasil resolve (DefaultFieldTypes, AllowedPreloads, SearchFields) jangan diubah di
+// salinannya — compile ulang; field lain (MaxPageSize, hooks, ...) boleh.
+func (c *CompiledOptions) Options() Options {
+	return c.opts
+}
+
+// compiledFor: opts berasal dari CompileOptions untuk model T (resolve dan validasi dilewati)
+func compiledFor[T any](opts Options) bool {
+	return opts.compiled != nil && opts.compiled.model == reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// resolveOptions menggabungkan konfigurasi turunan model T ke opts: tipe filter (map baru, milik caller tidak
+// diubah), relasi AutoAllowPreloads ke AllowedPreloads dan SearchFields dari tag bila TagDrivenConfig.
+func resolveOptions[T any](opts Options) Options {
+	if opts.AutoFieldTypes || opts.LegacyFieldTypes {
+		types := map[string]string{}
+		if opts.LegacyFieldTypes {
+			for k, v := range legacyFieldTypes {
+				types[k] = v
+			}
+		}
+		if opts.AutoFieldTypes {
+			for k, v := range modelFieldTypes[T]() {
+				types[k] = v
+			}
+		}
+		for k, v := range opts.DefaultFieldTypes {
+			types[k] = v
+		}
+		opts.DefaultFieldTypes = types
+		opts.AutoFieldTypes, opts.LegacyFieldTypes = false, false
+	}
+	if opts.AutoAllowPreloads {
+		opts.AllowedPreloads = append(append([]string{}, opts.AllowedPreloads...), modelRelations[T]()...)
+		opts.AutoAllowPreloads = false
+	}
+	if opts.TagDrivenConfig && len(opts.SearchFields) == 0 {
+		opts.SearchFields = ConfigFromModel[T]().SearchFields
+	}
+	return opts
+}
@@ -0,0 +1,69 @@
+package magicrest
+
+import (
+	"net/url"
+	"testing"
+)
+
+// benchOptions: konfigurasi yang di-resolve per request tanpa CompileOptions (tipe filter dari schema,
+// whitelist preload otomatis)
+var benchOptions = Options{OrderBy: "id", AutoFieldTypes: true, AutoAllowPreloads: true, DefaultPageSize: 20}
+
+// BenchmarkReadPaginated vs BenchmarkReadPaginatedCompiled: request tanpa filter, selisih alokasi = kerja
+// resolve yang dilewati Options hasil CompileOptions
+func BenchmarkReadPaginated(b *testing.B) {
+	db := newTestDB(b)
+	seedOrders(b, db, 20, 0)
+	query := url.Values{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, benchOptions); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadPaginatedCompiled(b *testing.B) {
+	db := newTestDB(b)
+	seedOrders(b, db, 20, 0)
+	compiled, err := CompileOptions[Order](benchOptions)
+	if err != nil {
+		b.Fatal(err)
+	}
+	opts := compiled.Options()
+	query := url.Values{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCompiledOptionsSameResult(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 1)
+	compiled, err := CompileOptions[Order](benchOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{"filter[gudang_id]": {"1"}, "preload": {"Items"}}
+	plain, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, benchOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, compiled.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain.Data) != 3 || len(fast.Data) != len(plain.Data) {
+		t.Fatalf("rows: plain %d, compiled %d", len(plain.Data), len(fast.Data))
+	}
+	for i := range plain.Data {
+		if plain.Data[i].ID != fast.Data[i].ID || len(fast.Data[i].Items) != 1 {
+			t.Fatalf("row %d: plain %+v, compiled %+v", i, plain.Data[i], fast.Data[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// CountList: hanya query count dari pipeline ReadPaginated (Scopes, filter, search) tanpa query data,
+// mengembalikan Meta["pagination"] yang sama dengan ReadPaginatedSource.
+func CountList[T any](ctx context.Context, query QuerySource, db *gorm.DB, opts Options) (map[string]interface{}, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db = readDB(ctx, db, query, opts)
+	if opts.QueryTimeout > 0 {
+		var pagination map[string]interface{}
+		err := withQueryTimeout(ctx, db, opts.QueryTimeout, func(ctx context.Context, tx *gorm.DB) error {
+			inner := opts
+			inner.QueryTimeout = 0
+			var err error
+			pagination, err = CountList[T](ctx, query, tx, inner)
+			return err
+		})
+		return pagination, err
+	}
+	db = db.WithContext(ctx)
+	q, info, err := BuildQuerySource[T](query, db.Model(new(T)), new(T), opts)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	slow := newSlowQuery(opts, info.Params, modelName[T]())
+	if err := slow.run(QueryLabelCount, countQuery(q), func(tx *gorm.DB) *gorm.DB { return tx.Count(&total) }); err != nil {
+		return nil, err
+	}
+	pagination := paginationMeta(total, info.Page, info.PageSize)
+	if pc, _ := pagination["pageCount"].(int); opts.StrictQuery && info.Page > 1 && info.Page > pc {
+		return pagination, newQueryError(ErrPageOutOfRange, "page", strconv.Itoa(info.Page), fmt.Sprintf("exceeds pageCount %d", pc))
+	}
+	return pagination, nil
+}
+
+// CountHeaders: header X-Total-Count, X-Page-Count dan Link untuk pagination dari CountList
+func CountHeaders(u *url.URL, pagination map[string]interface{}) http.Header {
+	h := http.Header{}
+	h.Set("X-Total-Count", fmt.Sprint(pagination["total"]))
+	h.Set("X-Page-Count", fmt.Sprint(pagination["pageCount"]))
+	if link := LinkHeader(PageLinks(u, pagination)); link != "" {
+		h.Set("Link", link)
+	}
+	return h
+}
+
+// headList: respon HEAD list — hanya count, header dari CountHeaders, tanpa body
+func headList[T any](w http.ResponseWriter, r *http.Request, db *gorm.DB, opts Options) {
+	pagination, err := CountList[T](r.Context(), FromURLValues(r.URL.Query()), db, opts)
+	if err != nil {
+		w.WriteHeader(StatusForError(err))
+		return
+	}
+	for k, v := range CountHeaders(r.URL, pagination) {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(http.StatusOK)
+}
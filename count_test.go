@@ -0,0 +1,53 @@
+package magicrest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListHandlerHead(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 2)
+	cases := []struct {
+		name      string
+		target    string
+		status    int
+		total     string
+		pageCount string
+		link      string // potongan header Link
+	}{
+		{"all", "/orders?pageSize=2", http.StatusOK, "5", "3", `rel="next"`},
+		{"filtered", "/orders?pageSize=2&filter[status]=aktif", http.StatusOK, "3", "2", `filter%5Bstatus%5D=aktif`},
+		{"last page", "/orders?pageSize=2&page=3", http.StatusOK, "5", "3", `rel="prev"`},
+		{"invalid filter", "/orders?filter[tidak_ada]=1", http.StatusBadRequest, "", "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, sql := recordSQL(db)
+			handler := ListHandlerHTTP[Order](rdb, Options{StrictQuery: true, PreloadFields: []string{"Items"}})
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodHead, tc.target, nil))
+
+			if rec.Code != tc.status || rec.Body.Len() != 0 {
+				t.Fatalf("status %d, body %q", rec.Code, rec.Body.String())
+			}
+			if rec.Header().Get("X-Total-Count") != tc.total || rec.Header().Get("X-Page-Count") != tc.pageCount {
+				t.Fatalf("headers %v", rec.Header())
+			}
+			if !strings.Contains(rec.Header().Get("Link"), tc.link) {
+				t.Fatalf("Link %q, want %q", rec.Header().Get("Link"), tc.link)
+			}
+			// hanya query count: tanpa SELECT data maupun preload
+			for _, s := range sql.statements() {
+				if !strings.Contains(s, "count(*)") {
+					t.Fatalf("non-count query issued: %s", s)
+				}
+			}
+			if tc.status == http.StatusOK && len(sql.statements()) != 1 {
+				t.Fatalf("queries %v, want one count", sql.statements())
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidWithCount digunakan bila ?with_count= / Options.WithCounts menyebut relasi yang tidak valid
+var ErrInvalidWithCount = errors.New("invalid with_count relation")
+
+// relationCount: satu relasi yang dihitung lewat correlated subquery COUNT(*)
+type relationCount struct {
+	Relation string
+	Alias    string // <relation>_count, e.g. "items_count"
+	SQL      string // (SELECT COUNT(*) FROM ... WHERE ...)
+	InModel  bool   // model punya field dengan nama alias -> langsung ter-scan ke T
+}
+
+// countSubquery menyusun correlated subquery COUNT(*) untuk relasi has-one/has-many/many2many.
+func countSubquery(db *gorm.DB, sch *schema.Schema, rel *schema.Relationship) (string, error) {
+	quote := db.Statement.Quote
+	table := rel.FieldSchema.Table
+	if rel.JoinTable != nil {
+		table = rel.JoinTable.Table
+	}
+
+	var conds []string
+	for _, ref := range rel.References {
+		switch {
+		case ref.PrimaryValue != "":
+			conds = append(conds, fmt.Sprintf("%s.%s = '%s'", quote(table), quote(ref.ForeignKey.DBName),
+				strings.ReplaceAll(ref.PrimaryValue, "'", "''")))
+		case ref.OwnPrimaryKey:
+			conds = append(conds, fmt.Sprintf("%s.%s = %s.%s", quote(table), quote(ref.ForeignKey.DBName),
+				quote(sch.Table), quote(ref.PrimaryKey.DBName)))
+		}
+	}
+	if len(conds) == 0 {
+		return "", fmt.Errorf("%w: %s is not a has-one, has-many or many2many relation", ErrInvalidWithCount, rel.Name)
+	}
+	if rel.JoinTable == nil {
+		if sd := softDeleteField(rel.FieldSchema); sd != nil {
+			conds = append(conds, fmt.Sprintf("%s.%s IS NULL", quote(table), quote(sd.DBName)))
+		}
+	}
+	return fmt.Sprintf("(SELECT COUNT(*) FROM %s WHERE %s)", quote(table), strings.Join(conds, " AND ")), nil
+}
+
+// resolveWithCounts menggabungkan Options.WithCounts dengan ?with_count=A,B (requested, dicek terhadap
+// Options.AllowedPreloads) lalu memvalidasi tiap relasi terhadap schema model.
+func resolveWithCounts(db *gorm.DB, sch *schema.Schema, requested []string, opts Options) ([]relationCount, error) {
+	seen := map[string]bool{}
+	var names []string
+	for _, n := range opts.WithCounts {
+		if !seen[n] {
+			seen[n] = true
+			names = append(names, n)
+		}
+	}
+	for _, n := range requested {
+		if seen[n] {
+			continue
+		}
+		if !preloadAllowed(n, opts.AllowedPreloads) {
+			return nil, fmt.Errorf("%w: %s", ErrPreloadNotAllowed, n)
+		}
+		seen[n] = true
+		names = append(names, n)
+	}
+
+	var counts []relationCount
+	for _, n := range names {
+		rel, ok := sch.Relationships.Relations[n]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown relation %q", ErrInvalidWithCount, n)
+		}
+		sql, err := countSubquery(db, sch, rel)
+		if err != nil {
+			return nil, err
+		}
+		alias := db.NamingStrategy.ColumnName("", rel.Name) + "_count"
+		f := sch.LookUpField(alias)
+		counts = append(counts, relationCount{
+			Relation: n,
+			Alias:    alias,
+			SQL:      sql,
+			InModel:  f != nil && f.Readable,
+		})
+	}
+	return counts, nil
+}
+
+// loadCountsMeta menghitung relasi yang tidak punya field di model untuk baris di halaman ini
+// (satu query tambahan by primary key). Hasil: map[pk]map[alias]count untuk Meta["counts"].
+func loadCountsMeta[T any](db *gorm.DB, sch *schema.Schema, data []T, counts []relationCount, slow *slowQuery) (map[string]map[string]interface{}, error) {
+	var extra []relationCount
+	for _, c := range counts {
+		if !c.InModel {
+			extra = append(extra, c)
+		}
+	}
+	if len(extra) == 0 || len(data) == 0 {
+		return nil, nil
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, fmt.Errorf("%w: model %s has no single primary key", ErrInvalidWithCount, sch.Name)
+	}
+
+	pks := make([]interface{}, 0, len(data))
+	for i := range data {
+		if v, zero := pk.ValueOf(db.Statement.Context, reflect.ValueOf(&data[i]).Elem()); !zero {
+			pks = append(pks, v)
+		}
+	}
+
+	selects := []string{db.Statement.Quote(sch.Table) + "." + db.Statement.Quote(pk.DBName) + " AS " + db.Statement.Quote("pk")}
+	for _, c := range extra {
+		selects = append(selects, fmt.Sprintf("%s AS %s", c.SQL, db.Statement.Quote(c.Alias)))
+	}
+	var rows []map[string]interface{}
+	q := db.Session(&gorm.Session{NewDB: true}).
+		Table(sch.Table).
+		Select(strings.Join(selects, ", ")).
+		Where(fmt.Sprintf("%s.%s IN ?", db.Statement.Quote(sch.Table), db.Statement.Quote(pk.DBName)), pks)
+	err := slow.run(QueryLabelWithCount, q, func(tx *gorm.DB) *gorm.DB { return tx.Find(&rows) })
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		key := fmt.Sprint(scannedValue(row["pk"]))
+		delete(row, "pk")
+		for k, v := range row {
+			row[k] = scannedValue(v)
+		}
+		out[key] = row
+	}
+	return out, nil
+}
+
+// scannedValue: nilai hasil scan ke map tanpa pointer (driver tanpa tipe kolom untuk subquery memberi *interface{})
+func scannedValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
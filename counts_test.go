@@ -0,0 +1,106 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+// OrderRingkas: order dengan field items_count (read-only) untuk hasil with_count
+type OrderRingkas struct {
+	ID         uint   `json:"id"`
+	Kode       string `json:"kode"`
+	Status     string `json:"status"`
+	Items      []Item `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+	ItemsCount int    `json:"items_count" gorm:"->;-:migration"`
+}
+
+func (OrderRingkas) TableName() string { return "orders" }
+
+func TestReadPaginatedWithCountInModelField(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 4, 0)
+	for i, o := range orders {
+		for j := 0; j < i; j++ {
+			db.Create(&Item{OrderID: o.ID, Nama: fmt.Sprintf("item-%d", j), Jumlah: 1})
+		}
+	}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  string // kode:items_count per row
+	}{
+		{"query", url.Values{"with_count": {"Items"}}, Options{}, "[ORD-01:0 ORD-02:1 ORD-03:2 ORD-04:3]"},
+		{"options", url.Values{}, Options{WithCounts: []string{"Items"}}, "[ORD-01:0 ORD-02:1 ORD-03:2 ORD-04:3]"},
+		{"with filter and page", url.Values{"with_count": {"Items"}, "filter[status]": {"selesai"}, "pageSize": {"1"}, "page": {"2"}},
+			Options{}, "[ORD-04:3]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&OrderRingkas{}), &OrderRingkas{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, o := range res.Data {
+				if o.Items != nil {
+					t.Fatalf("items preloaded for %s", o.Kode)
+				}
+				got = append(got, fmt.Sprintf("%s:%d", o.Kode, o.ItemsCount))
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+			// field di model: tidak ada query tambahan dan tidak ada Meta["counts"]
+			if len(rec.statements()) != 2 || res.Meta["counts"] != nil {
+				t.Fatalf("meta %v, SQL %v", res.Meta, rec.statements())
+			}
+		})
+	}
+}
+
+func TestReadPaginatedWithCountInMeta(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 3, 2)
+	res, err := ReadPaginated(url.Values{"with_count": {"Items"}, "filter[status]": {"aktif"}}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, ok := res.Meta["counts"].(map[string]map[string]interface{})
+	if !ok || len(res.Data) != 2 || len(counts) != 2 {
+		t.Fatalf("data %d rows, counts %#v", len(res.Data), res.Meta["counts"])
+	}
+	for _, o := range []Order{orders[0], orders[2]} {
+		if got := counts[fmt.Sprint(o.ID)]["items_count"]; got != int64(2) {
+			t.Fatalf("counts[%d] = %#v, want items_count 2", o.ID, counts[fmt.Sprint(o.ID)])
+		}
+	}
+}
+
+func TestReadPaginatedWithCountErrors(t *testing.T) {
+	db := newTestDB(t)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  error
+	}{
+		{"unknown relation", url.Values{"with_count": {"Itemz"}}, Options{}, ErrInvalidWithCount},
+		{"belongs-to", url.Values{"with_count": {"Gudang"}}, Options{}, ErrInvalidWithCount},
+		{"unknown in options", url.Values{}, Options{WithCounts: []string{"Itemz"}}, ErrInvalidWithCount},
+		{"outside preload whitelist", url.Values{"with_count": {"Items"}}, Options{AllowedPreloads: []string{"Gudang"}}, ErrPreloadNotAllowed},
+		{"options skip whitelist", url.Values{}, Options{WithCounts: []string{"Items"}, AllowedPreloads: []string{}}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			if _, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
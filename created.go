@@ -0,0 +1,60 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// createdSchemas: cache schema untuk ResourceLocation (tanpa *gorm.DB, naming strategy default)
+var createdSchemas sync.Map
+
+// ResourceLocation menyusun URL resource baru dari basePath dan primary key created (pointer ke model),
+// e.g. "/api/barang" + ID 42 -> "/api/barang/42". Primary key komposit digabung dengan koma.
+func ResourceLocation(basePath string, created any) (string, error) {
+	sch, err := schema.Parse(created, &createdSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return "", err
+	}
+	if len(sch.PrimaryFields) == 0 {
+		return "", fmt.Errorf("model %s has no primary key", sch.Name)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(created))
+	keys := make([]string, len(sch.PrimaryFields))
+	for i, pk := range sch.PrimaryFields {
+		v, zero := pk.ValueOf(context.Background(), rv)
+		if zero {
+			return "", fmt.Errorf("model %s: primary key %s is empty", sch.Name, pk.Name)
+		}
+		keys[i] = url.PathEscape(fmt.Sprint(v))
+	}
+	return strings.TrimRight(basePath, "/") + "/" + strings.Join(keys, ","), nil
+}
+
+// WriteCreated menulis {"data": created} dengan status 201 dan header Location dari ResourceLocation.
+// Untuk mengirim DTO alih-alih model pakai WriteCreatedAs.
+func WriteCreated(w http.ResponseWriter, basePath string, created any) {
+	writeCreated(w, basePath, created, created)
+}
+
+// WriteCreatedAs: WriteCreated dengan body hasil mapFn (DTO, sama seperti ReadPaginatedInto);
+// Location tetap diambil dari primary key model.
+func WriteCreatedAs[T any, D any](w http.ResponseWriter, basePath string, created *T, mapFn func(T) D) {
+	writeCreated(w, basePath, created, mapFn(*created))
+}
+
+func writeCreated(w http.ResponseWriter, basePath string, created any, body any) {
+	location, err := ResourceLocation(basePath, created)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Location", location)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": body})
+}
@@ -0,0 +1,70 @@
+package magicrest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Tarif: primary key komposit untuk Location
+type Tarif struct {
+	Asal   string `json:"asal" gorm:"primaryKey"`
+	Tujuan string `json:"tujuan" gorm:"primaryKey"`
+	Harga  int    `json:"harga"`
+}
+
+func TestResourceLocation(t *testing.T) {
+	cases := []struct {
+		name     string
+		basePath string
+		created  any
+		want     string // "" = error
+	}{
+		{"uint id", "/api/orders", &Order{ID: 42}, "/api/orders/42"},
+		{"trailing slash", "/api/orders/", &Order{ID: 7}, "/api/orders/7"},
+		{"uuid", "/api/pelanggan", &Pelanggan{ID: uuid.MustParse("8f14e45f-ceea-467f-a0e8-1b5a0c1f2a3b")}, "/api/pelanggan/8f14e45f-ceea-467f-a0e8-1b5a0c1f2a3b"},
+		{"composite escaped", "/api/tarif", &Tarif{Asal: "Kota Baru", Tujuan: "Bogor"}, "/api/tarif/Kota%20Baru,Bogor"},
+		{"empty primary key", "/api/orders", &Order{Kode: "ORD-01"}, ""},
+		{"composite partly empty", "/api/tarif", &Tarif{Asal: "Bogor"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResourceLocation(tc.basePath, tc.created)
+			if (err != nil) != (tc.want == "") || got != tc.want {
+				t.Fatalf("got %q, err %v; want %q", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteCreated(t *testing.T) {
+	created := &Order{ID: 3, Kode: "ORD-03", Status: "aktif"}
+	cases := []struct {
+		name     string
+		write    func(w http.ResponseWriter)
+		status   int
+		location string
+		body     string
+	}{
+		{"model", func(w http.ResponseWriter) { WriteCreated(w, "/api/orders", created) }, http.StatusCreated, "/api/orders/3",
+			`{"data":{"id":3,"kode":"ORD-03","status":"aktif","telepon":"","gudang_id":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}}`},
+		{"dto", func(w http.ResponseWriter) {
+			WriteCreatedAs(w, "/api/orders", created, func(o Order) OrderDTO { return OrderDTO{Kode: o.Kode} })
+		}, http.StatusCreated, "/api/orders/3", `{"data":{"Kode":"ORD-03","Gudang":""}}`},
+		{"without primary key", func(w http.ResponseWriter) { WriteCreated(w, "/api/orders", &Order{}) }, http.StatusInternalServerError, "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tc.write(rec)
+			if rec.Code != tc.status || rec.Header().Get("Location") != tc.location {
+				t.Fatalf("status %d, Location %q", rec.Code, rec.Header().Get("Location"))
+			}
+			if tc.body != "" && rec.Body.String() != tc.body+"\n" {
+				t.Fatalf("body %s", rec.Body.String())
+			}
+		})
+	}
+}
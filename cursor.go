@@ -0,0 +1,33 @@
+package magicrest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor: cursor opaque (base64url JSON) dari posisi v, e.g. nilai kolom sort dan primary key row
+// terakhir, untuk keyset pagination di handler sendiri (?cursor=...). Cursor tidak ditandatangani:
+// jangan menaruh data yang tidak boleh dibaca atau diubah klien.
+func EncodeCursor(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor: kebalikan EncodeCursor ke v (pointer). Cursor rusak atau tidak cocok dengan v ->
+// *QueryError dengan ErrInvalidCursor (400 di StatusForError, kode "invalid_cursor").
+func DecodeCursor(cursor string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return newQueryError(ErrInvalidCursor, "cursor", cursor, "is not valid base64url")
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return newQueryError(ErrInvalidCursor, "cursor", cursor, "does not match this endpoint")
+	}
+	return nil
+}
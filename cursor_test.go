@@ -0,0 +1,61 @@
+package magicrest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type orderCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := orderCursor{CreatedAt: time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC), ID: 42}
+	cursor, err := EncodeCursor(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got orderCursor
+	if err := DecodeCursor(cursor, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	other, _ := EncodeCursor(map[string]string{"kode": "ORD-01"})
+	wrongType, _ := EncodeCursor(map[string]string{"i": "satu"})
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "%%%"},
+		{"not json", "bm90IGpzb24"},
+		{"other endpoint", other},
+		{"wrong type", wrongType},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var c orderCursor
+			err := DecodeCursor(tc.cursor, &c)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("err = %v, want ErrInvalidCursor", err)
+			}
+			var qe *QueryError
+			if !errors.As(err, &qe) || qe.Param != "cursor" || qe.Value != tc.cursor {
+				t.Fatalf("QueryError = %+v", qe)
+			}
+			if status := StatusForError(err); status != http.StatusBadRequest {
+				t.Fatalf("status %d, want 400", status)
+			}
+			if d := DetailsFromError(err); len(d) != 1 || d[0].Code != "invalid_cursor" {
+				t.Fatalf("details = %+v", d)
+			}
+		})
+	}
+}
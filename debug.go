@@ -0,0 +1,57 @@
+package magicrest
+
+import (
+	"net/url"
+
+	"gorm.io/gorm"
+)
+
+// SQLDebug: SQL query data dan count (placeholder ? beserta nilai bind-nya) untuk Meta["sql"]
+type SQLDebug struct {
+	Data      string        `json:"data"`
+	DataVars  []interface{} `json:"dataVars"`
+	Count     string        `json:"count"`
+	CountVars []interface{} `json:"countVars"`
+}
+
+// captureSQL menyusun ulang query count dan data di session DryRun sehingga tidak ada yang dieksekusi
+func captureSQL[T any](db *gorm.DB, page, pageSize int) SQLDebug {
+	var out SQLDebug
+	var total int64
+	if tx := countQuery(db).Session(&gorm.Session{DryRun: true}).Count(&total); tx.Error == nil {
+		out.Count, out.CountVars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}
+	if tx := pageQuery(db, page, pageSize).Session(&gorm.Session{DryRun: true}).Find(new([]T)); tx.Error == nil {
+		out.Data, out.DataVars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}
+	return out
+}
+
+// DryRunResult: rencana query ReadPaginated tanpa menyentuh database
+type DryRunResult struct {
+	DataSQL   string
+	CountSQL  string
+	Vars      []interface{} // nilai bind query data
+	CountVars []interface{} // nilai bind query count
+	Params    QueryParams
+	Info      QueryInfo
+}
+
+// ReadPaginatedDryRun membangun query persis seperti ReadPaginated (lewat BuildQuery yang sama)
+// lalu mengembalikan SQL data dan count tanpa eksekusi. Cocok untuk unit test, endpoint "explain"
+// admin, dan tooling review query.
+func ReadPaginatedDryRun[T any](query url.Values, db *gorm.DB, modelPtr *T, opts Options) (DryRunResult, error) {
+	db, info, err := BuildQuery[T](query, db.Session(&gorm.Session{DryRun: true}), modelPtr, opts)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	sql := captureSQL[T](db, info.Page, info.PageSize)
+	return DryRunResult{
+		DataSQL:   sql.Data,
+		CountSQL:  sql.Count,
+		Vars:      sql.DataVars,
+		CountVars: sql.CountVars,
+		Params:    info.Params,
+		Info:      info,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReadPaginatedDebugSQL(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		sql   bool
+	}{
+		{"Debug", url.Values{}, Options{Debug: true}, true},
+		{"debug=sql allowed", url.Values{"debug": {"sql"}}, Options{AllowDebugSQL: true}, true},
+		{"debug=sql without opt-in", url.Values{"debug": {"sql"}}, Options{}, false},
+		{"query echo only", url.Values{"debug": {"1"}}, Options{AllowDebugSQL: true}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.query.Set("filter[status]", "aktif")
+			tc.query.Set("page", "2")
+			tc.query.Set("pageSize", "2")
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Data) != 1 || res.Data[0].Kode != "ORD-05" {
+				t.Fatalf("data %v", res.Data)
+			}
+			got, ok := res.Meta["sql"].(SQLDebug)
+			if ok != tc.sql {
+				t.Fatalf("meta.sql present = %v, want %v", ok, tc.sql)
+			}
+			if !tc.sql {
+				return
+			}
+			if !strings.Contains(got.Data, "ORDER BY id LIMIT 2 OFFSET 2") || fmt.Sprint(got.DataVars) != "[aktif]" {
+				t.Fatalf("data SQL %q %v", got.Data, got.DataVars)
+			}
+			if !strings.Contains(got.Count, "count(*)") || strings.Contains(got.Count, "LIMIT") || fmt.Sprint(got.CountVars) != "[aktif]" {
+				t.Fatalf("count SQL %q %v", got.Count, got.CountVars)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedDryRun(t *testing.T) {
+	// koneksi sudah ditutup: query yang benar-benar dieksekusi akan gagal
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", 7) }
+	opts := Options{OrderBy: "id", Scopes: []Scope{tenant}, DefaultFieldTypes: map[string]string{"status": "string"}}
+	query := url.Values{"filter[status]": {"aktif,selesai"}, "page": {"3"}, "pageSize": {"4"}, "order": {"kode desc"}}
+	plan, err := ReadPaginatedDryRun(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan.DataSQL, "WHERE gudang_id = ? AND") || !strings.Contains(plan.DataSQL, "ORDER BY kode desc LIMIT 4 OFFSET 8") {
+		t.Fatalf("data SQL %q", plan.DataSQL)
+	}
+	if !strings.Contains(plan.CountSQL, "count(*)") || strings.Contains(plan.CountSQL, "LIMIT") {
+		t.Fatalf("count SQL %q", plan.CountSQL)
+	}
+	if fmt.Sprint(plan.Vars) != "[7 aktif selesai]" || fmt.Sprint(plan.CountVars) != "[7 aktif selesai]" {
+		t.Fatalf("vars %v, count vars %v", plan.Vars, plan.CountVars)
+	}
+	if plan.Params.Page != 3 || plan.Params.PageSize != 4 || len(plan.Params.Filters) != 1 || plan.Info.Order != "kode desc" {
+		t.Fatalf("params %+v, info order %q", plan.Params, plan.Info.Order)
+	}
+
+	// error validasi sama dengan ReadPaginated
+	if _, err := ReadPaginatedDryRun(url.Values{"preload": {"Itemz"}}, db.Model(&Order{}), &Order{}, Options{}); !errors.Is(err, ErrInvalidPreload) {
+		t.Fatalf("err = %v, want ErrInvalidPreload", err)
+	}
+}
@@ -0,0 +1,169 @@
+package magicrest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// filterOperators: operator filter[field] yang dipahami ParseQuery (nilai tunggal = eq, a,b = in)
+var filterOperators = []string{"eq", "in"}
+
+// FilterDescription: satu kolom yang boleh difilter beserta tipe parse nilainya
+type FilterDescription struct {
+	Field     string   `json:"field"`
+	Type      string   `json:"type"`
+	Operators []string `json:"operators"`
+}
+
+// ResourceDescription: kemampuan query sebuah resource, dihasilkan dari Options dan schema model saat runtime
+type ResourceDescription struct {
+	Filters         []FilterDescription `json:"filters"`
+	Sortable        []string            `json:"sortable"`
+	Searchable      []string            `json:"searchable"`
+	Preloads        []string            `json:"preloads"`
+	DefaultOrder    string              `json:"defaultOrder,omitempty"`
+	DefaultPageSize int                 `json:"defaultPageSize"`
+	MaxPageSize     int                 `json:"maxPageSize,omitempty"` // 0 = tanpa batas
+	Verbs           []Verb              `json:"verbs"`
+}
+
+// Describe menyusun ResourceDescription model T dengan aturan yang sama dengan BuildQuery: tipe filter
+// dari DefaultFieldTypes (dan schema bila AutoFieldTypes, selain itu "string"), kolom bertag bila
+// TagDrivenConfig, ComputedColumns, AllowedFilterFields / AllowedOrderFields, whitelist preload (nil = semua
+// relasi level pertama). verbs nil = AllVerbs.
+func Describe[T any](db *gorm.DB, opts Options, verbs []Verb) (ResourceDescription, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return ResourceDescription{}, err
+	}
+	types := fieldTypes(opts)
+	if opts.AutoFieldTypes {
+		types = FieldTypesFromModel[T]()
+		for k, v := range opts.DefaultFieldTypes {
+			types[k] = v
+		}
+	}
+
+	var filterable, sortable []string
+	if opts.TagDrivenConfig {
+		cfg := ConfigFromModel[T]()
+		filterable = append(filterable, cfg.FilterFields...)
+		sortable = append(sortable, cfg.SortFields...)
+		if len(opts.SearchFields) == 0 {
+			opts.SearchFields = cfg.SearchFields
+		}
+	} else {
+		for _, f := range sch.Fields {
+			if isColumnField(f) {
+				filterable = append(filterable, f.DBName)
+			}
+		}
+		sortable = append(sortable, filterable...)
+	}
+	computed := make([]string, 0, len(opts.ComputedColumns))
+	for alias := range opts.ComputedColumns {
+		computed = append(computed, alias)
+	}
+	sort.Strings(computed)
+	filterable = append(filterable, computed...)
+	if opts.AllowedFilterFields != nil {
+		allowed := make([]string, 0, len(filterable))
+		for _, field := range filterable {
+			if containsString(opts.AllowedFilterFields, field) {
+				allowed = append(allowed, field)
+			}
+		}
+		filterable = allowed
+	}
+	if opts.AllowedOrderFields != nil {
+		allowed := make([]string, 0, len(sortable))
+		for _, field := range sortable {
+			if containsString(opts.AllowedOrderFields, field) {
+				allowed = append(allowed, field)
+			}
+		}
+		sortable = allowed
+	}
+	sortable = append(sortable, computed...)
+
+	d := ResourceDescription{
+		Filters:         make([]FilterDescription, len(filterable)),
+		Sortable:        sortable,
+		Searchable:      searchFields(opts),
+		Preloads:        describePreloads[T](opts),
+		DefaultOrder:    strings.TrimSpace(opts.OrderBy),
+		DefaultPageSize: opts.DefaultPageSize,
+		MaxPageSize:     opts.MaxPageSize,
+		Verbs:           verbs,
+	}
+	for i, field := range filterable {
+		t := types[field]
+		if t == "" {
+			t = "string"
+		}
+		d.Filters[i] = FilterDescription{Field: field, Type: t, Operators: filterOperators}
+	}
+	if d.DefaultPageSize <= 0 {
+		d.DefaultPageSize = 10
+	}
+	if d.Verbs == nil {
+		d.Verbs = AllVerbs
+	}
+	if d.Sortable == nil {
+		d.Sortable = []string{}
+	}
+	if d.Searchable == nil {
+		d.Searchable = []string{}
+	}
+	return d, nil
+}
+
+// describePreloads: whitelist efektif ?preload= (AllowedPreloads + AutoAllowPreloads, nil = semua relasi),
+// kosong untuk PreloadServerOnly
+func describePreloads[T any](opts Options) []string {
+	if opts.PreloadMergeMode == PreloadServerOnly {
+		return []string{}
+	}
+	allowed := opts.AllowedPreloads
+	if opts.AutoAllowPreloads {
+		allowed = append(append([]string{}, allowed...), modelRelations[T]()...)
+	}
+	if allowed == nil {
+		allowed = DiscoverRelations[T]()
+	}
+	if allowed == nil {
+		return []string{}
+	}
+	return allowed
+}
+
+// AllowHeader: header Allow untuk path koleksi dari verbs, e.g. "GET, HEAD, POST, OPTIONS"
+func AllowHeader(verbs []Verb) string {
+	var methods []string
+	for _, v := range verbs {
+		switch v {
+		case VerbList:
+			methods = append(methods, http.MethodGet, http.MethodHead)
+		case VerbCreate:
+			methods = append(methods, http.MethodPost)
+		}
+	}
+	return strings.Join(append(methods, http.MethodOptions), ", ")
+}
+
+// DescribeHandlerHTTP: OPTIONS (atau GET /_meta) untuk resource T — {"data": ResourceDescription} lewat
+// Options.Envelope, header Allow dari verbs (nil = AllVerbs). Deskripsi dihitung per request dari opts.
+func DescribeHandlerHTTP[T any](db *gorm.DB, opts Options, verbs []Verb) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := Describe[T](db, opts, verbs)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Allow", AllowHeader(d.Verbs))
+		opts.Envelope.Write(w, http.StatusOK, d, nil)
+	}
+}
@@ -0,0 +1,98 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	db := newTestDB(t)
+	filters := func(d ResourceDescription) string {
+		var out []string
+		for _, f := range d.Filters {
+			out = append(out, f.Field+":"+f.Type)
+		}
+		return fmt.Sprint(out)
+	}
+	t.Run("schema columns", func(t *testing.T) {
+		d, err := Describe[Order](db, Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"gudang_id": "int"}, MaxPageSize: 50}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "[id:string kode:string status:string telepon:string gudang_id:int created_at:string updated_at:string deleted_at:string]"
+		if got := filters(d); got != want {
+			t.Fatalf("filters %s, want %s", got, want)
+		}
+		if fmt.Sprint(d.Sortable, d.Preloads, d.Verbs) != "[id kode status telepon gudang_id created_at updated_at deleted_at] [Gudang Items] [list get create update patch delete]" {
+			t.Fatalf("sortable %v, preloads %v, verbs %v", d.Sortable, d.Preloads, d.Verbs)
+		}
+		if d.DefaultOrder != "id" || d.DefaultPageSize != 10 || d.MaxPageSize != 50 || fmt.Sprint(d.Filters[0].Operators) != "[eq in]" {
+			t.Fatalf("%+v", d)
+		}
+	})
+	t.Run("schema types", func(t *testing.T) {
+		d, err := Describe[Jadwal](db, Options{AutoFieldTypes: true, DefaultFieldTypes: map[string]string{"kapasitas": "string"}, DefaultPageSize: 25}, []Verb{VerbList})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := filters(d); got != "[id:int aktif:bool kapasitas:string tanggal:date mulai:datetime catatan:string]" {
+			t.Fatalf("filters %s", got)
+		}
+		if d.DefaultPageSize != 25 || fmt.Sprint(d.Verbs) != "[list]" || d.Preloads == nil || len(d.Preloads) != 0 {
+			t.Fatalf("%+v", d)
+		}
+	})
+	t.Run("tag driven with computed column", func(t *testing.T) {
+		opts := Options{TagDrivenConfig: true, ComputedColumns: map[string]string{"inisial": "substr(nama, 1, 1)"}}
+		d, err := Describe[Kontak](db, opts, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(filters(d), d.Sortable, d.Searchable); got != "[nama:string kota:string inisial:string][id nama inisial] [nama email]" {
+			t.Fatalf("got %s", got)
+		}
+	})
+	t.Run("preload whitelist", func(t *testing.T) {
+		d, err := Describe[Order](db, Options{AllowedPreloads: []string{"Items.Produk"}, AutoAllowPreloads: true, SearchFields: []string{"kode"}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(d.Preloads, d.Searchable) != "[Items.Produk Gudang Items] [kode]" {
+			t.Fatalf("preloads %v, searchable %v", d.Preloads, d.Searchable)
+		}
+	})
+}
+
+func TestAllowHeader(t *testing.T) {
+	cases := []struct {
+		verbs []Verb
+		want  string
+	}{
+		{AllVerbs, "GET, HEAD, POST, OPTIONS"},
+		{[]Verb{VerbGet, VerbDelete}, "OPTIONS"},
+		{[]Verb{VerbCreate}, "POST, OPTIONS"},
+	}
+	for _, tc := range cases {
+		if got := AllowHeader(tc.verbs); got != tc.want {
+			t.Fatalf("AllowHeader(%v) = %q, want %q", tc.verbs, got, tc.want)
+		}
+	}
+}
+
+func TestDescribeHandlerHTTP(t *testing.T) {
+	db := newTestDB(t)
+	rec := httptest.NewRecorder()
+	DescribeHandlerHTTP[Order](db, Options{Envelope: EnvelopeConfig{DataKey: "resource"}}, []Verb{VerbList, VerbGet})(rec, httptest.NewRequest(http.MethodOptions, "/orders", nil))
+	var body struct {
+		Resource ResourceDescription `json:"resource"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Allow") != "GET, HEAD, OPTIONS" || fmt.Sprint(body.Resource.Verbs) != "[list get]" || len(body.Resource.Filters) == 0 {
+		t.Fatalf("Allow %q, body %s", rec.Header().Get("Allow"), rec.Body.String())
+	}
+}
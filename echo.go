@@ -0,0 +1,78 @@
+package magicrest
+
+// QueryEcho: isi Meta["query"], yaitu apa yang benar-benar dipahami dan diterapkan server.
+// Nama field JSON dijaga stabil karena ditampilkan frontend.
+type QueryEcho struct {
+	Page     int          `json:"page"`
+	PageSize int          `json:"pageSize"`
+	Sort     []SortEcho   `json:"sort"`
+	Filters  []FilterEcho `json:"filters"`
+	Search   *SearchEcho  `json:"search,omitempty"`
+	Preloads []string     `json:"preloads"`
+	Defaults []string     `json:"defaults"`
+}
+
+// SortEcho: satu item order efektif
+type SortEcho struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+// FilterEcho: satu filter yang diterapkan beserta nilai ter-parse
+type FilterEcho struct {
+	Field  string        `json:"field"`
+	Op     string        `json:"op"`
+	Type   string        `json:"type"`
+	Values []interface{} `json:"values"`
+}
+
+// SearchEcho: pencarian yang diterapkan
+type SearchEcho struct {
+	Term   string   `json:"term"`
+	Mode   string   `json:"mode"`
+	Fields []string `json:"fields"`
+}
+
+// searchFields: kolom efektif untuk ?search= (SearchField + SearchFields)
+func searchFields(opts Options) []string {
+	if len(opts.SearchFields) == 0 {
+		if opts.SearchField == "" {
+			return nil
+		}
+		return []string{opts.SearchField}
+	}
+	if opts.SearchField != "" {
+		return append([]string{opts.SearchField}, opts.SearchFields...)
+	}
+	return opts.SearchFields
+}
+
+// echoQuery menyusun QueryEcho dari QueryInfo hasil BuildQuery
+func echoQuery(info QueryInfo) QueryEcho {
+	e := QueryEcho{
+		Page:     info.Page,
+		PageSize: info.PageSize,
+		Sort:     []SortEcho{},
+		Filters:  []FilterEcho{},
+		Preloads: append([]string{}, info.Preloads...),
+		Defaults: append([]string{}, info.Params.Defaults...),
+	}
+	for _, s := range parseSort(info.Order) {
+		dir := "asc"
+		if s.Desc {
+			dir = "desc"
+		}
+		e.Sort = append(e.Sort, SortEcho{Field: s.Field, Direction: dir})
+	}
+	for _, f := range info.Params.Filters {
+		typ := f.Type
+		if typ == "" {
+			typ = "string"
+		}
+		e.Filters = append(e.Filters, FilterEcho{Field: f.Field, Op: f.Op, Type: typ, Values: f.Values})
+	}
+	if info.Search != "" && len(info.SearchFields) > 0 {
+		e.Search = &SearchEcho{Term: info.Search, Mode: "ilike", Fields: info.SearchFields}
+	}
+	return e
+}
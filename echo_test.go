@@ -0,0 +1,75 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestReadPaginatedEchoQuery(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+	opts := Options{OrderBy: "id desc", DefaultFieldTypes: map[string]string{"gudang_id": "int"}, EchoQuery: true}
+	query := url.Values{"filter[gudang_id]": {"1,2"}, "filter[status]": {"aktif"}, "preload": {"Items"}, "pageSize": {"2"}}
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(res.Meta["query"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nama field JSON stabil, dipakai frontend
+	want := `{"page":1,"pageSize":2,"sort":[{"field":"id","direction":"desc"}],` +
+		`"filters":[{"field":"gudang_id","op":"in","type":"int","values":[1,2]},{"field":"status","op":"eq","type":"string","values":["aktif"]}],` +
+		`"preloads":["Items"],"defaults":["page","order"]}`
+	if string(body) != want {
+		t.Fatalf("meta.query\n%s\nwant\n%s", body, want)
+	}
+}
+
+func TestReadPaginatedDebugQuery(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 1, 0)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		echo  bool
+	}{
+		{"off by default", url.Values{}, Options{}, false},
+		{"debug without opt-in", url.Values{"debug": {"1"}}, Options{}, false},
+		{"debug allowed", url.Values{"debug": {"1"}}, Options{AllowDebugQuery: true}, true},
+		{"allowed but not requested", url.Values{}, Options{AllowDebugQuery: true}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := res.Meta["query"]; ok != tc.echo {
+				t.Fatalf("meta.query present = %v, want %v", ok, tc.echo)
+			}
+		})
+	}
+}
+
+func TestEchoQuerySearch(t *testing.T) {
+	db := newTestDB(t)
+	_, info, err := BuildQuery(url.Values{"search": {"semen"}}, db.Model(&Order{}), &Order{}, Options{SearchField: "kode", SearchFields: []string{"telepon"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := echoQuery(info)
+	if e.Search == nil || e.Search.Term != "semen" || e.Search.Mode != "ilike" || len(e.Search.Fields) != 2 {
+		t.Fatalf("search %+v", e.Search)
+	}
+	if e.Sort[0] != (SortEcho{Field: "created_at", Direction: "desc"}) {
+		t.Fatalf("default sort %+v", e.Sort)
+	}
+	if _, info, _ = BuildQuery(url.Values{}, db.Model(&Order{}), &Order{}, Options{SearchField: "kode"}); echoQuery(info).Search != nil {
+		t.Fatal("search echoed without a term")
+	}
+}
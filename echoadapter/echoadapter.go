@@ -0,0 +1,67 @@
+// Package echoadapter memasang resource magicrest ke Echo v4. Modul terpisah (go.mod sendiri) agar Echo
+// tidak ikut ke dependency graph pemakai lain. Handler memanggil handler net/http magicrest
+// (ListHandlerHTTP, ...) sehingga perilakunya sama dengan ginrest.
+package echoadapter
+
+import (
+	"net/http"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Wrap memasang http.HandlerFunc magicrest sebagai echo.HandlerFunc; path param Echo (:id) diteruskan
+// sebagai r.PathValue.
+func Wrap(h http.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		for i, name := range c.ParamNames() {
+			r.SetPathValue(name, c.ParamValues()[i])
+		}
+		h(c.Response(), r)
+		return nil
+	}
+}
+
+// ListHandler: GET list untuk model T via magicrest.ListHandlerHTTP
+func ListHandler[T any](db *gorm.DB, opts magicrest.Options) echo.HandlerFunc {
+	return Wrap(magicrest.ListHandlerHTTP[T](db, opts))
+}
+
+// DescribeHandler: deskripsi kemampuan query resource T untuk OPTIONS / GET /_meta (magicrest.DescribeHandlerHTTP)
+func DescribeHandler[T any](db *gorm.DB, opts magicrest.Options, verbs []magicrest.Verb) echo.HandlerFunc {
+	return Wrap(magicrest.DescribeHandlerHTTP[T](db, opts, verbs))
+}
+
+// GetHandler: GET /:id via magicrest.ReadOne
+func GetHandler[T any](db *gorm.DB, opts magicrest.Options) echo.HandlerFunc {
+	return Wrap(magicrest.GetHandlerHTTP[T](db, opts))
+}
+
+// CreateHandler: POST via magicrest.CreateGeneric, response 201 dengan Location
+func CreateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) echo.HandlerFunc {
+	return Wrap(magicrest.CreateHandlerHTTP[T](db, opts))
+}
+
+// UpdateHandler: PUT /:id via magicrest.UpdateGeneric
+func UpdateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) echo.HandlerFunc {
+	return Wrap(magicrest.UpdateHandlerHTTP[T](db, opts))
+}
+
+// PatchHandler: PATCH /:id via magicrest.PatchGeneric
+func PatchHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) echo.HandlerFunc {
+	return Wrap(magicrest.PatchHandlerHTTP[T](db, opts))
+}
+
+// DeleteHandler: DELETE /:id via magicrest.DeleteGeneric, response 204
+func DeleteHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) echo.HandlerFunc {
+	return Wrap(magicrest.DeleteHandlerHTTP[T](db, opts))
+}
+
+// WriteError: varian Echo dari magicrest.WriteError — status dari magicrest.StatusForError dan
+// {"error": "...", "errors": [...]} dalam locale dari ?lang= / Accept-Language.
+func WriteError(c echo.Context, err error) error {
+	magicrest.WriteErrorLocale(c.Response(), err, magicrest.LocaleFromRequest(c.Request()))
+	return nil
+}
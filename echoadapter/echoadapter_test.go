@@ -0,0 +1,100 @@
+package echoadapter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/labstack/echo/v4"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Barang struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Harga int    `json:"harga"`
+}
+
+func newBarangDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Barang{{Nama: "Semen", Harga: 50}, {Nama: "Pasir", Harga: 20}})
+	return db
+}
+
+func TestHandlers(t *testing.T) {
+	db := newBarangDB(t)
+	e := echo.New()
+	opts := magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}}
+	g := e.Group("/api/barang")
+	g.GET("", ListHandler[Barang](db, opts))
+	g.GET("/:id", GetHandler[Barang](db, opts))
+	g.POST("", CreateHandler[Barang](db, magicrest.WriteOptions{}))
+	g.PUT("/:id", UpdateHandler[Barang](db, magicrest.WriteOptions{}))
+	g.PATCH("/:id", PatchHandler[Barang](db, magicrest.WriteOptions{}))
+	g.DELETE("/:id", DeleteHandler[Barang](db, magicrest.WriteOptions{}))
+
+	cases := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		status   int
+		want     string // potongan body
+		location string
+	}{
+		{"list", http.MethodGet, "/api/barang?filter[harga]=20", "", http.StatusOK, `"nama":"Pasir"`, ""},
+		{"list invalid filter", http.MethodGet, "/api/barang?filter[harga]=mahal", "", http.StatusBadRequest, `"code":"invalid_int"`, ""},
+		{"get", http.MethodGet, "/api/barang/1", "", http.StatusOK, `"nama":"Semen"`, ""},
+		{"get not found", http.MethodGet, "/api/barang/99", "", http.StatusNotFound, `"error"`, ""},
+		{"create", http.MethodPost, "/api/barang", `{"nama":"Bata","harga":3}`, http.StatusCreated, `"id":3`, "/api/barang/3"},
+		{"update", http.MethodPut, "/api/barang/3", `{"nama":"Bata Merah","harga":4}`, http.StatusOK, `"nama":"Bata Merah"`, ""},
+		{"patch", http.MethodPatch, "/api/barang/3", `{"harga":5}`, http.StatusOK, `"harga":5`, ""},
+		{"delete", http.MethodDelete, "/api/barang/3", "", http.StatusNoContent, "", ""},
+		{"get deleted", http.MethodGet, "/api/barang/3", "", http.StatusNotFound, `"error"`, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+			if rec.Code != tc.status || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, body %s; want %d with %s", rec.Code, rec.Body.String(), tc.status, tc.want)
+			}
+			if got := rec.Header().Get("Location"); got != tc.location {
+				t.Fatalf("Location %q, want %q", got, tc.location)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/barang?lang=id", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if err := WriteError(c, magicrest.ErrNotFound); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+	}
+	// error lain tetap lewat mapping status magicrest
+	rec = httptest.NewRecorder()
+	_ = WriteError(e.NewContext(req, rec), errors.New("db down"))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d for unknown error", rec.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package magicrest
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ErrorFormat: bentuk body error dari EnvelopeConfig
+type ErrorFormat int
+
+const (
+	// ErrorFormatDefault: {"error": "pesan", "errors": [ValidationDetail...]} (errors hanya bila ada detail)
+	ErrorFormatDefault ErrorFormat = iota
+	// ErrorFormatObject: {"error": {"code": "...", "message": "...", "details": [ValidationDetail...]}}
+	ErrorFormatObject
+	// ErrorFormatList: {"errors": [ValidationDetail...]}, minimal satu entri (code + message) walau tanpa detail
+	ErrorFormatList
+)
+
+// EnvelopeConfig: bentuk response handler bawaan (ListHandlerHTTP, ginrest, adapter lain) agar bisa
+// menyamai kontrak API yang sudah ada. Zero value = {"data": ..., "meta": ...} dan
+// {"error": "...", "errors": [...]}.
+type EnvelopeConfig struct {
+	DataKey              string      // default "data"; "-" = body hanya data (array datar, meta tidak dikirim)
+	MetaKey              string      // default "meta"; "-" = meta tidak dikirim
+	FlattenSingleMeta    bool        // meta dengan satu key ({"pagination": {...}}) ditulis langsung di MetaKey
+	NullDataAsEmptyArray bool        // data nil (slice nil) ditulis [] bukan null
+	ErrorFormat          ErrorFormat // bentuk body error
+	ErrorKey             string      // default "error"
+	ErrorsKey            string      // default "errors"
+}
+
+func keyOr(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
+
+// Wrap menyusun body response dari data dan meta (meta nil tidak ditulis)
+func (e EnvelopeConfig) Wrap(data interface{}, meta map[string]interface{}) interface{} {
+	if e.NullDataAsEmptyArray && isNilData(data) {
+		data = []interface{}{}
+	}
+	if e.DataKey == "-" {
+		return data
+	}
+	body := map[string]interface{}{keyOr(e.DataKey, "data"): data}
+	if meta != nil && e.MetaKey != "-" {
+		body[keyOr(e.MetaKey, "meta")] = e.metaValue(meta)
+	}
+	return body
+}
+
+// metaValue: meta yang ditulis di MetaKey (FlattenSingleMeta)
+func (e EnvelopeConfig) metaValue(meta map[string]interface{}) interface{} {
+	if e.FlattenSingleMeta && len(meta) == 1 {
+		for _, v := range meta {
+			return v
+		}
+	}
+	return meta
+}
+
+// isNilData: nil atau slice nil
+func isNilData(data interface{}) bool {
+	if data == nil {
+		return true
+	}
+	rv := reflect.ValueOf(data)
+	return rv.Kind() == reflect.Slice && rv.IsNil()
+}
+
+// Write menulis Wrap(data, meta) sebagai JSON dengan status
+func (e EnvelopeConfig) Write(w http.ResponseWriter, status int, data interface{}, meta map[string]interface{}) {
+	writeJSON(w, status, e.Wrap(data, meta))
+}
+
+// ErrorBody: status (StatusForError) dan body error sesuai ErrorFormat. Detail memakai ValidationDetail dalam
+// locale; pesan error 500 diganti teks status.
+func (e EnvelopeConfig) ErrorBody(err error, locale string) (int, map[string]interface{}) {
+	status := StatusForError(err)
+	message := err.Error()
+	var details []ValidationDetail
+	code := errorCode(err)
+	if status == http.StatusInternalServerError {
+		message, code = http.StatusText(status), "internal_error"
+	} else {
+		details = DetailsFromErrorLocale(err, locale)
+	}
+	errorKey, errorsKey := keyOr(e.ErrorKey, "error"), keyOr(e.ErrorsKey, "errors")
+
+	switch e.ErrorFormat {
+	case ErrorFormatObject:
+		obj := map[string]interface{}{"code": code, "message": message}
+		if len(details) > 0 {
+			obj["details"] = details
+		}
+		return status, map[string]interface{}{errorKey: obj}
+	case ErrorFormatList:
+		if len(details) == 0 {
+			details = []ValidationDetail{{Code: code, Message: message}}
+		}
+		return status, map[string]interface{}{errorsKey: details}
+	}
+	body := map[string]interface{}{errorKey: message}
+	if len(details) > 0 {
+		body[errorsKey] = details
+	}
+	return status, body
+}
+
+// WriteError menulis ErrorBody(err, LocaleFromRequest(r)) sebagai JSON
+func (e EnvelopeConfig) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := e.ErrorBody(err, LocaleFromRequest(r))
+	writeJSON(w, status, body)
+}
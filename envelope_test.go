@@ -0,0 +1,104 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEnvelopeConfigWrap(t *testing.T) {
+	meta := map[string]interface{}{"total": 2}
+	cases := []struct {
+		name string
+		env  EnvelopeConfig
+		meta map[string]interface{}
+		want string
+	}{
+		{"default", EnvelopeConfig{}, meta, `{"data":[1,2],"meta":{"total":2}}`},
+		{"nil meta", EnvelopeConfig{}, nil, `{"data":[1,2]}`},
+		{"custom keys", EnvelopeConfig{DataKey: "items", MetaKey: "info"}, meta, `{"info":{"total":2},"items":[1,2]}`},
+		{"meta disabled", EnvelopeConfig{MetaKey: "-"}, meta, `{"data":[1,2]}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.env.Wrap([]int{1, 2}, tc.meta))
+			if err != nil || string(b) != tc.want {
+				t.Fatalf("got %s, err %v; want %s", b, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnvelopeConfigShapes(t *testing.T) {
+	meta := map[string]interface{}{"pagination": map[string]interface{}{"total": 0}}
+	var empty []Order
+	cases := []struct {
+		name string
+		env  EnvelopeConfig
+		data interface{}
+		want string
+	}{
+		{"flat data", EnvelopeConfig{DataKey: "-"}, []int{1}, `[1]`},
+		{"flattened single meta", EnvelopeConfig{DataKey: "items", MetaKey: "pagination", FlattenSingleMeta: true}, []int{1}, `{"items":[1],"pagination":{"total":0}}`},
+		{"nil slice as null", EnvelopeConfig{}, empty, `{"data":null,"meta":{"pagination":{"total":0}}}`},
+		{"nil slice as empty array", EnvelopeConfig{NullDataAsEmptyArray: true}, empty, `{"data":[],"meta":{"pagination":{"total":0}}}`},
+		{"flat empty array", EnvelopeConfig{DataKey: "-", NullDataAsEmptyArray: true}, nil, `[]`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.env.Wrap(tc.data, meta))
+			if err != nil || string(b) != tc.want {
+				t.Fatalf("got %s, err %v; want %s", b, err, tc.want)
+			}
+		})
+	}
+	// FlattenSingleMeta hanya untuk meta dengan satu key
+	two := map[string]interface{}{"pagination": 1, "counts": 2}
+	if b, _ := json.Marshal(EnvelopeConfig{FlattenSingleMeta: true}.Wrap(nil, two)); string(b) != `{"data":null,"meta":{"counts":2,"pagination":1}}` {
+		t.Fatalf("two meta keys: %s", b)
+	}
+}
+
+func TestEnvelopeConfigErrorBody(t *testing.T) {
+	_, invalid := ParseQuery(url.Values{"filter[jumlah]": {"abc"}}, Options{DefaultFieldTypes: map[string]string{"jumlah": "int"}})
+	cases := []struct {
+		name   string
+		env    EnvelopeConfig
+		err    error
+		status int
+		want   string
+	}{
+		{"default with details", EnvelopeConfig{}, invalid, 400,
+			`{"error":"invalid filter value: filter[jumlah]=abc is not a valid int","errors":[{"field":"jumlah","code":"invalid_int","value":"abc","message":"jumlah must be an integer, got abc"}]}`},
+		{"default without details", EnvelopeConfig{}, errors.New("dial tcp: refused"), 500, `{"error":"Internal Server Error"}`},
+		{"custom keys", EnvelopeConfig{ErrorKey: "message", ErrorsKey: "fields"}, invalid, 400,
+			`{"fields":[{"field":"jumlah","code":"invalid_int","value":"abc","message":"jumlah must be an integer, got abc"}],"message":"invalid filter value: filter[jumlah]=abc is not a valid int"}`},
+		{"object", EnvelopeConfig{ErrorFormat: ErrorFormatObject}, errors.New("dial tcp: refused"), 500, `{"error":{"code":"internal_error","message":"Internal Server Error"}}`},
+		{"object with details", EnvelopeConfig{ErrorFormat: ErrorFormatObject}, invalid, 400,
+			`{"error":{"code":"invalid_filter","details":[{"field":"jumlah","code":"invalid_int","value":"abc","message":"jumlah must be an integer, got abc"}],"message":"invalid filter value: filter[jumlah]=abc is not a valid int"}}`},
+		{"list", EnvelopeConfig{ErrorFormat: ErrorFormatList}, invalid, 400,
+			`{"errors":[{"field":"jumlah","code":"invalid_int","value":"abc","message":"jumlah must be an integer, got abc"}]}`},
+		{"list without details", EnvelopeConfig{ErrorFormat: ErrorFormatList}, errors.New("dial tcp: refused"), 500,
+			`{"errors":[{"field":"","code":"internal_error","message":"Internal Server Error"}]}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := tc.env.ErrorBody(tc.err, "en")
+			b, _ := json.Marshal(body)
+			if status != tc.status || string(b) != tc.want {
+				t.Fatalf("status %d, body %s\nwant %d, %s", status, b, tc.status, tc.want)
+			}
+		})
+	}
+
+	// WriteError: locale dari request
+	rec := httptest.NewRecorder()
+	EnvelopeConfig{ErrorFormat: ErrorFormatList}.WriteError(rec, httptest.NewRequest(http.MethodGet, "/?lang=id", nil), invalid)
+	var got struct{ Errors []ValidationDetail }
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != 400 || len(got.Errors) != 1 || got.Errors[0].Message != "jumlah harus berupa bilangan bulat, bukan abc" {
+		t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
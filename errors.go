@@ -0,0 +1,206 @@
+package magicrest
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownFilterField digunakan bila filter[field] menyebut kolom yang tidak ada di model (Options.StrictQuery)
+	// atau tidak ada di Options.AllowedFilterFields
+	ErrUnknownFilterField = errors.New("unknown filter field")
+	// ErrPageOutOfRange digunakan bila ?page= / ?pageSize= tidak valid atau melewati halaman terakhir (Options.StrictQuery)
+	ErrPageOutOfRange = errors.New("page out of range")
+	// ErrPageSizeTooLarge digunakan bila ?pageSize= melebihi Options.MaxPageSize (Options.StrictQuery)
+	ErrPageSizeTooLarge = errors.New("page size too large")
+	// ErrUnsupportedDialect digunakan bila fitur butuh dukungan dialect (mis. limit preload per parent) yang tidak ada
+	ErrUnsupportedDialect = errors.New("unsupported dialect")
+	// ErrInvalidCursor digunakan DecodeCursor bila cursor dari klien rusak, diubah, atau bukan milik endpoint ini
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// QueryError: detail error parsing/validasi query. errors.Is(err, ErrInvalidFilter) tetap jalan
+// lewat Unwrap; errors.As(err, &qe) memberi akses ke parameter dan nilainya.
+type QueryError struct {
+	Kind   error  // sentinel, e.g. ErrInvalidFilter
+	Param  string // parameter query, e.g. "filter[jumlah]", "order", "page"
+	Value  string // nilai dari klien (bisa kosong)
+	Reason string // penjelasan tambahan (bisa kosong)
+	Code   string // kode stabil untuk klien, e.g. "invalid_int" (kosong = dari Kind, lihat errorCodes)
+}
+
+func (e *QueryError) Error() string {
+	msg := e.Kind.Error()
+	if e.Param != "" {
+		msg += ": " + e.Param
+		if e.Value != "" {
+			msg += "=" + e.Value
+		}
+	}
+	if e.Reason != "" {
+		msg += " " + e.Reason
+	}
+	return msg
+}
+
+func (e *QueryError) Unwrap() error { return e.Kind }
+
+// newQueryError membuat *QueryError
+func newQueryError(kind error, param, value, reason string) error {
+	return &QueryError{Kind: kind, Param: param, Value: value, Reason: reason}
+}
+
+// joinQueryErrors menggabungkan beberapa QueryError (urut berdasarkan Param) menjadi satu error
+func joinQueryErrors(errs []*QueryError) error {
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Param < errs[j].Param })
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	joined := make([]error, len(errs))
+	for i, e := range errs {
+		joined[i] = e
+	}
+	return errors.Join(joined...)
+}
+
+// ValidationDetail: satu entri {"field","code","value","message"} untuk response error API
+type ValidationDetail struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Value   string `json:"value,omitempty"`
+	Param   string `json:"param,omitempty"` // parameter aturan validasi, e.g. "100" untuk max=100
+	Message string `json:"message"`
+}
+
+// errorCodes: kode stabil per sentinel (jangan diubah, klien bergantung padanya)
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrInvalidFilter, "invalid_filter"},
+	{ErrUnknownFilterField, "unknown_filter_field"},
+	{ErrInvalidOrder, "invalid_order"},
+	{ErrInvalidField, "invalid_field"},
+	{ErrTooManyPreloads, "too_many_preloads"}, // sebelum ErrInvalidPreload yang juga di-wrap
+	{ErrPreloadTooDeep, "preload_too_deep"},
+	{ErrInvalidPreload, "invalid_preload"},
+	{ErrPreloadNotAllowed, "preload_not_allowed"},
+	{ErrInvalidWithCount, "invalid_with_count"},
+	{ErrPageOutOfRange, "page_out_of_range"},
+	{ErrPageSizeTooLarge, "page_size_too_large"},
+	{ErrUnsupportedDialect, "unsupported_dialect"},
+	{ErrInvalidCursor, "invalid_cursor"},
+	{ErrConflict, "conflict"},
+	{ErrNotFound, "not_found"},
+	{ErrInvalidPatch, "invalid_patch"},
+	{ErrValidation, "validation_failed"},
+	{ErrMissingConditions, "missing_conditions"},
+	{ErrTooManyAffected, "too_many_affected"},
+	{ErrNotDeleted, "not_deleted"},
+	{ErrInvalidID, "invalid_id"},
+	{ErrMultipleResults, "multiple_results"},
+	{ErrStaleRecord, "stale_record"},
+	{ErrMissingActor, "missing_actor"},
+	{ErrInvalidBody, "invalid_body"},
+	{ErrExportTooLarge, "export_too_large"},
+	{ErrTooManyRows, "too_many_rows"},
+	{ErrRangeNotSatisfiable, "invalid_range"},
+	{ErrQueryTimeout, "query_timeout"},
+}
+
+// errorCode mengembalikan kode stabil untuk err ("" bila bukan error query package ini)
+func errorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// DetailsFromError membongkar error query package ini (termasuk gabungan beberapa filter yang
+// tidak valid) menjadi daftar ValidationDetail dengan Message dalam locale default (SetLocale).
+// Field untuk filter[x] adalah "x"; untuk parameter lain nama parameternya (order, page, fields, ...).
+// nil bila err bukan error query.
+func DetailsFromError(err error) []ValidationDetail {
+	return DetailsFromErrorLocale(err, "")
+}
+
+// DetailsFromErrorLocale: DetailsFromError dengan Message dari katalog locale (e.g. "id", "en-US").
+// Locale kosong = locale default; locale tanpa katalog jatuh ke bahasa Inggris.
+func DetailsFromErrorLocale(err error, locale string) []ValidationDetail {
+	if err == nil {
+		return nil
+	}
+	if errs := joinedErrors(err); errs != nil {
+		var out []ValidationDetail
+		for _, e := range errs {
+			out = append(out, DetailsFromErrorLocale(e, locale)...)
+		}
+		return out
+	}
+	var be *BulkError
+	if errors.As(err, &be) {
+		return bulkDetails(be, locale)
+	}
+	var de DetailedError
+	if errors.As(err, &de) {
+		details := de.ValidationDetails()
+		out := make([]ValidationDetail, len(details))
+		for i, d := range details {
+			if d.Code == "" {
+				d.Code = "invalid"
+			}
+			if d.Message == "" {
+				generic := renderMessage(locale, ValidationDetail{Field: d.Field, Code: "invalid"}, d.Code)
+				d.Message = renderMessage(locale, d, generic)
+			}
+			out[i] = d
+		}
+		return out
+	}
+	var qe *QueryError
+	if errors.As(err, &qe) {
+		code := qe.Code
+		if code == "" {
+			code = errorCode(qe.Kind)
+		}
+		field := qe.Param
+		if strings.HasPrefix(field, "filter[") && strings.HasSuffix(field, "]") {
+			field = field[7 : len(field)-1]
+		}
+		d := ValidationDetail{Field: field, Code: code, Value: qe.Value}
+		d.Message = renderMessage(locale, d, qe.Error())
+		return []ValidationDetail{d}
+	}
+	if code := errorCode(err); code != "" {
+		// error preload / with_count: detailnya (bahasa Inggris) ada di Value
+		msg := err.Error()
+		value := msg
+		if i := strings.LastIndex(msg, ": "); i >= 0 {
+			value = msg[i+2:]
+		}
+		d := ValidationDetail{Code: code, Value: value}
+		d.Message = renderMessage(locale, d, msg)
+		return []ValidationDetail{d}
+	}
+	return nil
+}
+
+// joinedErrors: anggota error dari errors.Join (bukan fmt.Errorf dengan beberapa %w, yang tetap satu error)
+func joinedErrors(err error) []error {
+	multi, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+	errs := multi.Unwrap()
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	if strings.Join(msgs, "\n") != err.Error() {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package magicrest
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestDetailsFromErrorMultipleFilters(t *testing.T) {
+	opts := Options{DefaultFieldTypes: map[string]string{"jumlah": "int", "aktif": "bool", "tanggal": "date"}}
+	query := url.Values{
+		"filter[jumlah]":  {"abc"},
+		"filter[aktif]":   {"mungkin"},
+		"filter[tanggal]": {"31-12-2024"},
+		"filter[status]":  {"ok"}, // valid, tidak dilaporkan
+	}
+	_, err := ParseQuery(query, opts)
+	details := DetailsFromError(err)
+	want := []ValidationDetail{
+		{Field: "aktif", Code: "invalid_bool", Value: "mungkin"},
+		{Field: "jumlah", Code: "invalid_int", Value: "abc"},
+		{Field: "tanggal", Code: "invalid_date", Value: "31-12-2024"},
+	}
+	if len(details) != len(want) {
+		t.Fatalf("details = %+v, want %d", details, len(want))
+	}
+	for i, w := range want {
+		d := details[i]
+		if d.Field != w.Field || d.Code != w.Code || d.Value != w.Value || d.Message == "" {
+			t.Fatalf("detail %d = %+v, want %+v with a message", i, d, w)
+		}
+	}
+}
+
+func TestDetailsFromErrorKinds(t *testing.T) {
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		field string
+		code  string
+	}{
+		{"order", url.Values{"order": {"id; --"}}, Options{}, "order", "invalid_order"},
+		{"order not allowed", url.Values{"order": {"nama"}}, Options{AllowedOrderFields: []string{"id"}}, "order", "invalid_order"},
+		{"field name", url.Values{"filter[a b]": {"x"}}, Options{}, "a b", "invalid_field_name"},
+		{"unknown filter", url.Values{"filter[x]": {"1"}}, Options{AllowedFilterFields: []string{"status"}}, "x", "unknown_filter_field"},
+		{"page size", url.Values{"pageSize": {"500"}}, Options{MaxPageSize: 100, StrictQuery: true}, "pageSize", "page_size_too_large"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseQuery(tc.query, tc.opts)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			details := DetailsFromError(fmt.Errorf("handler: %w", err)) // tetap dikenali saat dibungkus
+			if len(details) != 1 || details[0].Field != tc.field || details[0].Code != tc.code {
+				t.Fatalf("details = %+v, want field %q code %q", details, tc.field, tc.code)
+			}
+		})
+	}
+
+	// error preload muncul saat query dibangun (schema), bukan saat ParseQuery
+	db := newTestDB(t)
+	_, err := ReadPaginated(url.Values{"preload": {"Itemz"}}, db.Model(&Order{}), &Order{}, Options{})
+	if d := DetailsFromError(err); len(d) != 1 || d[0].Code != "invalid_preload" || d[0].Value != `unknown relation "Itemz" in Itemz` {
+		t.Fatalf("preload details = %+v", d)
+	}
+	if DetailsFromError(nil) != nil || DetailsFromError(fmt.Errorf("db down")) != nil {
+		t.Fatal("non-query errors must have no details")
+	}
+}
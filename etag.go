@@ -0,0 +1,106 @@
+package magicrest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ListETag menghitung ETag kuat untuk satu halaman list: hash dari query kanonik (key terurut) dan QueryParams
+// hasil parse, bagian Options yang mengubah isi response (etagConfig — perubahan konfigurasi ikut mengganti ETag),
+// total dari Meta, serta primary key dan kolom updated_at setiap baris di res (data yang sudah diambil, tanpa query
+// tambahan). Hasilnya sama di setiap proses / replica untuk data dan konfigurasi yang sama.
+func ListETag[T any](db *gorm.DB, query url.Values, opts Options, res Result[T]) (string, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	var modelTypes map[string]string
+	if opts.AutoFieldTypes {
+		modelTypes = modelFieldTypes[T]()
+	}
+	// query kanonik tetap ikut untuk parameter di luar QueryParams (preload[Rel][...], debug, ...)
+	fmt.Fprintf(h, "%s\n", query.Encode())
+	if params, err := parseQuerySource(FromURLValues(query), opts, modelTypes); err == nil {
+		fmt.Fprintf(h, "%+v\n", params)
+	}
+	etagConfig(h, opts)
+	if p, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+		fmt.Fprintf(h, "total=%v\n", p["total"])
+	}
+	fields := append([]*schema.Field{}, sch.PrimaryFields...)
+	if f := modifiedField(sch, opts); f != nil {
+		fields = append(fields, f)
+	}
+	for i := range res.Data {
+		rv := reflect.ValueOf(&res.Data[i]).Elem()
+		for _, f := range fields {
+			v, _ := f.ValueOf(context.Background(), rv)
+			fmt.Fprintf(h, "%v|", v)
+		}
+		h.Write([]byte{'\n'})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, nil
+}
+
+// etagConfig menulis Options yang menentukan isi response list ke w. Pointer, func dan interface (ReadDB, Logger,
+// hooks, cache) tidak ikut karena fmt mencetaknya sebagai alamat yang berbeda per proses; map dicetak fmt dengan
+// key terurut.
+func etagConfig(w io.Writer, opts Options) {
+	for _, v := range []interface{}{
+		opts.SearchField, opts.SearchFields, opts.OrderBy, opts.PreloadFields, opts.DefaultFieldTypes,
+		opts.AutoFieldTypes, opts.LegacyFieldTypes, opts.TagDrivenConfig, opts.EchoQuery, opts.Debug,
+		opts.DefaultPage, opts.DefaultPageSize, opts.MaxPageSize, opts.AllowGroupBy, opts.AllowedPreloads,
+		opts.PreloadPolicy, opts.PreloadSelects, opts.PreloadLimits, opts.PreloadMergeMode, opts.PreloadStrategy,
+		opts.WithCounts, opts.AutoAllowPreloads, opts.AllowDistinct, opts.DistinctFields, opts.StrictFields,
+		opts.StrictQuery, opts.ComputedColumns, opts.SelectableColumns, opts.MaskedColumns, opts.Envelope,
+		opts.ModifiedColumn, opts.ResourceType, opts.AllowedFilterFields, opts.AllowedOrderFields,
+	} {
+		fmt.Fprintf(w, "%#v\n", v)
+	}
+}
+
+// modifiedField: kolom waktu perubahan terakhir — Options.ModifiedColumn, field autoUpdateTime atau
+// "updated_at" (nil bila model tidak punya)
+func modifiedField(sch *schema.Schema, opts Options) *schema.Field {
+	if opts.ModifiedColumn != "" {
+		if f := sch.LookUpField(opts.ModifiedColumn); isColumnField(f) {
+			return f
+		}
+		return nil
+	}
+	for _, f := range sch.Fields {
+		if f.AutoUpdateTime > 0 && isColumnField(f) {
+			return f
+		}
+	}
+	if f := sch.LookUpField("updated_at"); isColumnField(f) {
+		return f
+	}
+	return nil
+}
+
+// ETagMatches: true bila header If-None-Match memuat tag (atau "*"). Perbandingan lemah sesuai RFC 9110,
+// jadi W/"x" cocok dengan "x".
+func ETagMatches(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
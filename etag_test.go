@@ -0,0 +1,122 @@
+package magicrest
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestListHandlerETag(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	handler := ListHandlerHTTP[Order](db, Options{OrderBy: "id", ETag: true})
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := get("/orders?page=1", "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || tag == "" {
+		t.Fatalf("status %d, ETag %q", first.Code, tag)
+	}
+
+	t.Run("match", func(t *testing.T) {
+		rec := get("/orders?page=1", tag)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Fatalf("status %d, body %q, want 304 without body", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("ETag") != tag {
+			t.Fatalf("ETag %q, want %q", rec.Header().Get("ETag"), tag)
+		}
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		rec := get("/orders?page=1", `"stale"`)
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Fatalf("status %d, want 200 with body", rec.Code)
+		}
+	})
+	t.Run("different query", func(t *testing.T) {
+		rec := get("/orders?page=1&filter[status]=aktif", tag)
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+			t.Fatalf("status %d, ETag %q: filtered page must not match", rec.Code, rec.Header().Get("ETag"))
+		}
+	})
+	t.Run("row updated", func(t *testing.T) {
+		db.Model(&Order{}).Where("id = ?", 1).Update("updated_at", time.Now().Add(time.Hour))
+		rec := get("/orders?page=1", tag)
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+			t.Fatalf("status %d, ETag %q: updated row must change the tag", rec.Code, rec.Header().Get("ETag"))
+		}
+	})
+}
+
+func TestListETagIgnoresPointerOptions(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	query := url.Values{"page": {"1"}}
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// dua salinan Options yang hanya berbeda pointer / func (seperti di dua instance) menghasilkan tag yang sama
+	a := Options{OrderBy: "id", ReadDB: db, Logger: slog.Default()}
+	b := Options{OrderBy: "id", ReadDB: db.Session(&gorm.Session{}), Logger: slog.New(slog.DiscardHandler),
+		BeforeQuery: func(_ context.Context, db *gorm.DB, _ QueryParams) (*gorm.DB, error) { return db, nil }}
+	compiled, err := CompileOptions[Order](a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = compiled.Options()
+	tagA, err := ListETag(db, query, a, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagB, err := ListETag(db, query, b, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tagA != tagB {
+		t.Fatalf("tags differ: %s vs %s", tagA, tagB)
+	}
+
+	// konfigurasi yang mengubah response tetap mengganti tag
+	tagC, _ := ListETag(db, query, Options{OrderBy: "id", MaxPageSize: 5}, res)
+	if tagC == tagA {
+		t.Fatal("MaxPageSize change must change the tag")
+	}
+	tagD, _ := ListETag(db, url.Values{"page": {"1"}, "preload[Items][jumlah]": {"1"}}, Options{OrderBy: "id"}, res)
+	if tagD == tagA {
+		t.Fatal("conditional preload must change the tag")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{"*", true},
+		{`"abcd"`, false},
+	}
+	for _, tc := range cases {
+		if got := ETagMatches(tc.header, `"abc"`); got != tc.want {
+			t.Errorf("ETagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
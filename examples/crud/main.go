@@ -0,0 +1,61 @@
+// Contoh aplikasi CRUD lengkap dengan ginrest.RegisterCRUD dan SQLite in-memory.
+//
+//	go run .
+//	curl -i -X POST localhost:8080/api/barang -d '{"kode":"BRS","nama":"Beras","jumlah":10}'
+//	curl 'localhost:8080/api/barang?filter[kode]=BRS&order=nama'
+//	curl -X PATCH localhost:8080/api/barang/1 -d '{"jumlah":"abc"}'   # 400 invalid_int
+//	curl -X PATCH localhost:8080/api/barang/1 -d '{"jumlah":7}'
+//	curl -X PUT localhost:8080/api/barang/1 -d '{"kode":"BRS","nama":"Beras Premium","jumlah":5}'
+//	curl -i -X DELETE localhost:8080/api/barang/1 -H 'X-Role: admin'
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/Jupriadi/magic-rest/ginrest"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type Barang struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Kode      string         `gorm:"uniqueIndex;not null" json:"kode"`
+	Nama      string         `gorm:"not null" json:"nama"`
+	Jumlah    int            `json:"jumlah"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// adminOnly: contoh middleware per verb
+func adminOnly(c *gin.Context) {
+	if c.GetHeader("X-Role") != "admin" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin only"})
+	}
+}
+
+func main() {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		log.Fatal(err)
+	}
+
+	r := gin.Default()
+	ginrest.RegisterCRUD[Barang](r.Group("/api/barang"), db, ginrest.ResourceConfig{
+		Middleware: map[ginrest.Verb][]gin.HandlerFunc{ginrest.VerbDelete: {adminOnly}},
+		Options: magicrest.Options{
+			AutoFieldTypes: true,
+			OrderBy:        "created_at desc",
+			MaxPageSize:    100,
+		},
+		WriteOptions: magicrest.WriteOptions{ProtectedColumns: []string{"kode"}},
+	})
+	log.Fatal(r.Run(":8080"))
+}
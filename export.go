@@ -0,0 +1,553 @@
+package magicrest
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrExportTooLarge: jumlah row yang cocok melebihi batas export (Options.ExportMaxRows)
+var ErrExportTooLarge = errors.New("export too large")
+
+// Format: representasi response list
+type Format string
+
+const (
+	FormatJSON    Format = "json"    // application/json (default, dengan envelope dan pagination)
+	FormatCSV     Format = "csv"     // text/csv, semua row hasil filter/order
+	FormatNDJSON  Format = "ndjson"  // application/x-ndjson, satu objek JSON per baris
+	FormatHAL     Format = "hal"     // application/hal+json, halaman list sebagai dokumen HAL (HALList)
+	FormatJSONAPI Format = "jsonapi" // application/vnd.api+json, halaman list sebagai dokumen JSON:API (JSONAPIList)
+)
+
+// formatTypes: media type Accept -> Format (application/json ikut agar bisa menang berdasarkan q)
+var formatTypes = map[string]Format{
+	"application/json":     FormatJSON,
+	"text/csv":             FormatCSV,
+	"application/x-ndjson": FormatNDJSON,
+	"application/ndjson":   FormatNDJSON,
+	HALContentType:         FormatHAL,
+	JSONAPIContentType:     FormatJSONAPI,
+}
+
+// ContentType: header Content-Type untuk format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatHAL:
+		return HALContentType
+	case FormatJSONAPI:
+		return JSONAPIContentType
+	}
+	return "application/json"
+}
+
+// IsExport: format export tanpa pagination (CSV, NDJSON) — format lain menulis satu halaman list
+func (f Format) IsExport() bool {
+	return f == FormatCSV || f == FormatNDJSON
+}
+
+// NegotiateFormat memilih Format dari header Accept berdasarkan q. Kosong, */* atau media type yang
+// tidak dikenal = FormatJSON.
+func NegotiateFormat(accept string) Format {
+	type choice struct {
+		format Format
+		q      float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		f, ok := formatTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			choices = append(choices, choice{format: f, q: q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	if len(choices) == 0 {
+		return FormatJSON
+	}
+	return choices[0].format
+}
+
+// ExportConfig: pengaturan export (ExportCSV, ExportCSVHandler, PrepareExport untuk exporter lain). Default
+// kolom diambil dari tag `export:"header=Nama Gudang,order=2,format=date:02-01-2006"` model (export:"-" =
+// tidak ikut); field ExportConfig yang di-set menimpa tag.
+type ExportConfig struct {
+	Columns   []string          // kolom dan urutannya (nama json atau kolom DB); nil = semua kolom model
+	BatchSize int               // row per query (0 = Options.StreamBatchSize, default 500)
+	MaxRows   int               // batas row (0 = Options.ExportMaxRows), lebih -> ErrExportTooLarge
+	Filename  string            // nama file Content-Disposition handler export (default "export.csv" / ".xlsx")
+	Headers   map[string]string // label header per kolom (nama json atau kolom DB), default tag export / nama json
+	Formats   map[string]string // format per kolom, e.g. "tanggal": "date:02-01-2006" (menimpa format= tag export)
+	SheetName string            // nama sheet export XLSX (default "Sheet1")
+}
+
+// Export: semua row hasil filter/search/order (tanpa pagination) yang sudah divalidasi dan dihitung
+// oleh PrepareExport; row baru di-query per batch saat Write.
+type Export[T any] struct {
+	Total int64 // jumlah row yang akan ditulis
+
+	s    *listStream[T]
+	cols []exportColumn
+}
+
+// PrepareExport menyusun query dari pipeline BuildQuery (Scopes, filter, search, order, fields), me-resolve
+// kolom cfg dan menghitung row-nya. Lebih dari cfg.MaxRows / Options.ExportMaxRows -> ErrExportTooLarge,
+// sebelum ada yang ditulis. Dipakai handler list (negosiasi Accept), ExportCSV dan exporter di modul lain.
+func PrepareExport[T any](ctx context.Context, query QuerySource, db *gorm.DB, opts Options, cfg ExportConfig) (exp *Export[T], err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpExport)
+	defer func() {
+		if err != nil {
+			op.end(-1, err)
+		}
+	}()
+	if cfg.MaxRows > 0 {
+		opts.ExportMaxRows = cfg.MaxRows
+	}
+	if cfg.BatchSize > 0 {
+		opts.StreamBatchSize = cfg.BatchSize
+	}
+	db = readDB(ctx, db, query, opts)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	q, info, err := BuildQuerySource[T](query, db.Model(new(T)), new(T), opts)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := exportColumns(sch, info.Fields, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	slow := newSlowQuery(opts, info.Params, modelName[T]())
+	if err := slow.run(QueryLabelCount, countQuery(q), func(tx *gorm.DB) *gorm.DB { return tx.Count(&total) }); err != nil {
+		return nil, err
+	}
+	if opts.ExportMaxRows > 0 && total > int64(opts.ExportMaxRows) {
+		return nil, fmt.Errorf("%w: %d rows match, max %d", ErrExportTooLarge, total, opts.ExportMaxRows)
+	}
+	s := &listStream[T]{ctx: ctx, db: q, opts: opts, limit: int(total), order: newStreamOrder(sch, info), slow: slow,
+		op: op, audit: newReadAudit[T](opts, OpExport, start, info, "")}
+	return &Export[T]{Total: total, s: s, cols: cols}, nil
+}
+
+// ExportCSV menulis semua row yang cocok dengan query (filter, search, order, fields= / omit=) sebagai CSV
+// ke w. Row di-query per cfg.BatchSize dengan keyset paging pada ORDER BY query + primary key (seperti
+// FindInBatches, tetapi urutan query tetap berlaku; lihat streamOrder) sehingga hasil tidak pernah dimuat
+// sekaligus ke memori. Lebih dari cfg.MaxRows -> ErrExportTooLarge sebelum ada yang ditulis.
+func ExportCSV[T any](ctx context.Context, w io.Writer, query url.Values, db *gorm.DB, modelPtr *T, opts Options, cfg ExportConfig) error {
+	exp, err := PrepareExport[T](ctx, FromURLValues(query), db, opts, cfg)
+	if err != nil {
+		return err
+	}
+	return exp.WriteCSV(w)
+}
+
+// ExportCSVHandler: GET export CSV untuk model T (ExportCSV) dengan Content-Disposition attachment;
+// error sebelum streaming dijawab seperti ListHandlerHTTP.
+func ExportCSVHandler[T any](db *gorm.DB, opts Options, cfg ExportConfig) http.HandlerFunc {
+	filename := cfg.Filename
+	if filename == "" {
+		filename = "export.csv"
+	}
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	return func(w http.ResponseWriter, r *http.Request) {
+		exp, err := PrepareExport[T](r.Context(), FromURLValues(r.URL.Query()), db, opts, cfg)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", FormatCSV.ContentType())
+		w.Header().Set("Content-Disposition", disposition)
+		w.WriteHeader(http.StatusOK)
+		_ = exp.WriteCSV(w)
+	}
+}
+
+// ExportNDJSON menulis semua row hasil filter/search/order sebagai NDJSON — satu objek JSON per baris,
+// tanpa envelope dan meta pagination — sampai habis atau cfg.MaxRows (lebih -> ErrExportTooLarge sebelum
+// ada yang ditulis). Row di-query per batch seperti ExportCSV dan w di-Flush tiap batch (http.Flusher atau
+// Flush() error, e.g. *gzip.Writer) agar consumer menerima data selagi export berjalan.
+func ExportNDJSON[T any](ctx context.Context, w io.Writer, query url.Values, db *gorm.DB, modelPtr *T, opts Options, cfg ExportConfig) error {
+	exp, err := PrepareExport[T](ctx, FromURLValues(query), db, opts, cfg)
+	if err != nil {
+		return err
+	}
+	return exp.WriteNDJSON(w)
+}
+
+// ExportNDJSONHandler: GET export NDJSON untuk model T (ExportNDJSON). Dikompres gzip bila klien mengirim
+// Accept-Encoding: gzip; tiap batch di-flush sampai ke koneksi.
+func ExportNDJSONHandler[T any](db *gorm.DB, opts Options, cfg ExportConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exp, err := PrepareExport[T](r.Context(), FromURLValues(r.URL.Query()), db, opts, cfg)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", FormatNDJSON.ContentType())
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			w.WriteHeader(http.StatusOK)
+			_ = exp.WriteNDJSON(w)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		gz := gzipFlushWriter{Writer: gzip.NewWriter(w), w: w}
+		_ = exp.WriteNDJSON(gz)
+		_ = gz.Close()
+	}
+}
+
+// acceptsGzip: header Accept-Encoding mengizinkan gzip (q > 0)
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			return err == nil && v > 0
+		}
+		return true
+	}
+	return false
+}
+
+// gzipFlushWriter: Flush mengosongkan buffer gzip lalu mem-flush http.ResponseWriter di bawahnya
+type gzipFlushWriter struct {
+	*gzip.Writer
+	w io.Writer
+}
+
+func (g gzipFlushWriter) Flush() error {
+	if err := g.Writer.Flush(); err != nil {
+		return err
+	}
+	flush(g.w)
+	return nil
+}
+
+// Write menulis export dalam format f (FormatJSON = array JSON tanpa envelope)
+func (e *Export[T]) Write(w io.Writer, f Format) error {
+	switch f {
+	case FormatCSV:
+		return e.WriteCSV(w)
+	case FormatNDJSON:
+		return e.WriteNDJSON(w)
+	}
+	s := *e.s
+	s.opts.Envelope = EnvelopeConfig{DataKey: "-"}
+	return s.writeTo(w)
+}
+
+// Columns: kolom export (urutan, header dan tipe) yang dipakai WriteCSV / EachRecord
+func (e *Export[T]) Columns() []ExportColumn {
+	out := make([]ExportColumn, len(e.cols))
+	for i, c := range e.cols {
+		out[i] = c.ExportColumn
+	}
+	return out
+}
+
+// EachRecord memanggil fn untuk tiap row dengan nilai per kolom Columns() (nil = NULL, waktu dan teks
+// tanggal kolom date/datetime time.Time, uuid / Valuer lain sudah dikonversi). record dipakai ulang antar panggilan. Untuk exporter format lain.
+func (e *Export[T]) EachRecord(fn func(record []interface{}) error) error {
+	record := make([]interface{}, len(e.cols))
+	return e.s.each(func(rows []T) error {
+		for i := range rows {
+			rv := reflect.ValueOf(&rows[i]).Elem()
+			for j, c := range e.cols {
+				v, _ := c.field.ValueOf(e.s.ctx, rv)
+				record[j] = c.value(v)
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteNDJSON menulis satu objek JSON per baris (bentuk sama dengan item response JSON)
+func (e *Export[T]) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	return e.s.each(func(rows []T) error {
+		for i := range rows {
+			if err := enc.Encode(rows[i]); err != nil {
+				return err
+			}
+		}
+		flush(w)
+		return nil
+	})
+}
+
+// WriteCSV menulis header (Columns) lalu satu baris per row. Kolom mengikuti ExportConfig / tag export atau
+// urutan schema, dibatasi ?fields= / ?omit=; relasi dan field json:"-" dilewati. Waktu ditulis RFC3339 atau
+// dengan layout Format kolom.
+func (e *Export[T]) WriteCSV(w io.Writer) error {
+	cols := e.cols
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(cols))
+	return e.s.each(func(rows []T) error {
+		for i := range rows {
+			rv := reflect.ValueOf(&rows[i]).Elem()
+			for j, c := range cols {
+				v, _ := c.field.ValueOf(e.s.ctx, rv)
+				record[j] = c.text(v)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		flush(w)
+		return nil
+	})
+}
+
+// ExportColumn: kolom export yang sudah di-resolve dari schema dan ExportConfig
+type ExportColumn struct {
+	Name   string // kolom DB
+	Header string // label header (ExportConfig.Headers, default nama json)
+	Type   string // "int", "float", "bool", "datetime", "date", "uuid" atau "string" (schemaFieldType)
+	Format string // layout Go untuk kolom date/datetime dari format= (tag export / ExportConfig.Formats), "" = default
+}
+
+// exportColumn: ExportColumn beserta field schema untuk membaca nilainya
+type exportColumn struct {
+	ExportColumn
+	field *schema.Field
+	json  string // nama json (untuk mencari kolom dari ExportConfig)
+	order int    // order= tag export (0 = tanpa)
+	skip  bool   // export:"-"
+}
+
+// value: nilai sel (exportValue); teks tanggal di kolom date/datetime menjadi time.Time
+func (c exportColumn) value(v interface{}) interface{} {
+	v = exportValue(v)
+	if s, ok := v.(string); ok && (c.Type == "date" || c.Type == "datetime") {
+		if t, ok := asTime(s); ok {
+			return t
+		}
+	}
+	return v
+}
+
+// text: nilai sel CSV — waktu dengan layout Format bila di-set, selain itu csvValue
+func (c exportColumn) text(v interface{}) string {
+	if c.Format != "" {
+		if t, ok := asTime(exportValue(v)); ok {
+			return t.Format(c.Format)
+		}
+	}
+	return csvValue(v)
+}
+
+// exportColumns: kolom database model dengan nama json-nya. Urutan: cfg.Columns (nama json atau kolom DB),
+// atau kolom bertag order= lalu sisanya dalam urutan schema; label cfg.Headers > tag header= > nama json;
+// format cfg.Formats > tag format=. fields (nil = semua) membuang kolom yang tidak di-select lewat ?fields= /
+// ?omit=. Nama yang tidak dikenal -> ErrInvalidField, tag export yang salah -> ErrInvalidExportTag.
+func exportColumns(sch *schema.Schema, fields []string, cfg ExportConfig) ([]exportColumn, error) {
+	var all []exportColumn
+	for _, f := range sch.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.StructField.Tag.Lookup("json"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		c := exportColumn{ExportColumn: ExportColumn{Name: f.DBName, Header: name, Type: exportType(f)}, field: f, json: name}
+		tag, err := parseExportTag(f)
+		if err == nil && tag.kind != "" {
+			err = c.applyFormat(tag.kind, tag.layout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s.%s: %v", ErrInvalidExportTag, sch.Name, f.Name, err)
+		}
+		if tag.header != "" {
+			c.Header = tag.header
+		}
+		c.order, c.skip = tag.order, tag.skip
+		all = append(all, c)
+	}
+	find := func(name string) (int, error) {
+		i := slices.IndexFunc(all, func(c exportColumn) bool { return c.json == name || c.Name == name })
+		if i < 0 {
+			return 0, fmt.Errorf("%w: export column %s", ErrInvalidField, name)
+		}
+		return i, nil
+	}
+	for name, label := range cfg.Headers {
+		i, err := find(name)
+		if err != nil {
+			return nil, err
+		}
+		all[i].Header = label
+	}
+	for name, format := range cfg.Formats {
+		i, err := find(name)
+		if err != nil {
+			return nil, err
+		}
+		kind, layout, err := parseExportFormat(format)
+		if err == nil {
+			err = all[i].applyFormat(kind, layout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: export column %s: %v", ErrInvalidField, name, err)
+		}
+	}
+	var cols []exportColumn
+	if cfg.Columns != nil {
+		cols = make([]exportColumn, 0, len(cfg.Columns))
+		for _, name := range cfg.Columns {
+			i, err := find(name)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, all[i])
+		}
+	} else {
+		for _, c := range all {
+			if !c.skip {
+				cols = append(cols, c)
+			}
+		}
+		sort.SliceStable(cols, func(i, j int) bool {
+			a, b := cols[i].order, cols[j].order
+			return a > 0 && (b == 0 || a < b)
+		})
+	}
+	out := make([]exportColumn, 0, len(cols))
+	for _, c := range cols {
+		if fields != nil && !containsString(fields, c.Name) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// exportType: tipe kolom export dari schema (schemaFieldType, ditambah "float")
+func exportType(f *schema.Field) string {
+	t := schemaFieldType(f)
+	if t != "string" {
+		return t
+	}
+	ft := f.FieldType
+	for ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	if ft.Kind() == reflect.Float32 || ft.Kind() == reflect.Float64 {
+		return "float"
+	}
+	return t
+}
+
+// exportValue: nilai sel export — pointer nil / NULL = nil, time.Time apa adanya, driver.Valuer (uuid,
+// DeletedAt, Null*) lewat Value(), []byte dan fmt.Stringer sebagai string
+func exportValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	v = rv.Interface()
+	if _, ok := v.(time.Time); ok {
+		return v
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil || dv == nil {
+			return nil
+		}
+		if _, same := dv.(driver.Valuer); !same {
+			return exportValue(dv)
+		}
+	}
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+// csvValue: exportValue sebagai teks CSV (NULL kosong, waktu RFC3339)
+func csvValue(v interface{}) string {
+	switch x := exportValue(v).(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case string:
+		return x
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,152 @@
+package magicrest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// flushCounter: writer dengan Flush() error untuk menghitung flush per batch
+type flushCounter struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushCounter) Flush() error {
+	f.flushes++
+	return nil
+}
+
+// newGudangDB: 5 gudang GD-01..GD-05, nama "Utara" untuk id ganjil dan "Selatan" untuk id genap
+func newGudangDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	for i := 1; i <= 5; i++ {
+		nama := "Utara"
+		if i%2 == 0 {
+			nama = "Selatan"
+		}
+		db.Create(&Gudang{Kode: fmt.Sprintf("GD-%02d", i), Nama: nama})
+	}
+	return db
+}
+
+func gudangLines(ids ...int) string {
+	var b strings.Builder
+	for _, id := range ids {
+		nama := "Utara"
+		if id%2 == 0 {
+			nama = "Selatan"
+		}
+		fmt.Fprintf(&b, `{"id":%d,"kode":"GD-%02d","nama":"%s"}`+"\n", id, id, nama)
+	}
+	return b.String()
+}
+
+func TestExportNDJSON(t *testing.T) {
+	db := newGudangDB(t)
+	cases := []struct {
+		name    string
+		query   url.Values
+		cfg     ExportConfig
+		want    string
+		flushes int
+		err     error
+	}{
+		{"filtered and ordered", url.Values{"filter[nama]": {"Utara"}, "order": {"kode desc"}}, ExportConfig{}, gudangLines(5, 3, 1), 1, nil},
+		{"batched", url.Values{}, ExportConfig{BatchSize: 2}, gudangLines(1, 2, 3, 4, 5), 3, nil},
+		{"no pagination", url.Values{"pageSize": {"1"}, "page": {"2"}}, ExportConfig{}, gudangLines(1, 2, 3, 4, 5), 1, nil},
+		{"empty set", url.Values{"filter[kode]": {"GD-99"}}, ExportConfig{}, "", 0, nil},
+		{"too many rows", url.Values{}, ExportConfig{MaxRows: 4}, "", 0, ErrExportTooLarge},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var w flushCounter
+			err := ExportNDJSON(context.Background(), &w, tc.query, db, &Gudang{}, Options{OrderBy: "id"}, tc.cfg)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if w.String() != tc.want || w.flushes != tc.flushes {
+				t.Fatalf("%d flushes, got:\n%s\nwant %d flushes:\n%s", w.flushes, w.String(), tc.flushes, tc.want)
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	cases := map[string]bool{
+		"":                   false,
+		"gzip":               true,
+		"deflate, GZIP":      true,
+		"br;q=1, gzip;q=0.5": true,
+		"gzip;q=0":           false,
+		"identity":           false,
+	}
+	for header, want := range cases {
+		if got := acceptsGzip(header); got != want {
+			t.Fatalf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestExportNDJSONHandler(t *testing.T) {
+	db := newGudangDB(t)
+	opts := Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"id": "int"}}
+	h := ExportNDJSONHandler[Gudang](db, opts, ExportConfig{MaxRows: 3})
+	want := gudangLines(1, 2)
+	cases := []struct {
+		name     string
+		target   string
+		encoding string
+		gzip     bool
+		status   int
+	}{
+		{"plain", "/gudang?filter[id]=1,2", "", false, http.StatusOK},
+		{"gzip", "/gudang?filter[id]=1,2", "gzip, deflate", true, http.StatusOK},
+		{"gzip refused", "/gudang?filter[id]=1,2", "gzip;q=0", false, http.StatusOK},
+		{"invalid filter", "/gudang?filter[id]=satu", "", false, http.StatusBadRequest},
+		{"too many rows", "/gudang", "", false, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set("Accept-Encoding", tc.encoding)
+			rec := httptest.NewRecorder()
+			h(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			if rec.Header().Get("Content-Type") != "application/x-ndjson" || rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Fatalf("headers %v", rec.Header())
+			}
+			var body io.Reader = rec.Body
+			if tc.gzip {
+				if rec.Header().Get("Content-Encoding") != "gzip" {
+					t.Fatalf("Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+				}
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			}
+			b, _ := io.ReadAll(body)
+			if string(b) != want {
+				t.Fatalf("body:\n%s", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidExportTag: tag `export:"..."` model tidak bisa di-parse (dilaporkan saat export, dengan nama field)
+var ErrInvalidExportTag = errors.New("invalid export tag")
+
+// layout default format=date / format=datetime tanpa layout
+const (
+	defaultDateLayout     = "2006-01-02"
+	defaultDatetimeLayout = "2006-01-02 15:04:05"
+)
+
+// exportTag: isi tag `export:"header=Nama Gudang,order=2,format=date:02-01-2006"`; "-" = skip
+type exportTag struct {
+	skip   bool
+	header string
+	order  int
+	kind   string // "date" / "datetime" dari format=
+	layout string // layout Go dari format=
+}
+
+// parseExportTag membaca tag export field f. Key yang tidak dikenal, order bukan angka positif atau
+// format yang tidak valid -> error (tanpa nama field; dibungkus exportColumns).
+func parseExportTag(f *schema.Field) (exportTag, error) {
+	var t exportTag
+	raw, ok := f.StructField.Tag.Lookup("export")
+	if !ok {
+		return t, nil
+	}
+	if strings.TrimSpace(raw) == "-" {
+		t.skip = true
+		return t, nil
+	}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		k, v, found := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !found {
+			return t, fmt.Errorf("%q is not key=value", item)
+		}
+		switch k {
+		case "header":
+			t.header = v
+		case "order":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return t, fmt.Errorf("order %q is not a positive integer", v)
+			}
+			t.order = n
+		case "format":
+			kind, layout, err := parseExportFormat(v)
+			if err != nil {
+				return t, err
+			}
+			t.kind, t.layout = kind, layout
+		default:
+			return t, fmt.Errorf("unknown key %q", k)
+		}
+	}
+	return t, nil
+}
+
+// parseExportFormat: "date", "date:02-01-2006", "datetime" atau "datetime:02/01/2006 15:04" -> jenis dan layout Go
+func parseExportFormat(format string) (kind, layout string, err error) {
+	kind, layout, _ = strings.Cut(format, ":")
+	switch kind {
+	case "date":
+		if layout == "" {
+			layout = defaultDateLayout
+		}
+	case "datetime":
+		if layout == "" {
+			layout = defaultDatetimeLayout
+		}
+	default:
+		return "", "", fmt.Errorf("unknown format %q (want date or datetime)", format)
+	}
+	return kind, layout, nil
+}
+
+// applyFormat: jenis/layout format ke kolom; hanya untuk kolom waktu atau teks tanggal
+func (c *exportColumn) applyFormat(kind, layout string) error {
+	switch c.Type {
+	case "date", "datetime", "string":
+	default:
+		return fmt.Errorf("format %s on %s column", kind, c.Type)
+	}
+	c.Type, c.Format = kind, layout
+	return nil
+}
+
+// asTime: nilai export sebagai waktu — time.Time atau teks tanggal (RFC3339, "2006-01-02 15:04:05", "2006-01-02")
+func asTime(v interface{}) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, defaultDatetimeLayout, defaultDateLayout} {
+			if t, err := time.Parse(layout, x); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
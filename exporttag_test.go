@@ -0,0 +1,137 @@
+package magicrest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Stok: kolom export dari tag
+type Stok struct {
+	ID       uint      `json:"id" export:"order=3"`
+	Gudang   string    `json:"gudang" export:"header=Nama Gudang,order=1"`
+	Jumlah   int       `json:"jumlah" export:"order=2"`
+	Masuk    time.Time `json:"masuk" export:"header=Tanggal Masuk,format=date:02-01-2006"`
+	Internal string    `json:"internal" export:"-"`
+}
+
+type stokBadKey struct {
+	ID   uint   `json:"id"`
+	Nama string `json:"nama" export:"judul=Nama"`
+}
+
+type stokBadOrder struct {
+	ID   uint   `json:"id"`
+	Nama string `json:"nama" export:"order=0"`
+}
+
+type stokBadFormat struct {
+	ID     uint `json:"id"`
+	Jumlah int  `json:"jumlah" export:"format=date"`
+}
+
+type stokNoValue struct {
+	ID   uint   `json:"id"`
+	Nama string `json:"nama" export:"header"`
+}
+
+func TestParseExportTag(t *testing.T) {
+	cases := []struct {
+		model interface{}
+		field string // nama field di pesan error ("" = valid)
+	}{
+		{&Stok{}, ""},
+		{&stokBadKey{}, "stokBadKey.Nama"},
+		{&stokBadOrder{}, "stokBadOrder.Nama"},
+		{&stokBadFormat{}, "stokBadFormat.Jumlah"},
+		{&stokNoValue{}, "stokNoValue.Nama"},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%T", tc.model), func(t *testing.T) {
+			sch, err := parseModelSchema(tc.model)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = exportColumns(sch, nil, ExportConfig{})
+			if tc.field == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidExportTag) || !strings.Contains(err.Error(), tc.field) {
+				t.Fatalf("err = %v, want ErrInvalidExportTag naming %s", err, tc.field)
+			}
+		})
+	}
+}
+
+// exportStokCSV: hasil ExportCSV model Stok sebagai record
+func exportStokCSV(t *testing.T, cfg ExportConfig) [][]string {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Stok{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&Stok{Gudang: "Utama", Jumlah: 7, Masuk: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), Internal: "x"})
+	var buf bytes.Buffer
+	if err := ExportCSV(context.Background(), &buf, url.Values{}, db, &Stok{}, Options{OrderBy: "id"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestExportCSVColumnsFromTags(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  ExportConfig
+		want [][]string
+	}{
+		{"tags", ExportConfig{}, [][]string{
+			{"Nama Gudang", "jumlah", "id", "Tanggal Masuk"},
+			{"Utama", "7", "1", "05-03-2024"},
+		}},
+		{"config overrides tags", ExportConfig{
+			Columns: []string{"masuk", "gudang"},
+			Headers: map[string]string{"gudang": "Gudang"},
+			Formats: map[string]string{"masuk": "date:2006/01/02"},
+		}, [][]string{
+			{"Tanggal Masuk", "Gudang"},
+			{"2024/03/05", "Utama"},
+		}},
+		// export:"-" hanya default: kolom yang diminta eksplisit tetap ikut
+		{"explicit skipped column", ExportConfig{Columns: []string{"id", "internal"}}, [][]string{
+			{"id", "internal"},
+			{"1", "x"},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := exportStokCSV(t, tc.cfg); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExportCSVInvalidTag(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&stokBadOrder{}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err := ExportCSV(context.Background(), &buf, url.Values{}, db, &stokBadOrder{}, Options{OrderBy: "id"}, ExportConfig{})
+	if !errors.Is(err, ErrInvalidExportTag) || !strings.Contains(err.Error(), "stokBadOrder.Nama") || buf.Len() != 0 {
+		t.Fatalf("err = %v, %d bytes written", err, buf.Len())
+	}
+}
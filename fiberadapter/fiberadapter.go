@@ -0,0 +1,223 @@
+// Package fiberadapter memasang resource magicrest ke Fiber v2. Modul terpisah (go.mod sendiri) agar
+// Fiber / fasthttp tidak ikut ke dependency graph pemakai lain. Fiber tidak memakai tipe net/http,
+// jadi query string dikonversi ke QuerySource, context dari c.UserContext(), dan response ditulis dengan
+// envelope dan pemetaan error yang sama seperti handler net/http magicrest.
+package fiberadapter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Values: query string Fiber (fasthttp.Args) sebagai url.Values. Nilai di-copy karena buffer fasthttp
+// dipakai ulang setelah request selesai.
+func Values(c *fiber.Ctx) url.Values {
+	out := url.Values{}
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		k := string(key)
+		out[k] = append(out[k], string(value))
+	})
+	return out
+}
+
+// Query: QuerySource dari query string Fiber
+func Query(c *fiber.Ctx) magicrest.QuerySource {
+	return magicrest.FromURLValues(Values(c))
+}
+
+// WriteError: status dari magicrest.StatusForError dan {"error": "...", "errors": [...]} dalam locale
+// dari ?lang= / Accept-Language.
+func WriteError(c *fiber.Ctx, err error) error {
+	return WriteErrorWith(c, magicrest.EnvelopeConfig{}, err)
+}
+
+// WriteErrorWith: WriteError dengan bentuk body dari env (ErrorFormat, ErrorKey, ErrorsKey)
+func WriteErrorWith(c *fiber.Ctx, env magicrest.EnvelopeConfig, err error) error {
+	status, body := env.ErrorBody(err, magicrest.LocaleFrom(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage)))
+	return c.Status(status).JSON(body)
+}
+
+// decodeBody men-decode body JSON ke out; gagal -> magicrest.ErrInvalidBody (400)
+func decodeBody(c *fiber.Ctx, out any) error {
+	if err := json.Unmarshal(c.Body(), out); err != nil {
+		return fmt.Errorf("%w: %v", magicrest.ErrInvalidBody, err)
+	}
+	return nil
+}
+
+// ListHandler: GET list untuk model T via magicrest.ReadPaginatedSource, response {"data": [...], "meta": {...}}.
+// Options.ETag, Options.LastModified, Options.NegotiateContent, Options.RangeHeader dan HEAD (hanya count di header) berlaku sama
+// seperti magicrest.ListHandlerHTTP; app.Get sudah ikut mendaftarkan HEAD.
+func ListHandler[T any](db *gorm.DB, opts magicrest.Options) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := Values(c)
+		if c.Method() == fiber.MethodHead {
+			pagination, err := magicrest.CountList[T](c.UserContext(), magicrest.FromURLValues(query), db, opts)
+			if err != nil {
+				return c.SendStatus(magicrest.StatusForError(err))
+			}
+			u := &url.URL{Path: c.Path(), RawQuery: query.Encode()}
+			for k, v := range magicrest.CountHeaders(u, pagination) {
+				c.Set(k, v[0])
+			}
+			return c.SendStatus(http.StatusOK)
+		}
+		lastModified, notModified, err := magicrest.CheckLastModified[T](c.UserContext(), query, db, opts,
+			c.Get(fiber.HeaderIfNoneMatch), c.Get(fiber.HeaderIfModifiedSince))
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		if !lastModified.IsZero() {
+			c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+		}
+		if notModified {
+			return c.SendStatus(http.StatusNotModified)
+		}
+		format := magicrest.FormatJSON
+		if opts.NegotiateContent {
+			c.Vary(fiber.HeaderAccept)
+			if format = magicrest.NegotiateFormat(c.Get(fiber.HeaderAccept)); format.IsExport() {
+				exp, err := magicrest.PrepareExport[T](c.UserContext(), magicrest.FromURLValues(query), db, opts, magicrest.ExportConfig{})
+				if err != nil {
+					return WriteErrorWith(c, opts.Envelope, err)
+				}
+				c.Set(fiber.HeaderContentType, format.ContentType())
+				c.Status(http.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+					_ = exp.Write(w, format)
+				})
+				return nil
+			}
+		}
+		if opts.RangeHeader && format == magicrest.FormatJSON {
+			c.Set(fiber.HeaderAcceptRanges, "items")
+			if start, end, ok := magicrest.ParseItemsRange(c.Get(fiber.HeaderRange)); ok {
+				res, err := magicrest.ReadRange[T](c.UserContext(), magicrest.FromURLValues(query), db, opts, start, end)
+				if pagination, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+					c.Set(fiber.HeaderContentRange, magicrest.ContentRange(pagination))
+				}
+				if err != nil {
+					return WriteErrorWith(c, opts.Envelope, err)
+				}
+				status := http.StatusPartialContent
+				if len(res.Data) == 0 {
+					status = http.StatusOK
+				}
+				return c.Status(status).JSON(opts.Envelope.Wrap(res.Data, res.Meta))
+			}
+		}
+		res, err := magicrest.ReadPaginatedSource[T](c.UserContext(), magicrest.FromURLValues(query), db.Model(new(T)), new(T), opts)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		if opts.ETag {
+			tag, err := magicrest.ListETag(db, query, opts, res)
+			if err != nil {
+				return WriteErrorWith(c, opts.Envelope, err)
+			}
+			c.Set(fiber.HeaderETag, tag)
+			if magicrest.ETagMatches(c.Get(fiber.HeaderIfNoneMatch), tag) {
+				return c.SendStatus(http.StatusNotModified)
+			}
+		}
+		u := &url.URL{Path: c.Path(), RawQuery: query.Encode()}
+		body, err := magicrest.ListBody(db, res, u, opts, format)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		if format == magicrest.FormatJSON {
+			return c.Status(http.StatusOK).JSON(body)
+		}
+		return c.Status(http.StatusOK).JSON(body, format.ContentType())
+	}
+}
+
+// DescribeHandler: deskripsi kemampuan query resource T (magicrest.Describe) untuk OPTIONS / GET /_meta,
+// header Allow sama dengan magicrest.DescribeHandlerHTTP
+func DescribeHandler[T any](db *gorm.DB, opts magicrest.Options, verbs []magicrest.Verb) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		d, err := magicrest.Describe[T](db, opts, verbs)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		c.Set(fiber.HeaderAllow, magicrest.AllowHeader(d.Verbs))
+		return c.Status(http.StatusOK).JSON(opts.Envelope.Wrap(d, nil))
+	}
+}
+
+// GetHandler: GET /:id via magicrest.ReadOne
+func GetHandler[T any](db *gorm.DB, opts magicrest.Options) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		out, err := magicrest.ReadOne[T](c.UserContext(), Values(c), db, c.Params("id"), opts)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		return c.Status(http.StatusOK).JSON(opts.Envelope.Wrap(out, nil))
+	}
+}
+
+// CreateHandler: POST via magicrest.CreateGeneric, response 201 dengan Location <path request>/<pk>
+func CreateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		payload := new(T)
+		if err := decodeBody(c, payload); err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		created, err := magicrest.CreateGeneric(c.UserContext(), db, payload, opts)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		location, err := magicrest.ResourceLocation(c.Path(), created)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		c.Set(fiber.HeaderLocation, location)
+		return c.Status(http.StatusCreated).JSON(opts.Envelope.Wrap(created, nil))
+	}
+}
+
+// UpdateHandler: PUT /:id via magicrest.UpdateGeneric (full replace)
+func UpdateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		payload := new(T)
+		if err := decodeBody(c, payload); err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		out, err := magicrest.UpdateGeneric(c.UserContext(), db, c.Params("id"), payload, opts)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		return c.Status(http.StatusOK).JSON(opts.Envelope.Wrap(out, nil))
+	}
+}
+
+// PatchHandler: PATCH /:id via magicrest.PatchGeneric dari objek JSON
+func PatchHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var patch map[string]interface{}
+		if err := decodeBody(c, &patch); err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		out, err := magicrest.PatchGeneric[T](c.UserContext(), db, c.Params("id"), patch, opts)
+		if err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		return c.Status(http.StatusOK).JSON(opts.Envelope.Wrap(out, nil))
+	}
+}
+
+// DeleteHandler: DELETE /:id via magicrest.DeleteGeneric, response 204
+func DeleteHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := magicrest.DeleteGeneric[T](c.UserContext(), db, c.Params("id"), opts); err != nil {
+			return WriteErrorWith(c, opts.Envelope, err)
+		}
+		return c.SendStatus(http.StatusNoContent)
+	}
+}
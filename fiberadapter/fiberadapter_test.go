@@ -0,0 +1,128 @@
+package fiberadapter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Barang struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Harga int    `json:"harga"`
+}
+
+func newBarangDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Barang{{Nama: "Semen", Harga: 50}, {Nama: "Pasir", Harga: 20}})
+	return db
+}
+
+func TestValues(t *testing.T) {
+	app := fiber.New()
+	var got string
+	app.Get("/", func(c *fiber.Ctx) error {
+		got = fmt.Sprint(Values(c))
+		return nil
+	})
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/?filter[status]=aktif&preload=Items&preload=Gudang&search=semen%20putih", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "map[filter[status]:[aktif] preload:[Items Gudang] search:[semen putih]]" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	db := newBarangDB(t)
+	app := fiber.New()
+	opts := magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}}
+	app.Get("/api/barang", ListHandler[Barang](db, opts))
+	app.Get("/api/barang/:id", GetHandler[Barang](db, opts))
+	app.Post("/api/barang", CreateHandler[Barang](db, magicrest.WriteOptions{}))
+	app.Put("/api/barang/:id", UpdateHandler[Barang](db, magicrest.WriteOptions{}))
+	app.Patch("/api/barang/:id", PatchHandler[Barang](db, magicrest.WriteOptions{}))
+	app.Delete("/api/barang/:id", DeleteHandler[Barang](db, magicrest.WriteOptions{}))
+
+	cases := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		status   int
+		want     string // potongan body
+		location string
+	}{
+		{"list", http.MethodGet, "/api/barang?filter[harga]=20", "", http.StatusOK, `"nama":"Pasir"`, ""},
+		{"list invalid filter", http.MethodGet, "/api/barang?filter[harga]=mahal", "", http.StatusBadRequest, `"code":"invalid_int"`, ""},
+		{"get", http.MethodGet, "/api/barang/1", "", http.StatusOK, `"nama":"Semen"`, ""},
+		{"get invalid id", http.MethodGet, "/api/barang/satu", "", http.StatusBadRequest, `"error"`, ""},
+		{"create", http.MethodPost, "/api/barang", `{"nama":"Bata","harga":3}`, http.StatusCreated, `"id":3`, "/api/barang/3"},
+		{"create invalid body", http.MethodPost, "/api/barang", `{"nama":`, http.StatusBadRequest, `"code":"invalid_body"`, ""},
+		{"update", http.MethodPut, "/api/barang/3", `{"nama":"Bata Merah","harga":4}`, http.StatusOK, `"nama":"Bata Merah"`, ""},
+		{"patch", http.MethodPatch, "/api/barang/3", `{"harga":5}`, http.StatusOK, `"harga":5`, ""},
+		{"delete", http.MethodDelete, "/api/barang/3", "", http.StatusNoContent, "", ""},
+		{"get deleted", http.MethodGet, "/api/barang/3", "", http.StatusNotFound, `"error"`, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tc.status || !strings.Contains(string(body), tc.want) {
+				t.Fatalf("status %d, body %s; want %d with %s", resp.StatusCode, body, tc.status, tc.want)
+			}
+			if got := resp.Header.Get("Location"); got != tc.location {
+				t.Fatalf("Location %q, want %q", got, tc.location)
+			}
+		})
+	}
+}
+
+// body Fiber sama dengan handler net/http magicrest (tanpa newline akhir dari json.Encoder)
+func TestHandlersMatchHTTP(t *testing.T) {
+	db := newBarangDB(t)
+	opts := magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}}
+	app := fiber.New()
+	app.Get("/barang", ListHandler[Barang](db, opts))
+	app.Get("/barang/:id", GetHandler[Barang](db, opts))
+	mux := http.NewServeMux()
+	mux.Handle("GET /barang", magicrest.ListHandlerHTTP[Barang](db, opts))
+	mux.Handle("GET /barang/{id}", magicrest.GetHandlerHTTP[Barang](db, opts))
+	for _, target := range []string{"/barang?pageSize=1", "/barang?filter[harga]=mahal&lang=id", "/barang/2", "/barang/99"} {
+		t.Run(target, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			if resp.StatusCode != rec.Code || string(body) != strings.TrimSuffix(rec.Body.String(), "\n") {
+				t.Fatalf("fiber %d %s\nnet/http %d %s", resp.StatusCode, body, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package ginrest
+
+import (
+	"fmt"
+	"net/http"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Verb: operasi CRUD yang dipasang RegisterCRUD (alias magicrest.Verb, sama untuk semua adapter)
+type Verb = magicrest.Verb
+
+const (
+	VerbList   = magicrest.VerbList
+	VerbGet    = magicrest.VerbGet
+	VerbCreate = magicrest.VerbCreate
+	VerbUpdate = magicrest.VerbUpdate
+	VerbPatch  = magicrest.VerbPatch
+	VerbDelete = magicrest.VerbDelete
+)
+
+// AllVerbs: semua verb, urutan pendaftaran route
+var AllVerbs = magicrest.AllVerbs
+
+// ResourceConfig: konfigurasi RegisterCRUD
+type ResourceConfig struct {
+	Verbs        []Verb                     // verb yang dipasang (nil = AllVerbs); yang lain tidak didaftarkan sama sekali
+	Middleware   map[Verb][]gin.HandlerFunc // middleware per verb, e.g. VerbDelete: {adminOnly}
+	Options      magicrest.Options          // list dan get
+	WriteOptions magicrest.WriteOptions     // create, update, patch, delete
+	Describe     bool                       // OPTIONS / dan GET /_meta: magicrest.Describe untuk komponen tabel generik
+}
+
+// RegisterCRUD memasang GET / (dan HEAD /), GET /:id, POST /, PUT /:id, PATCH /:id dan DELETE /:id untuk model T di rg
+// memakai helper generik (ReadPaginatedSource, ReadOne, CreateGeneric, UpdateGeneric, PatchGeneric,
+// DeleteGeneric). Verb yang tidak dipilih tidak didaftarkan sehingga router menjawab 404/405 sendiri.
+// Envelope WriteOptions mengikuti Options.Envelope bila tidak di-set. ResourceConfig.Describe menambah OPTIONS /
+// dan GET /_meta (DescribeHandler).
+func RegisterCRUD[T any](rg *gin.RouterGroup, db *gorm.DB, cfg ResourceConfig) {
+	verbs := cfg.Verbs
+	if verbs == nil {
+		verbs = AllVerbs
+	}
+	write := cfg.WriteOptions
+	if write.Envelope == (magicrest.EnvelopeConfig{}) {
+		write.Envelope = cfg.Options.Envelope
+	}
+	for _, v := range verbs {
+		var method, path string
+		var h gin.HandlerFunc
+		switch v {
+		case VerbList:
+			method, path, h = http.MethodGet, "", ListHandler[T](db, cfg.Options)
+		case VerbGet:
+			method, path, h = http.MethodGet, "/:id", GetHandler[T](db, cfg.Options)
+		case VerbCreate:
+			method, path, h = http.MethodPost, "", CreateHandler[T](db, write)
+		case VerbUpdate:
+			method, path, h = http.MethodPut, "/:id", UpdateHandler[T](db, write)
+		case VerbPatch:
+			method, path, h = http.MethodPatch, "/:id", PatchHandler[T](db, write)
+		case VerbDelete:
+			method, path, h = http.MethodDelete, "/:id", DeleteHandler[T](db, write)
+		default:
+			panic(fmt.Sprintf("ginrest: unknown verb %q", v))
+		}
+		handlers := append(append([]gin.HandlerFunc{}, cfg.Middleware[v]...), h)
+		rg.Handle(method, path, handlers...)
+		if v == VerbList {
+			// HEAD / = hanya count di header (X-Total-Count, X-Page-Count, Link)
+			rg.Handle(http.MethodHead, path, handlers...)
+		}
+	}
+	if cfg.Describe {
+		h := DescribeHandler[T](db, cfg.Options, verbs)
+		rg.OPTIONS("", h)
+		rg.GET("/_meta", h)
+	}
+}
+
+// DescribeHandler: deskripsi kemampuan query resource T (magicrest.DescribeHandlerHTTP), untuk OPTIONS / GET /_meta
+func DescribeHandler[T any](db *gorm.DB, opts magicrest.Options, verbs []Verb) gin.HandlerFunc {
+	return Wrap(magicrest.DescribeHandlerHTTP[T](db, opts, verbs))
+}
+
+// ExportCSVHandler: GET export CSV untuk model T (magicrest.ExportCSVHandler), Content-Disposition dari cfg.Filename,
+// e.g. rg.GET("/export.csv", ginrest.ExportCSVHandler[Barang](db, opts, magicrest.ExportConfig{MaxRows: 100000}))
+func ExportCSVHandler[T any](db *gorm.DB, opts magicrest.Options, cfg magicrest.ExportConfig) gin.HandlerFunc {
+	return Wrap(magicrest.ExportCSVHandler[T](db, opts, cfg))
+}
+
+// ExportNDJSONHandler: GET export NDJSON untuk model T (magicrest.ExportNDJSONHandler), gzip bila diminta klien
+func ExportNDJSONHandler[T any](db *gorm.DB, opts magicrest.Options, cfg magicrest.ExportConfig) gin.HandlerFunc {
+	return Wrap(magicrest.ExportNDJSONHandler[T](db, opts, cfg))
+}
+
+// JSONAPIListHandler: GET list sebagai dokumen JSON:API (magicrest.JSONAPIListHandlerHTTP)
+func JSONAPIListHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.JSONAPIListHandlerHTTP[T](db, opts))
+}
+
+// JSONAPIGetHandler: GET /:id sebagai dokumen JSON:API (magicrest.JSONAPIGetHandlerHTTP)
+func JSONAPIGetHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.JSONAPIGetHandlerHTTP[T](db, opts))
+}
+
+// HALListHandler: GET list sebagai dokumen HAL (magicrest.HALListHandlerHTTP)
+func HALListHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.HALListHandlerHTTP[T](db, opts))
+}
+
+// HALGetHandler: GET /:id sebagai resource HAL (magicrest.HALGetHandlerHTTP)
+func HALGetHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.HALGetHandlerHTTP[T](db, opts))
+}
+
+// CreateHandler: POST via magicrest.CreateGeneric, response 201 dengan Location <path request>/<pk>
+func CreateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) gin.HandlerFunc {
+	return Wrap(magicrest.CreateHandlerHTTP[T](db, opts))
+}
+
+// UpdateHandler: PUT /:id via magicrest.UpdateGeneric (full replace)
+func UpdateHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) gin.HandlerFunc {
+	return Wrap(magicrest.UpdateHandlerHTTP[T](db, opts))
+}
+
+// PatchHandler: PATCH /:id via magicrest.PatchGeneric dari objek JSON
+func PatchHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) gin.HandlerFunc {
+	return Wrap(magicrest.PatchHandlerHTTP[T](db, opts))
+}
+
+// DeleteHandler: DELETE /:id via magicrest.DeleteGeneric, response 204 tanpa body
+func DeleteHandler[T any](db *gorm.DB, opts magicrest.WriteOptions) gin.HandlerFunc {
+	return Wrap(magicrest.DeleteHandlerHTTP[T](db, opts))
+}
@@ -0,0 +1,115 @@
+package ginrest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterCRUD(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newBarangDB(t)
+	r := gin.New()
+	adminOnly := func(c *gin.Context) {
+		if c.GetHeader("X-Role") != "admin" {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+	RegisterCRUD[Barang](r.Group("/api/barang"), db, ResourceConfig{
+		Middleware: map[Verb][]gin.HandlerFunc{VerbDelete: {adminOnly}},
+		Options:    magicrest.Options{OrderBy: "id"},
+	})
+	RegisterCRUD[Barang](r.Group("/api/katalog"), db, ResourceConfig{Verbs: []Verb{VerbList, VerbGet}})
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		role     string
+		status   int
+		want     string // potongan body
+		location string
+	}{
+		{"create", http.MethodPost, "/api/barang", `{"nama":"Bata","harga":3}`, "", http.StatusCreated, `"nama":"Bata"`, "/api/barang/3"},
+		{"create invalid body", http.MethodPost, "/api/barang", `{"nama":`, "", http.StatusBadRequest, "invalid_body", ""},
+		{"list", http.MethodGet, "/api/barang?pageSize=10", "", "", http.StatusOK, `"total":3`, ""},
+		{"get", http.MethodGet, "/api/barang/3", "", "", http.StatusOK, `"nama":"Bata"`, ""},
+		{"get invalid id", http.MethodGet, "/api/barang/tiga", "", "", http.StatusBadRequest, "", ""},
+		{"update", http.MethodPut, "/api/barang/3", `{"nama":"Bata Merah","harga":4}`, "", http.StatusOK, `"nama":"Bata Merah"`, ""},
+		{"patch", http.MethodPatch, "/api/barang/3", `{"harga":5}`, "", http.StatusOK, `"harga":5`, ""},
+		{"patch unknown field", http.MethodPatch, "/api/barang/3", `{"warna":"merah"}`, "", http.StatusBadRequest, "", ""},
+		{"delete without role", http.MethodDelete, "/api/barang/3", "", "", http.StatusForbidden, "", ""},
+		{"delete", http.MethodDelete, "/api/barang/3", "", "admin", http.StatusNoContent, "", ""},
+		{"get deleted", http.MethodGet, "/api/barang/3", "", "", http.StatusNotFound, "", ""},
+		{"read-only list", http.MethodGet, "/api/katalog/1", "", "", http.StatusOK, `"nama":"Semen"`, ""},
+		{"disabled create", http.MethodPost, "/api/katalog", `{"nama":"Bata"}`, "", http.StatusNotFound, "", ""},
+		{"disabled delete", http.MethodDelete, "/api/katalog/1", "", "admin", http.StatusNotFound, "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.role != "" {
+				req.Header.Set("X-Role", tc.role)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tc.status || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, body %s; want %d with %s", rec.Code, rec.Body.String(), tc.status, tc.want)
+			}
+			if got := rec.Header().Get("Location"); got != tc.location {
+				t.Fatalf("Location %q, want %q", got, tc.location)
+			}
+		})
+	}
+	var stored []Barang
+	db.Order("id").Find(&stored)
+	if len(stored) != 2 {
+		t.Fatalf("rows after delete: %+v", stored)
+	}
+}
+
+func TestRegisterCRUDEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterCRUD[Barang](r.Group("/barang"), newBarangDB(t), ResourceConfig{Options: magicrest.Options{Envelope: magicrest.EnvelopeConfig{DataKey: "barang"}}})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/barang", strings.NewReader(`{"nama":"Bata"}`)))
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusCreated || body["barang"] == nil {
+		t.Fatalf("status %d, body %s: write handlers should follow Options.Envelope", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRegisterCRUDDescribe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	db := newBarangDB(t)
+	RegisterCRUD[Barang](r.Group("/barang"), db, ResourceConfig{Describe: true, Verbs: []Verb{VerbList, VerbCreate}})
+	RegisterCRUD[Barang](r.Group("/katalog"), db, ResourceConfig{})
+	for _, req := range []*http.Request{httptest.NewRequest(http.MethodOptions, "/barang", nil), httptest.NewRequest(http.MethodGet, "/barang/_meta", nil)} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		var body struct {
+			Data magicrest.ResourceDescription `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK || rec.Header().Get("Allow") != "GET, HEAD, POST, OPTIONS" {
+			t.Fatalf("%s %s: status %d, Allow %q, body %s", req.Method, req.URL, rec.Code, rec.Header().Get("Allow"), rec.Body.String())
+		}
+		if len(body.Data.Verbs) != 2 || len(body.Data.Filters) != 3 {
+			t.Fatalf("description %+v", body.Data)
+		}
+	}
+	// tanpa Describe: tidak ada route OPTIONS / _meta
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/katalog", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("OPTIONS without Describe: status %d", rec.Code)
+	}
+}
@@ -0,0 +1,108 @@
+// Package ginrest memasang resource magicrest ke router Gin.
+package ginrest
+
+import (
+	"net/http"
+	"strings"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RouteOption mengatur route yang dipasang MountResource (mis. middleware autentikasi per route).
+type RouteOption func(*routeConfig)
+
+type routeConfig struct {
+	list []gin.HandlerFunc
+	get  []gin.HandlerFunc
+}
+
+// WithListMiddleware menambahkan middleware untuk GET /path
+func WithListMiddleware(h ...gin.HandlerFunc) RouteOption {
+	return func(c *routeConfig) { c.list = append(c.list, h...) }
+}
+
+// WithGetMiddleware menambahkan middleware untuk GET /path/:id
+func WithGetMiddleware(h ...gin.HandlerFunc) RouteOption {
+	return func(c *routeConfig) { c.get = append(c.get, h...) }
+}
+
+// MountResource memasang GET /path (list via ReadPaginatedCtx) dan GET /path/:id untuk model T.
+// Response memakai envelope {"data": ..., "meta": ...}; error package dipetakan ke status HTTP.
+func MountResource[T any](rg *gin.RouterGroup, path string, db *gorm.DB, opts magicrest.Options, routeOpts ...RouteOption) {
+	var cfg routeConfig
+	for _, o := range routeOpts {
+		o(&cfg)
+	}
+	path = "/" + strings.Trim(path, "/")
+
+	rg.GET(path, append(cfg.list, ListHandler[T](db, opts))...)
+	rg.GET(path+"/:id", append(cfg.get, GetHandler[T](db, opts))...)
+}
+
+// Query: QuerySource dari query string request Gin
+func Query(c *gin.Context) magicrest.QuerySource {
+	return magicrest.FromURLValues(c.Request.URL.Query())
+}
+
+// Wrap memasang http.HandlerFunc (magicrest.ListHandlerHTTP, ...) sebagai handler gin. Parameter route
+// gin (:id) diteruskan sebagai r.PathValue sehingga handler net/http yang sama dipakai di kedua framework.
+func Wrap(h http.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, p := range c.Params {
+			c.Request.SetPathValue(p.Key, p.Value)
+		}
+		h(c.Writer, c.Request)
+	}
+}
+
+// ListHandler: handler GET list untuk model T (magicrest.ListHandlerHTTP) — membaca query string, ReadPaginatedSource
+// dengan context request, menulis {"data": [...], "meta": {...}} (key dari Options.Envelope) atau error.
+func ListHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.ListHandlerHTTP[T](db, opts))
+}
+
+// GetHandler: handler GET satu record berdasarkan primary key (:id) via magicrest.ReadOne
+// (Options.PreloadFields, ?preload=, Scopes, masking)
+func GetHandler[T any](db *gorm.DB, opts magicrest.Options) gin.HandlerFunc {
+	return Wrap(magicrest.GetHandlerHTTP[T](db, opts))
+}
+
+// WriteCreated: varian gin dari magicrest.WriteCreated — 201, header Location basePath/<pk> dan {"data": created}
+func WriteCreated(c *gin.Context, basePath string, created any) {
+	location, err := magicrest.ResourceLocation(basePath, created)
+	if err != nil {
+		WriteError(c, err)
+		return
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// WriteCreatedAs: WriteCreated dengan body DTO dari mapFn; Location tetap dari primary key model
+func WriteCreatedAs[T any, D any](c *gin.Context, basePath string, created *T, mapFn func(T) D) {
+	location, err := magicrest.ResourceLocation(basePath, created)
+	if err != nil {
+		WriteError(c, err)
+		return
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusCreated, gin.H{"data": mapFn(*created)})
+}
+
+// WriteError: varian gin dari magicrest.WriteError — status dari magicrest.StatusForError,
+// body {"error": "...", "errors": [ValidationDetail...]} dalam locale dari ?lang= / Accept-Language, lalu c.Abort().
+func WriteError(c *gin.Context, err error) {
+	WriteErrorWith(c, magicrest.EnvelopeConfig{}, err)
+}
+
+// WriteErrorWith: WriteError dengan bentuk body dari env (ErrorFormat, ErrorKey, ErrorsKey)
+func WriteErrorWith(c *gin.Context, env magicrest.EnvelopeConfig, err error) {
+	c.AbortWithStatusJSON(env.ErrorBody(err, magicrest.LocaleFromRequest(c.Request)))
+}
+
+// WriteData: varian gin dari magicrest.EnvelopeConfig.Write
+func WriteData(c *gin.Context, env magicrest.EnvelopeConfig, status int, data interface{}, meta map[string]interface{}) {
+	c.JSON(status, env.Wrap(data, meta))
+}
@@ -0,0 +1,224 @@
+package ginrest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestWriteError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, err := magicrest.ParseQuery(url.Values{"filter[jumlah]": {"abc"}}, magicrest.Options{DefaultFieldTypes: map[string]string{"jumlah": "int"}})
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/barang?lang=id", nil)
+	WriteError(c, err)
+
+	if rec.Code != http.StatusBadRequest || !c.IsAborted() {
+		t.Fatalf("status %d, aborted %v", rec.Code, c.IsAborted())
+	}
+	var body struct {
+		Errors []magicrest.ValidationDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Errors) != 1 || body.Errors[0].Field != "jumlah" || body.Errors[0].Code != "invalid_int" {
+		t.Fatalf("body %s", rec.Body.String())
+	}
+	// pesan dari katalog locale ?lang=id, bukan default bahasa Inggris
+	en := magicrest.DetailsFromErrorLocale(err, "en")[0].Message
+	if body.Errors[0].Message == "" || body.Errors[0].Message == en {
+		t.Fatalf("message %q not localized (en %q)", body.Errors[0].Message, en)
+	}
+}
+
+type Barang struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Harga int    `json:"harga"`
+}
+
+func newBarangDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Barang{{Nama: "Semen", Harga: 50}, {Nama: "Pasir", Harga: 20}})
+	return db
+}
+
+func TestMountResource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	auth := func(c *gin.Context) {
+		if c.GetHeader("X-Role") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+	opts := magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}}
+	MountResource[Barang](r.Group("/api"), "/barang/", newBarangDB(t), opts, WithListMiddleware(auth), WithGetMiddleware(auth))
+
+	cases := []struct {
+		name   string
+		path   string
+		role   string
+		status int
+		want   string // nama di data
+	}{
+		{"list", "/api/barang?pageSize=1", "admin", http.StatusOK, "Semen"},
+		{"list filter", "/api/barang?filter[harga]=20", "admin", http.StatusOK, "Pasir"},
+		{"list invalid filter", "/api/barang?filter[harga]=mahal", "admin", http.StatusBadRequest, ""},
+		{"list without role", "/api/barang", "", http.StatusUnauthorized, ""},
+		{"get", "/api/barang/2", "admin", http.StatusOK, "Pasir"},
+		{"get not found", "/api/barang/99", "admin", http.StatusNotFound, ""},
+		{"get without role", "/api/barang/1", "", http.StatusUnauthorized, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.role != "" {
+				req.Header.Set("X-Role", tc.role)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tc.status, rec.Body.String())
+			}
+			if tc.want == "" {
+				return
+			}
+			var list struct {
+				Data []Barang                  `json:"data"`
+				Meta map[string]map[string]any `json:"meta"`
+			}
+			var one struct {
+				Data Barang `json:"data"`
+			}
+			if tc.name == "get" {
+				if err := json.Unmarshal(rec.Body.Bytes(), &one); err != nil || one.Data.Nama != tc.want {
+					t.Fatalf("body %s, err %v", rec.Body.String(), err)
+				}
+				return
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Data) != 1 || list.Data[0].Nama != tc.want {
+				t.Fatalf("body %s, err %v", rec.Body.String(), err)
+			}
+			if list.Meta["pagination"]["pageSize"] == nil {
+				t.Fatalf("meta without pagination: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteCreated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	type barangDTO struct {
+		Label string `json:"label"`
+	}
+	created := &Barang{ID: 5, Nama: "Bata", Harga: 3}
+	cases := []struct {
+		name     string
+		write    func(c *gin.Context)
+		status   int
+		location string
+		body     string
+	}{
+		{"model", func(c *gin.Context) { WriteCreated(c, "/api/barang/", created) }, http.StatusCreated, "/api/barang/5",
+			`{"data":{"id":5,"nama":"Bata","harga":3}}`},
+		{"dto", func(c *gin.Context) {
+			WriteCreatedAs(c, "/api/barang", created, func(b Barang) barangDTO { return barangDTO{Label: b.Nama} })
+		}, http.StatusCreated, "/api/barang/5", `{"data":{"label":"Bata"}}`},
+		{"without primary key", func(c *gin.Context) { WriteCreated(c, "/api/barang", &Barang{Nama: "Bata"}) }, http.StatusInternalServerError, "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/barang", nil)
+			tc.write(c)
+			if rec.Code != tc.status || rec.Header().Get("Location") != tc.location {
+				t.Fatalf("status %d, Location %q", rec.Code, rec.Header().Get("Location"))
+			}
+			if tc.body != "" && rec.Body.String() != tc.body {
+				t.Fatalf("body %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlersEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newBarangDB(t)
+	cases := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		keys    string // key body, urut
+	}{
+		{"list default", ListHandler[Barang](db, magicrest.Options{OrderBy: "id"}), "/?pageSize=1", "[data meta]"},
+		{"list custom", ListHandler[Barang](db, magicrest.Options{OrderBy: "id", Envelope: magicrest.EnvelopeConfig{DataKey: "items", MetaKey: "info"}}), "/", "[info items]"},
+		{"list without meta", ListHandler[Barang](db, magicrest.Options{OrderBy: "id", Envelope: magicrest.EnvelopeConfig{MetaKey: "-"}}), "/", "[data]"},
+		{"get custom", GetHandler[Barang](db, magicrest.Options{Envelope: magicrest.EnvelopeConfig{DataKey: "barang"}}), "/", "[barang]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodGet, tc.target, nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			tc.handler(c)
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+			}
+			keys := make([]string, 0, len(body))
+			for k := range body {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if fmt.Sprint(keys) != tc.keys {
+				t.Fatalf("keys %v, want %s", keys, tc.keys)
+			}
+		})
+	}
+}
+
+// handler gin dan net/http harus menghasilkan response yang sama
+func TestHandlersMatchHTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newBarangDB(t)
+	opts := magicrest.Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"harga": "int"}}
+	r := gin.New()
+	r.GET("/barang", ListHandler[Barang](db, opts))
+	r.GET("/barang/:id", GetHandler[Barang](db, opts))
+	mux := http.NewServeMux()
+	mux.Handle("GET /barang", magicrest.ListHandlerHTTP[Barang](db, opts))
+	mux.Handle("GET /barang/{id}", magicrest.GetHandlerHTTP[Barang](db, opts))
+	for _, target := range []string{"/barang?pageSize=1", "/barang?filter[harga]=mahal&lang=id", "/barang/2", "/barang/dua", "/barang/99"} {
+		t.Run(target, func(t *testing.T) {
+			viaGin, viaHTTP := httptest.NewRecorder(), httptest.NewRecorder()
+			r.ServeHTTP(viaGin, httptest.NewRequest(http.MethodGet, target, nil))
+			mux.ServeHTTP(viaHTTP, httptest.NewRequest(http.MethodGet, target, nil))
+			if viaGin.Code != viaHTTP.Code || viaGin.Body.String() != viaHTTP.Body.String() {
+				t.Fatalf("gin %d %s\nnet/http %d %s", viaGin.Code, viaGin.Body.String(), viaHTTP.Code, viaHTTP.Body.String())
+			}
+		})
+	}
+}
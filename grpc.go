@@ -0,0 +1,56 @@
+package magicrest
+
+import "sort"
+
+// PageRequest: padanan struct message proto PageRequest{page, page_size, filter, order_by, search} agar
+// service gRPC memakai pipeline query dan Options yang sama dengan HTTP. Tidak ada kode hasil protoc di
+// package ini; salin field dari message hasil generate (nilai nol = default Options).
+type PageRequest struct {
+	Page     int32
+	PageSize int32
+	Filter   map[string]string // field -> nilai, "a,b" = IN seperti filter[field]=a,b
+	OrderBy  string            // format ?order=, e.g. "created_at desc"
+	Search   string
+}
+
+// Source: PageRequest sebagai QuerySource untuk ReadPaginatedSource / BuildQuerySource
+func (r PageRequest) Source() QuerySource {
+	b := NewQuery()
+	if r.Page > 0 {
+		b.Page(int(r.Page))
+	}
+	if r.PageSize > 0 {
+		b.PageSize(int(r.PageSize))
+	}
+	fields := make([]string, 0, len(r.Filter))
+	for field := range r.Filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		b.Filter(field, r.Filter[field])
+	}
+	if r.OrderBy != "" {
+		b.Order(r.OrderBy)
+	}
+	if r.Search != "" {
+		b.Search(r.Search)
+	}
+	return b
+}
+
+// PageResponse: padanan message proto PageResponse{total, page_count, has_next}
+type PageResponse struct {
+	Total     int64
+	PageCount int32
+	HasNext   bool
+}
+
+// PageResponseFromMeta mengisi PageResponse dari Result.Meta (Meta["pagination"] ReadPaginatedSource)
+func PageResponseFromMeta(meta map[string]interface{}) PageResponse {
+	pagination, _ := meta["pagination"].(map[string]interface{})
+	total, _ := pagination["total"].(int64)
+	pageCount, _ := pagination["pageCount"].(int)
+	hasNext, _ := pagination["hasNext"].(bool)
+	return PageResponse{Total: total, PageCount: int32(pageCount), HasNext: hasNext}
+}
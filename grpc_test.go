@@ -0,0 +1,51 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestPageRequestSource(t *testing.T) {
+	cases := []struct {
+		name string
+		req  PageRequest
+		want string
+	}{
+		{"zero value", PageRequest{}, ""},
+		{"all fields", PageRequest{Page: 2, PageSize: 5, Filter: map[string]string{"status": "aktif", "gudang_id": "1,2"}, OrderBy: "kode desc", Search: "ord"},
+			"filter%5Bgudang_id%5D=1%2C2&filter%5Bstatus%5D=aktif&order=kode+desc&page=2&pageSize=5&search=ord"},
+		{"negative page ignored", PageRequest{Page: -1, PageSize: 0}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := url.Values(tc.req.Source().Values()).Encode(); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPageRequestReadPaginated(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	req := PageRequest{Page: 1, PageSize: 2, Filter: map[string]string{"status": "aktif"}, OrderBy: "kode desc"}
+	res, err := ReadPaginatedSource[Order](context.Background(), req.Source(), db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kode []string
+	for _, o := range res.Data {
+		kode = append(kode, o.Kode)
+	}
+	if fmt.Sprint(kode) != "[ORD-05 ORD-03]" {
+		t.Fatalf("data %v", kode)
+	}
+	if got := PageResponseFromMeta(res.Meta); got != (PageResponse{Total: 3, PageCount: 2, HasNext: true}) {
+		t.Fatalf("PageResponse %+v", got)
+	}
+	if got := PageResponseFromMeta(nil); got != (PageResponse{}) {
+		t.Fatalf("nil meta: %+v", got)
+	}
+}
@@ -0,0 +1,116 @@
+package magicrest
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// HALContentType: media type dokumen HAL
+const HALContentType = "application/hal+json"
+
+// HALList menyusun dokumen HAL dari Result ReadPaginatedSource: item di _embedded[<Options.ResourceType,
+// default nama tabel>] masing-masing dengan _links.self dari Options.SelfLinkTemplate (default
+// "<path request>/{id}"), _links self/first/prev/next/last dari PageLinks, dan isi Meta (pagination, ...)
+// sebagai properti top-level.
+func HALList[T any](db *gorm.DB, res Result[T], u *url.URL, opts Options) (map[string]interface{}, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	template := opts.SelfLinkTemplate
+	if template == "" {
+		template = strings.TrimSuffix(u.Path, "/") + "/{id}"
+	}
+	items := make([]map[string]interface{}, 0, len(res.Data))
+	for i := range res.Data {
+		item, err := halItem(sch, reflect.ValueOf(&res.Data[i]).Elem(), template)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	rel := opts.ResourceType
+	if rel == "" {
+		rel = sch.Table
+	}
+	doc := make(map[string]interface{}, len(res.Meta)+2)
+	for k, v := range res.Meta {
+		doc[k] = v
+	}
+	links := map[string]string{"self": u.String()}
+	if pagination, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+		links = PageLinks(u, pagination)
+	}
+	doc["_links"] = halLinks(links)
+	doc["_embedded"] = map[string]interface{}{rel: items}
+	return doc, nil
+}
+
+// HALItem: satu record sebagai resource HAL dengan _links.self (Options.SelfLinkTemplate, default u.Path)
+func HALItem[T any](db *gorm.DB, item *T, u *url.URL, opts Options) (map[string]interface{}, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	template := opts.SelfLinkTemplate
+	if template == "" {
+		template = u.Path
+	}
+	return halItem(sch, reflect.ValueOf(item).Elem(), template)
+}
+
+// halItem: struct v sebagai objek JSON ditambah _links.self; {id} di template diganti primary key
+func halItem(sch *schema.Schema, v reflect.Value, template string) (map[string]interface{}, error) {
+	obj, err := jsonObject(v.Addr().Interface())
+	if err != nil {
+		return nil, err
+	}
+	if pk, ok := primaryKeyString(sch, v); ok {
+		obj["_links"] = halLinks(map[string]string{"self": strings.ReplaceAll(template, "{id}", url.PathEscape(pk))})
+	}
+	return obj, nil
+}
+
+// halLinks: rel -> href menjadi rel -> {"href": href}
+func halLinks(links map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(links))
+	for rel, href := range links {
+		out[rel] = map[string]string{"href": href}
+	}
+	return out
+}
+
+// HALListHandlerHTTP: ListHandlerHTTP dengan response dokumen HAL (HALList)
+func HALListHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := ReadPaginatedSource[T](r.Context(), FromURLValues(r.URL.Query()), db.Model(new(T)), new(T), opts)
+		if err == nil {
+			var doc map[string]interface{}
+			if doc, err = HALList(db, res, r.URL, opts); err == nil {
+				writeBody(w, http.StatusOK, HALContentType, doc)
+				return
+			}
+		}
+		opts.Envelope.WriteError(w, r, err)
+	}
+}
+
+// HALGetHandlerHTTP: GetHandlerHTTP dengan response resource HAL (HALItem)
+func HALGetHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out, err := ReadOne[T](r.Context(), r.URL.Query(), db, r.PathValue("id"), opts)
+		if err == nil {
+			var doc map[string]interface{}
+			if doc, err = HALItem(db, out, r.URL, opts); err == nil {
+				writeBody(w, http.StatusOK, HALContentType, doc)
+				return
+			}
+		}
+		opts.Envelope.WriteError(w, r, err)
+	}
+}
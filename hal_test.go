@@ -0,0 +1,125 @@
+package magicrest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestHALList(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	cases := []struct {
+		name   string
+		target string
+		opts   Options
+		rel    string
+		self   string // _links.self item pertama
+		links  string // rel _links top-level
+		page   string // href _links.self
+	}{
+		{"default", "/api/orders?pageSize=2", Options{}, "orders", "/api/orders/1", "[first last next self]", "/api/orders?page=1&pageSize=2"},
+		{"last page", "/api/orders?pageSize=2&page=2", Options{}, "orders", "/api/orders/3", "[first last prev self]", "/api/orders?page=2&pageSize=2"},
+		{"template and type", "/api/orders?pageSize=2", Options{ResourceType: "pesanan", SelfLinkTemplate: "/v2/pesanan/{id}"}, "pesanan", "/v2/pesanan/1", "[first last next self]", "/api/orders?page=1&pageSize=2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			u, _ := url.Parse(tc.target)
+			res, err := ReadPaginated(u.Query(), db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := HALList(db, res, u, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := json.Marshal(doc)
+			var got struct {
+				Links      map[string]struct{ Href string } `json:"_links"`
+				Embedded   map[string][]map[string]any      `json:"_embedded"`
+				Pagination map[string]any                   `json:"pagination"`
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			items := got.Embedded[tc.rel]
+			if len(items) == 0 || got.Pagination["total"] != float64(3) {
+				t.Fatalf("doc %s", b)
+			}
+			self, _ := json.Marshal(items[0]["_links"])
+			if string(self) != `{"self":{"href":"`+tc.self+`"}}` || items[0]["kode"] == nil {
+				t.Fatalf("item %v", items[0])
+			}
+			var rels []string
+			for rel := range got.Links {
+				rels = append(rels, rel)
+			}
+			sort.Strings(rels)
+			if fmt.Sprint(rels) != tc.links || got.Links["self"].Href != tc.page {
+				t.Fatalf("links %v", got.Links)
+			}
+		})
+	}
+}
+
+func TestHALItem(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	o, err := ReadOne[Order](context.Background(), url.Values{}, db, 2, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("/api/orders/2?fields=kode")
+	for opts, want := range map[string]Options{"/api/orders/2": {}, "/pesanan/2": {SelfLinkTemplate: "/pesanan/{id}"}} {
+		doc, err := HALItem(db, o, u, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if links, _ := json.Marshal(doc["_links"]); string(links) != `{"self":{"href":"`+opts+`"}}` || doc["kode"] != "ORD-02" {
+			t.Fatalf("doc %v", doc)
+		}
+	}
+}
+
+func TestHALHandlers(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	opts := Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"gudang_id": "int"}}
+	mux := http.NewServeMux()
+	mux.Handle("GET /orders", HALListHandlerHTTP[Order](db, opts))
+	mux.Handle("GET /orders/{id}", HALGetHandlerHTTP[Order](db, opts))
+	mux.Handle("GET /negotiated", ListHandlerHTTP[Order](db, Options{OrderBy: "id", NegotiateContent: true}))
+	cases := []struct {
+		target      string
+		accept      string
+		status      int
+		contentType string
+		want        string
+	}{
+		{"/orders", "", http.StatusOK, HALContentType, `"_embedded":{"orders":[`},
+		{"/orders/2", "", http.StatusOK, HALContentType, `"_links":{"self":{"href":"/orders/2"}}`},
+		{"/orders?filter[gudang_id]=satu", "", http.StatusBadRequest, "application/json", `"code":"invalid_int"`},
+		{"/orders/9", "", http.StatusNotFound, "application/json", `"error"`},
+		{"/negotiated", HALContentType, http.StatusOK, HALContentType, `"_embedded":{"orders":[`},
+		{"/negotiated", JSONAPIContentType, http.StatusOK, JSONAPIContentType, `"data":[{"type":"orders","id":"1"`},
+		{"/negotiated", "application/json", http.StatusOK, "application/json", `"data":[{"id":1`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.target+" "+tc.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header.Set("Accept", tc.accept)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tc.status || !strings.HasPrefix(rec.Header().Get("Content-Type"), tc.contentType) || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, Content-Type %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"gorm.io/gorm"
+)
+
+// Handler net/http untuk satu resource. Id diambil dari r.PathValue("id") (pola "GET /barang/{id}" di
+// http.ServeMux Go 1.22+, adapter lain cukup mengisi r.SetPathValue). ginrest memanggil handler yang
+// sama sehingga perilaku antar framework tidak bisa berbeda.
+
+// Verb: operasi CRUD yang dipasang adapter router (ginrest.RegisterCRUD, chiadapter.Mount)
+type Verb string
+
+const (
+	VerbList   Verb = "list"   // GET /
+	VerbGet    Verb = "get"    // GET /{id}
+	VerbCreate Verb = "create" // POST /
+	VerbUpdate Verb = "update" // PUT /{id}
+	VerbPatch  Verb = "patch"  // PATCH /{id}
+	VerbDelete Verb = "delete" // DELETE /{id}
+)
+
+// AllVerbs: semua verb, urutan pendaftaran route
+var AllVerbs = []Verb{VerbList, VerbGet, VerbCreate, VerbUpdate, VerbPatch, VerbDelete}
+
+// writeJSON menulis body sebagai JSON dengan status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	writeBody(w, status, "application/json; charset=utf-8", body)
+}
+
+// writeBody: writeJSON dengan Content-Type lain (JSON:API, HAL)
+func writeBody(w http.ResponseWriter, status int, contentType string, body interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// decodeBody men-decode body JSON ke out; gagal -> ErrInvalidBody (400)
+func decodeBody(r *http.Request, out interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBody, err)
+	}
+	return nil
+}
+
+// ListHandlerHTTP: GET list untuk model T — query string, ReadPaginatedSource dengan r.Context(),
+// response {"data": [...], "meta": {...}} (Options.Envelope) atau error dengan status dari StatusForError.
+// Dengan Options.ETag header ETag di-set dan If-None-Match yang cocok dijawab 304 tanpa body; dengan
+// Options.LastModified header Last-Modified di-set dan If-Modified-Since yang tidak lebih lama dijawab 304
+// sebelum query halaman dijalankan. Options.StreamAbove > 0 mengalihkan pageSize yang lebih besar ke StreamListHandler.
+// Dengan Options.NegotiateContent, Accept text/csv / application/x-ndjson menulis semua row lewat PrepareExport
+// dan application/hal+json / application/vnd.api+json menulis halaman sebagai dokumen HAL / JSON:API (ListBody).
+// Options.RangeHeader menjawab Range: items=0-49 lewat ReadRange (206 + Content-Range) tanpa ETag.
+// HEAD hanya menjalankan count (CountList) dan menjawab X-Total-Count, X-Page-Count dan Link tanpa body.
+func ListHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			headList[T](w, r, db, opts)
+			return
+		}
+		query := r.URL.Query()
+		lastModified, notModified, err := CheckLastModified[T](r.Context(), query, db, opts,
+			r.Header.Get("If-None-Match"), r.Header.Get("If-Modified-Since"))
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		if !lastModified.IsZero() {
+			w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+		if notModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		format := FormatJSON
+		if opts.NegotiateContent {
+			w.Header().Add("Vary", "Accept")
+			if format = NegotiateFormat(r.Header.Get("Accept")); format.IsExport() {
+				exp, err := PrepareExport[T](r.Context(), FromURLValues(query), db, opts, ExportConfig{})
+				if err != nil {
+					opts.Envelope.WriteError(w, r, err)
+					return
+				}
+				w.Header().Set("Content-Type", format.ContentType())
+				w.WriteHeader(http.StatusOK)
+				_ = exp.Write(w, format)
+				return
+			}
+		}
+		if opts.RangeHeader && format == FormatJSON {
+			w.Header().Set("Accept-Ranges", "items")
+			if start, end, ok := ParseItemsRange(r.Header.Get("Range")); ok {
+				writeRange[T](w, r, db, opts, start, end)
+				return
+			}
+		}
+		if opts.StreamAbove > 0 && format == FormatJSON {
+			if params, err := ParseQuery(query, opts); err == nil && params.PageSize > opts.StreamAbove {
+				StreamListHandler[T](db, opts)(w, r)
+				return
+			}
+		}
+		res, err := ReadPaginatedSource[T](r.Context(), FromURLValues(query), db.Model(new(T)), new(T), opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		if opts.ETag {
+			tag, err := ListETag(db, query, opts, res)
+			if err != nil {
+				opts.Envelope.WriteError(w, r, err)
+				return
+			}
+			w.Header().Set("ETag", tag)
+			if ETagMatches(r.Header.Get("If-None-Match"), tag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		if format == FormatJSON {
+			opts.Envelope.Write(w, http.StatusOK, res.Data, res.Meta)
+			return
+		}
+		body, err := ListBody(db, res, r.URL, opts, format)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		writeBody(w, http.StatusOK, format.ContentType(), body)
+	}
+}
+
+// ListBody: body response halaman list dalam format f — FormatHAL (HALList), FormatJSONAPI (JSONAPIList),
+// selain itu Options.Envelope. u (path + query request) untuk link HAL / JSON:API.
+func ListBody[T any](db *gorm.DB, res Result[T], u *url.URL, opts Options, f Format) (interface{}, error) {
+	switch f {
+	case FormatHAL:
+		return HALList(db, res, u, opts)
+	case FormatJSONAPI:
+		return JSONAPIList(db, res, u, opts)
+	}
+	return opts.Envelope.Wrap(res.Data, res.Meta), nil
+}
+
+// GetHandlerHTTP: GET satu record via ReadOne (id dari path)
+func GetHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out, err := ReadOne[T](r.Context(), r.URL.Query(), db, r.PathValue("id"), opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		opts.Envelope.Write(w, http.StatusOK, out, nil)
+	}
+}
+
+// CreateHandlerHTTP: POST via CreateGeneric, response 201 dengan Location <path request>/<pk>
+func CreateHandlerHTTP[T any](db *gorm.DB, opts WriteOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload := new(T)
+		if err := decodeBody(r, payload); err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		created, err := CreateGeneric(r.Context(), db, payload, opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		location, err := ResourceLocation(r.URL.Path, created)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Location", location)
+		opts.Envelope.Write(w, http.StatusCreated, created, nil)
+	}
+}
+
+// UpdateHandlerHTTP: PUT via UpdateGeneric (full replace)
+func UpdateHandlerHTTP[T any](db *gorm.DB, opts WriteOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload := new(T)
+		if err := decodeBody(r, payload); err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		out, err := UpdateGeneric(r.Context(), db, r.PathValue("id"), payload, opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		opts.Envelope.Write(w, http.StatusOK, out, nil)
+	}
+}
+
+// PatchHandlerHTTP: PATCH via PatchGeneric dari objek JSON
+func PatchHandlerHTTP[T any](db *gorm.DB, opts WriteOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var patch map[string]interface{}
+		if err := decodeBody(r, &patch); err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		out, err := PatchGeneric[T](r.Context(), db, r.PathValue("id"), patch, opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		opts.Envelope.Write(w, http.StatusOK, out, nil)
+	}
+}
+
+// DeleteHandlerHTTP: DELETE via DeleteGeneric, response 204 tanpa body
+func DeleteHandlerHTTP[T any](db *gorm.DB, opts WriteOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := DeleteGeneric[T](r.Context(), db, r.PathValue("id"), opts); err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
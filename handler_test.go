@@ -0,0 +1,66 @@
+package magicrest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandlers(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	mux := http.NewServeMux()
+	opts := Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"gudang_id": "int"}}
+	mux.Handle("GET /orders", ListHandlerHTTP[Order](db, opts))
+	mux.Handle("GET /orders/{id}", GetHandlerHTTP[Order](db, opts))
+	mux.Handle("POST /orders", CreateHandlerHTTP[Order](db, WriteOptions{}))
+	mux.Handle("PUT /orders/{id}", UpdateHandlerHTTP[Order](db, WriteOptions{}))
+	mux.Handle("PATCH /orders/{id}", PatchHandlerHTTP[Order](db, WriteOptions{}))
+	mux.Handle("DELETE /orders/{id}", DeleteHandlerHTTP[Order](db, WriteOptions{}))
+
+	cases := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		lang     string
+		status   int
+		want     string // potongan body
+		location string
+	}{
+		{"list", http.MethodGet, "/orders?filter[status]=aktif", "", "", http.StatusOK, `"meta":{"pagination"`, ""},
+		{"list invalid filter", http.MethodGet, "/orders?filter[gudang_id]=satu", "", "", http.StatusBadRequest, `"error"`, ""},
+		{"get", http.MethodGet, "/orders/2", "", "", http.StatusOK, `"kode":"ORD-02"`, ""},
+		{"get invalid id", http.MethodGet, "/orders/dua", "", "", http.StatusBadRequest, `"error"`, ""},
+		{"get not found", http.MethodGet, "/orders/99", "", "", http.StatusNotFound, `"error"`, ""},
+		{"create", http.MethodPost, "/orders", `{"kode":"ORD-03","status":"aktif"}`, "", http.StatusCreated, `{"data":{"id":3,"kode":"ORD-03"`, "/orders/3"},
+		{"create duplicate", http.MethodPost, "/orders", `{"kode":"ORD-03"}`, "", http.StatusConflict, `"error"`, ""},
+		{"create invalid body", http.MethodPost, "/orders", `[1,2]`, "", http.StatusBadRequest, `"code":"invalid_body"`, ""},
+		{"invalid body localized", http.MethodPost, "/orders", `{`, "id", http.StatusBadRequest, "body request bukan JSON yang valid", ""},
+		{"update", http.MethodPut, "/orders/3", `{"kode":"ORD-03","status":"selesai"}`, "", http.StatusOK, `"status":"selesai"`, ""},
+		{"patch", http.MethodPatch, "/orders/3", `{"telepon":"0813"}`, "", http.StatusOK, `"telepon":"0813"`, ""},
+		{"patch not an object", http.MethodPatch, "/orders/3", `"x"`, "", http.StatusBadRequest, `"code":"invalid_body"`, ""},
+		{"delete", http.MethodDelete, "/orders/3", "", "", http.StatusNoContent, "", ""},
+		{"delete again", http.MethodDelete, "/orders/3", "", "", http.StatusNotFound, `"error"`, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.lang != "" {
+				req.Header.Set("Accept-Language", tc.lang)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tc.status || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, body %s; want %d with %s", rec.Code, rec.Body.String(), tc.status, tc.want)
+			}
+			if got := rec.Header().Get("Location"); got != tc.location {
+				t.Fatalf("Location %q, want %q", got, tc.location)
+			}
+			if tc.status != http.StatusNoContent && !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+				t.Fatalf("Content-Type %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 🔹 Model uji: Order -> Items -> Produk -> Kategori (tiga level) dan Order -> Gudang (belongs-to)
+
+type Kategori struct {
+	ID   uint   `json:"id"`
+	Nama string `json:"nama"`
+}
+
+type Produk struct {
+	ID         uint      `json:"id"`
+	Nama       string    `json:"nama"`
+	KategoriID uint      `json:"kategori_id"`
+	Kategori   *Kategori `json:"kategori,omitempty"`
+}
+
+type Item struct {
+	ID       uint    `json:"id"`
+	OrderID  uint    `json:"order_id"`
+	ProdukID uint    `json:"produk_id"`
+	Produk   *Produk `json:"produk,omitempty"`
+	Nama     string  `json:"nama"`
+	Jumlah   int     `json:"jumlah"`
+	Catatan  string  `json:"catatan"`
+}
+
+type Gudang struct {
+	ID   uint   `json:"id"`
+	Kode string `json:"kode"`
+	Nama string `json:"nama"`
+}
+
+type Order struct {
+	ID        uint           `json:"id"`
+	Kode      string         `json:"kode" gorm:"uniqueIndex"`
+	Status    string         `json:"status"`
+	Telepon   string         `json:"telepon"`
+	GudangID  uint           `json:"gudang_id"`
+	Gudang    *Gudang        `json:"gudang,omitempty"`
+	Items     []Item         `json:"items,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
+
+// newTestDB: SQLite in-memory dengan tabel model uji (satu koneksi agar semua query melihat database yang sama)
+func newTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&Kategori{}, &Produk{}, &Gudang{}, &Order{}, &Item{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedOrders: n order (kode ORD-01, ...) di dua gudang, masing-masing dengan items item
+func seedOrders(t testing.TB, db *gorm.DB, n, items int) []Order {
+	t.Helper()
+	kat := Kategori{Nama: "Bahan"}
+	db.Create(&kat)
+	produk := Produk{Nama: "Semen", KategoriID: kat.ID}
+	db.Create(&produk)
+	gudang := []Gudang{{Kode: "GD-01", Nama: "Pusat"}, {Kode: "GD-02", Nama: "Cabang"}}
+	db.Create(&gudang)
+	orders := make([]Order, n)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range orders {
+		status := "aktif"
+		if i%2 == 1 {
+			status = "selesai"
+		}
+		orders[i] = Order{Kode: fmt.Sprintf("ORD-%02d", i+1), Status: status, Telepon: "0812",
+			GudangID: gudang[i%2].ID, CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+		for j := 0; j < items; j++ {
+			orders[i].Items = append(orders[i].Items, Item{ProdukID: produk.ID, Nama: fmt.Sprintf("item-%d-%d", i+1, j+1), Jumlah: j + 1})
+		}
+	}
+	if err := db.Create(&orders).Error; err != nil {
+		t.Fatal(err)
+	}
+	return orders
+}
+
+// sqlRecorder: logger gorm yang mencatat setiap SQL (dengan nilai) yang dijalankan
+type sqlRecorder struct {
+	logger.Interface
+	mu  sync.Mutex
+	sql []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface { return r }
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.mu.Lock()
+	r.sql = append(r.sql, sql)
+	r.mu.Unlock()
+}
+
+// statements: SQL yang tercatat sejauh ini
+func (r *sqlRecorder) statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.sql...)
+}
+
+// matching: SQL yang mengandung sub
+func (r *sqlRecorder) matching(sub string) []string {
+	var out []string
+	for _, s := range r.statements() {
+		if strings.Contains(s, sub) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// recordSQL: db yang mencatat SQL-nya ke recorder yang dikembalikan
+func recordSQL(db *gorm.DB) (*gorm.DB, *sqlRecorder) {
+	rec := &sqlRecorder{Interface: logger.Discard}
+	return db.Session(&gorm.Session{Logger: rec}), rec
+}
+
+// namedDialector: dialector SQLite yang mengaku dialect lain (kode yang hanya melihat Name: index hint, timeout)
+type namedDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d namedDialector) Name() string { return d.name }
+
+// SavePoint / RollbackTo: transaksi bersarang (SAVEPOINT) diteruskan ke dialector aslinya
+func (d namedDialector) SavePoint(tx *gorm.DB, name string) error {
+	return d.Dialector.(gorm.SavePointerDialectorInterface).SavePoint(tx, name)
+}
+
+func (d namedDialector) RollbackTo(tx *gorm.DB, name string) error {
+	return d.Dialector.(gorm.SavePointerDialectorInterface).RollbackTo(tx, name)
+}
+
+// dryRunDB: DB DryRun dengan dialect name; SQL data dan count dicatat recorder
+func dryRunDB(t *testing.T, name string) (*gorm.DB, *sqlRecorder) {
+	t.Helper()
+	db, err := gorm.Open(namedDialector{Dialector: sqlite.Open("file::memory:"), name: name}, &gorm.Config{Logger: logger.Discard, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recordSQL(db)
+}
@@ -0,0 +1,22 @@
+package magicrest
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BeforeQueryFunc dipanggil setelah Options.Scopes dan sebelum filter dinamis; db yang dikembalikan
+// dipakai untuk sisa query (mis. menambah kondisi otorisasi). Error membatalkan request.
+type BeforeQueryFunc func(ctx context.Context, db *gorm.DB, params QueryParams) (*gorm.DB, error)
+
+// AfterQueryFunc dipanggil setelah Result lengkap (data, preload, meta). result adalah *Result[T]
+// (type-erased karena Options tidak generic). Error dikembalikan bersama Result yang sudah terisi.
+type AfterQueryFunc func(ctx context.Context, params QueryParams, result any, elapsed time.Duration) error
+
+// TransformItemFunc mengubah item ke-i (pointer ke T) di tempat, mis. format mata uang.
+type TransformItemFunc func(i int, item any)
+
+// TransformResultFunc memperkaya Meta sebelum Result dikembalikan.
+type TransformResultFunc func(meta map[string]interface{})
@@ -0,0 +1,123 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestReadPaginatedHooks(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 0)
+	ctx := withRole("kasir")
+	var before, after int
+	opts := Options{
+		OrderBy: "id",
+		BeforeQuery: func(hctx context.Context, db *gorm.DB, params QueryParams) (*gorm.DB, error) {
+			before++
+			if hctx.Value(roleKey{}) != "kasir" {
+				t.Errorf("BeforeQuery context without request values")
+			}
+			if len(params.Filters) != 1 || params.Filters[0].Field != "status" || params.Page != 1 {
+				t.Errorf("BeforeQuery params = %+v", params)
+			}
+			// kondisi otorisasi ikut membatasi data dan count
+			return db.Where("gudang_id = ?", 1), nil
+		},
+		AfterQuery: func(hctx context.Context, params QueryParams, result any, elapsed time.Duration) error {
+			after++
+			res, ok := result.(*Result[Order])
+			if !ok {
+				t.Fatalf("AfterQuery result %T, want *Result[Order]", result)
+			}
+			if len(res.Data) != 2 || elapsed <= 0 || params.Filters[0].Field != "status" {
+				t.Errorf("AfterQuery: %d rows, elapsed %v, params %+v", len(res.Data), elapsed, params)
+			}
+			res.Meta["audited"] = true
+			return nil
+		},
+	}
+	res, err := ReadPaginatedCtx(ctx, url.Values{"filter[status]": {"aktif"}}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != 1 || after != 1 {
+		t.Fatalf("BeforeQuery %d, AfterQuery %d calls, want 1 each", before, after)
+	}
+	if res.Meta["audited"] != true {
+		t.Fatalf("AfterQuery change lost: %v", res.Meta)
+	}
+	if total := res.Meta["pagination"].(map[string]interface{})["total"].(int64); total != 2 {
+		t.Fatalf("total = %d, want 2 (BeforeQuery condition in count)", total)
+	}
+}
+
+func TestReadPaginatedHookErrors(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	errDenied := errors.New("denied")
+
+	rdb, rec := recordSQL(db)
+	afterCalled := false
+	_, err := ReadPaginated(url.Values{}, rdb.Model(&Order{}), &Order{}, Options{
+		BeforeQuery: func(context.Context, *gorm.DB, QueryParams) (*gorm.DB, error) { return nil, errDenied },
+		AfterQuery:  func(context.Context, QueryParams, any, time.Duration) error { afterCalled = true; return nil },
+	})
+	if !errors.Is(err, errDenied) {
+		t.Fatalf("err = %v, want BeforeQuery error", err)
+	}
+	if len(rec.statements()) > 0 || afterCalled {
+		t.Fatalf("request continued after BeforeQuery error: SQL %v, AfterQuery %v", rec.statements(), afterCalled)
+	}
+
+	// error AfterQuery dikembalikan bersama Data yang sudah terisi
+	res, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, Options{
+		AfterQuery: func(context.Context, QueryParams, any, time.Duration) error { return errDenied },
+	})
+	if !errors.Is(err, errDenied) || len(res.Data) != 2 {
+		t.Fatalf("err = %v, %d rows: want AfterQuery error with data", err, len(res.Data))
+	}
+}
+
+func TestReadPaginatedTransforms(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 2)
+	var indexes []int
+	opts := Options{
+		OrderBy:       "id",
+		PreloadFields: []string{"Items"},
+		TransformItem: func(i int, item any) {
+			o := item.(*Order)
+			indexes = append(indexes, i)
+			// dipanggil setelah preload: child sudah ada
+			o.Kode = fmt.Sprintf("%s/%d", o.Kode, len(o.Items))
+		},
+		TransformResult: func(meta map[string]interface{}) {
+			p := meta["pagination"].(map[string]interface{})
+			meta["server_time"] = "2024-01-01T00:00:00Z"
+			meta["sisa"] = p["total"].(int64) - int64(p["pageSize"].(int))
+		},
+	}
+	res, err := ReadPaginated(url.Values{"pageSize": {"2"}}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(indexes) != "[0 1]" || res.Data[0].Kode != "ORD-01/2" || res.Data[1].Kode != "ORD-02/2" {
+		t.Fatalf("indexes %v, data %v", indexes, res.Data)
+	}
+	if res.Meta["server_time"] != "2024-01-01T00:00:00Z" || res.Meta["sisa"] != int64(1) {
+		t.Fatalf("meta %v", res.Meta)
+	}
+
+	// error sebelum query: hook tidak dipanggil
+	indexes = nil
+	opts.TransformResult = func(map[string]interface{}) { t.Fatal("TransformResult called on error") }
+	if _, err := ReadPaginated(url.Values{"preload": {"Itemz"}}, db.Model(&Order{}), &Order{}, opts); err == nil || indexes != nil {
+		t.Fatalf("err = %v, TransformItem calls %v", err, indexes)
+	}
+}
@@ -0,0 +1,223 @@
+package magicrest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 🔹 Katalog pesan error per locale, key = kode stabil ValidationDetail.Code.
+// Placeholder {field}, {value} dan {param} diisi dari ValidationDetail.
+var (
+	messagesMu    sync.RWMutex
+	defaultLocale = "en"
+	messages      = map[string]map[string]string{
+		"en": {
+			"invalid_int":          "{field} must be an integer, got {value}",
+			"invalid_uuid":         "{field} must be a UUID, got {value}",
+			"invalid_bool":         "{field} must be true or false, got {value}",
+			"invalid_datetime":     "{field} must be a date-time (RFC3339), got {value}",
+			"invalid_date":         "{field} must be a date (YYYY-MM-DD), got {value}",
+			"invalid_filter":       "{field} cannot be filtered",
+			"unknown_filter_field": "{field} is not a known field",
+			"invalid_field_name":   "{field} is not a valid field name",
+			"invalid_order":        "cannot sort by {value}",
+			"invalid_field":        "field {value} is unknown or not allowed",
+			"invalid_preload":      "invalid preload",
+			"preload_not_allowed":  "preload is not allowed",
+			"too_many_preloads":    "too many preloads",
+			"preload_too_deep":     "preload is nested too deeply",
+			"invalid_with_count":   "invalid with_count relation",
+			"page_out_of_range":    "page {value} is out of range",
+			"page_size_too_large":  "page size {value} is too large",
+			"unsupported_dialect":  "this feature is not supported by the database",
+			"invalid_cursor":       "cursor is invalid",
+			"conflict":             "a record with the same {value} already exists",
+			"not_found":            "record not found",
+			"invalid_patch":        "invalid value for {field}",
+			"unknown_field":        "{field} is not a known field",
+			"protected_field":      "{field} cannot be changed",
+			"validation_failed":    "{field} is invalid: {value}",
+			"invalid":              "{field} is invalid",
+			"required":             "{field} is required",
+			"min":                  "{field} must be at least {param}",
+			"max":                  "{field} must be at most {param}",
+			"len":                  "{field} must be exactly {param}",
+			"gte":                  "{field} must be at least {param}",
+			"lte":                  "{field} must be at most {param}",
+			"gt":                   "{field} must be greater than {param}",
+			"lt":                   "{field} must be less than {param}",
+			"oneof":                "{field} must be one of {param}",
+			"email":                "{field} must be a valid email address",
+			"url":                  "{field} must be a valid URL",
+			"uuid":                 "{field} must be a UUID",
+			"numeric":              "{field} must be numeric",
+			"missing_conditions":   "at least one filter is required",
+			"too_many_affected":    "too many rows match ({value})",
+			"not_deleted":          "record is not deleted",
+			"invalid_id":           "invalid id {value}",
+			"multiple_results":     "more than one record matches",
+			"stale_record":         "record was modified by someone else, reload and try again",
+			"missing_actor":        "an authenticated user is required",
+			"invalid_body":         "request body is not valid JSON",
+			"export_too_large":     "too many rows to export ({value})",
+			"too_many_rows":        "too many rows to load at once ({value})",
+			"invalid_range":        "requested range is out of bounds ({value})",
+			"query_timeout":        "the query took too long, narrow the filters and try again",
+		},
+		"id": {
+			"invalid_int":          "{field} harus berupa bilangan bulat, bukan {value}",
+			"invalid_uuid":         "{field} harus berupa UUID, bukan {value}",
+			"invalid_bool":         "{field} harus bernilai true atau false, bukan {value}",
+			"invalid_datetime":     "{field} harus berupa tanggal dan waktu (RFC3339), bukan {value}",
+			"invalid_date":         "{field} harus berupa tanggal (YYYY-MM-DD), bukan {value}",
+			"invalid_filter":       "{field} tidak dapat difilter",
+			"unknown_filter_field": "{field} bukan kolom yang dikenal",
+			"invalid_field_name":   "{field} bukan nama kolom yang valid",
+			"invalid_order":        "tidak dapat mengurutkan berdasarkan {value}",
+			"invalid_field":        "kolom {value} tidak dikenal atau tidak diizinkan",
+			"invalid_preload":      "preload tidak valid",
+			"preload_not_allowed":  "preload tidak diizinkan",
+			"too_many_preloads":    "terlalu banyak preload",
+			"preload_too_deep":     "preload terlalu dalam",
+			"invalid_with_count":   "relasi with_count tidak valid",
+			"page_out_of_range":    "halaman {value} di luar jangkauan",
+			"page_size_too_large":  "ukuran halaman {value} terlalu besar",
+			"unsupported_dialect":  "fitur ini tidak didukung oleh database",
+			"invalid_cursor":       "cursor tidak valid",
+			"conflict":             "data dengan {value} yang sama sudah ada",
+			"not_found":            "data tidak ditemukan",
+			"invalid_patch":        "nilai {field} tidak valid",
+			"unknown_field":        "{field} bukan kolom yang dikenal",
+			"protected_field":      "{field} tidak boleh diubah",
+			"validation_failed":    "{field} tidak valid: {value}",
+			"invalid":              "{field} tidak valid",
+			"required":             "{field} wajib diisi",
+			"min":                  "{field} minimal {param}",
+			"max":                  "{field} maksimal {param}",
+			"len":                  "{field} harus tepat {param}",
+			"gte":                  "{field} minimal {param}",
+			"lte":                  "{field} maksimal {param}",
+			"gt":                   "{field} harus lebih dari {param}",
+			"lt":                   "{field} harus kurang dari {param}",
+			"oneof":                "{field} harus salah satu dari {param}",
+			"email":                "{field} harus berupa alamat email yang valid",
+			"url":                  "{field} harus berupa URL yang valid",
+			"uuid":                 "{field} harus berupa UUID",
+			"numeric":              "{field} harus berupa angka",
+			"missing_conditions":   "minimal satu filter wajib diisi",
+			"too_many_affected":    "terlalu banyak data yang cocok ({value})",
+			"not_deleted":          "data tidak sedang dihapus",
+			"invalid_id":           "id {value} tidak valid",
+			"multiple_results":     "lebih dari satu data yang cocok",
+			"stale_record":         "data sudah diubah pengguna lain, muat ulang lalu coba lagi",
+			"missing_actor":        "pengguna harus login",
+			"invalid_body":         "body request bukan JSON yang valid",
+			"export_too_large":     "terlalu banyak data untuk diekspor ({value})",
+			"too_many_rows":        "terlalu banyak data untuk dimuat sekaligus ({value})",
+			"invalid_range":        "range di luar jumlah data ({value})",
+			"query_timeout":        "query terlalu lama, persempit filter lalu coba lagi",
+		},
+	}
+)
+
+// SetLocale mengatur locale default untuk DetailsFromError / WriteError (default "en").
+func SetLocale(locale string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	defaultLocale = normalizeLocale(locale)
+}
+
+// RegisterMessages menambah atau menimpa pesan untuk locale, key = kode error (e.g. "invalid_int").
+// Pesan yang tidak ada di locale tersebut tetap jatuh ke bahasa Inggris.
+func RegisterMessages(locale string, msgs map[string]string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	locale = normalizeLocale(locale)
+	if messages[locale] == nil {
+		messages[locale] = map[string]string{}
+	}
+	for code, msg := range msgs {
+		messages[locale][code] = msg
+	}
+}
+
+// normalizeLocale: "id-ID" / "id_ID" / "ID" -> "id"
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// hasLocale: ada katalog untuk locale (sudah dinormalisasi)
+func hasLocale(locale string) bool {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	return messages[locale] != nil
+}
+
+// LocaleFromRequest memilih locale dari ?lang= lalu header Accept-Language (berdasarkan q).
+// Kosong bila tidak ada yang punya katalog (= locale default).
+func LocaleFromRequest(r *http.Request) string {
+	return LocaleFrom(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+}
+
+// LocaleFrom: LocaleFromRequest untuk framework tanpa *http.Request (nilai ?lang= dan header Accept-Language)
+func LocaleFrom(lang, acceptLanguage string) string {
+	if l := normalizeLocale(lang); l != "" && hasLocale(l) {
+		return l
+	}
+	return LocaleFromAcceptLanguage(acceptLanguage)
+}
+
+// LocaleFromAcceptLanguage: "id-ID,id;q=0.9,en;q=0.8" -> "id". Kosong bila tidak ada katalog yang cocok.
+func LocaleFromAcceptLanguage(header string) string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		l := lang{tag: normalizeLocale(fields[0]), q: 1}
+		for _, f := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(f), "q="); ok {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					l.q = q
+				}
+			}
+		}
+		if l.tag != "" && l.q > 0 {
+			langs = append(langs, l)
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	for _, l := range langs {
+		if hasLocale(l.tag) {
+			return l.tag
+		}
+	}
+	return ""
+}
+
+// renderMessage merender pesan untuk d.Code dari katalog locale -> "en" -> fallback.
+func renderMessage(locale string, d ValidationDetail, fallback string) string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		locale = defaultLocale
+	}
+	tmpl, ok := messages[locale][d.Code]
+	if !ok {
+		if tmpl, ok = messages["en"][d.Code]; !ok {
+			return fallback
+		}
+	}
+	msg := strings.NewReplacer("{field}", d.Field, "{value}", d.Value, "{param}", d.Param).Replace(tmpl)
+	return strings.Join(strings.Fields(msg), " ")
+}
@@ -0,0 +1,88 @@
+package magicrest
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func invalidJumlah(t *testing.T) error {
+	t.Helper()
+	_, err := ParseQuery(url.Values{"filter[jumlah]": {"abc"}}, Options{DefaultFieldTypes: map[string]string{"jumlah": "int"}})
+	if err == nil {
+		t.Fatal("no error")
+	}
+	return err
+}
+
+func TestDetailsFromErrorLocale(t *testing.T) {
+	err := invalidJumlah(t)
+	cases := []struct {
+		locale string
+		want   string
+	}{
+		{"", "jumlah must be an integer, got abc"},
+		{"en", "jumlah must be an integer, got abc"},
+		{"id", "jumlah harus berupa bilangan bulat, bukan abc"},
+		{"id-ID", "jumlah harus berupa bilangan bulat, bukan abc"},
+		{"fr", "jumlah must be an integer, got abc"},
+	}
+	for _, tc := range cases {
+		if d := DetailsFromErrorLocale(err, tc.locale); len(d) != 1 || d[0].Message != tc.want {
+			t.Fatalf("locale %q: %+v, want %q", tc.locale, d, tc.want)
+		}
+	}
+
+	SetLocale("id_ID")
+	t.Cleanup(func() { SetLocale("en") })
+	if d := DetailsFromError(err); d[0].Message != "jumlah harus berupa bilangan bulat, bukan abc" {
+		t.Fatalf("default locale id: %q", d[0].Message)
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	err := invalidJumlah(t)
+	old := messages["id"]["invalid_int"]
+	t.Cleanup(func() {
+		RegisterMessages("id", map[string]string{"invalid_int": old})
+		messagesMu.Lock()
+		delete(messages, "jv")
+		messagesMu.Unlock()
+	})
+
+	RegisterMessages("id", map[string]string{"invalid_int": "{field} wajib angka ({value})"})
+	RegisterMessages("jv", map[string]string{"invalid_uuid": "{field} kudu UUID"})
+	if d := DetailsFromErrorLocale(err, "id"); d[0].Message != "jumlah wajib angka (abc)" {
+		t.Fatalf("override: %q", d[0].Message)
+	}
+	// kode yang tidak ada di katalog baru jatuh ke bahasa Inggris
+	if d := DetailsFromErrorLocale(err, "jv"); d[0].Message != "jumlah must be an integer, got abc" {
+		t.Fatalf("fallback: %q", d[0].Message)
+	}
+	if LocaleFromAcceptLanguage("jv-ID") != "jv" {
+		t.Fatal("registered locale not selectable")
+	}
+}
+
+func TestLocaleFromRequest(t *testing.T) {
+	cases := []struct {
+		target string
+		accept string
+		want   string
+	}{
+		{"/orders", "", ""},
+		{"/orders", "id-ID,id;q=0.9,en;q=0.8", "id"},
+		{"/orders", "fr-FR,en;q=0.5,id;q=0.7", "id"},
+		{"/orders", "fr, de;q=0.5", ""},
+		{"/orders", "id;q=0, en", "en"},
+		{"/orders?lang=id", "en", "id"},
+		{"/orders?lang=fr", "id", "id"},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		r.Header.Set("Accept-Language", tc.accept)
+		if got := LocaleFromRequest(r); got != tc.want {
+			t.Fatalf("%s with %q: %q, want %q", tc.target, tc.accept, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package magicrest
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// indexHint menulis Options.UseIndex tepat setelah nama tabel di FROM (sebelum JOIN), hanya pada SELECT dan
+// dialect yang mendukungnya: MySQL "USE INDEX (...)", SQL Server "WITH (INDEX(...))". Dialect lain (Postgres,
+// SQLite) tidak disentuh sama sekali; DELETE/UPDATE dari BuildQuery tidak diberi hint.
+type indexHint struct {
+	names []string
+}
+
+// Build: hint ditulis oleh buildFrom (db.Clauses memanggil ModifyStatement)
+func (h indexHint) Build(clause.Builder) {}
+
+func (h indexHint) ModifyStatement(stmt *gorm.Statement) {
+	if name := stmt.Dialector.Name(); name != "mysql" && name != "sqlserver" {
+		return
+	}
+	c := stmt.Clauses["FROM"]
+	c.Name = "FROM"
+	c.Builder = h.buildFrom
+	if c.Expression == nil {
+		c.Expression = clause.From{}
+	}
+	stmt.Clauses["FROM"] = c
+}
+
+// buildFrom: sama dengan clause.From.Build, dengan hint di antara tabel dan JOIN
+func (h indexHint) buildFrom(c clause.Clause, builder clause.Builder) {
+	from, _ := c.Expression.(clause.From)
+	var verb string
+	if stmt, ok := builder.(*gorm.Statement); ok && len(stmt.BuildClauses) > 0 {
+		verb = stmt.BuildClauses[0]
+	}
+	if verb == "UPDATE" && len(from.Tables) == 0 && len(from.Joins) == 0 {
+		return // UPDATE ... FROM hanya bila caller memang menambah tabel/join
+	}
+	builder.WriteString("FROM ")
+	if len(from.Tables) > 0 {
+		for i, table := range from.Tables {
+			if i > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(table)
+		}
+	} else {
+		builder.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+	}
+	if verb == "SELECT" {
+		switch builder.(*gorm.Statement).Dialector.Name() {
+		case "mysql":
+			builder.WriteString(" USE INDEX (")
+			h.writeNames(builder)
+			builder.WriteByte(')')
+		case "sqlserver":
+			builder.WriteString(" WITH (INDEX(")
+			h.writeNames(builder)
+			builder.WriteString("))")
+		}
+	}
+	for _, join := range from.Joins {
+		builder.WriteByte(' ')
+		join.Build(builder)
+	}
+}
+
+func (h indexHint) writeNames(builder clause.Builder) {
+	for i, name := range h.names {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(name)
+	}
+}
@@ -0,0 +1,58 @@
+package magicrest
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestReadPaginatedUseIndex(t *testing.T) {
+	cases := []struct {
+		dialect string
+		query   url.Values
+		want    string // potongan SQL data dan count ("" = tanpa hint)
+	}{
+		{"mysql", url.Values{}, "FROM `orders` USE INDEX (`idx_status`,`idx_created`) WHERE"},
+		{"mysql", url.Values{"filter[Gudang.kode]": {"GD-01"}}, "FROM `orders` USE INDEX (`idx_status`,`idx_created`) LEFT JOIN `gudangs`"},
+		{"sqlserver", url.Values{}, "FROM `orders` WITH (INDEX(`idx_status`,`idx_created`)) WHERE"},
+		{"postgres", url.Values{}, ""},
+		{"sqlite", url.Values{}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.dialect+" "+tc.query.Encode(), func(t *testing.T) {
+			db, rec := dryRunDB(t, tc.dialect)
+			opts := Options{OrderBy: "id", UseIndex: []string{"idx_status", "idx_created"}}
+			if _, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, opts); err != nil {
+				t.Fatal(err)
+			}
+			stmts := rec.statements()
+			if len(stmts) != 2 || !strings.Contains(stmts[0], "count(*)") {
+				t.Fatalf("statements %v, want count and data", stmts)
+			}
+			for _, sql := range stmts {
+				if tc.want == "" {
+					if strings.Contains(sql, "INDEX") {
+						t.Fatalf("hint on %s: %s", tc.dialect, sql)
+					}
+				} else if !strings.Contains(sql, tc.want) {
+					t.Fatalf("SQL %s\nwant %q", sql, tc.want)
+				}
+			}
+		})
+	}
+}
+
+// DELETE dari BuildQuery (BulkDeleteByQuery) tidak boleh ikut diberi hint
+func TestBuildQueryUseIndexSelectOnly(t *testing.T) {
+	db, _ := dryRunDB(t, "mysql")
+	q, _, err := BuildQuery[Order](url.Values{"filter[status]": {"aktif"}}, db.Model(&Order{}), &Order{}, Options{UseIndex: []string{"idx_status"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := q.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Order{}).Statement.SQL.String()
+	if strings.Contains(sql, "INDEX") || !strings.HasPrefix(sql, "UPDATE `orders`") && !strings.HasPrefix(sql, "DELETE FROM `orders`") {
+		t.Fatalf("SQL %s", sql)
+	}
+}
@@ -0,0 +1,177 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// InvalidateScope: cakupan invalidasi cache setelah write (WriteOptions.InvalidateScope)
+type InvalidateScope int
+
+const (
+	// InvalidateResource: semua entry resource, prefix CacheKeyPrefix ("<pkg>.Barang:") — list ikut bersih (default)
+	InvalidateResource InvalidateScope = iota
+	// InvalidateRows: hanya key row yang berubah (RowCacheKey), untuk cache per item. Entry list tetap sampai
+	// TTL; write tanpa id (bulk by query) tetap satu resource.
+	InvalidateRows
+)
+
+// Invalidation: yang harus dihapus dari cache setelah write model Resource
+type Invalidation struct {
+	Resource string          // nama model, e.g. "Barang"
+	Scope    InvalidateScope // cakupan yang dipakai (InvalidateRows tanpa id = InvalidateResource)
+	Keys     []string        // prefix key cache: CacheKeyPrefix atau RowCacheKey per row
+}
+
+// Invalidator dipanggil helper tulis (CreateGeneric, UpdateGeneric, PatchGeneric, DeleteGeneric, Bulk*, ...)
+// setelah write berhasil di-commit (WriteOptions.Invalidator)
+type Invalidator interface {
+	Invalidate(ctx context.Context, inv Invalidation) error
+}
+
+// InvalidatorFunc: fungsi sebagai Invalidator
+type InvalidatorFunc func(ctx context.Context, inv Invalidation) error
+
+func (f InvalidatorFunc) Invalidate(ctx context.Context, inv Invalidation) error { return f(ctx, inv) }
+
+// CacheInvalidator: Invalidator yang menghapus setiap Invalidation.Keys dari cache lewat InvalidatePrefix
+// (MemoryCache, rediscache.Cache)
+func CacheInvalidator(cache ResultCache) Invalidator {
+	return InvalidatorFunc(func(ctx context.Context, inv Invalidation) error {
+		for _, key := range inv.Keys {
+			if err := InvalidatePrefix(ctx, cache, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RowCacheKey: awalan key cache satu row model T ("<nama model>:#<id>:"), yang dihapus InvalidateRows.
+// Berawalan CacheKeyPrefix, jadi InvalidateResource ikut menghapusnya.
+func RowCacheKey[T any](id any) string {
+	return CacheKeyPrefix[T]() + "#" + fmt.Sprint(id) + ":"
+}
+
+// pendingKey: key context Transact untuk invalidasi yang menunggu commit
+type pendingKey struct{}
+
+// pendingByTx: invalidasi yang menunggu commit per transaksi Transact, dengan key Statement.ConnPool (*sql.Tx)
+// yang sama untuk semua *gorm.DB turunan tx.DB (Session, WithContext, SAVEPOINT). Helper yang dipanggil dengan
+// tx.DB tetapi ctx lain (e.g. ctx request) tetap ikut menunggu commit.
+var pendingByTx sync.Map
+
+// txPoolKey: ConnPool db sebagai key pendingByTx (hanya pointer, selain itu tidak bisa dipakai sebagai key)
+func txPoolKey(db *gorm.DB) (any, bool) {
+	if db == nil || db.Statement == nil || db.Statement.ConnPool == nil {
+		return nil, false
+	}
+	pool := db.Statement.ConnPool
+	return pool, reflect.ValueOf(pool).Kind() == reflect.Pointer
+}
+
+// pendingFor: antrean invalidasi Transact untuk write ini — dari ctx (tx.Context()) atau dari transaksi db
+func pendingFor(ctx context.Context, db *gorm.DB) (*pendingInvalidations, bool) {
+	if p, ok := ctx.Value(pendingKey{}).(*pendingInvalidations); ok {
+		return p, true
+	}
+	if key, ok := txPoolKey(db); ok {
+		if p, ok := pendingByTx.Load(key); ok {
+			return p.(*pendingInvalidations), true
+		}
+	}
+	return nil, false
+}
+
+// pendingInvalidations: invalidasi dari helper tulis di dalam Transact, dijalankan setelah commit
+type pendingInvalidations struct {
+	mu    sync.Mutex
+	items []pendingInvalidation
+}
+
+type pendingInvalidation struct {
+	opts WriteOptions
+	inv  Invalidation
+}
+
+func (p *pendingInvalidations) add(opts WriteOptions, inv Invalidation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.items = append(p.items, pendingInvalidation{opts: opts, inv: inv})
+}
+
+// run menjalankan semua invalidasi yang tertunda (setelah commit)
+func (p *pendingInvalidations) run(ctx context.Context) {
+	p.mu.Lock()
+	items := p.items
+	p.items = nil
+	p.mu.Unlock()
+	for _, it := range items {
+		runInvalidation(ctx, it.opts, it.inv)
+	}
+}
+
+// invalidateItems: invalidasi setelah write items model T (id dari primary key bila InvalidateRows)
+func invalidateItems[T any](ctx context.Context, db *gorm.DB, opts WriteOptions, items []T) {
+	if opts.Invalidator == nil {
+		return
+	}
+	var ids []any
+	if opts.InvalidateScope == InvalidateRows {
+		if sch, err := parseSchema(db, new(T)); err == nil && sch.PrioritizedPrimaryField != nil {
+			for i := range items {
+				if v, zero := sch.PrioritizedPrimaryField.ValueOf(ctx, reflect.ValueOf(&items[i]).Elem()); !zero {
+					ids = append(ids, v)
+				}
+			}
+		}
+	}
+	invalidateWrite[T](ctx, db, opts, ids...)
+}
+
+// invalidateWrite: invalidasi setelah write model T lewat db pada row ids (kosong = seluruh resource). Di dalam
+// Transact (ctx dari tx.Context() atau db dari tx.DB) ditunda sampai commit (rollback = tidak dijalankan),
+// selain itu langsung.
+func invalidateWrite[T any](ctx context.Context, db *gorm.DB, opts WriteOptions, ids ...any) {
+	if opts.Invalidator == nil {
+		return
+	}
+	inv := Invalidation{Resource: modelName[T](), Scope: InvalidateResource}
+	if opts.InvalidateScope == InvalidateRows && len(ids) > 0 {
+		inv.Scope = InvalidateRows
+		for _, id := range ids {
+			inv.Keys = append(inv.Keys, RowCacheKey[T](id))
+		}
+	} else {
+		inv.Keys = []string{CacheKeyPrefix[T]()}
+	}
+	if p, ok := pendingFor(ctx, db); ok {
+		p.add(opts, inv)
+		return
+	}
+	runInvalidation(ctx, opts, inv)
+}
+
+// runInvalidation memanggil Invalidator; error tidak menggagalkan write, hanya dilaporkan ke
+// WriteOptions.OnInvalidateError atau, tanpa itu, sebagai error di WriteOptions.Logger (nil = slog.Default())
+func runInvalidation(ctx context.Context, opts WriteOptions, inv Invalidation) {
+	err := opts.Invalidator.Invalidate(ctx, inv)
+	if err == nil {
+		return
+	}
+	err = fmt.Errorf("magicrest: invalidate %s cache: %w", inv.Resource, err)
+	if opts.OnInvalidateError != nil {
+		opts.OnInvalidateError(ctx, err)
+		return
+	}
+	l := opts.Logger
+	if l == nil {
+		l = slog.Default()
+	}
+	l.ErrorContext(ctx, "magicrest: cache invalidation failed", "model", inv.Resource, "keys", inv.Keys, "error", err)
+}
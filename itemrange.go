@@ -0,0 +1,114 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrRangeNotSatisfiable: awal Range: items=... melewati jumlah row (ReadRange)
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// itemRange: jendela item inklusif dari header Range (end < 0 = sampai pageSize)
+type itemRange struct {
+	start, end int
+}
+
+// ParseItemsRange membaca header `Range: items=0-49` (inklusif, e.g. 0 dan 49). `items=50-` memberi end -1
+// (satu halaman pageSize). ok false untuk unit lain, beberapa range atau sintaks tidak valid — header
+// seperti itu diabaikan dan pagination query string berlaku.
+func ParseItemsRange(header string) (start, end int, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "items=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	from, to, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.Atoi(from)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	if to == "" {
+		return start, -1, true
+	}
+	end, err = strconv.Atoi(to)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// ReadRange: ReadPaginatedSource dengan jendela item start..end (inklusif, end < 0 = start + pageSize - 1)
+// alih-alih page/pageSize, dibatasi Options.MaxPageSize. Meta["pagination"] berisi offset, count, total,
+// hasNext dan hasPrev. start >= total -> ErrRangeNotSatisfiable (Meta tetap berisi total).
+func ReadRange[T any](ctx context.Context, query QuerySource, db *gorm.DB, opts Options, start, end int) (Result[T], error) {
+	return readList[T](ctx, query, db.Model(new(T)), new(T), opts, &itemRange{start: start, end: end})
+}
+
+// rangeGeneric: count lalu Limit/Offset sesuai rng (padanan PaginateGenericCtx)
+func rangeGeneric[T any](ctx context.Context, db *gorm.DB, rng itemRange, pageSize, maxPageSize, hard int, slow *slowQuery) ([]T, map[string]interface{}, error) {
+	db = db.WithContext(ctx)
+	var total int64
+	if err := slow.run(QueryLabelCount, countQuery(db), func(tx *gorm.DB) *gorm.DB { return tx.Count(&total) }); err != nil {
+		return nil, nil, err
+	}
+	pagination := map[string]interface{}{"offset": rng.start, "count": 0, "total": total, "hasNext": false, "hasPrev": rng.start > 0}
+	if int64(rng.start) >= total && (rng.start > 0 || total > 0) {
+		return nil, pagination, fmt.Errorf("%w: start %d, total %d", ErrRangeNotSatisfiable, rng.start, total)
+	}
+	limit := pageSize
+	if rng.end >= 0 {
+		limit = rng.end - rng.start + 1
+	}
+	if maxPageSize > 0 && limit > maxPageSize {
+		limit = maxPageSize
+	}
+	out := []T{}
+	if total > 0 {
+		if err := slow.run(QueryLabelData, db.Limit(cappedLimit(limit, hard)).Offset(rng.start), func(tx *gorm.DB) *gorm.DB { return tx.Find(&out) }); err != nil {
+			return nil, nil, err
+		}
+		if err := checkRowLimit(len(out), hard); err != nil {
+			return nil, pagination, err
+		}
+	}
+	pagination["count"] = len(out)
+	pagination["hasNext"] = int64(rng.start+len(out)) < total
+	return out, pagination, nil
+}
+
+// ContentRange: header Content-Range dari Meta["pagination"] ReadRange, e.g. "items 0-49/1234";
+// "items */1234" bila tidak ada item (juga untuk respon 416).
+func ContentRange(pagination map[string]interface{}) string {
+	offset, _ := pagination["offset"].(int)
+	count, _ := pagination["count"].(int)
+	total := fmt.Sprint(pagination["total"])
+	if count == 0 {
+		return "items */" + total
+	}
+	return fmt.Sprintf("items %d-%d/%s", offset, offset+count-1, total)
+}
+
+// writeRange: respon list untuk header Range — 206 + Content-Range, 416 bila awal range melewati total
+func writeRange[T any](w http.ResponseWriter, r *http.Request, db *gorm.DB, opts Options, start, end int) {
+	res, err := ReadRange[T](r.Context(), FromURLValues(r.URL.Query()), db, opts, start, end)
+	if pagination, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+		w.Header().Set("Content-Range", ContentRange(pagination))
+	}
+	if err != nil {
+		opts.Envelope.WriteError(w, r, err)
+		return
+	}
+	status := http.StatusPartialContent
+	if len(res.Data) == 0 {
+		status = http.StatusOK
+	}
+	opts.Envelope.Write(w, status, res.Data, res.Meta)
+}
@@ -0,0 +1,132 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseItemsRange(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string // "start-end", "" = ok false
+	}{
+		{"items=0-49", "0-49"},
+		{" items= 10-10 ", "10-10"},
+		{"items=50-", "50--1"},
+		{"bytes=0-49", ""},
+		{"items=0-9,20-29", ""},
+		{"items=9-0", ""},
+		{"items=-5", ""},
+		{"items=a-b", ""},
+		{"items=5", ""},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		start, end, ok := ParseItemsRange(tc.header)
+		got := ""
+		if ok {
+			got = fmt.Sprintf("%d-%d", start, end)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseItemsRange(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	cases := []struct {
+		name       string
+		start, end int
+		opts       Options
+		want       string // kode
+		pagination string
+		err        error
+	}{
+		{"window", 1, 2, Options{}, "[ORD-02 ORD-03]", "map[count:2 hasNext:true hasPrev:true offset:1 total:5]", nil},
+		{"open end uses pageSize", 3, -1, Options{}, "[ORD-04 ORD-05]", "map[count:2 hasNext:false hasPrev:true offset:3 total:5]", nil},
+		{"end past total", 0, 99, Options{}, "[ORD-01 ORD-02 ORD-03 ORD-04 ORD-05]", "map[count:5 hasNext:false hasPrev:false offset:0 total:5]", nil},
+		{"capped by MaxPageSize", 0, 99, Options{MaxPageSize: 2}, "[ORD-01 ORD-02]", "map[count:2 hasNext:true hasPrev:false offset:0 total:5]", nil},
+		{"start past total", 5, 9, Options{}, "[]", "map[count:0 hasNext:false hasPrev:true offset:5 total:5]", ErrRangeNotSatisfiable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadRange[Order](context.Background(), FromURLValues(url.Values{}), db, tc.opts, tc.start, tc.end)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			kode := []string{}
+			for _, o := range res.Data {
+				kode = append(kode, o.Kode)
+			}
+			if fmt.Sprint(kode) != tc.want || fmt.Sprint(res.Meta["pagination"]) != tc.pagination {
+				t.Fatalf("data %v, pagination %v", kode, res.Meta["pagination"])
+			}
+		})
+	}
+	// set kosong: items=0- bukan 416
+	res, err := ReadRange[Order](context.Background(), FromURLValues(url.Values{"filter[kode]": {"ORD-99"}}), db, Options{OrderBy: "id"}, 0, -1)
+	if err != nil || len(res.Data) != 0 {
+		t.Fatalf("empty set: %v, err %v", res.Data, err)
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	cases := []struct {
+		pagination map[string]interface{}
+		want       string
+	}{
+		{map[string]interface{}{"offset": 0, "count": 50, "total": int64(1234)}, "items 0-49/1234"},
+		{map[string]interface{}{"offset": 1230, "count": 4, "total": int64(1234)}, "items 1230-1233/1234"},
+		{map[string]interface{}{"offset": 2000, "count": 0, "total": int64(1234)}, "items */1234"},
+	}
+	for _, tc := range cases {
+		if got := ContentRange(tc.pagination); got != tc.want {
+			t.Fatalf("ContentRange(%v) = %q, want %q", tc.pagination, got, tc.want)
+		}
+	}
+}
+
+func TestListHandlerRangeHeader(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	cases := []struct {
+		name         string
+		opts         Options
+		target       string
+		rangeHeader  string
+		status       int
+		contentRange string
+	}{
+		{"partial", Options{RangeHeader: true}, "/orders", "items=0-1", http.StatusPartialContent, "items 0-1/5"},
+		{"with filter", Options{RangeHeader: true}, "/orders?filter[status]=aktif", "items=1-9", http.StatusPartialContent, "items 1-2/3"},
+		{"not satisfiable", Options{RangeHeader: true}, "/orders", "items=10-19", http.StatusRequestedRangeNotSatisfiable, "items */5"},
+		{"other unit ignored", Options{RangeHeader: true}, "/orders?page=2&pageSize=2", "bytes=0-1", http.StatusOK, ""},
+		{"no header", Options{RangeHeader: true}, "/orders?page=2&pageSize=2", "", http.StatusOK, ""},
+		{"disabled", Options{}, "/orders", "items=0-1", http.StatusOK, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.rangeHeader != "" {
+				req.Header.Set("Range", tc.rangeHeader)
+			}
+			rec := httptest.NewRecorder()
+			ListHandlerHTTP[Order](db, tc.opts)(rec, req)
+			if rec.Code != tc.status || rec.Header().Get("Content-Range") != tc.contentRange {
+				t.Fatalf("status %d, Content-Range %q: %s", rec.Code, rec.Header().Get("Content-Range"), rec.Body.String())
+			}
+			if tc.opts.RangeHeader && rec.Header().Get("Accept-Ranges") != "items" {
+				t.Fatalf("Accept-Ranges %q", rec.Header().Get("Accept-Ranges"))
+			}
+		})
+	}
+}
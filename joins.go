@@ -0,0 +1,225 @@
+package magicrest
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// joinRegistry: JOIN relasi untuk satu query. Filter, search dan order "Relasi.kolom" (belongs-to/has-one)
+// memakai relasi yang sudah di-join — db.Joins dari caller, preload StrategyJoin, atau JOIN mentah caller dengan
+// alias yang sama — dan db.Joins untuk sisanya ditambahkan sekali per relasi (apply). Selama query punya JOIN,
+// kolom model ditulis lengkap dengan tabelnya agar tidak ambigu (id, created_at, ...).
+type joinRegistry struct {
+	sch    *schema.Schema
+	paths  map[string]bool // relasi yang dipakai lewat alias gorm: "Gudang", "Gudang.Kota" (alias Gudang__Kota)
+	raw    map[string]bool // relasi yang sudah di-JOIN mentah oleh caller: kolomnya ditulis apa adanya seperti dulu
+	active bool            // query punya JOIN: kolom model di-qualify dengan tabelnya
+}
+
+// newJoinRegistry: nil bila refs tidak menyebut relasi dan db belum punya JOIN (SQL tidak berubah)
+func newJoinRegistry(db *gorm.DB, modelPtr interface{}, refs []string) (*joinRegistry, error) {
+	if len(refs) == 0 && len(db.Statement.Joins) == 0 {
+		return nil, nil
+	}
+	sch, err := parseSchema(db, modelPtr)
+	if err != nil {
+		return nil, err
+	}
+	r := &joinRegistry{sch: sch, paths: map[string]bool{}, raw: map[string]bool{}, active: len(db.Statement.Joins) > 0}
+	aliases := map[string]bool{}
+	for _, j := range db.Statement.Joins {
+		for _, alias := range rawJoinAliases(j.Name) {
+			aliases[alias] = true
+		}
+	}
+	for _, ref := range refs {
+		path, _, ok := r.split(ref)
+		if !ok {
+			continue
+		}
+		if aliases[joinAlias(path)] && !hasJoin(db, path) {
+			r.raw[path] = true
+		} else {
+			r.paths[path] = true
+		}
+		r.active = true
+	}
+	return r, nil
+}
+
+// relationRefs: field filter, search dan order yang mungkin kolom relasi ("Gudang.kode")
+func relationRefs(params QueryParams, opts Options, search string) []string {
+	var refs []string
+	add := func(field string) {
+		if strings.Contains(field, ".") {
+			refs = append(refs, field)
+		}
+	}
+	for _, f := range params.Filters {
+		if _, ok := opts.ComputedColumns[f.Field]; !ok {
+			add(f.Field)
+		}
+	}
+	if search != "" {
+		for _, f := range searchFields(opts) {
+			add(f)
+		}
+	}
+	order := params.Order
+	if order == "" {
+		order = opts.OrderBy
+	}
+	for _, s := range parseSort(order) {
+		add(s.Field)
+	}
+	return refs
+}
+
+// split("Gudang.kode") -> "Gudang", "kode": path relasi yang bisa di-join dan nama kolom DB-nya
+func (r *joinRegistry) split(field string) (string, string, bool) {
+	i := strings.LastIndex(field, ".")
+	if i <= 0 || !joinable(r.sch, field[:i]) {
+		return "", "", false
+	}
+	cur := r.sch
+	for _, seg := range strings.Split(field[:i], ".") {
+		cur = cur.Relationships.Relations[seg].FieldSchema
+	}
+	f := cur.LookUpField(field[i+1:])
+	if !isColumnField(f) {
+		return "", "", false
+	}
+	return field[:i], f.DBName, true
+}
+
+// known: field adalah kolom relasi yang bisa di-join (StrictQuery)
+func (r *joinRegistry) known(field string) bool {
+	if r == nil {
+		return false
+	}
+	_, _, ok := r.split(field)
+	return ok
+}
+
+// column: kolom ter-quote untuk kolom relasi beralias gorm, atau kolom model selama query punya JOIN
+func (r *joinRegistry) column(field string) (clause.Column, bool) {
+	if r == nil {
+		return clause.Column{}, false
+	}
+	if path, name, ok := r.split(field); ok {
+		return clause.Column{Table: joinAlias(path), Name: name}, r.paths[path]
+	}
+	if f := r.sch.LookUpField(field); r.active && isColumnField(f) {
+		return clause.Column{Table: clause.CurrentTable, Name: f.DBName}, true
+	}
+	return clause.Column{}, false
+}
+
+// expr: field untuk SQL kondisi — "?" + clause.Column bila column mengenalinya, selain itu apa adanya
+func (r *joinRegistry) expr(field string) (string, []interface{}) {
+	if col, ok := r.column(field); ok {
+		return "?", []interface{}{col}
+	}
+	return field, nil
+}
+
+// filterExpr: kolom filter[field] untuk SQL kondisi, selalu ter-quote sesuai dialect kecuali kolom relasi dari
+// JOIN mentah caller (ditulis apa adanya seperti dulu; nama field sudah lolos fieldNamePattern)
+func filterExpr(sch *schema.Schema, joins *joinRegistry, field string) (string, []interface{}) {
+	if col, ok := joins.column(field); ok {
+		return "?", []interface{}{col}
+	}
+	if joins != nil {
+		if path, _, ok := joins.split(field); ok && joins.raw[path] {
+			return field, nil
+		}
+	}
+	if f := sch.LookUpField(field); isColumnField(f) {
+		return "?", []interface{}{clause.Column{Name: f.DBName}}
+	}
+	if i := strings.LastIndex(field, "."); i > 0 {
+		return "?", []interface{}{clause.Column{Table: field[:i], Name: field[i+1:]}}
+	}
+	return "?", []interface{}{clause.Column{Name: field}}
+}
+
+// order: ORDER BY dengan kolom relasi / kolom model ter-quote; string aslinya bila tidak ada yang diubah
+func (r *joinRegistry) order(order string) interface{} {
+	if r == nil {
+		return order
+	}
+	var cols []clause.OrderByColumn
+	changed := false
+	for _, item := range strings.Split(order, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 {
+			continue
+		}
+		if col, ok := r.column(parts[0]); ok && (len(parts) == 1 || len(parts) == 2 && isDirection(parts[1])) {
+			cols = append(cols, clause.OrderByColumn{Column: col, Desc: len(parts) == 2 && strings.EqualFold(parts[1], "desc")})
+			changed = true
+			continue
+		}
+		cols = append(cols, clause.OrderByColumn{Column: clause.Column{Name: strings.Join(parts, " "), Raw: true}})
+	}
+	if !changed {
+		return order
+	}
+	return clause.OrderBy{Columns: cols}
+}
+
+// apply menambah db.Joins untuk relasi yang belum di-join. Kolom relasi tidak ikut di-select: isi relasi
+// tetap hanya dari preload (whitelist AllowedPreloads berlaku).
+func (r *joinRegistry) apply(db *gorm.DB) *gorm.DB {
+	if r == nil {
+		return db
+	}
+	paths := make([]string, 0, len(r.paths))
+	for path := range r.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if !hasJoin(db, path) {
+			db = db.Joins(path, db.Session(&gorm.Session{NewDB: true}).Omit("*"))
+		}
+	}
+	return db
+}
+
+// joinAlias: alias tabel yang dipakai gorm untuk db.Joins(path), "Gudang.Kota" -> "Gudang__Kota"
+func joinAlias(path string) string {
+	return strings.ReplaceAll(path, ".", "__")
+}
+
+// rawJoinAliases: alias (atau nama tabel) setiap JOIN pada SQL join mentah,
+// e.g. "LEFT JOIN gudangs AS Gudang ON ..." -> ["Gudang"]
+func rawJoinAliases(sql string) []string {
+	var aliases []string
+	tokens := strings.Fields(sql)
+	for i, tok := range tokens {
+		if !strings.EqualFold(tok, "JOIN") || i+1 >= len(tokens) {
+			continue
+		}
+		alias := tokens[i+1]
+		if i+2 < len(tokens) {
+			next := tokens[i+2]
+			switch {
+			case strings.EqualFold(next, "AS") && i+3 < len(tokens):
+				alias = tokens[i+3]
+			case !strings.EqualFold(next, "ON") && !strings.EqualFold(next, "USING"):
+				alias = next
+			}
+		}
+		aliases = append(aliases, strings.Trim(alias, "`\"[]"))
+	}
+	return aliases
+}
+
+func isDirection(s string) bool {
+	return strings.EqualFold(s, "asc") || strings.EqualFold(s, "desc")
+}
@@ -0,0 +1,86 @@
+package magicrest
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBuildQueryJoinsRelationOnce(t *testing.T) {
+	db := newTestDB(t)
+	all := url.Values{"filter[Gudang.kode]": {"GD-01"}, "order": {"Gudang.nama desc"}, "search": {"x"}}
+	search := Options{SearchFields: []string{"kode", "Gudang.nama"}}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		db    func(*gorm.DB) *gorm.DB // JOIN dari caller
+		want  []string                // potongan SQL
+	}{
+		{"filter and sort", url.Values{"filter[Gudang.kode]": {"GD-01"}, "order": {"Gudang.nama"}}, Options{}, nil,
+			[]string{"`Gudang`.`kode` = ", "ORDER BY `Gudang`.`nama`"}},
+		{"filter sort and search", all, search, nil,
+			[]string{"`Gudang`.`kode` = ", "ORDER BY `Gudang`.`nama` DESC", "`Gudang`.`nama` ILIKE"}},
+		{"server order", url.Values{"filter[Gudang.kode]": {"GD-01"}}, Options{OrderBy: "Gudang.nama"}, nil,
+			[]string{"ORDER BY `Gudang`.`nama`"}},
+		{"caller Joins", all, search, func(db *gorm.DB) *gorm.DB { return db.Joins("Gudang") },
+			[]string{"`Gudang`.`kode` = ", "ORDER BY `Gudang`.`nama` DESC"}},
+		{"caller raw join", url.Values{"filter[Gudang.kode]": {"GD-01"}, "order": {"Gudang.nama"}}, Options{},
+			func(db *gorm.DB) *gorm.DB {
+				return db.Joins("LEFT JOIN gudangs AS Gudang ON Gudang.id = orders.gudang_id")
+			},
+			[]string{"Gudang.kode = "}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			base := db.Model(&Order{})
+			if tc.db != nil {
+				base = tc.db(base)
+			}
+			q, _, err := BuildQuery(tc.query, base, &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sql := q.Session(&gorm.Session{DryRun: true}).Find(&[]Order{}).Statement.SQL.String()
+			if n := strings.Count(strings.ToLower(sql), " join "); n != 1 {
+				t.Fatalf("%d joins, want 1: %s", n, sql)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(sql, want) {
+					t.Fatalf("SQL lacks %q: %s", want, sql)
+				}
+			}
+			// kolom model ditulis dengan tabelnya selama ada JOIN (id ada di kedua tabel)
+			if strings.Contains(sql, " `id`") || strings.Contains(sql, "(`id`") {
+				t.Fatalf("unqualified id: %s", sql)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedFilterAndSortSameRelation(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 6, 0)
+	rdb, rec := recordSQL(db)
+	query := url.Values{"filter[Gudang.kode]": {"GD-02"}, "order": {"Gudang.nama desc, id desc"}, "preload": {"Gudang"}}
+	opts := Options{PreloadStrategy: map[string]Strategy{"Gudang": StrategyJoin}}
+	res, err := ReadPaginated(query, rdb.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 3 || res.Data[0].Kode != "ORD-06" {
+		t.Fatalf("rows %+v", res.Data)
+	}
+	for _, o := range res.Data {
+		if o.Gudang == nil || o.Gudang.Kode != "GD-02" {
+			t.Fatalf("order %s: gudang %+v", o.Kode, o.Gudang)
+		}
+	}
+	for _, sql := range rec.statements() {
+		if n := strings.Count(sql, "JOIN `gudangs`"); n > 1 {
+			t.Fatalf("%d gudang joins: %s", n, sql)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package magicrest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// JSONAPIContentType: media type dokumen JSON:API
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIDocument: dokumen top-level JSON:API (https://jsonapi.org/format/). Data berisi
+// []JSONAPIResource untuk list atau *JSONAPIResource untuk satu record.
+type JSONAPIDocument struct {
+	Data     interface{}            `json:"data"`
+	Included []JSONAPIResource      `json:"included,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	Links    map[string]string      `json:"links,omitempty"`
+}
+
+// JSONAPIResource: resource object — type, id (primary key) dan attributes dengan nama json
+type JSONAPIResource struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id"`
+	Attributes    map[string]interface{}         `json:"attributes,omitempty"`
+	Relationships map[string]JSONAPIRelationship `json:"relationships,omitempty"`
+}
+
+// JSONAPIIdentifier: resource identifier {"type", "id"} di relationships
+type JSONAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIRelationship: data berisi *JSONAPIIdentifier (belongs-to / has-one) atau []JSONAPIIdentifier
+type JSONAPIRelationship struct {
+	Data interface{} `json:"data"`
+}
+
+// JSONAPIList menyusun dokumen JSON:API dari Result ReadPaginatedSource: data sebagai resource object
+// (type dari Options.ResourceType, default nama tabel), relasi yang di-preload sebagai relationships +
+// included (unik per type dan id, termasuk relasi bertingkat), meta dari res.Meta dan links dari
+// PageLinks(u, Meta["pagination"]). Relasi nil / tidak di-preload tidak ditulis.
+func JSONAPIList[T any](db *gorm.DB, res Result[T], u *url.URL, opts Options) (JSONAPIDocument, error) {
+	enc, err := newJSONAPIEncoder[T](db, opts)
+	if err != nil {
+		return JSONAPIDocument{}, err
+	}
+	for i := range res.Data {
+		enc.markSeen(enc.sch, reflect.ValueOf(&res.Data[i]).Elem())
+	}
+	data := make([]JSONAPIResource, 0, len(res.Data))
+	for i := range res.Data {
+		r, err := enc.resource(enc.sch, reflect.ValueOf(&res.Data[i]).Elem())
+		if err != nil {
+			return JSONAPIDocument{}, err
+		}
+		data = append(data, r)
+	}
+	doc := JSONAPIDocument{Data: data, Included: enc.included, Meta: res.Meta}
+	if pagination, ok := res.Meta["pagination"].(map[string]interface{}); ok && u != nil {
+		doc.Links = PageLinks(u, pagination)
+	}
+	return doc, nil
+}
+
+// JSONAPIItem: dokumen JSON:API untuk satu record (ReadOne), data berupa satu resource object
+func JSONAPIItem[T any](db *gorm.DB, item *T, opts Options) (JSONAPIDocument, error) {
+	enc, err := newJSONAPIEncoder[T](db, opts)
+	if err != nil {
+		return JSONAPIDocument{}, err
+	}
+	v := reflect.ValueOf(item).Elem()
+	enc.markSeen(enc.sch, v)
+	r, err := enc.resource(enc.sch, v)
+	if err != nil {
+		return JSONAPIDocument{}, err
+	}
+	return JSONAPIDocument{Data: &r, Included: enc.included}, nil
+}
+
+// JSONAPIErrors: status (StatusForError) dan body {"errors": [...]} JSON:API — satu error object per
+// ValidationDetail dengan source.parameter dari Field; pesan error 500 diganti teks status.
+func JSONAPIErrors(err error, locale string) (int, map[string]interface{}) {
+	status := StatusForError(err)
+	code, message := errorCode(err), err.Error()
+	var details []ValidationDetail
+	if status == http.StatusInternalServerError {
+		code, message = "internal_error", http.StatusText(status)
+	} else {
+		details = DetailsFromErrorLocale(err, locale)
+	}
+	if len(details) == 0 {
+		details = []ValidationDetail{{Code: code, Message: message}}
+	}
+	errs := make([]map[string]interface{}, len(details))
+	for i, d := range details {
+		obj := map[string]interface{}{"status": strconv.Itoa(status), "title": http.StatusText(status), "detail": d.Message}
+		if d.Code != "" {
+			obj["code"] = d.Code
+		}
+		if d.Field != "" {
+			obj["source"] = map[string]string{"parameter": d.Field}
+		}
+		errs[i] = obj
+	}
+	return status, map[string]interface{}{"errors": errs}
+}
+
+// JSONAPIListHandlerHTTP: ListHandlerHTTP dengan response dokumen JSON:API (JSONAPIList) dan error JSONAPIErrors
+func JSONAPIListHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := ReadPaginatedSource[T](r.Context(), FromURLValues(r.URL.Query()), db.Model(new(T)), new(T), opts)
+		if err == nil {
+			var doc JSONAPIDocument
+			if doc, err = JSONAPIList(db, res, r.URL, opts); err == nil {
+				writeBody(w, http.StatusOK, JSONAPIContentType, doc)
+				return
+			}
+		}
+		writeJSONAPIError(w, r, err)
+	}
+}
+
+// JSONAPIGetHandlerHTTP: GetHandlerHTTP dengan response dokumen JSON:API (JSONAPIItem)
+func JSONAPIGetHandlerHTTP[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out, err := ReadOne[T](r.Context(), r.URL.Query(), db, r.PathValue("id"), opts)
+		if err == nil {
+			var doc JSONAPIDocument
+			if doc, err = JSONAPIItem(db, out, opts); err == nil {
+				writeBody(w, http.StatusOK, JSONAPIContentType, doc)
+				return
+			}
+		}
+		writeJSONAPIError(w, r, err)
+	}
+}
+
+func writeJSONAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := JSONAPIErrors(err, LocaleFromRequest(r))
+	writeBody(w, status, JSONAPIContentType, body)
+}
+
+// jsonAPIEncoder: state satu dokumen — resource yang sudah ada (data + included) per type/id
+type jsonAPIEncoder struct {
+	sch          *schema.Schema
+	resourceType string
+	seen         map[JSONAPIIdentifier]bool
+	included     []JSONAPIResource
+}
+
+func newJSONAPIEncoder[T any](db *gorm.DB, opts Options) (*jsonAPIEncoder, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	return &jsonAPIEncoder{sch: sch, resourceType: opts.ResourceType, seen: map[JSONAPIIdentifier]bool{}}, nil
+}
+
+// typeOf: Options.ResourceType untuk model T (juga bila muncul sebagai relasi), selain itu nama tabel
+func (e *jsonAPIEncoder) typeOf(sch *schema.Schema) string {
+	if e.resourceType != "" && sch.ModelType == e.sch.ModelType {
+		return e.resourceType
+	}
+	return sch.Table
+}
+
+// identifier: type dan id (primary key, composite digabung ",") dari struct v; ok false bila pk kosong
+func (e *jsonAPIEncoder) identifier(sch *schema.Schema, v reflect.Value) (JSONAPIIdentifier, bool) {
+	id, ok := primaryKeyString(sch, v)
+	if !ok {
+		return JSONAPIIdentifier{}, false
+	}
+	return JSONAPIIdentifier{Type: e.typeOf(sch), ID: id}, true
+}
+
+// primaryKeyString: primary key struct v sebagai teks (composite digabung ","); ok false bila kosong
+func primaryKeyString(sch *schema.Schema, v reflect.Value) (string, bool) {
+	ids := make([]string, 0, len(sch.PrimaryFields))
+	for _, f := range sch.PrimaryFields {
+		val, zero := f.ValueOf(context.Background(), v)
+		if zero {
+			return "", false
+		}
+		ids = append(ids, csvValue(val))
+	}
+	return strings.Join(ids, ","), len(ids) > 0
+}
+
+func (e *jsonAPIEncoder) markSeen(sch *schema.Schema, v reflect.Value) {
+	if id, ok := e.identifier(sch, v); ok {
+		e.seen[id] = true
+	}
+}
+
+// resource: resource object untuk struct v (addressable). Attributes = field json v tanpa primary key dan
+// relasi; relasi yang terisi menjadi relationships dan resource-nya masuk included (sekali per type/id).
+func (e *jsonAPIEncoder) resource(sch *schema.Schema, v reflect.Value) (JSONAPIResource, error) {
+	id, _ := e.identifier(sch, v)
+	attrs, err := jsonObject(v.Addr().Interface())
+	if err != nil {
+		return JSONAPIResource{}, fmt.Errorf("jsonapi attributes %s: %w", sch.Name, err)
+	}
+	for _, f := range sch.PrimaryFields {
+		delete(attrs, jsonKey(f.StructField))
+	}
+	out := JSONAPIResource{Type: id.Type, ID: id.ID, Attributes: attrs}
+
+	names := make([]string, 0, len(sch.Relationships.Relations))
+	for name, rel := range sch.Relationships.Relations {
+		if rel.Schema == sch { // gorm juga menyimpan relasi balik "_<Model>_<Field>" di schema tujuan
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rel := sch.Relationships.Relations[name]
+		key := jsonKey(rel.Field.StructField)
+		if key == "-" {
+			continue
+		}
+		delete(attrs, key)
+		fv := reflect.Indirect(rel.Field.ReflectValueOf(context.Background(), v))
+		var data interface{}
+		switch {
+		case !fv.IsValid():
+			continue
+		case fv.Kind() == reflect.Slice:
+			if fv.IsNil() {
+				continue
+			}
+			ids := make([]JSONAPIIdentifier, 0, fv.Len())
+			for i := 0; i < fv.Len(); i++ {
+				elem := reflect.Indirect(fv.Index(i))
+				if !elem.IsValid() {
+					continue
+				}
+				rid, ok, err := e.include(rel.FieldSchema, elem)
+				if err != nil {
+					return out, err
+				}
+				if ok {
+					ids = append(ids, rid)
+				}
+			}
+			data = ids
+		default:
+			rid, ok, err := e.include(rel.FieldSchema, fv)
+			if err != nil {
+				return out, err
+			}
+			if !ok {
+				continue
+			}
+			data = &rid
+		}
+		if out.Relationships == nil {
+			out.Relationships = map[string]JSONAPIRelationship{}
+		}
+		out.Relationships[key] = JSONAPIRelationship{Data: data}
+	}
+	return out, nil
+}
+
+// include menambahkan resource relasi v ke included bila type/id-nya belum ada (siklus berhenti di sini)
+func (e *jsonAPIEncoder) include(sch *schema.Schema, v reflect.Value) (JSONAPIIdentifier, bool, error) {
+	id, ok := e.identifier(sch, v)
+	if !ok || e.seen[id] {
+		return id, ok, nil
+	}
+	e.seen[id] = true
+	if !v.CanAddr() {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr.Elem()
+	}
+	r, err := e.resource(sch, v)
+	if err != nil {
+		return id, false, err
+	}
+	e.included = append(e.included, r)
+	return id, true, nil
+}
+
+// jsonObject: v di-encode lalu di-decode sebagai objek JSON (nama dan isi persis seperti response JSON)
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // int64 besar tidak jadi float64
+	return out, dec.Decode(&out)
+}
+
+// jsonKey: nama field di output encoding/json (tag json, default nama field Go)
+func jsonKey(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
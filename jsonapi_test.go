@@ -0,0 +1,174 @@
+package magicrest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// jsonAPISummary: "type:id" resource object, urut seperti di dokumen
+func jsonAPISummary(rs []JSONAPIResource) string {
+	out := make([]string, len(rs))
+	for i, r := range rs {
+		out[i] = r.Type + ":" + r.ID
+	}
+	return fmt.Sprint(out)
+}
+
+func TestJSONAPIList(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 2)
+	query := url.Values{"preload": {"Gudang,Items.Produk.Kategori"}, "pageSize": {"2"}}
+	opts := Options{OrderBy: "id"}
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("/orders?" + query.Encode())
+	doc, err := JSONAPIList(db, res, u, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := doc.Data.([]JSONAPIResource)
+	if got := jsonAPISummary(data); got != "[orders:1 orders:2]" {
+		t.Fatalf("data %s", got)
+	}
+	var attrs []string
+	for k := range data[0].Attributes {
+		attrs = append(attrs, k)
+	}
+	sort.Strings(attrs)
+	if fmt.Sprint(attrs) != "[created_at gudang_id kode status telepon updated_at]" || data[0].Attributes["kode"] != "ORD-01" {
+		t.Fatalf("attributes %v", data[0].Attributes)
+	}
+	rel, _ := json.Marshal(data[1].Relationships)
+	if string(rel) != `{"gudang":{"data":{"type":"gudangs","id":"2"}},"items":{"data":[{"type":"items","id":"3"},{"type":"items","id":"4"}]}}` {
+		t.Fatalf("relationships %s", rel)
+	}
+	// gudang, produk dan kategori yang sama hanya sekali di included
+	var included []string
+	for _, r := range doc.Included {
+		included = append(included, r.Type+":"+r.ID)
+	}
+	sort.Strings(included)
+	if fmt.Sprint(included) != "[gudangs:1 gudangs:2 items:1 items:2 items:3 items:4 kategoris:1 produks:1]" {
+		t.Fatalf("included %v", included)
+	}
+	if doc.Meta["pagination"] == nil || !strings.Contains(doc.Links["next"], "page=2") {
+		t.Fatalf("meta %v, links %v", doc.Meta, doc.Links)
+	}
+}
+
+func TestJSONAPIItem(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	opts := Options{ResourceType: "pesanan"}
+	o, err := ReadOne[Order](context.Background(), url.Values{"preload": {"Gudang"}}, db, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := JSONAPIItem(db, o, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := doc.Data.(*JSONAPIResource)
+	if r.Type != "pesanan" || r.ID != "2" || len(r.Relationships) != 1 || jsonAPISummary(doc.Included) != "[gudangs:2]" {
+		t.Fatalf("resource %+v, included %s", r, jsonAPISummary(doc.Included))
+	}
+	// relasi yang tidak di-preload tidak ditulis
+	o.Gudang = nil
+	if doc, _ := JSONAPIItem(db, o, opts); len(doc.Data.(*JSONAPIResource).Relationships) != 0 || len(doc.Included) != 0 {
+		t.Fatalf("without preload: %+v", doc)
+	}
+}
+
+// Pegawai: relasi ke tabel sendiri (atasan dan bawahan) untuk included yang melingkar
+type Pegawai struct {
+	ID       uint      `json:"id"`
+	Nama     string    `json:"nama"`
+	AtasanID *uint     `json:"atasan_id"`
+	Atasan   *Pegawai  `json:"atasan,omitempty"`
+	Bawahan  []Pegawai `json:"bawahan,omitempty" gorm:"foreignKey:AtasanID"`
+}
+
+func TestJSONAPICyclicIncludes(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Pegawai{}); err != nil {
+		t.Fatal(err)
+	}
+	kepala := Pegawai{Nama: "Sari"}
+	db.Create(&kepala)
+	db.Create(&[]Pegawai{{Nama: "Budi", AtasanID: &kepala.ID}, {Nama: "Ani", AtasanID: &kepala.ID}})
+	p, err := ReadOne[Pegawai](context.Background(), url.Values{"preload": {"Atasan.Bawahan"}}, db, 2, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := JSONAPIItem(db, p, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pegawai 2 (data) muncul lagi sebagai bawahan atasannya: tidak ikut included
+	if got := jsonAPISummary(doc.Included); got != "[pegawais:3 pegawais:1]" {
+		t.Fatalf("included %s", got)
+	}
+	rel, _ := json.Marshal(doc.Included[1].Relationships)
+	if string(rel) != `{"bawahan":{"data":[{"type":"pegawais","id":"2"},{"type":"pegawais","id":"3"}]}}` {
+		t.Fatalf("atasan relationships %s", rel)
+	}
+}
+
+func TestJSONAPIErrors(t *testing.T) {
+	_, invalid := ParseQuery(url.Values{"filter[jumlah]": {"abc"}}, Options{DefaultFieldTypes: map[string]string{"jumlah": "int"}})
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		want   string
+	}{
+		{"validation", invalid, 400, `{"errors":[{"code":"invalid_int","detail":"jumlah harus berupa bilangan bulat, bukan abc","source":{"parameter":"jumlah"},"status":"400","title":"Bad Request"}]}`},
+		{"internal", fmt.Errorf("dial tcp: refused"), 500, `{"errors":[{"code":"internal_error","detail":"Internal Server Error","status":"500","title":"Internal Server Error"}]}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := JSONAPIErrors(tc.err, "id")
+			b, _ := json.Marshal(body)
+			if status != tc.status || string(b) != tc.want {
+				t.Fatalf("status %d, body %s", status, b)
+			}
+		})
+	}
+}
+
+func TestJSONAPIHandlers(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	mux := http.NewServeMux()
+	opts := Options{OrderBy: "id", DefaultFieldTypes: map[string]string{"gudang_id": "int"}}
+	mux.Handle("GET /orders", JSONAPIListHandlerHTTP[Order](db, opts))
+	mux.Handle("GET /orders/{id}", JSONAPIGetHandlerHTTP[Order](db, opts))
+	cases := []struct {
+		target string
+		status int
+		want   string
+	}{
+		{"/orders", http.StatusOK, `"data":[{"type":"orders","id":"1"`},
+		{"/orders/2", http.StatusOK, `"data":{"type":"orders","id":"2"`},
+		{"/orders?filter[gudang_id]=satu", http.StatusBadRequest, `"source":{"parameter":"gudang_id"}`},
+		{"/orders/9", http.StatusNotFound, `"status":"404"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if rec.Code != tc.status || rec.Header().Get("Content-Type") != JSONAPIContentType || !strings.Contains(rec.Body.String(), tc.want) {
+				t.Fatalf("status %d, Content-Type %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+			}
+		})
+	}
+}
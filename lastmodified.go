@@ -0,0 +1,104 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// timeLayouts: format MAX(kolom waktu) yang dikembalikan driver sebagai string (SQLite)
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+// LastModified mengambil MAX(kolom modified) dari set yang cocok dengan query — kondisi sama dengan list
+// (Scopes, filter, search) tanpa order, limit dan preload, dalam satu query agregat. Kolomnya
+// Options.ModifiedColumn (default field autoUpdateTime / updated_at). ok=false bila model tidak punya
+// kolom tersebut, set kosong, atau query memakai groupby / distinct.
+func LastModified[T any](ctx context.Context, query url.Values, db *gorm.DB, opts Options) (time.Time, bool, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db = readDB(ctx, db, FromURLValues(query), opts)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	f := modifiedField(sch, opts)
+	if f == nil {
+		return time.Time{}, false, nil
+	}
+	// tanpa Options.Logger: warning query dicatat oleh list yang memakai query yang sama
+	q, info, err := buildQuerySource[T](FromURLValues(query), db.Model(new(T)), new(T), opts)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if info.Distinct || len(info.GroupBy) > 0 {
+		return time.Time{}, false, nil
+	}
+	tx := q.Select("MAX(" + db.Statement.Quote(sch.Table+"."+f.DBName) + ")")
+	delete(tx.Statement.Clauses, "ORDER BY")
+	tx.Statement.Preloads = nil
+
+	var raw interface{}
+	err = newSlowQuery(opts, info.Params, modelName[T]()).run(QueryLabelLastModified, tx, func(tx *gorm.DB) *gorm.DB {
+		if err := tx.Row().Scan(&raw); err != nil {
+			tx.AddError(err)
+		}
+		return tx
+	})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return parseDBTime(raw)
+}
+
+// parseDBTime: nilai MAX dari driver ke time.Time (NULL = ok false)
+func parseDBTime(raw interface{}) (time.Time, bool, error) {
+	switch v := raw.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return v, true, nil
+	case []byte:
+		return parseDBTime(string(v))
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true, nil
+			}
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("last modified: unexpected value %v (%T)", raw, raw)
+}
+
+// notModifiedSince: true bila If-Modified-Since >= lastModified (presisi detik seperti header HTTP)
+func notModifiedSince(ifModifiedSince string, lastModified time.Time) bool {
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// CheckLastModified: langkah Options.LastModified untuk handler list, dijalankan sebelum query halaman.
+// Mengembalikan MAX(ModifiedColumn) untuk header Last-Modified (zero = tidak dikirim) dan apakah request
+// cukup dijawab 304. If-Modified-Since diabaikan bila ada If-None-Match (RFC 9110, ETag yang menentukan).
+func CheckLastModified[T any](ctx context.Context, query url.Values, db *gorm.DB, opts Options, ifNoneMatch, ifModifiedSince string) (time.Time, bool, error) {
+	if !opts.LastModified {
+		return time.Time{}, false, nil
+	}
+	lastModified, ok, err := LastModified[T](ctx, query, db, opts)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	notModified := ifNoneMatch == "" && ifModifiedSince != "" && notModifiedSince(ifModifiedSince, lastModified)
+	return lastModified, notModified, nil
+}
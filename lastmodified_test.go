@@ -0,0 +1,96 @@
+package magicrest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// seedModified: 4 order dengan updated_at 2024-02-0N 10:00:00.5 UTC (N = id)
+func seedModified(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 0)
+	for id := 1; id <= 4; id++ {
+		db.Model(&Order{}).Where("id = ?", id).UpdateColumn("updated_at", time.Date(2024, 2, id, 10, 0, 0, 5e8, time.UTC))
+	}
+	return db
+}
+
+func TestLastModified(t *testing.T) {
+	db := seedModified(t)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  string // "" = ok false
+	}{
+		{"all rows", url.Values{}, Options{}, "2024-02-04 10:00:00.5"},
+		{"filtered", url.Values{"filter[status]": {"aktif"}}, Options{}, "2024-02-03 10:00:00.5"},
+		{"scoped", url.Values{}, Options{Scopes: []Scope{func(db *gorm.DB) *gorm.DB { return db.Where("id < ?", 3) }}}, "2024-02-02 10:00:00.5"},
+		{"custom column", url.Values{}, Options{ModifiedColumn: "created_at"}, "2024-01-01 03:00:00"},
+		{"unknown column", url.Values{}, Options{ModifiedColumn: "diubah_pada"}, ""},
+		{"empty set", url.Values{"filter[kode]": {"ORD-99"}}, Options{}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := LastModified[Order](context.Background(), tc.query, db, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != (tc.want != "") || (ok && got.UTC().Format("2006-01-02 15:04:05.999") != tc.want) {
+				t.Fatalf("got %v, ok %v; want %q", got, ok, tc.want)
+			}
+		})
+	}
+	// model tanpa updated_at: fitur mati sendiri
+	if _, ok, err := LastModified[Gudang](context.Background(), url.Values{}, db, Options{}); ok || err != nil {
+		t.Fatalf("Gudang: ok %v, err %v", ok, err)
+	}
+}
+
+func TestListHandlerLastModified(t *testing.T) {
+	db := seedModified(t)
+	newest := "Sun, 04 Feb 2024 10:00:00 GMT"
+	cases := []struct {
+		name    string
+		opts    Options
+		target  string
+		headers map[string]string
+		status  int
+		lastMod string
+	}{
+		{"header set", Options{LastModified: true}, "/orders", nil, http.StatusOK, newest},
+		{"disabled", Options{}, "/orders", map[string]string{"If-Modified-Since": newest}, http.StatusOK, ""},
+		{"same second", Options{LastModified: true}, "/orders", map[string]string{"If-Modified-Since": newest}, http.StatusNotModified, newest},
+		{"newer", Options{LastModified: true}, "/orders", map[string]string{"If-Modified-Since": "Mon, 05 Feb 2024 00:00:00 GMT"}, http.StatusNotModified, newest},
+		{"older", Options{LastModified: true}, "/orders", map[string]string{"If-Modified-Since": "Sun, 04 Feb 2024 09:59:59 GMT"}, http.StatusOK, newest},
+		{"filtered set", Options{LastModified: true}, "/orders?filter[status]=aktif", map[string]string{"If-Modified-Since": "Sat, 03 Feb 2024 12:00:00 GMT"},
+			http.StatusNotModified, "Sat, 03 Feb 2024 10:00:00 GMT"},
+		{"invalid date ignored", Options{LastModified: true}, "/orders", map[string]string{"If-Modified-Since": "kemarin"}, http.StatusOK, newest},
+		{"ETag wins", Options{LastModified: true, ETag: true}, "/orders", map[string]string{"If-Modified-Since": newest, "If-None-Match": `"stale"`}, http.StatusOK, newest},
+		{"invalid filter", Options{LastModified: true, DefaultFieldTypes: map[string]string{"gudang_id": "int"}}, "/orders?filter[gudang_id]=satu", nil, http.StatusBadRequest, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			ListHandlerHTTP[Order](db, tc.opts)(rec, req)
+			if rec.Code != tc.status || rec.Header().Get("Last-Modified") != tc.lastMod {
+				t.Fatalf("status %d, Last-Modified %q; want %d, %q", rec.Code, rec.Header().Get("Last-Modified"), tc.status, tc.lastMod)
+			}
+			if tc.status == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Fatalf("304 with body %s", rec.Body.String())
+			}
+		})
+	}
+}
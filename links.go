@@ -0,0 +1,46 @@
+package magicrest
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// linkRels: urutan rel di PageLinks / LinkHeader
+var linkRels = []string{"self", "first", "prev", "next", "last"}
+
+// PageLinks menyusun URL self/first/prev/next/last dari u (path + query request) dan Meta["pagination"]:
+// hanya ?page= yang diganti, filter/order/pageSize tetap. prev/next tidak ada di halaman pertama/terakhir,
+// first/last tidak ada bila hasil kosong.
+func PageLinks(u *url.URL, pagination map[string]interface{}) map[string]string {
+	page, _ := pagination["page"].(int)
+	pageCount, _ := pagination["pageCount"].(int)
+	link := func(p int) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path, RawQuery: q.Encode()}).String()
+	}
+	links := map[string]string{"self": link(page)}
+	if pageCount > 0 {
+		links["first"] = link(1)
+		links["last"] = link(pageCount)
+	}
+	if page > 1 && pageCount > 0 {
+		links["prev"] = link(min(page-1, pageCount))
+	}
+	if page < pageCount {
+		links["next"] = link(page + 1)
+	}
+	return links
+}
+
+// LinkHeader: PageLinks sebagai header Link RFC 8288, e.g. `</barang?page=2>; rel="next", ...`
+func LinkHeader(links map[string]string) string {
+	var parts []string
+	for _, rel := range linkRels {
+		if href, ok := links[rel]; ok {
+			parts = append(parts, "<"+href+`>; rel="`+rel+`"`)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
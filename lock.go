@@ -0,0 +1,118 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrStaleRecord digunakan UpdateGeneric / PatchGeneric bila precondition optimistic locking gagal
+// (versi berbeda atau row sudah berubah sejak WriteOptions.UnmodifiedSince); berbeda dengan ErrNotFound.
+var ErrStaleRecord = errors.New("stale record")
+
+// lockPrecondition: kondisi optimistic locking untuk satu update
+type lockPrecondition struct {
+	version   *schema.Field // kolom versi (nil = tidak dipakai)
+	expected  int64         // versi yang dikirim klien
+	updatedAt *schema.Field // kolom untuk UnmodifiedSince
+	since     time.Time
+}
+
+// newLockPrecondition me-resolve WriteOptions.VersionColumn dan UpdatedAtColumn terhadap schema
+func newLockPrecondition(sch *schema.Schema, opts WriteOptions) (lockPrecondition, error) {
+	var lock lockPrecondition
+	if opts.VersionColumn != "" {
+		lock.version = sch.LookUpField(opts.VersionColumn)
+		if !isColumnField(lock.version) {
+			return lock, fmt.Errorf("%w: VersionColumn: unknown column %q on %s", ErrInvalidConfig, opts.VersionColumn, sch.Name)
+		}
+	}
+	if !opts.UnmodifiedSince.IsZero() {
+		lock.since = opts.UnmodifiedSince
+		name := opts.UpdatedAtColumn
+		if name == "" {
+			name = "updated_at"
+		}
+		lock.updatedAt = sch.LookUpField(name)
+		if !isColumnField(lock.updatedAt) {
+			return lock, fmt.Errorf("%w: UpdatedAtColumn: unknown column %q on %s", ErrInvalidConfig, name, sch.Name)
+		}
+	}
+	return lock, nil
+}
+
+func (l lockPrecondition) active() bool { return l.version != nil || l.updatedAt != nil }
+
+// apply menambahkan kondisi WHERE versi = expected dan updated_at < since (+1 detik, karena
+// If-Unmodified-Since / Last-Modified hanya presisi detik).
+func (l lockPrecondition) apply(db *gorm.DB, sch *schema.Schema) *gorm.DB {
+	if l.version != nil {
+		db = db.Where(db.Statement.Quote(sch.Table+"."+l.version.DBName)+" = ?", l.expected)
+	}
+	if l.updatedAt != nil {
+		db = db.Where(db.Statement.Quote(sch.Table+"."+l.updatedAt.DBName)+" < ?", l.since.Truncate(time.Second).Add(time.Second))
+	}
+	return db
+}
+
+// setVersion membaca versi yang dikirim klien dari item lalu mengisinya dengan versi berikutnya
+func (l *lockPrecondition) setVersion(ctx context.Context, rv reflect.Value) error {
+	v, _ := l.version.ValueOf(ctx, rv)
+	n, ok := toInt64(v)
+	if !ok {
+		return fmt.Errorf("%w: VersionColumn %s is not an integer", ErrInvalidConfig, l.version.Name)
+	}
+	l.expected = n
+	return l.version.Set(ctx, rv, n+1)
+}
+
+// staleError: ErrStaleRecord untuk row id
+func staleError(sch *schema.Schema, id any) error {
+	return fmt.Errorf("%w: %s %v was modified by someone else", ErrStaleRecord, sch.Name, id)
+}
+
+// toInt64 mengubah nilai integer apa pun (int, uint, pointer) ke int64
+func toInt64(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return 0, true
+		}
+		rv = rv.Elem()
+	}
+	switch {
+	case rv.CanInt():
+		return rv.Int(), true
+	case rv.CanUint():
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// patchVersion mengeluarkan key versi dari patch (nama kolom / field Go / tag json) dan mem-parse nilainya.
+// Patch asli tidak diubah; tanpa key versi -> ErrInvalidPatch karena precondition wajib dikirim.
+func (l *lockPrecondition) patchVersion(sch *schema.Schema, patch map[string]interface{}) (map[string]interface{}, error) {
+	rest := make(map[string]interface{}, len(patch))
+	found := false
+	for key, raw := range patch {
+		if lookUpPatchField(sch, key) != l.version {
+			rest[key] = raw
+			continue
+		}
+		v, err := patchValue("int", raw)
+		n, ok := toInt64(v)
+		if err != nil || !ok || v == nil {
+			return nil, &QueryError{Kind: ErrInvalidPatch, Param: key, Value: fmt.Sprint(raw), Reason: "is not a valid int", Code: "invalid_int"}
+		}
+		l.expected, found = n, true
+	}
+	if !found {
+		return nil, &QueryError{Kind: ErrInvalidPatch, Param: l.version.DBName, Reason: "is required", Code: "required"}
+	}
+	return rest, nil
+}
@@ -0,0 +1,168 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Tagihan: optimistic locking dengan kolom versi
+type Tagihan struct {
+	ID        uint      `json:"id"`
+	Nomor     string    `json:"nomor" gorm:"uniqueIndex"`
+	Total     int       `json:"total"`
+	Versi     int       `json:"versi"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var lama = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+func TestPatchGenericRejectsTimestampColumns(t *testing.T) {
+	cases := []struct {
+		name  string
+		patch map[string]interface{}
+		opts  WriteOptions
+		param string
+	}{
+		{"updated_at", map[string]interface{}{"status": "batal", "updated_at": "2099-01-01T00:00:00Z"}, WriteOptions{}, "updated_at"},
+		{"go field name", map[string]interface{}{"UpdatedAt": "2099-01-01"}, WriteOptions{}, "UpdatedAt"},
+		{"listed in WritableColumns", map[string]interface{}{"updated_at": "2099-01-01"}, WriteOptions{WritableColumns: []string{"status", "updated_at"}}, "updated_at"},
+		{"with UnmodifiedSince", map[string]interface{}{"updated_at": "2099-01-01"}, WriteOptions{UnmodifiedSince: lama}, "updated_at"},
+		{"created_at", map[string]interface{}{"created_at": "2020-01-01"}, WriteOptions{}, "created_at"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			o := seedOrders(t, db, 1, 0)[0]
+			db.Model(&Order{}).Where("id = ?", o.ID).UpdateColumn("updated_at", lama)
+			_, err := PatchGeneric[Order](context.Background(), db, o.ID, tc.patch, tc.opts)
+			var qe *QueryError
+			if !errors.Is(err, ErrInvalidPatch) || !errors.As(err, &qe) || qe.Param != tc.param || qe.Code != "protected_field" {
+				t.Fatalf("err = %v, want protected_field for %s", err, tc.param)
+			}
+			var got Order
+			db.First(&got, o.ID)
+			if !got.UpdatedAt.Equal(lama) || got.Status != o.Status {
+				t.Fatalf("row changed: %+v", got)
+			}
+
+			_, err = BulkUpdateByQuery(context.Background(), url.Values{"filter[kode]": {o.Kode}}, db.Model(&Order{}), &Order{}, Options{}, tc.patch, tc.opts)
+			if !errors.Is(err, ErrInvalidPatch) {
+				t.Fatalf("bulk update: err = %v, want ErrInvalidPatch", err)
+			}
+		})
+	}
+}
+
+func TestUnmodifiedSince(t *testing.T) {
+	cases := []struct {
+		name  string
+		since time.Time
+		stale bool
+	}{
+		{"unchanged since", lama, false},
+		{"same second", lama.Add(500 * time.Millisecond), false},
+		{"changed after", lama.Add(-time.Second), true},
+	}
+	for _, tc := range cases {
+		for _, op := range []string{"patch", "update"} {
+			t.Run(tc.name+"/"+op, func(t *testing.T) {
+				db := newTestDB(t)
+				o := seedOrders(t, db, 1, 0)[0]
+				db.Model(&Order{}).Where("id = ?", o.ID).UpdateColumn("updated_at", lama)
+				opts := WriteOptions{UnmodifiedSince: tc.since}
+				var err error
+				if op == "patch" {
+					_, err = PatchGeneric[Order](context.Background(), db, o.ID, map[string]interface{}{"status": "batal"}, opts)
+				} else {
+					// updated_at dari payload tidak pernah ditulis: gorm mengisinya sendiri
+					_, err = UpdateGeneric(context.Background(), db, o.ID, &Order{Kode: o.Kode, Status: "batal", UpdatedAt: lama.AddDate(10, 0, 0)}, opts)
+				}
+				var got Order
+				db.First(&got, o.ID)
+				if tc.stale {
+					if !errors.Is(err, ErrStaleRecord) || got.Status != o.Status || !got.UpdatedAt.Equal(lama) {
+						t.Fatalf("err = %v, row %+v: want ErrStaleRecord and no change", err, got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != "batal" || !got.UpdatedAt.After(lama) || got.UpdatedAt.After(time.Now()) {
+					t.Fatalf("row %+v: want status batal and updated_at set by the server", got)
+				}
+			})
+		}
+	}
+}
+
+func TestVersionColumn(t *testing.T) {
+	cases := []struct {
+		name  string
+		patch map[string]interface{}
+		want  error
+		versi int
+	}{
+		{"current version", map[string]interface{}{"total": 200, "versi": 3}, nil, 4},
+		{"json number", map[string]interface{}{"total": 200, "versi": float64(3)}, nil, 4},
+		{"stale version", map[string]interface{}{"total": 200, "versi": 2}, ErrStaleRecord, 3},
+		{"missing version", map[string]interface{}{"total": 200}, ErrInvalidPatch, 3},
+		{"version only", map[string]interface{}{"versi": 3}, nil, 4},
+	}
+	opts := WriteOptions{VersionColumn: "versi"}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if err := db.AutoMigrate(&Tagihan{}); err != nil {
+				t.Fatal(err)
+			}
+			tg := Tagihan{Nomor: "INV-1", Total: 100, Versi: 3}
+			db.Create(&tg)
+			out, err := PatchGeneric[Tagihan](context.Background(), db, tg.ID, tc.patch, opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			var got Tagihan
+			db.First(&got, tg.ID)
+			if got.Versi != tc.versi || err == nil && out.Versi != tc.versi {
+				t.Fatalf("versi %d (returned %+v), want %d", got.Versi, out, tc.versi)
+			}
+		})
+	}
+
+	t.Run("update", func(t *testing.T) {
+		db := newTestDB(t)
+		if err := db.AutoMigrate(&Tagihan{}); err != nil {
+			t.Fatal(err)
+		}
+		tg := Tagihan{Nomor: "INV-1", Total: 100, Versi: 3}
+		db.Create(&tg)
+		if _, err := UpdateGeneric(context.Background(), db, tg.ID, &Tagihan{Nomor: "INV-1", Total: 5, Versi: 2}, opts); !errors.Is(err, ErrStaleRecord) {
+			t.Fatalf("stale update: err = %v", err)
+		}
+		payload := &Tagihan{Nomor: "INV-1", Total: 5, Versi: 3}
+		out, err := UpdateGeneric(context.Background(), db, tg.ID, payload, opts)
+		if err != nil || out.Versi != 4 || out.Total != 5 || payload.Versi != 4 {
+			t.Fatalf("update: %+v, payload %+v, err %v", out, payload, err)
+		}
+	})
+}
+
+func TestUpsertGenericRefreshesUpdatedAt(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Tagihan{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&Tagihan{Nomor: "INV-1", Total: 100})
+	db.Model(&Tagihan{}).Where("nomor = ?", "INV-1").UpdateColumn("updated_at", lama)
+	out, inserted, err := UpsertGeneric(context.Background(), db, &Tagihan{Nomor: "INV-1", Total: 150}, []string{"nomor"}, nil, WriteOptions{})
+	if err != nil || inserted {
+		t.Fatalf("inserted %v, err %v", inserted, err)
+	}
+	if out.Total != 150 || !out.UpdatedAt.After(lama) {
+		t.Fatalf("row %+v: want total 150 and a fresh updated_at", out)
+	}
+}
@@ -0,0 +1,58 @@
+package magicrest
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// logBuild: Options.Logger untuk BuildQuerySource — warn untuk pageSize yang dipotong ke MaxPageSize, parameter
+// yang diabaikan (QueryInfo.Warnings: preload di luar whitelist, kolom tidak dikenal, distinct; ?preload= dengan
+// PreloadServerOnly) dan query yang ditolak. ctx dari db sehingga correlation id di context ikut ke handler slog.
+func logBuild(ctx context.Context, opts Options, model string, query QuerySource, info QueryInfo, err error) {
+	l := opts.Logger
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+	if err != nil {
+		if code := rejectedCode(err); code != "" {
+			l.WarnContext(ctx, "magicrest: query rejected", "model", model, "code", code, "error", err)
+		}
+		return
+	}
+	if requested, _ := strconv.Atoi(query.Get("pageSize")); requested > 0 && containsString(info.Params.Defaults, "maxPageSize") {
+		l.WarnContext(ctx, "magicrest: pageSize clamped", "model", model, "requested", requested, "pageSize", info.PageSize)
+	}
+	if len(info.Warnings) > 0 {
+		l.WarnContext(ctx, "magicrest: query parameters ignored", "model", model, "warnings", info.Warnings)
+	}
+	if opts.PreloadMergeMode == PreloadServerOnly && requestsPreloads(query) {
+		l.WarnContext(ctx, "magicrest: query preloads ignored (PreloadServerOnly)", "model", model)
+	}
+}
+
+// logList: Options.Logger, debug — query yang di-parse, durasi dan hasil satu list
+func logList[T any](ctx context.Context, l *slog.Logger, info QueryInfo, res Result[T], start time.Time, err error) {
+	if !l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{"model", modelName[T](), "duration", time.Since(start), "rows", len(res.Data)}
+	if info.PageSize > 0 { // 0 = query gagal di-parse
+		attrs = append(attrs, slog.Group("params", "page", info.Page, "pageSize", info.PageSize, "filters", info.Filters,
+			"search", info.Search, "order", info.Order, "preloads", info.Preloads, "fields", info.Fields))
+	}
+	if p, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+		attrs = append(attrs, "total", p["total"])
+	}
+	if cached, ok := res.Meta["cached"].(bool); ok {
+		attrs = append(attrs, "cached", cached)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	l.DebugContext(ctx, "magicrest: list", attrs...)
+}
@@ -0,0 +1,116 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// correlationKey: key context correlation id (request_id), dibaca logRecorder
+type correlationKey struct{}
+
+// logRecorder: slog.Handler yang mencatat pesan record dengan level minimal level; lines juga memuat level,
+// atribut dan request id dari context
+type logRecorder struct {
+	mu    sync.Mutex
+	level slog.Level
+	msgs  []string
+	lines []string
+}
+
+func (h *logRecorder) Enabled(_ context.Context, l slog.Level) bool { return l >= h.level }
+
+func (h *logRecorder) Handle(ctx context.Context, r slog.Record) error {
+	line := r.Level.String() + " " + r.Message
+	if id, ok := ctx.Value(correlationKey{}).(string); ok {
+		line += " request_id=" + id
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		line += " " + a.String()
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.msgs = append(h.msgs, r.Message)
+	h.lines = append(h.lines, line)
+	return nil
+}
+
+func (h *logRecorder) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *logRecorder) WithGroup(string) slog.Handler      { return h }
+
+// count: jumlah record dengan pesan yang mengandung sub
+func (h *logRecorder) count(sub string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, m := range h.msgs {
+		if strings.Contains(m, sub) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestOptionsLogger(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		level slog.Level
+		want  []string // awalan tiap line, urut
+	}{
+		{"clamped pageSize", url.Values{"pageSize": {"500"}}, Options{MaxPageSize: 2}, slog.LevelWarn,
+			[]string{"WARN magicrest: pageSize clamped request_id=r-1 model=Order requested=500 pageSize=2"}},
+		{"rejected", url.Values{"filter[gudang_id]": {"satu"}}, Options{DefaultFieldTypes: map[string]string{"gudang_id": "int"}}, slog.LevelWarn,
+			[]string{"WARN magicrest: query rejected request_id=r-1 model=Order code=invalid_filter error="}},
+		{"quiet list", url.Values{"filter[status]": {"aktif"}}, Options{}, slog.LevelWarn, nil},
+		{"debug list", url.Values{"filter[status]": {"aktif"}}, Options{}, slog.LevelDebug,
+			[]string{"DEBUG magicrest: list request_id=r-1 model=Order duration="}},
+		{"debug after rejection", url.Values{"filter[gudang_id]": {"satu"}}, Options{DefaultFieldTypes: map[string]string{"gudang_id": "int"}}, slog.LevelDebug,
+			[]string{"WARN magicrest: query rejected", "DEBUG magicrest: list request_id=r-1 model=Order duration="}},
+		{"handler above warn", url.Values{"pageSize": {"500"}}, Options{MaxPageSize: 2}, slog.LevelError, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := &logRecorder{level: tc.level}
+			tc.opts.OrderBy, tc.opts.Logger = "id", slog.New(logs)
+			ctx := context.WithValue(context.Background(), correlationKey{}, "r-1")
+			ReadPaginatedCtx(ctx, tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if len(logs.lines) != len(tc.want) {
+				t.Fatalf("lines %q, want %q", logs.lines, tc.want)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(logs.lines[i], want) {
+					t.Fatalf("line %q, want prefix %q", logs.lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsLoggerDebugAttrs(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	logs := &logRecorder{level: slog.LevelDebug}
+	query := url.Values{"filter[status]": {"aktif"}, "pageSize": {"1"}, "order": {"id desc"}}
+	if _, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{Logger: slog.New(logs)}); err != nil {
+		t.Fatal(err)
+	}
+	line := fmt.Sprint(logs.lines)
+	for _, want := range []string{"rows=1", "params=[page=1 pageSize=1 filters=map[status:aktif]", "order=id desc", "total=2"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("%s: missing %s", line, want)
+		}
+	}
+	// tanpa Logger tidak ada yang dicatat dan tidak panic
+	if _, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package magicrest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// MaskFunc mengosongkan/menyamarkan data sensitif satu item (pointer ke T) berdasarkan context request.
+type MaskFunc func(ctx context.Context, item any)
+
+// RoleFunc membaca role pemanggil (mis. dari user yang terautentikasi di context).
+type RoleFunc func(ctx context.Context) string
+
+// applyMasks menjalankan Options.MaskedColumns (sesuai role dari Options.MaskRole) lalu
+// Options.MaskFields untuk tiap item. ctx diambil dari db.WithContext milik caller.
+func applyMasks[T any](ctx context.Context, data []T, opts Options) {
+	var paths [][]string
+	if opts.MaskRole != nil && len(opts.MaskedColumns) > 0 {
+		for _, c := range opts.MaskedColumns[opts.MaskRole(ctx)] {
+			paths = append(paths, strings.Split(c, "."))
+		}
+	}
+	if len(paths) == 0 && opts.MaskFields == nil {
+		return
+	}
+	for i := range data {
+		for _, p := range paths {
+			zeroPath(reflect.ValueOf(&data[i]), p)
+		}
+		if opts.MaskFields != nil {
+			opts.MaskFields(ctx, &data[i])
+		}
+	}
+}
+
+// zeroPath mengosongkan field berdasarkan nama json (atau nama field Go), e.g. "telepon" atau
+// "pelanggan.alamat". Pointer dan slice (hasil preload) ditelusuri.
+func zeroPath(v reflect.Value, path []string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			zeroPath(v.Index(i), path)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	f, ok := fieldByJSONName(v, path[0])
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		if f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+		return
+	}
+	zeroPath(f, path[1:])
+}
+
+// fieldByJSONName mencari field struct (termasuk embedded) dengan nama json atau nama Go yang cocok.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if jsonName == name || (jsonName == "" && strings.EqualFold(sf.Name, name)) {
+			return v.Field(i), true
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if f, ok := fieldByJSONName(v.Field(i), name); ok {
+				return f, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
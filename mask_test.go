@@ -0,0 +1,103 @@
+package magicrest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+type roleKey struct{}
+
+func withRole(role string) context.Context {
+	return context.WithValue(context.Background(), roleKey{}, role)
+}
+
+func TestReadPaginatedMaskedColumns(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 2)
+	db.Model(&Item{}).Where("1 = 1").Update("catatan", "harga khusus")
+	opts := Options{
+		OrderBy:  "id",
+		MaskRole: func(ctx context.Context) string { role, _ := ctx.Value(roleKey{}).(string); return role },
+		MaskedColumns: map[string][]string{
+			"kasir": {"telepon", "items.catatan", "gudang.nama", "items.produk.nama"},
+		},
+	}
+	query := url.Values{"preload": {"Items.Produk,Gudang"}}
+	cases := []struct {
+		role   string
+		masked bool
+	}{
+		{"kasir", true},
+		{"admin", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		t.Run("role "+tc.role, func(t *testing.T) {
+			res, err := ReadPaginatedCtx(withRole(tc.role), query, db.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, o := range res.Data {
+				if got := o.Telepon == ""; got != tc.masked {
+					t.Fatalf("telepon %q, masked %v", o.Telepon, tc.masked)
+				}
+				if got := o.Gudang.Nama == ""; got != tc.masked {
+					t.Fatalf("gudang.nama %q, masked %v", o.Gudang.Nama, tc.masked)
+				}
+				if o.Gudang.Kode == "" || o.Kode == "" {
+					t.Fatalf("unmasked field cleared: %+v", o)
+				}
+				for _, it := range o.Items {
+					if got := it.Catatan == ""; got != tc.masked {
+						t.Fatalf("items.catatan %q, masked %v", it.Catatan, tc.masked)
+					}
+					if got := it.Produk.Nama == ""; got != tc.masked {
+						t.Fatalf("items.produk.nama %q, masked %v", it.Produk.Nama, tc.masked)
+					}
+					if it.Nama == "" {
+						t.Fatalf("unmasked item field cleared: %+v", it)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReadPaginatedMaskFields(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+	calls := 0
+	opts := Options{
+		OrderBy:  "id",
+		MaskRole: func(context.Context) string { return "kasir" },
+		// MaskedColumns lebih dulu, MaskFields menerima item yang sudah di-mask
+		MaskedColumns: map[string][]string{"kasir": {"status"}},
+		MaskFields: func(ctx context.Context, item any) {
+			calls++
+			if ctx.Value(roleKey{}) != "kasir" {
+				t.Errorf("MaskFields got a context without the request role")
+			}
+			o := item.(*Order)
+			if o.Status != "" {
+				t.Errorf("MaskedColumns not applied before MaskFields: %+v", o)
+			}
+			o.Telepon = o.Telepon[:2] + "**"
+			for i := range o.Items {
+				o.Items[i].Nama = "***"
+			}
+		},
+	}
+	res, err := ReadPaginatedCtx(withRole("kasir"), url.Values{"preload": {"Items"}}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("MaskFields called %d times, want 3", calls)
+	}
+	for _, o := range res.Data {
+		if o.Telepon != "08**" || o.Items[0].Nama != "***" {
+			t.Fatalf("mask not applied: %+v", o)
+		}
+	}
+}
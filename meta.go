@@ -0,0 +1,159 @@
+package magicrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"gorm.io/gorm"
+)
+
+// Pagination: bentuk typed dari Meta["pagination"]
+type Pagination struct {
+	Page      int   `json:"page"`
+	PageSize  int   `json:"pageSize"`
+	PageCount int   `json:"pageCount"`
+	Total     int64 `json:"total"`
+	HasNext   bool  `json:"hasNext"`
+	HasPrev   bool  `json:"hasPrev"`
+}
+
+// Meta: bentuk typed dari Result.Meta. Key yang tidak dikenal (mis. dari TransformResult) ada di
+// Extra; JSON-nya identik dengan map aslinya.
+type Meta struct {
+	Pagination     Pagination
+	Counts         map[string]map[string]interface{}
+	ImplicitFields []string
+	Warnings       []string
+	Query          *QueryEcho
+	SQL            *SQLDebug
+	Stats          *QueryStats
+	Extra          map[string]interface{}
+}
+
+// TypedResult: Result dengan Meta bertipe M (umumnya Meta)
+type TypedResult[T any, M any] struct {
+	Data []T
+	Meta M
+}
+
+// Typed mengubah Result ke TypedResult[T, Meta] tanpa type assertion di sisi pemanggil.
+func (r Result[T]) Typed() TypedResult[T, Meta] {
+	return TypedResult[T, Meta]{Data: r.Data, Meta: metaFromMap(r.Meta)}
+}
+
+// ReadPaginatedTyped: ReadPaginated dengan Meta bertipe.
+func ReadPaginatedTyped[T any](query url.Values, db *gorm.DB, modelPtr *T, opts Options) (TypedResult[T, Meta], error) {
+	return ReadPaginatedTypedCtx[T](db.Statement.Context, query, db, modelPtr, opts)
+}
+
+// ReadPaginatedTypedCtx: ReadPaginatedCtx dengan Meta bertipe.
+func ReadPaginatedTypedCtx[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options) (TypedResult[T, Meta], error) {
+	res, err := ReadPaginatedCtx[T](ctx, query, db, modelPtr, opts)
+	return res.Typed(), err
+}
+
+// metaFromMap memetakan map Meta ke struct Meta
+func metaFromMap(m map[string]interface{}) Meta {
+	var out Meta
+	for k, v := range m {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if k == "pagination" {
+				out.Pagination = paginationFromMap(val)
+				continue
+			}
+		case map[string]map[string]interface{}:
+			if k == "counts" {
+				out.Counts = val
+				continue
+			}
+		case []string:
+			if k == "implicitFields" {
+				out.ImplicitFields = val
+				continue
+			}
+			if k == "warnings" {
+				out.Warnings = val
+				continue
+			}
+		case QueryEcho:
+			if k == "query" {
+				out.Query = &val
+				continue
+			}
+		case SQLDebug:
+			if k == "sql" {
+				out.SQL = &val
+				continue
+			}
+		case QueryStats:
+			if k == "stats" {
+				out.Stats = &val
+				continue
+			}
+		}
+		if out.Extra == nil {
+			out.Extra = map[string]interface{}{}
+		}
+		out.Extra[k] = v
+	}
+	return out
+}
+
+// paginationFromMap membaca map pagination dari PaginateGeneric
+func paginationFromMap(m map[string]interface{}) Pagination {
+	var p Pagination
+	p.Page, _ = m["page"].(int)
+	p.PageSize, _ = m["pageSize"].(int)
+	p.PageCount, _ = m["pageCount"].(int)
+	p.Total, _ = m["total"].(int64)
+	p.HasNext, _ = m["hasNext"].(bool)
+	p.HasPrev, _ = m["hasPrev"].(bool)
+	return p
+}
+
+// Map mengembalikan Pagination ke bentuk map seperti Meta["pagination"]
+func (p Pagination) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"page":      p.Page,
+		"pageSize":  p.PageSize,
+		"pageCount": p.PageCount,
+		"total":     p.Total,
+		"hasNext":   p.HasNext,
+		"hasPrev":   p.HasPrev,
+	}
+}
+
+// Map mengembalikan Meta ke bentuk map seperti Result.Meta
+func (m Meta) Map() map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range m.Extra {
+		out[k] = v
+	}
+	out["pagination"] = m.Pagination.Map()
+	if m.Counts != nil {
+		out["counts"] = m.Counts
+	}
+	if len(m.ImplicitFields) > 0 {
+		out["implicitFields"] = m.ImplicitFields
+	}
+	if len(m.Warnings) > 0 {
+		out["warnings"] = m.Warnings
+	}
+	if m.Query != nil {
+		out["query"] = m.Query
+	}
+	if m.SQL != nil {
+		out["sql"] = m.SQL
+	}
+	if m.Stats != nil {
+		out["stats"] = m.Stats
+	}
+	return out
+}
+
+// MarshalJSON menghasilkan JSON yang sama dengan Result.Meta versi map
+func (m Meta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Map())
+}
@@ -0,0 +1,46 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestReadPaginatedTyped(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	opts := Options{
+		OrderBy:         "id",
+		EchoQuery:       true,
+		Debug:           true,
+		TransformResult: func(meta map[string]interface{}) { meta["server"] = "api-1" },
+	}
+	query := url.Values{"page": {"2"}, "pageSize": {"2"}, "distinct": {"true"}}
+	typed, err := ReadPaginatedTyped(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Pagination{Page: 2, PageSize: 2, PageCount: 3, Total: 5, HasNext: true, HasPrev: true}
+	if typed.Meta.Pagination != want || len(typed.Data) != 2 || typed.Data[0].Kode != "ORD-03" {
+		t.Fatalf("pagination %+v, data %v", typed.Meta.Pagination, typed.Data)
+	}
+	m := typed.Meta
+	if m.Query == nil || m.Query.Page != 2 || m.SQL == nil || m.SQL.Data == "" || len(m.Warnings) != 1 || m.Extra["server"] != "api-1" {
+		t.Fatalf("meta %+v", m)
+	}
+
+	// JSON identik dengan Result versi map
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := json.Marshal(res)
+	typedJSON, _ := json.Marshal(typed)
+	if string(plain) != string(typedJSON) {
+		t.Fatalf("JSON differs:\n%s\n%s", plain, typedJSON)
+	}
+	back, _ := json.Marshal(res.Typed().Meta.Map())
+	if meta, _ := json.Marshal(res.Meta); string(back) != string(meta) {
+		t.Fatalf("Meta.Map round trip:\n%s\n%s", back, meta)
+	}
+}
@@ -0,0 +1,90 @@
+package magicrest
+
+import (
+	"errors"
+	"time"
+)
+
+// Recorder: metrik opsional per operasi (Options.Metrics / WriteOptions.Metrics / DefaultMetrics). Core tidak
+// bergantung pada Prometheus; modul prommetrics menyediakan implementasinya. Dipanggil dari goroutine request,
+// jadi implementasi harus aman untuk dipakai bersamaan.
+type Recorder interface {
+	// ObserveQuery: durasi satu operasi (OpList, OpCreate, ...) atau query di dalamnya (QueryLabelData,
+	// QueryLabelCount, ...); err = gagal karena database / timeout, bukan karena input klien
+	ObserveQuery(model, phase string, d time.Duration, err bool)
+	// ObserveRows: jumlah row hasil list, export, atau yang terkena write bulk
+	ObserveRows(model string, n int)
+	// ObserveRejected: request ditolak dengan kode error stabil (invalid_filter, page_size_too_large,
+	// validation_failed, ...); tidak ikut ObserveQuery agar latensi tidak tercampur penolakan cepat
+	ObserveRejected(model, code string)
+}
+
+// DefaultMetrics dipakai bila Options.Metrics / WriteOptions.Metrics nil (nil = tanpa metrik), juga oleh
+// PaginateGeneric. prommetrics.Install() mengisinya. Set sekali saat init.
+var DefaultMetrics Recorder
+
+// operasi untuk Recorder.ObserveQuery dan AuditEntry.Operation (query di dalamnya memakai label QueryLabel*)
+const (
+	OpList        = "list"         // ReadPaginated, ReadRange, handler list
+	OpReadOne     = "read_one"     // ReadOne, ReadOneBy, ReadFirst
+	OpReadAll     = "read_all"     // ReadAll, ReadStream
+	OpExport      = "export"       // PrepareExport sampai Write selesai, StreamList
+	OpCreate      = "create"       // CreateGeneric
+	OpUpdate      = "update"       // UpdateGeneric
+	OpPatch       = "patch"        // PatchGeneric
+	OpDelete      = "delete"       // DeleteGeneric
+	OpUpsert      = "upsert"       // UpsertGeneric
+	OpRestore     = "restore"      // RestoreGeneric
+	OpBulkCreate  = "bulk_create"  // BulkCreateGeneric
+	OpBulkUpdate  = "bulk_update"  // BulkUpdateByQuery
+	OpBulkDelete  = "bulk_delete"  // BulkDeleteByQuery
+	OpBulkRestore = "bulk_restore" // BulkRestoreByQuery
+)
+
+// metricsFor: rec, atau DefaultMetrics
+func metricsFor(rec Recorder) Recorder {
+	if rec != nil {
+		return rec
+	}
+	return DefaultMetrics
+}
+
+// metricOp mengukur satu operasi untuk Recorder; nil = tidak aktif
+type metricOp struct {
+	rec   Recorder
+	model string
+	op    string
+	start time.Time
+}
+
+// startOp: nil bila rec dan DefaultMetrics nil
+func startOp[T any](rec Recorder, op string) *metricOp {
+	rec = metricsFor(rec)
+	if rec == nil {
+		return nil
+	}
+	return &metricOp{rec: rec, model: modelName[T](), op: op, start: time.Now()}
+}
+
+// end melaporkan durasi dan rows (negatif = tidak dilaporkan), atau penolakan bila err punya kode stabil
+func (m *metricOp) end(rows int, err error) {
+	if m == nil {
+		return
+	}
+	if code := rejectedCode(err); code != "" {
+		m.rec.ObserveRejected(m.model, code)
+		return
+	}
+	m.rec.ObserveQuery(m.model, m.op, time.Since(m.start), err != nil)
+	if err == nil && rows >= 0 {
+		m.rec.ObserveRows(m.model, rows)
+	}
+}
+
+// rejectedCode: kode error stabil untuk penolakan karena input klien; timeout tetap dihitung sebagai error query
+func rejectedCode(err error) string {
+	if err == nil || errors.Is(err, ErrQueryTimeout) {
+		return ""
+	}
+	return errorCode(err)
+}
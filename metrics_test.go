@@ -0,0 +1,102 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRecorder: Recorder yang mencatat panggilan sebagai string
+type fakeRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *fakeRecorder) add(format string, args ...any) {
+	r.mu.Lock()
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+	r.mu.Unlock()
+}
+
+func (r *fakeRecorder) ObserveQuery(model, phase string, d time.Duration, err bool) {
+	if d < 0 {
+		r.add("negative duration %s/%s", model, phase)
+	}
+	r.add("query %s/%s err=%v", model, phase, err)
+}
+
+func (r *fakeRecorder) ObserveRows(model string, n int) { r.add("rows %s %d", model, n) }
+
+func (r *fakeRecorder) ObserveRejected(model, code string) { r.add("rejected %s %s", model, code) }
+
+// take: panggilan sejauh ini (urut), lalu dikosongkan
+func (r *fakeRecorder) take() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	sort.Strings(calls)
+	return fmt.Sprint(calls)
+}
+
+func TestMetricsList(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	rec := &fakeRecorder{}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  string
+	}{
+		{"list", url.Values{"pageSize": {"2"}}, Options{},
+			"[query Order/count err=false query Order/data err=false query Order/list err=false rows Order 2]"},
+		{"database error", url.Values{"order": {"tidak_ada"}}, Options{},
+			"[query Order/count err=false query Order/data err=true query Order/list err=true]"},
+		{"invalid filter", url.Values{"filter[gudang_id]": {"satu"}}, Options{DefaultFieldTypes: map[string]string{"gudang_id": "int"}},
+			"[rejected Order invalid_filter]"},
+		{"page size too large", url.Values{"pageSize": {"50"}}, Options{MaxPageSize: 10, StrictQuery: true},
+			"[rejected Order page_size_too_large]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy, tc.opts.Metrics = "id", rec
+			ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if got := rec.take(); got != tc.want {
+				t.Fatalf("got %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMetricsWriteAndDefault(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	rec := &fakeRecorder{}
+	opts := WriteOptions{Metrics: rec}
+	if _, err := CreateGeneric(context.Background(), db, &Order{Kode: "ORD-09"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	CreateGeneric(context.Background(), db, &Order{Kode: "ORD-01"}, opts)
+	DeleteGeneric[Order](context.Background(), db, 99, opts)
+	if got := rec.take(); got != "[query Order/create err=false rejected Order conflict rejected Order not_found]" {
+		t.Fatalf("writes: %s", got)
+	}
+
+	// DefaultMetrics dipakai bila Metrics nil, juga oleh PaginateGeneric
+	DefaultMetrics = rec
+	t.Cleanup(func() { DefaultMetrics = nil })
+	if _, _, err := PaginateGeneric(db.Model(&Order{}), &Order{}, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PatchGeneric[Order](context.Background(), db, 1, map[string]interface{}{"status": "batal"}, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.take(); got != "[query Order/count err=false query Order/data err=false query Order/patch err=false]" {
+		t.Fatalf("DefaultMetrics: %s", got)
+	}
+}
@@ -0,0 +1,76 @@
+package magicrest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   Format
+	}{
+		{"", FormatJSON},
+		{"*/*", FormatJSON},
+		{"text/html", FormatJSON},
+		{"text/csv", FormatCSV},
+		{"application/x-ndjson", FormatNDJSON},
+		{"application/ndjson", FormatNDJSON},
+		{"text/html, text/csv;q=0.8", FormatCSV},
+		{"text/csv;q=0.5, application/json", FormatJSON},
+		{"application/json;q=0.2, application/x-ndjson;q=0.9", FormatNDJSON},
+		{"text/csv;q=0", FormatJSON},
+		{"text/csv;;;", FormatJSON},
+	}
+	for _, tc := range cases {
+		if got := NegotiateFormat(tc.accept); got != tc.want {
+			t.Fatalf("NegotiateFormat(%q) = %s, want %s", tc.accept, got, tc.want)
+		}
+	}
+}
+
+func TestListHandlerNegotiateContent(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	opts := Options{OrderBy: "id", NegotiateContent: true, DefaultFieldTypes: map[string]string{"gudang_id": "int"}}
+	cases := []struct {
+		name        string
+		opts        Options
+		target      string
+		accept      string
+		status      int
+		contentType string
+		lines       int    // baris body (CSV: header + row)
+		want        string // potongan body
+	}{
+		{"json default", opts, "/orders?pageSize=1", "", http.StatusOK, "application/json", 1, `"pagination"`},
+		{"unknown accept", opts, "/orders?pageSize=1", "text/html", http.StatusOK, "application/json", 1, `"pagination"`},
+		{"csv ignores page", opts, "/orders?pageSize=1&filter[status]=aktif", "text/csv", http.StatusOK, "text/csv; charset=utf-8", 3, "ORD-03"},
+		{"ndjson", opts, "/orders?pageSize=1", "application/x-ndjson", http.StatusOK, "application/x-ndjson", 3, `{"id":1,"kode":"ORD-01"`},
+		{"invalid filter", opts, "/orders?filter[gudang_id]=satu", "text/csv", http.StatusBadRequest, "application/json", 1, `"error"`},
+		{"export cap", Options{OrderBy: "id", NegotiateContent: true, ExportMaxRows: 2}, "/orders", "text/csv", http.StatusBadRequest, "application/json", 1, "export_too_large"},
+		{"disabled", Options{OrderBy: "id"}, "/orders", "text/csv", http.StatusOK, "application/json", 1, `"pagination"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			ListHandlerHTTP[Order](db, tc.opts)(rec, req)
+			body := strings.TrimSuffix(rec.Body.String(), "\n")
+			if rec.Code != tc.status || !strings.HasPrefix(rec.Header().Get("Content-Type"), tc.contentType) {
+				t.Fatalf("status %d, Content-Type %q: %s", rec.Code, rec.Header().Get("Content-Type"), body)
+			}
+			if n := len(strings.Split(body, "\n")); n != tc.lines || !strings.Contains(body, tc.want) {
+				t.Fatalf("%d lines, want %d with %s:\n%s", n, tc.lines, tc.want, body)
+			}
+			if tc.opts.NegotiateContent && rec.Header().Get("Vary") != "Accept" {
+				t.Fatalf("Vary %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
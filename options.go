@@ -0,0 +1,229 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOption digunakan bila functional option menerima input tidak valid atau setting saling bertentangan
+var ErrInvalidOption = errors.New("invalid option")
+
+// Option mengubah Options dan memvalidasi inputnya saat NewOptions dipanggil.
+type Option func(*Options) error
+
+// NewOptions menyusun Options dari functional options. Input tiap option divalidasi langsung dan
+// kombinasi yang bertentangan dikembalikan sebagai ErrInvalidOption. Struct Options tetap bisa
+// diisi langsung untuk kompatibilitas.
+func NewOptions(opts ...Option) (Options, error) {
+	var o Options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return Options{}, err
+		}
+	}
+	if err := o.checkConflicts(); err != nil {
+		return Options{}, err
+	}
+	return o, nil
+}
+
+// checkConflicts: setting yang masing-masing valid tapi tidak masuk akal bila digabung
+func (o Options) checkConflicts() error {
+	if o.MaxPageSize > 0 && o.DefaultPageSize > o.MaxPageSize {
+		return fmt.Errorf("%w: DefaultPageSize %d exceeds MaxPageSize %d", ErrInvalidOption, o.DefaultPageSize, o.MaxPageSize)
+	}
+	if len(o.DistinctFields) > 0 && !o.AllowDistinct {
+		return fmt.Errorf("%w: DistinctFields is set but AllowDistinct is false", ErrInvalidOption)
+	}
+	if o.PreloadMergeMode == PreloadServerOnly && o.AllowedPreloads != nil {
+		return fmt.Errorf("%w: AllowedPreloads has no effect with PreloadServerOnly", ErrInvalidOption)
+	}
+	return nil
+}
+
+// nonEmptyNames memastikan daftar nama tidak kosong dan tidak berisi string kosong
+func nonEmptyNames(option string, names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%w: %s needs at least one name", ErrInvalidOption, option)
+	}
+	for _, n := range names {
+		if strings.TrimSpace(n) == "" {
+			return fmt.Errorf("%w: %s contains an empty name", ErrInvalidOption, option)
+		}
+	}
+	return nil
+}
+
+// WithSearchFields: kolom untuk ?search= (OR), e.g. WithSearchFields("nama", "kode")
+func WithSearchFields(fields ...string) Option {
+	return func(o *Options) error {
+		if err := nonEmptyNames("WithSearchFields", fields); err != nil {
+			return err
+		}
+		o.SearchFields = append(o.SearchFields, fields...)
+		return nil
+	}
+}
+
+// WithOrderBy: order default bila ?order= tidak diisi, e.g. "created_at desc"
+func WithOrderBy(order string) Option {
+	return func(o *Options) error {
+		if strings.TrimSpace(order) == "" {
+			return fmt.Errorf("%w: WithOrderBy needs an order clause", ErrInvalidOption)
+		}
+		o.OrderBy = order
+		return nil
+	}
+}
+
+// WithFieldTypes: tipe filter per kolom ("uuid", "int", "bool", "datetime", "date" atau "string")
+func WithFieldTypes(types map[string]string) Option {
+	return func(o *Options) error {
+		if o.DefaultFieldTypes == nil {
+			o.DefaultFieldTypes = map[string]string{}
+		}
+		for field, t := range types {
+			if !validFieldType(t) {
+				return fmt.Errorf("%w: unknown field type %q for %s", ErrInvalidOption, t, field)
+			}
+			o.DefaultFieldTypes[field] = t
+		}
+		return nil
+	}
+}
+
+// WithPageSize: ukuran halaman default
+func WithPageSize(size int) Option {
+	return func(o *Options) error {
+		if size <= 0 {
+			return fmt.Errorf("%w: page size must be positive, got %d", ErrInvalidOption, size)
+		}
+		o.DefaultPageSize = size
+		return nil
+	}
+}
+
+// WithMaxPageSize: batas atas ?pageSize=
+func WithMaxPageSize(size int) Option {
+	return func(o *Options) error {
+		if size <= 0 {
+			return fmt.Errorf("%w: max page size must be positive, got %d", ErrInvalidOption, size)
+		}
+		o.MaxPageSize = size
+		return nil
+	}
+}
+
+// WithPreloads: relasi yang selalu di-preload (PreloadFields), e.g. WithPreloads("Gudang", "Items.Produk")
+func WithPreloads(relations ...string) Option {
+	return func(o *Options) error {
+		if err := nonEmptyNames("WithPreloads", relations); err != nil {
+			return err
+		}
+		o.PreloadFields = append(o.PreloadFields, relations...)
+		return nil
+	}
+}
+
+// WithAllowedPreloads: whitelist ?preload= (memanggilnya tanpa argumen = tolak semua preload dari query)
+func WithAllowedPreloads(relations ...string) Option {
+	return func(o *Options) error {
+		for _, r := range relations {
+			if strings.TrimSpace(r) == "" {
+				return fmt.Errorf("%w: WithAllowedPreloads contains an empty name", ErrInvalidOption)
+			}
+		}
+		o.AllowedPreloads = append([]string{}, relations...)
+		return nil
+	}
+}
+
+// WithAllowedFilterFields: whitelist filter[...] (memanggilnya tanpa argumen = tolak semua filter dari klien)
+func WithAllowedFilterFields(fields ...string) Option {
+	return func(o *Options) error {
+		for _, f := range fields {
+			if strings.TrimSpace(f) == "" {
+				return fmt.Errorf("%w: WithAllowedFilterFields contains an empty name", ErrInvalidOption)
+			}
+		}
+		o.AllowedFilterFields = append([]string{}, fields...)
+		return nil
+	}
+}
+
+// WithAllowedOrderFields: whitelist ?order= (memanggilnya tanpa argumen = tolak semua order dari klien)
+func WithAllowedOrderFields(fields ...string) Option {
+	return func(o *Options) error {
+		for _, f := range fields {
+			if strings.TrimSpace(f) == "" {
+				return fmt.Errorf("%w: WithAllowedOrderFields contains an empty name", ErrInvalidOption)
+			}
+		}
+		o.AllowedOrderFields = append([]string{}, fields...)
+		return nil
+	}
+}
+
+// WithGroupBy mengizinkan ?groupby=
+func WithGroupBy() Option {
+	return func(o *Options) error {
+		o.AllowGroupBy = true
+		return nil
+	}
+}
+
+// WithDistinct mengizinkan ?distinct=true untuk kolom yang disebut
+func WithDistinct(fields ...string) Option {
+	return func(o *Options) error {
+		if err := nonEmptyNames("WithDistinct", fields); err != nil {
+			return err
+		}
+		o.AllowDistinct = true
+		o.DistinctFields = append(o.DistinctFields, fields...)
+		return nil
+	}
+}
+
+// WithSelectableColumns: whitelist kolom yang boleh di-select
+func WithSelectableColumns(columns ...string) Option {
+	return func(o *Options) error {
+		if err := nonEmptyNames("WithSelectableColumns", columns); err != nil {
+			return err
+		}
+		o.SelectableColumns = append(o.SelectableColumns, columns...)
+		return nil
+	}
+}
+
+// WithComputedColumns: kolom turunan alias -> ekspresi SQL
+func WithComputedColumns(columns map[string]string) Option {
+	return func(o *Options) error {
+		if o.ComputedColumns == nil {
+			o.ComputedColumns = map[string]string{}
+		}
+		for alias, expr := range columns {
+			if strings.TrimSpace(alias) == "" || strings.TrimSpace(expr) == "" {
+				return fmt.Errorf("%w: computed column needs an alias and an expression", ErrInvalidOption)
+			}
+			o.ComputedColumns[alias] = expr
+		}
+		return nil
+	}
+}
+
+// WithAutoFieldTypes: tipe filter diambil dari schema model (FieldTypesFromModel)
+func WithAutoFieldTypes() Option {
+	return func(o *Options) error {
+		o.AutoFieldTypes = true
+		return nil
+	}
+}
+
+// WithStrictFields: ?fields= / ?omit= dengan kolom tidak dikenal menghasilkan ErrInvalidField
+func WithStrictFields() Option {
+	return func(o *Options) error {
+		o.StrictFields = true
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	opts, err := NewOptions(
+		WithSearchFields("kode", "telepon"),
+		WithOrderBy("id desc"),
+		WithFieldTypes(map[string]string{"gudang_id": "int"}),
+		WithPageSize(20),
+		WithMaxPageSize(50),
+		WithPreloads("Gudang"),
+		WithAllowedPreloads("Items", "Items.Produk"),
+		WithDistinct("status"),
+		WithComputedColumns(map[string]string{"ganda": "jumlah * 2"}),
+		WithStrictFields(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprintln(opts.SearchFields, opts.OrderBy, opts.DefaultFieldTypes, opts.DefaultPageSize, opts.MaxPageSize,
+		opts.PreloadFields, opts.AllowedPreloads, opts.AllowDistinct, opts.DistinctFields, opts.ComputedColumns, opts.StrictFields)
+	want := "[kode telepon] id desc map[gudang_id:int] 20 50 [Gudang] [Items Items.Produk] true [status] map[ganda:jumlah * 2] true\n"
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+
+	// tanpa argumen: slice kosong = tolak semua preload dari query
+	if opts, err := NewOptions(WithAllowedPreloads()); err != nil || opts.AllowedPreloads == nil || len(opts.AllowedPreloads) != 0 {
+		t.Fatalf("WithAllowedPreloads(): %#v, err %v", opts.AllowedPreloads, err)
+	}
+}
+
+func TestNewOptionsErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []Option
+	}{
+		{"no search fields", []Option{WithSearchFields()}},
+		{"empty search field", []Option{WithSearchFields("kode", " ")}},
+		{"empty order", []Option{WithOrderBy("")}},
+		{"unknown field type", []Option{WithFieldTypes(map[string]string{"id": "angka"})}},
+		{"zero page size", []Option{WithPageSize(0)}},
+		{"negative max page size", []Option{WithMaxPageSize(-1)}},
+		{"empty preload", []Option{WithPreloads("")}},
+		{"empty allowed preload", []Option{WithAllowedPreloads("Items", "")}},
+		{"computed without expression", []Option{WithComputedColumns(map[string]string{"ganda": ""})}},
+		{"page size above max", []Option{WithPageSize(100), WithMaxPageSize(50)}},
+		{"distinct fields without distinct", []Option{func(o *Options) error { o.DistinctFields = []string{"status"}; return nil }}},
+		{"allowed preloads with server only", []Option{WithAllowedPreloads("Items"), func(o *Options) error { o.PreloadMergeMode = PreloadServerOnly; return nil }}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := NewOptions(tc.opts...)
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("err = %v, want ErrInvalidOption", err)
+			}
+			if opts.DefaultPageSize != 0 || opts.AllowedPreloads != nil {
+				t.Fatalf("partial options returned: %+v", opts)
+			}
+		})
+	}
+}
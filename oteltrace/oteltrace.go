@@ -0,0 +1,85 @@
+// Package oteltrace: magicrest.Tracer di atas OpenTelemetry. Modul terpisah (go.mod sendiri) agar SDK
+// OpenTelemetry tidak ikut ke dependency graph pemakai core.
+//
+//	opts.Tracer = oteltrace.New(tp) // atau oteltrace.Install() untuk provider global di semua Options
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName: nama tracer (instrumentation scope) untuk span magicrest
+const instrumentationName = "github.com/Jupriadi/magic-rest"
+
+// Tracer: magicrest.Tracer di atas trace.TracerProvider
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New: Tracer dari tp; nil = provider global (otel.GetTracerProvider), yang tetap mengikuti
+// otel.SetTracerProvider yang dipanggil belakangan
+func New(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+// Install mengisi magicrest.DefaultTracer dengan provider global, sehingga semua Options tanpa Tracer ikut
+// di-trace
+func Install() {
+	magicrest.DefaultTracer = New(nil)
+}
+
+// Start: magicrest.Tracer
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...magicrest.TraceAttr) (context.Context, magicrest.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(convert(attrs)...))
+	return ctx, otelSpan{span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(attrs ...magicrest.TraceAttr) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// convert: TraceAttr ke attribute.KeyValue (tipe lain ditulis sebagai string)
+func convert(attrs []magicrest.TraceAttr) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			out = append(out, attribute.String(a.Key, v))
+		case bool:
+			out = append(out, attribute.Bool(a.Key, v))
+		case int:
+			out = append(out, attribute.Int(a.Key, v))
+		case int64:
+			out = append(out, attribute.Int64(a.Key, v))
+		case float64:
+			out = append(out, attribute.Float64(a.Key, v))
+		case []string:
+			out = append(out, attribute.StringSlice(a.Key, v))
+		default:
+			out = append(out, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package oteltrace
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Barang struct {
+	ID     uint   `json:"id"`
+	Nama   string `json:"nama"`
+	Status string `json:"status"`
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Barang{{Nama: "a", Status: "aktif"}, {Nama: "b", Status: "aktif"}, {Nama: "c", Status: "arsip"}})
+	return db
+}
+
+// spansByName: span yang sudah selesai per nama
+func spansByName(rec *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	out := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		out[s.Name()] = s
+	}
+	return out
+}
+
+func attrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+func TestTracerListSpans(t *testing.T) {
+	cases := []struct {
+		name   string
+		query  url.Values
+		drop   bool              // tabel dihapus: query gagal
+		spans  []string          // span yang harus ada
+		errors map[string]bool   // span dengan status error
+		list   map[string]string // atribut span list (Value.Emit)
+	}{
+		{"ok", url.Values{"filter[status]": {"aktif"}, "pageSize": {"10"}}, false,
+			[]string{"magicrest.list", "magicrest.parse", "magicrest.count", "magicrest.data"}, nil,
+			map[string]string{
+				magicrest.TraceAttrModel: "Barang", magicrest.TraceAttrPage: "1", magicrest.TraceAttrPageSize: "10",
+				magicrest.TraceAttrFilters: `["status"]`, magicrest.TraceAttrResultCount: "2", magicrest.TraceAttrTotal: "2",
+			}},
+		{"invalid filter", url.Values{"filter[tidak_ada]": {"x"}}, false,
+			[]string{"magicrest.list", "magicrest.parse"},
+			map[string]bool{"magicrest.list": true, "magicrest.parse": true},
+			map[string]string{magicrest.TraceAttrModel: "Barang", magicrest.TraceAttrResultCount: "0"}},
+		{"query error", url.Values{}, true,
+			[]string{"magicrest.list", "magicrest.parse", "magicrest.count"},
+			map[string]bool{"magicrest.list": true, "magicrest.count": true}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if tc.drop {
+				db.Migrator().DropTable(&Barang{})
+			}
+			rec := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+			opts := magicrest.Options{OrderBy: "id", StrictQuery: true, Tracer: New(tp)}
+			_, err := magicrest.ReadPaginatedCtx(context.Background(), tc.query, db.Model(&Barang{}), &Barang{}, opts)
+			if (err != nil) != (len(tc.errors) > 0) {
+				t.Fatalf("err = %v", err)
+			}
+
+			spans := spansByName(rec)
+			if len(spans) != len(tc.spans) {
+				t.Fatalf("spans %v, want %v", spans, tc.spans)
+			}
+			list := spans["magicrest.list"]
+			for _, name := range tc.spans {
+				s, ok := spans[name]
+				if !ok {
+					t.Fatalf("missing span %s", name)
+				}
+				if name != "magicrest.list" && s.Parent().SpanID() != list.SpanContext().SpanID() {
+					t.Fatalf("%s is not a child of magicrest.list", name)
+				}
+				wantErr := tc.errors[name]
+				if got := s.Status().Code == codes.Error; got != wantErr {
+					t.Fatalf("%s status %v, want error %v", name, s.Status(), wantErr)
+				}
+				if wantErr && (len(s.Events()) == 0 || s.Events()[0].Name != "exception") {
+					t.Fatalf("%s: error not recorded: %v", name, s.Events())
+				}
+			}
+			got := attrs(list)
+			for k, want := range tc.list {
+				if v, ok := got[attribute.Key(k)]; !ok || v.Emit() != want {
+					t.Fatalf("list attribute %s = %q, want %q (all %v)", k, v.Emit(), want, got)
+				}
+			}
+			if name := "magicrest.data"; spans[name] != nil {
+				if v := attrs(spans[name])[magicrest.TraceAttrRows]; v.AsInt64() != 2 {
+					t.Fatalf("%s rows = %v", name, v.Emit())
+				}
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	got := convert([]magicrest.TraceAttr{
+		{Key: "s", Value: "x"}, {Key: "b", Value: true}, {Key: "i", Value: 3}, {Key: "i64", Value: int64(4)},
+		{Key: "f", Value: 1.5}, {Key: "ss", Value: []string{"a", "b"}}, {Key: "u", Value: uint(7)},
+	})
+	want := []attribute.KeyValue{
+		attribute.String("s", "x"), attribute.Bool("b", true), attribute.Int("i", 3), attribute.Int64("i64", 4),
+		attribute.Float64("f", 1.5), attribute.StringSlice("ss", []string{"a", "b"}), attribute.String("u", "7"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v", got)
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || got[i].Value.Type() != want[i].Value.Type() || got[i].Value.Emit() != want[i].Value.Emit() {
+			t.Fatalf("attr %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
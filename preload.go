@@ -0,0 +1,666 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// defaultMaxPreloadDepth dipakai bila Options.MaxPreloadDepth tidak di-set
+	defaultMaxPreloadDepth = 3
+	// defaultMaxPreloads dipakai bila Options.MaxPreloads tidak di-set
+	defaultMaxPreloads = 10
+)
+
+// PreloadPolicy menentukan perlakuan preload dari query yang tidak ada di Options.AllowedPreloads
+type PreloadPolicy int
+
+const (
+	// PreloadPolicyReject: request ditolak dengan ErrPreloadNotAllowed (default)
+	PreloadPolicyReject PreloadPolicy = iota
+	// PreloadPolicyDrop: preload di-drop dan dicatat di Meta["warnings"]
+	PreloadPolicyDrop
+)
+
+// PreloadMergeMode menentukan hubungan ?preload= dari query dengan Options.PreloadFields
+type PreloadMergeMode int
+
+const (
+	// PreloadReplace: preload dari query menggantikan Options.PreloadFields (default, behaviour lama)
+	PreloadReplace PreloadMergeMode = iota
+	// PreloadMerge: gabungan keduanya tanpa duplikat (direkomendasikan untuk kode baru)
+	PreloadMerge
+	// PreloadServerOnly: preload dari query (?preload=, preload[Rel][...]) diabaikan, hanya Options.PreloadFields.
+	// Tidak masuk Meta["warnings"]; warn sekali per query lewat Options.Logger (nil = tanpa warning).
+	PreloadServerOnly
+)
+
+// Strategy menentukan cara relasi dimuat: Preload (query terpisah) atau Join (satu query)
+type Strategy int
+
+const (
+	// StrategyPreload: db.Preload (default)
+	StrategyPreload Strategy = iota
+	// StrategyJoin: db.Joins untuk relasi belongs-to/has-one; has-many tetap memakai Preload
+	StrategyJoin
+)
+
+// ErrPreloadNotAllowed digunakan bila ?preload= meminta relasi di luar whitelist
+var ErrPreloadNotAllowed = errors.New("preload not allowed")
+
+// ErrInvalidPreload digunakan bila path preload tidak dikenal di schema model atau terlalu dalam
+var ErrInvalidPreload = errors.New("invalid preload")
+
+// ErrTooManyPreloads digunakan bila query meminta lebih dari Options.MaxPreloads preload
+// (juga match errors.Is(err, ErrInvalidPreload))
+var ErrTooManyPreloads = errors.New("too many preloads")
+
+// ErrPreloadTooDeep digunakan bila path preload lebih dalam dari Options.MaxPreloadDepth
+// (juga match errors.Is(err, ErrInvalidPreload))
+var ErrPreloadTooDeep = errors.New("preload too deep")
+
+// preloadSpec: satu preload beserta kondisi, order dan kolom yang dipilih.
+// Sumber: ?preload=Items(id,nama), ?preload[Items][status]=active atau Options.PreloadFields.
+type preloadSpec struct {
+	Path       string
+	Conditions map[string]string // field -> nilai mentah (boleh "a,b" untuk IN)
+	Order      string
+	Columns    []string
+	Limit      int // maksimum child per parent (has-one/has-many), 0 = tanpa batas
+}
+
+// needsScope: true bila preload butuh fungsi kondisi (bukan sekadar db.Preload(path))
+func (s *preloadSpec) needsScope() bool {
+	return len(s.Conditions) > 0 || s.Order != "" || len(s.Columns) > 0 || s.Limit > 0
+}
+
+// splitPreloads memecah "A, B(id,nama),C" menjadi []string{"A","B(id,nama)","C"}
+// (koma di dalam kurung tidak memisah, entry kosong dibuang)
+func splitPreloads(raw string) []string {
+	var out []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		if f := strings.TrimSpace(raw[start:end]); f != "" {
+			out = append(out, f)
+		}
+	}
+	for i, r := range raw {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(raw))
+	return out
+}
+
+// parsePreloadItem memecah "Items(id,nama)" menjadi path "Items" dan kolom []string{"id","nama"}
+func parsePreloadItem(item string) (*preloadSpec, error) {
+	open := strings.Index(item, "(")
+	if open < 0 {
+		return &preloadSpec{Path: item}, nil
+	}
+	path := strings.TrimSpace(item[:open])
+	if path == "" || !strings.HasSuffix(item, ")") {
+		return nil, fmt.Errorf("%w: malformed preload %q", ErrInvalidPreload, item)
+	}
+	cols := splitPreloads(item[open+1 : len(item)-1])
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("%w: empty column list in %q", ErrInvalidPreload, item)
+	}
+	return &preloadSpec{Path: path, Columns: cols}, nil
+}
+
+// preloadAllowed: path boleh bila sama dengan entry whitelist atau prefix-nya
+// (whitelist "Items.Product" juga mengizinkan "Items").
+// allowed == nil berarti semua boleh (kompatibel dengan behaviour lama).
+func preloadAllowed(path string, allowed []string) bool {
+	if allowed == nil {
+		return true
+	}
+	for _, a := range allowed {
+		if a == path || strings.HasPrefix(a, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPreloads menyaring preload dari query terhadap Options.AllowedPreloads.
+// Mengembalikan preload yang lolos, warning untuk yang di-drop, atau error sesuai policy.
+func checkPreloads(requested []*preloadSpec, opts Options) ([]*preloadSpec, []string, error) {
+	var kept []*preloadSpec
+	var warnings []string
+	for _, p := range requested {
+		if preloadAllowed(p.Path, opts.AllowedPreloads) {
+			kept = append(kept, p)
+			continue
+		}
+		if opts.PreloadPolicy == PreloadPolicyDrop {
+			warnings = append(warnings, fmt.Sprintf("preload %q is not allowed and was ignored", p.Path))
+			continue
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrPreloadNotAllowed, p.Path)
+	}
+	return kept, warnings, nil
+}
+
+// checkPreloadLimits menolak query dengan jumlah preload > Options.MaxPreloads atau
+// path lebih dalam dari Options.MaxPreloadDepth (default 10 dan 3).
+func checkPreloadLimits(specs []*preloadSpec, opts Options) error {
+	maxPreloads := opts.MaxPreloads
+	if maxPreloads <= 0 {
+		maxPreloads = defaultMaxPreloads
+	}
+	if len(specs) > maxPreloads {
+		return fmt.Errorf("%w: %w: %d requested, max %d", ErrInvalidPreload, ErrTooManyPreloads, len(specs), maxPreloads)
+	}
+	maxDepth := opts.MaxPreloadDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxPreloadDepth
+	}
+	for _, spec := range specs {
+		if depth := strings.Count(spec.Path, ".") + 1; depth > maxDepth {
+			return fmt.Errorf("%w: %w: %s exceeds max depth %d", ErrInvalidPreload, ErrPreloadTooDeep, spec.Path, maxDepth)
+		}
+	}
+	return nil
+}
+
+// resolvePreloadPath me-resolve tiap segmen "Items.Produk.Kategori" terhadap relasi di schema
+// dan mengembalikan relasi terakhir. Error menyebut segmen pertama yang tidak dikenal.
+func resolvePreloadPath(sch *schema.Schema, path string) (*schema.Relationship, error) {
+	var rel *schema.Relationship
+	cur := sch
+	for _, seg := range strings.Split(path, ".") {
+		r, ok := cur.Relationships.Relations[seg]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown relation %q in %s", ErrInvalidPreload, seg, path)
+		}
+		rel, cur = r, r.FieldSchema
+	}
+	return rel, nil
+}
+
+// joinable: true bila semua segmen path adalah relasi belongs-to/has-one
+// (relasi yang bisa dimuat gorm lewat db.Joins)
+func joinable(sch *schema.Schema, path string) bool {
+	cur := sch
+	for _, seg := range strings.Split(path, ".") {
+		rel, ok := cur.Relationships.Relations[seg]
+		if !ok || (rel.Type != schema.BelongsTo && rel.Type != schema.HasOne) {
+			return false
+		}
+		cur = rel.FieldSchema
+	}
+	return true
+}
+
+// hasJoin: true bila db sudah punya join dengan nama tersebut (mis. ditambahkan caller), termasuk
+// join bertingkat yang melewatinya ("Gudang.Kota" ikut men-join "Gudang")
+func hasJoin(db *gorm.DB, name string) bool {
+	for _, j := range db.Statement.Joins {
+		if j.Name == name || strings.HasPrefix(j.Name, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBracketKey("preload[Items][status]", "preload") -> []string{"Items", "status"}
+func parseBracketKey(key, prefix string) ([]string, bool) {
+	if !strings.HasPrefix(key, prefix+"[") || !strings.HasSuffix(key, "]") {
+		return nil, false
+	}
+	return strings.Split(key[len(prefix)+1:len(key)-1], "]["), true
+}
+
+// parseConditionalPreloads mengumpulkan semua key preload[Rel][...] dari query, urut berdasarkan path.
+func parseConditionalPreloads(query QuerySource) ([]*preloadSpec, error) {
+	byPath := map[string]*preloadSpec{}
+	for key, vals := range query.Values() {
+		parts, ok := parseBracketKey(key, "preload")
+		if !ok {
+			continue
+		}
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: malformed key %s", ErrInvalidPreload, key)
+		}
+		spec, ok := byPath[parts[0]]
+		if !ok {
+			spec = &preloadSpec{Path: parts[0], Conditions: map[string]string{}}
+			byPath[parts[0]] = spec
+		}
+		switch parts[1] {
+		case "order":
+			spec.Order = vals[0]
+		case "limit":
+			limit, err := strconv.Atoi(vals[0])
+			if err != nil || limit <= 0 {
+				return nil, fmt.Errorf("%w: invalid limit %q for %s", ErrInvalidPreload, vals[0], parts[0])
+			}
+			spec.Limit = limit
+		default:
+			spec.Conditions[parts[1]] = vals[0]
+		}
+	}
+
+	specs := make([]*preloadSpec, 0, len(byPath))
+	for _, spec := range byPath {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Path < specs[j].Path })
+	return specs, nil
+}
+
+// sanitizeOrder memvalidasi "created_at desc, nama" terhadap kolom di schema dan
+// menyusun ulang order memakai nama kolom DB.
+func sanitizeOrder(sch *schema.Schema, order string) (string, error) {
+	var parts []string
+	for _, item := range strings.Split(order, ",") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 || len(fields) > 2 {
+			return "", fmt.Errorf("%w: malformed order %q", ErrInvalidPreload, order)
+		}
+		f := sch.LookUpField(fields[0])
+		if f == nil || f.DBName == "" {
+			return "", fmt.Errorf("%w: unknown order field %q on %s", ErrInvalidPreload, fields[0], sch.Name)
+		}
+		dir := "asc"
+		if len(fields) == 2 {
+			dir = strings.ToLower(fields[1])
+			if dir != "asc" && dir != "desc" {
+				return "", fmt.Errorf("%w: invalid order direction %q", ErrInvalidPreload, fields[1])
+			}
+		}
+		parts = append(parts, f.DBName+" "+dir)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// relationKeyColumns mengembalikan kolom di sisi sch yang dipakai relasi rel untuk
+// merakit asosiasi (FK di child untuk has-one/has-many, PK untuk belongs-to).
+func relationKeyColumns(rel *schema.Relationship, sch *schema.Schema) []string {
+	var cols []string
+	for _, ref := range rel.References {
+		if ref.ForeignKey != nil && ref.ForeignKey.Schema == sch {
+			cols = append(cols, ref.ForeignKey.DBName)
+		}
+		if ref.PrimaryKey != nil && ref.PrimaryKey.Schema == sch {
+			cols = append(cols, ref.PrimaryKey.DBName)
+		}
+	}
+	return cols
+}
+
+// preloadSelectColumns memvalidasi kolom yang diminta terhadap schema relasi lalu menambahkan
+// kolom kunci yang wajib ada: primary key, FK relasi ini, dan FK untuk preload nested di bawahnya.
+// Tanpa kunci tersebut gorm tidak bisa merakit asosiasi dan preload diam-diam kosong.
+func preloadSelectColumns(rel *schema.Relationship, spec *preloadSpec, all []*preloadSpec) ([]string, error) {
+	relSchema := rel.FieldSchema
+	seen := map[string]bool{}
+	var cols []string
+	add := func(c string) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			cols = append(cols, c)
+		}
+	}
+
+	for _, name := range spec.Columns {
+		f := relSchema.LookUpField(name)
+		if f == nil || f.DBName == "" {
+			return nil, fmt.Errorf("%w: unknown column %q on %s", ErrInvalidPreload, name, spec.Path)
+		}
+		add(f.DBName)
+	}
+	for _, pk := range relSchema.PrimaryFieldDBNames {
+		add(pk)
+	}
+	for _, c := range relationKeyColumns(rel, relSchema) {
+		add(c)
+	}
+	for _, other := range all {
+		if !strings.HasPrefix(other.Path, spec.Path+".") {
+			continue
+		}
+		next := strings.SplitN(strings.TrimPrefix(other.Path, spec.Path+"."), ".", 2)[0]
+		if nested, ok := relSchema.Relationships.Relations[next]; ok {
+			for _, c := range relationKeyColumns(nested, relSchema) {
+				add(c)
+			}
+		}
+	}
+	return cols, nil
+}
+
+// compilePreloadSpec memvalidasi kondisi, order dan kolom terhadap schema model relasi dan
+// mengembalikan fungsi kondisi untuk db.Preload(path, fn). nil bila preload tidak butuh kondisi.
+func compilePreloadSpec(sch *schema.Schema, spec *preloadSpec, all []*preloadSpec) (func(*gorm.DB) *gorm.DB, error) {
+	rel, err := resolvePreloadPath(sch, spec.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !spec.needsScope() {
+		return nil, nil
+	}
+	relSchema := rel.FieldSchema
+
+	type condition struct {
+		column string
+		values []interface{}
+	}
+	var conds []condition
+	names := make([]string, 0, len(spec.Conditions))
+	for name := range spec.Conditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := relSchema.LookUpField(name)
+		if f == nil || f.DBName == "" {
+			return nil, fmt.Errorf("%w: unknown field %q on %s", ErrInvalidPreload, name, spec.Path)
+		}
+		fieldType := schemaFieldType(f)
+		var values []interface{}
+		for _, v := range splitValues(spec.Conditions[name]) {
+			tv, err := parseTypedValue(fieldType, v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: preload[%s][%s]=%s", ErrInvalidFilter, spec.Path, name, v)
+			}
+			values = append(values, tv)
+		}
+		conds = append(conds, condition{column: f.DBName, values: values})
+	}
+
+	order := ""
+	if spec.Order != "" {
+		if order, err = sanitizeOrder(relSchema, spec.Order); err != nil {
+			return nil, err
+		}
+	}
+
+	var columns []string
+	if len(spec.Columns) > 0 {
+		if columns, err = preloadSelectColumns(rel, spec, all); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Limit > 0 && rel.Type != schema.HasMany && rel.Type != schema.HasOne {
+		return nil, fmt.Errorf("%w: limit is only supported on has-one/has-many relations (%s)", ErrInvalidPreload, spec.Path)
+	}
+
+	return func(tx *gorm.DB) *gorm.DB {
+		for _, c := range conds {
+			if len(c.values) == 1 {
+				tx = tx.Where("? = ?", clause.Column{Name: c.column}, c.values[0])
+			} else {
+				tx = tx.Where("? IN ?", clause.Column{Name: c.column}, c.values)
+			}
+		}
+		if spec.Limit > 0 {
+			tx = limitPerParent(tx, rel, spec.Limit, order)
+		} else if order != "" {
+			tx = tx.Order(order)
+		}
+		if len(columns) > 0 {
+			tx = tx.Select(columns)
+		}
+		return tx
+	}, nil
+}
+
+// supportsWindowFunctions: dialect yang mendukung ROW_NUMBER() OVER (...)
+func supportsWindowFunctions(db *gorm.DB) bool {
+	switch db.Dialector.Name() {
+	case "postgres", "mysql", "sqlite", "sqlserver":
+		return true
+	}
+	return false
+}
+
+// limitPerParent membatasi child per parent memakai subquery
+// ROW_NUMBER() OVER (PARTITION BY fk ORDER BY ...) sehingga tiap parent mendapat maksimal
+// limit child, bukan limit child total. tx sudah berisi kondisi FK IN (...) dari gorm.
+// Dialect tanpa window function jatuh ke LIMIT global (total child, bukan per parent);
+// MySQL < 8 tidak bisa dideteksi dari nama dialect sehingga tetap memakai window function.
+func limitPerParent(tx *gorm.DB, rel *schema.Relationship, limit int, order string) *gorm.DB {
+	if !supportsWindowFunctions(tx) {
+		if order != "" {
+			tx = tx.Order(order)
+		}
+		return tx.Limit(limit)
+	}
+
+	relSchema := rel.FieldSchema
+	var partition []string
+	for _, c := range relationKeyColumns(rel, relSchema) {
+		partition = append(partition, tx.Statement.Quote(c))
+	}
+	over := order
+	if over == "" {
+		var pks []string
+		for _, pk := range relSchema.PrimaryFieldDBNames {
+			pks = append(pks, tx.Statement.Quote(pk))
+		}
+		over = strings.Join(pks, ", ")
+	}
+
+	table := relSchema.Table
+	inner := tx.Select(fmt.Sprintf("%s.*, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS magicrest_rn",
+		tx.Statement.Quote(table), strings.Join(partition, ", "), over))
+	outer := tx.Session(&gorm.Session{NewDB: true}).
+		Table(fmt.Sprintf("(?) AS %s", table), inner).
+		Where("magicrest_rn <= ?", limit)
+	if order != "" {
+		outer = outer.Order(order)
+	}
+	return outer
+}
+
+// collectPreloads mengumpulkan spec preload dari query (plain ?preload= dan conditional
+// preload[Rel][...]) setelah dicek terhadap whitelist. fromQuery false bila query tidak
+// meminta preload sama sekali (Options.PreloadFields yang dipakai).
+func collectPreloads(query QuerySource, opts Options) (specs []*preloadSpec, warnings []string, fromQuery bool, err error) {
+	var server []*preloadSpec
+	for _, f := range opts.PreloadFields {
+		server = append(server, &preloadSpec{Path: f})
+	}
+	applyPreloadDefaults(server, opts)
+	if opts.PreloadMergeMode == PreloadServerOnly {
+		return server, nil, false, nil // query yang meminta preload hanya di-log (logBuild)
+	}
+
+	conditional, err := parseConditionalPreloads(query)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	preloadQuery := query.Get("preload")
+	if preloadQuery == "" && len(conditional) == 0 {
+		return server, nil, false, nil
+	}
+
+	var plain []*preloadSpec
+	for _, item := range splitPreloads(preloadQuery) {
+		spec, err := parsePreloadItem(item)
+		if err != nil {
+			return nil, nil, true, err
+		}
+		plain = append(plain, spec)
+	}
+
+	byPath := map[string]*preloadSpec{}
+	for _, spec := range plain {
+		if _, dup := byPath[spec.Path]; dup {
+			continue
+		}
+		byPath[spec.Path] = spec
+		specs = append(specs, spec)
+	}
+	for _, spec := range conditional {
+		if existing, ok := byPath[spec.Path]; ok {
+			existing.Conditions, existing.Order, existing.Limit = spec.Conditions, spec.Order, spec.Limit
+			continue
+		}
+		byPath[spec.Path] = spec
+		specs = append(specs, spec)
+	}
+	if err := checkPreloadLimits(specs, opts); err != nil {
+		return nil, nil, true, err
+	}
+
+	// kondisi pada relasi di luar whitelist selalu ditolak, preload biasa mengikuti policy
+	for _, spec := range specs {
+		if spec.Conditions != nil && !preloadAllowed(spec.Path, opts.AllowedPreloads) {
+			return nil, nil, true, fmt.Errorf("%w: %s", ErrPreloadNotAllowed, spec.Path)
+		}
+	}
+	if specs, warnings, err = checkPreloads(specs, opts); err != nil {
+		return nil, nil, true, err
+	}
+	applyPreloadDefaults(specs, opts)
+	if opts.PreloadMergeMode == PreloadMerge {
+		specs = mergePreloadSpecs(server, specs)
+	}
+	return specs, warnings, true, nil
+}
+
+// requestsPreloads: query memakai ?preload= atau preload[Rel][...]
+func requestsPreloads(query QuerySource) bool {
+	if query.Get("preload") != "" {
+		return true
+	}
+	for key := range query.Values() {
+		if _, ok := parseBracketKey(key, "preload"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPreloadDefaults mengisi kolom dari Options.PreloadSelects bila tidak dipilih sendiri,
+// dan limit dari Options.PreloadLimits sebagai default sekaligus batas atas limit dari client.
+func applyPreloadDefaults(specs []*preloadSpec, opts Options) {
+	for _, spec := range specs {
+		if len(spec.Columns) == 0 {
+			spec.Columns = opts.PreloadSelects[spec.Path]
+		}
+		if maxLimit := opts.PreloadLimits[spec.Path]; maxLimit > 0 && (spec.Limit == 0 || spec.Limit > maxLimit) {
+			spec.Limit = maxLimit
+		}
+	}
+}
+
+// mergePreloadSpecs menggabungkan preload server dan query: path sama diambil dari query
+// (bisa membawa kondisi/kolom), dan preload polos yang merupakan prefix path lain dibuang
+// karena gorm sudah memuatnya lewat path nested ("Items" vs "Items.Product").
+func mergePreloadSpecs(server, query []*preloadSpec) []*preloadSpec {
+	byPath := map[string]*preloadSpec{}
+	var order []string
+	for _, list := range [][]*preloadSpec{server, query} {
+		for _, spec := range list {
+			if _, ok := byPath[spec.Path]; !ok {
+				order = append(order, spec.Path)
+			}
+			byPath[spec.Path] = spec
+		}
+	}
+
+	merged := make([]*preloadSpec, 0, len(order))
+	for _, path := range order {
+		spec := byPath[path]
+		if !spec.needsScope() && hasNestedPath(path, order) {
+			continue
+		}
+		merged = append(merged, spec)
+	}
+	return merged
+}
+
+// hasNestedPath: true bila ada path lain di bawah prefix (mis. "Items.Product" untuk "Items")
+func hasNestedPath(prefix string, paths []string) bool {
+	for _, p := range paths {
+		if strings.HasPrefix(p, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPreloads menerapkan preload dari query atau dari Options.PreloadFields ke db dan
+// mengembalikan path yang dimuat. Preload dari query selalu divalidasi terhadap schema model;
+// preload server hanya bila butuh kondisi (mis. Options.PreloadSelects).
+func applyPreloads(db *gorm.DB, modelPtr interface{}, query QuerySource, opts Options) (*gorm.DB, []string, []string, error) {
+	specs, warnings, fromQuery, err := collectPreloads(query, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	paths := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		paths = append(paths, spec.Path)
+	}
+
+	needSchema := fromQuery
+	for _, spec := range specs {
+		if spec.needsScope() || opts.PreloadStrategy[spec.Path] == StrategyJoin {
+			needSchema = true
+		}
+	}
+	if !needSchema {
+		for _, spec := range specs {
+			db = db.Preload(spec.Path)
+		}
+		return db, paths, warnings, nil
+	}
+
+	sch, err := parseSchema(db, modelPtr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, spec := range specs {
+		// strategi Join hanya untuk preload tanpa kondisi pada relasi belongs-to/has-one;
+		// join yang sudah ada (dari caller) tidak ditambahkan dua kali
+		if opts.PreloadStrategy[spec.Path] == StrategyJoin && !spec.needsScope() {
+			if _, err := resolvePreloadPath(sch, spec.Path); err != nil {
+				return nil, nil, nil, err
+			}
+			if joinable(sch, spec.Path) {
+				if !hasJoin(db, spec.Path) {
+					db = db.Joins(spec.Path)
+				}
+				continue
+			}
+		}
+		if opts.StrictQuery && spec.Limit > 0 && !supportsWindowFunctions(db) {
+			return nil, nil, nil, newQueryError(ErrUnsupportedDialect, "preload", spec.Path,
+				"per-parent limit needs window functions on "+db.Dialector.Name())
+		}
+		fn, err := compilePreloadSpec(sch, spec, specs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if fn != nil {
+			db = db.Preload(spec.Path, fn)
+		} else {
+			db = db.Preload(spec.Path)
+		}
+	}
+	return db, paths, warnings, nil
+}
@@ -0,0 +1,496 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestReadPaginatedAllowedPreloads(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	cases := []struct {
+		name    string
+		preload string
+		opts    Options
+		err     error
+		loaded  string // relasi yang termuat: "items", "gudang", "both"
+		warning string // isi Meta["warnings"] ("" = tidak ada)
+	}{
+		{"nil allows all", "Items,Gudang", Options{}, nil, "both", ""},
+		{"listed", "Items", Options{AllowedPreloads: []string{"Items"}}, nil, "items", ""},
+		{"nested entry allows parent", "Items", Options{AllowedPreloads: []string{"Items.Produk"}}, nil, "items", ""},
+		{"nested path", "Items.Produk", Options{AllowedPreloads: []string{"Items.Produk"}}, nil, "items", ""},
+		{"not listed", "Items,Gudang", Options{AllowedPreloads: []string{"Items"}}, ErrPreloadNotAllowed, "", ""},
+		{"deeper than listed", "Items.Produk", Options{AllowedPreloads: []string{"Items"}}, ErrPreloadNotAllowed, "", ""},
+		{"empty list denies all", "Items", Options{AllowedPreloads: []string{}}, ErrPreloadNotAllowed, "", ""},
+		{"drop policy", "Items,Gudang", Options{AllowedPreloads: []string{"Items"}, PreloadPolicy: PreloadPolicyDrop}, nil, "items", `preload "Gudang" is not allowed`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(url.Values{"preload": {tc.preload}}, db.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			for _, o := range res.Data {
+				items, gudang := len(o.Items) == 1, o.Gudang != nil
+				if items != (tc.loaded != "gudang") || gudang != (tc.loaded != "items") {
+					t.Fatalf("order %s: items %v, gudang %v; want %s", o.Kode, items, gudang, tc.loaded)
+				}
+			}
+			if got := fmt.Sprint(res.Meta["warnings"]); (tc.warning == "") != (res.Meta["warnings"] == nil) || !strings.Contains(got, tc.warning) {
+				t.Fatalf("warnings %s, want %q", got, tc.warning)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedNestedPreloadPaths(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 2)
+	cases := []struct {
+		name    string
+		preload string
+		wantErr string // "" = berhasil
+	}{
+		{"three levels", "Items.Produk.Kategori", ""},
+		{"belongs-to", "Gudang", ""},
+		{"unknown root", "Itemz", `unknown relation "Itemz" in Itemz`},
+		{"unknown middle", "Items.Produks.Kategori", `unknown relation "Produks" in Items.Produks.Kategori`},
+		{"unknown leaf", "Items.Produk.Kategory", `unknown relation "Kategory"`},
+		{"column not relation", "Items.nama", `unknown relation "nama"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(url.Values{"preload": {tc.preload}}, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+			if tc.wantErr != "" {
+				if !errors.Is(err, ErrInvalidPreload) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want ErrInvalidPreload containing %q", err, tc.wantErr)
+				}
+				if sql := rec.statements(); len(sql) > 0 {
+					t.Fatalf("SQL executed for an invalid preload: %v", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.preload == "Items.Produk.Kategori" {
+				for _, o := range res.Data {
+					if len(o.Items) != 2 || o.Items[0].Produk == nil || o.Items[0].Produk.Kategori == nil ||
+						o.Items[0].Produk.Kategori.Nama != "Bahan" {
+						t.Fatalf("order %s: tree not loaded: %+v", o.Kode, o.Items)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReadPaginatedConditionalPreloads(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 3)
+	cases := []struct {
+		name   string
+		query  url.Values
+		opts   Options
+		err    error
+		jumlah []int // jumlah item per order, urut
+	}{
+		{"plain", url.Values{"preload": {"Items"}}, Options{}, nil, []int{1, 2, 3}},
+		{"in condition", url.Values{"preload[Items][jumlah]": {"1,3"}}, Options{}, nil, []int{1, 3}},
+		{"condition and order", url.Values{"preload[Items][jumlah]": {"2,3"}, "preload[Items][order]": {"jumlah desc"}}, Options{}, nil, []int{3, 2}},
+		{"with plain syntax", url.Values{"preload": {"Items,Gudang"}, "preload[Items][jumlah]": {"2"}}, Options{}, nil, []int{2}},
+		{"value of wrong type", url.Values{"preload[Items][jumlah]": {"dua"}}, Options{}, ErrInvalidFilter, nil},
+		{"unknown field", url.Values{"preload[Items][warna]": {"merah"}}, Options{}, ErrInvalidPreload, nil},
+		{"unknown order field", url.Values{"preload[Items][order]": {"warna"}}, Options{}, ErrInvalidPreload, nil},
+		{"bad order direction", url.Values{"preload[Items][order]": {"jumlah up"}}, Options{}, ErrInvalidPreload, nil},
+		{"not in whitelist", url.Values{"preload[Items][jumlah]": {"1"}}, Options{AllowedPreloads: []string{"Gudang"}}, ErrPreloadNotAllowed, nil},
+		{"not in whitelist with drop policy", url.Values{"preload[Items][jumlah]": {"1"}},
+			Options{AllowedPreloads: []string{"Gudang"}, PreloadPolicy: PreloadPolicyDrop}, ErrPreloadNotAllowed, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			for _, o := range res.Data {
+				var got []int
+				for _, it := range o.Items {
+					got = append(got, it.Jumlah)
+				}
+				if fmt.Sprint(got) != fmt.Sprint(tc.jumlah) {
+					t.Fatalf("order %s items %v, want %v", o.Kode, got, tc.jumlah)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPaginatedPreloadDepthDefault(t *testing.T) {
+	db := newTestDB(t)
+	// empat level melewati default MaxPreloadDepth 3 (ditolak sebelum path di-resolve)
+	_, err := ReadPaginated(url.Values{"preload": {"Items.Produk.Kategori.X"}}, db.Model(&Order{}), &Order{}, Options{})
+	if !errors.Is(err, ErrPreloadTooDeep) || !errors.Is(err, ErrInvalidPreload) {
+		t.Fatalf("err = %v, want ErrPreloadTooDeep", err)
+	}
+}
+
+func TestReadPaginatedPreloadSelects(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 2)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		sql   string // SELECT query items
+	}{
+		{"query columns", url.Values{"preload": {"Items(nama)"}}, Options{},
+			"SELECT `nama`,`id`,`order_id` FROM `items`"},
+		{"nested keeps fk", url.Values{"preload": {"Items(nama),Items.Produk(nama)"}}, Options{},
+			"SELECT `nama`,`id`,`order_id`,`produk_id` FROM `items`"},
+		{"options default", url.Values{}, Options{PreloadFields: []string{"Items"}, PreloadSelects: map[string][]string{"Items": {"jumlah"}}},
+			"SELECT `jumlah`,`id`,`order_id` FROM `items`"},
+		{"query overrides options", url.Values{"preload": {"Items(catatan)"}}, Options{PreloadSelects: map[string][]string{"Items": {"jumlah"}}},
+			"SELECT `catatan`,`id`,`order_id` FROM `items`"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.matching(tc.sql)) == 0 {
+				t.Fatalf("no SQL contains %s:\n%s", tc.sql, strings.Join(rec.statements(), "\n"))
+			}
+			// kunci ikut dipilih, jadi asosiasi tetap terakit
+			for _, o := range res.Data {
+				if len(o.Items) != 2 {
+					t.Fatalf("order %s has %d items, want 2", o.Kode, len(o.Items))
+				}
+			}
+		})
+	}
+
+	_, err := ReadPaginated(url.Values{"preload": {"Items(nama,harga)"}}, db.Model(&Order{}), &Order{}, Options{})
+	if !errors.Is(err, ErrInvalidPreload) || !strings.Contains(err.Error(), `unknown column "harga" on Items`) {
+		t.Fatalf("err = %v, want ErrInvalidPreload for harga", err)
+	}
+}
+
+func TestReadPaginatedPreloadLimitPerParent(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 5)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  int   // item per order
+		first []int // jumlah item pertama per order (nil = tidak dicek)
+	}{
+		{"options", url.Values{"preload": {"Items"}}, Options{PreloadLimits: map[string]int{"Items": 2}}, 2, nil},
+		{"query", url.Values{"preload[Items][limit]": {"3"}}, Options{}, 3, nil},
+		{"query capped by options", url.Values{"preload[Items][limit]": {"4"}}, Options{PreloadLimits: map[string]int{"Items": 2}}, 2, nil},
+		{"ordered", url.Values{"preload[Items][limit]": {"2"}, "preload[Items][order]": {"jumlah desc"}}, Options{}, 2, []int{5, 4}},
+		{"with condition", url.Values{"preload[Items][limit]": {"2"}, "preload[Items][jumlah]": {"1,2,3"}}, Options{}, 2, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.matching("ROW_NUMBER() OVER (PARTITION BY `order_id`")) == 0 {
+				t.Fatalf("no window function:\n%s", strings.Join(rec.statements(), "\n"))
+			}
+			if len(res.Data) != 3 {
+				t.Fatalf("orders = %d, want 3", len(res.Data))
+			}
+			// tiap parent mendapat N child, bukan N child total
+			for _, o := range res.Data {
+				if len(o.Items) != tc.want {
+					t.Fatalf("order %s has %d items, want %d", o.Kode, len(o.Items), tc.want)
+				}
+				for _, it := range o.Items {
+					if it.OrderID != o.ID {
+						t.Fatalf("order %s got item of order %d", o.Kode, it.OrderID)
+					}
+				}
+				if tc.first != nil && (o.Items[0].Jumlah != tc.first[0] || o.Items[1].Jumlah != tc.first[1]) {
+					t.Fatalf("order %s items %+v, want jumlah %v first", o.Kode, o.Items, tc.first)
+				}
+			}
+		})
+	}
+
+	for name, query := range map[string]url.Values{
+		"belongs-to":   {"preload[Gudang][limit]": {"1"}},
+		"not a number": {"preload[Items][limit]": {"lima"}},
+		"zero":         {"preload[Items][limit]": {"0"}},
+	} {
+		if _, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{}); !errors.Is(err, ErrInvalidPreload) {
+			t.Fatalf("%s: err = %v, want ErrInvalidPreload", name, err)
+		} else if name == "belongs-to" && !strings.Contains(err.Error(), "has-one/has-many") {
+			t.Fatalf("belongs-to: err = %v", err)
+		}
+	}
+}
+
+// Faktur -> Pembayaran: relasi has-one
+type Faktur struct {
+	ID         uint        `json:"id"`
+	Nomor      string      `json:"nomor"`
+	Pembayaran *Pembayaran `json:"pembayaran,omitempty"`
+}
+
+type Pembayaran struct {
+	ID       uint `json:"id"`
+	FakturID uint `json:"faktur_id"`
+	Jumlah   int  `json:"jumlah"`
+}
+
+func TestReadPaginatedPreloadLimitHasOne(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Faktur{}, &Pembayaran{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Faktur{{Nomor: "F-1", Pembayaran: &Pembayaran{Jumlah: 10}}, {Nomor: "F-2"}})
+	res, err := ReadPaginated(url.Values{"preload[Pembayaran][limit]": {"1"}}, db.Model(&Faktur{}), &Faktur{}, Options{OrderBy: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 2 || res.Data[0].Pembayaran == nil || res.Data[0].Pembayaran.Jumlah != 10 || res.Data[1].Pembayaran != nil {
+		t.Fatalf("data %+v", res.Data)
+	}
+}
+
+func TestReadPaginatedPreloadGuards(t *testing.T) {
+	db := newTestDB(t)
+	many := make([]string, 11)
+	for i := range many {
+		many[i] = fmt.Sprintf("Rel%d", i) // batas jumlah dicek sebelum path di-resolve
+	}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  error // nil = diterima
+	}{
+		{"duplicates counted once", url.Values{"preload": {strings.Repeat("Gudang,", 11) + "Items"}}, Options{}, nil},
+		{"default count exceeded", url.Values{"preload": {strings.Join(many, ",")}}, Options{}, ErrTooManyPreloads},
+		{"custom count ok", url.Values{"preload": {"Gudang,Items"}}, Options{MaxPreloads: 2}, nil},
+		{"custom count", url.Values{"preload": {"Gudang,Items"}}, Options{MaxPreloads: 1}, ErrTooManyPreloads},
+		{"conditional counted", url.Values{"preload": {"Gudang"}, "preload[Items][jumlah]": {"1"}}, Options{MaxPreloads: 1}, ErrTooManyPreloads},
+		{"default depth", url.Values{"preload": {"Items.Produk.Kategori"}}, Options{}, nil},
+		{"default depth exceeded", url.Values{"preload": {"Items.Produk.Kategori.Induk"}}, Options{}, ErrPreloadTooDeep},
+		{"custom depth", url.Values{"preload": {"Items.Produk"}}, Options{MaxPreloadDepth: 1}, ErrPreloadTooDeep},
+		{"custom depth ok", url.Values{"preload": {"Items"}}, Options{MaxPreloadDepth: 1}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			_, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) || !errors.Is(err, ErrInvalidPreload) {
+				t.Fatalf("err = %v, want %v (and ErrInvalidPreload)", err, tc.want)
+			}
+			if sql := rec.statements(); len(sql) > 0 {
+				t.Fatalf("SQL executed for a rejected preload: %v", sql)
+			}
+		})
+	}
+}
+
+func TestBuildQueryPreloadStrategy(t *testing.T) {
+	db := newTestDB(t)
+	join := map[string]Strategy{"Gudang": StrategyJoin, "Items": StrategyJoin}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		joins int // jumlah JOIN gudangs di query utama
+	}{
+		{"default preload", url.Values{"preload": {"Gudang"}}, Options{}, 0},
+		{"join", url.Values{"preload": {"Gudang"}}, Options{PreloadStrategy: join}, 1},
+		{"server preload join", url.Values{}, Options{PreloadFields: []string{"Gudang"}, PreloadStrategy: join}, 1},
+		{"filtered and joined once", url.Values{"preload": {"Gudang"}, "filter[Gudang.kode]": {"GD-01"}}, Options{PreloadStrategy: join}, 1},
+		{"filter only", url.Values{"filter[Gudang.kode]": {"GD-01"}}, Options{}, 1},
+		{"has-many stays preload", url.Values{"preload": {"Items"}}, Options{PreloadStrategy: join}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, _, err := BuildQuery(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sql := q.Session(&gorm.Session{DryRun: true}).Find(&[]Order{}).Statement.SQL.String()
+			if n := strings.Count(sql, "JOIN `gudangs`"); n != tc.joins {
+				t.Fatalf("%d gudang joins, want %d: %s", n, tc.joins, sql)
+			}
+			if strings.Contains(sql, "JOIN `items`") {
+				t.Fatalf("has-many relation joined: %s", sql)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedJoinStrategyLoadsRelation(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 0)
+	rdb, rec := recordSQL(db)
+	opts := Options{OrderBy: "id", PreloadStrategy: map[string]Strategy{"Gudang": StrategyJoin}}
+	res, err := ReadPaginated(url.Values{"preload": {"Gudang"}, "filter[Gudang.kode]": {"GD-02"}}, rdb.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 2 {
+		t.Fatalf("rows = %d, want 2", len(res.Data))
+	}
+	for _, o := range res.Data {
+		if o.Gudang == nil || o.Gudang.Kode != "GD-02" {
+			t.Fatalf("order %s: gudang %+v", o.Kode, o.Gudang)
+		}
+	}
+	if sql := rec.matching("FROM `gudangs`"); len(sql) > 0 {
+		t.Fatalf("separate preload query for a joined relation: %v", sql)
+	}
+}
+
+func TestReadPaginatedPreloadMergeMode(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	cases := []struct {
+		name    string
+		mode    PreloadMergeMode
+		server  []string
+		preload string
+		gudang  bool // Gudang dimuat
+		items   bool // Items dimuat
+		produk  bool // Items.Produk dimuat
+	}{
+		{"replace", PreloadReplace, []string{"Gudang"}, "Items", false, true, false},
+		{"replace without query", PreloadReplace, []string{"Gudang"}, "", true, false, false},
+		{"merge", PreloadMerge, []string{"Gudang"}, "Items", true, true, false},
+		{"merge same relation", PreloadMerge, []string{"Items"}, "Items", false, true, false},
+		{"merge nested prefix", PreloadMerge, []string{"Gudang", "Items"}, "Items.Produk", true, true, true},
+		{"merge prefix from query", PreloadMerge, []string{"Items.Produk"}, "Items", false, true, true},
+		{"server only", PreloadServerOnly, []string{"Gudang"}, "Items", true, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			query := url.Values{}
+			if tc.preload != "" {
+				query.Set("preload", tc.preload)
+			}
+			opts := Options{OrderBy: "id", PreloadFields: tc.server, PreloadMergeMode: tc.mode}
+			res, err := ReadPaginated(query, rdb.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := res.Data[0]
+			if got := o.Gudang != nil; got != tc.gudang {
+				t.Fatalf("Gudang loaded = %v, want %v", got, tc.gudang)
+			}
+			if got := len(o.Items) > 0; got != tc.items {
+				t.Fatalf("Items loaded = %v, want %v", got, tc.items)
+			}
+			if got := tc.items && o.Items[0].Produk != nil; got != tc.produk {
+				t.Fatalf("Items.Produk loaded = %v, want %v", got, tc.produk)
+			}
+			// relasi yang muncul di kedua sumber (atau sebagai prefix) dimuat sekali
+			if n := len(rec.matching("FROM `items`")); tc.items && n != 1 {
+				t.Fatalf("%d items queries, want 1:\n%s", n, strings.Join(rec.statements(), "\n"))
+			}
+		})
+	}
+}
+
+func TestReadPaginatedServerOnlyIgnoresQueryPreloads(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	const warning = "query preloads ignored"
+	cases := []struct {
+		name  string
+		query url.Values
+		level slog.Level // level minimal handler Logger
+		noLog bool       // Options.Logger nil
+		warns int
+	}{
+		{"preload", url.Values{"preload": {"Items,Gudang"}}, slog.LevelWarn, false, 1},
+		{"conditional preload", url.Values{"preload[Items][jumlah]": {"1"}}, slog.LevelWarn, false, 1},
+		{"no preload", url.Values{}, slog.LevelWarn, false, 0},
+		{"handler above warn", url.Values{"preload": {"Items"}}, slog.LevelError, false, 0},
+		{"without logger", url.Values{"preload": {"Items"}}, slog.LevelWarn, true, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := &logRecorder{level: tc.level}
+			opts := Options{OrderBy: "id", PreloadFields: []string{"Gudang"}, PreloadMergeMode: PreloadServerOnly, Logger: slog.New(logs)}
+			if tc.noLog {
+				opts.Logger = nil
+			}
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, o := range res.Data {
+				if o.Gudang == nil || len(o.Items) > 0 {
+					t.Fatalf("order %s: gudang %v, items %v: want only PreloadFields", o.Kode, o.Gudang, o.Items)
+				}
+			}
+			if sql := rec.matching("FROM `items`"); len(sql) > 0 {
+				t.Fatalf("items queried: %v", sql)
+			}
+			if w := res.Meta["warnings"]; w != nil {
+				t.Fatalf("meta warnings %v", w)
+			}
+			if n := logs.count(warning); n != tc.warns {
+				t.Fatalf("%d warnings %v, want %d", n, logs.msgs, tc.warns)
+			}
+		})
+	}
+}
+
+// satu request handler (dengan Last-Modified yang membangun query yang sama) tetap satu warning
+func TestListHandlerServerOnlyWarnsOnce(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	logs := &logRecorder{level: slog.LevelWarn}
+	opts := Options{OrderBy: "id", PreloadMergeMode: PreloadServerOnly, LastModified: true, ETag: true, Logger: slog.New(logs)}
+	rec := httptest.NewRecorder()
+	ListHandlerHTTP[Order](db, opts)(rec, httptest.NewRequest(http.MethodGet, "/orders?preload=Items", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), `"items":[{`) {
+		t.Fatalf("items preloaded: %s", rec.Body.String())
+	}
+	if n := logs.count("query preloads ignored"); n != 1 {
+		t.Fatalf("%d warnings %v, want 1", n, logs.msgs)
+	}
+}
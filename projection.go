@@ -0,0 +1,268 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidField digunakan bila ?fields= / ?omit= menyebut kolom yang tidak ada di model (Options.StrictFields)
+var ErrInvalidField = errors.New("invalid field")
+
+// ErrComputedColumnConflict: alias Options.ComputedColumns sama dengan kolom asli model
+var ErrComputedColumnConflict = errors.New("computed column collides with a model column")
+
+// computedColumn: kolom turunan dari Options.ComputedColumns yang di-select sebagai "(SQL) AS alias"
+type computedColumn struct {
+	Alias string
+	SQL   string
+}
+
+// checkComputedColumns memastikan tidak ada alias computed yang menimpa kolom asli model.
+// Field virtual `gorm:"->;-:migration"` untuk menampung hasilnya tidak dihitung sebagai kolom.
+func checkComputedColumns(sch *schema.Schema, computed map[string]string) error {
+	for alias := range computed {
+		if isColumnField(sch.LookUpField(alias)) {
+			return fmt.Errorf("%w: %s", ErrComputedColumnConflict, alias)
+		}
+	}
+	return nil
+}
+
+// splitComputed memisahkan alias computed dari nama kolom biasa di ?fields=
+func splitComputed(names []string, computed map[string]string) ([]string, []computedColumn) {
+	var rest []string
+	var out []computedColumn
+	seen := map[string]bool{}
+	for _, name := range names {
+		expr, ok := computed[name]
+		if !ok {
+			rest = append(rest, name)
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, computedColumn{Alias: name, SQL: expr})
+		}
+	}
+	return rest, out
+}
+
+// computedOrder mengganti alias computed di klausa order ("sisa_stok desc, nama") dengan
+// ekspresinya, sehingga urutan tetap jalan walau kolomnya tidak di-select.
+func computedOrder(order string, computed map[string]string) string {
+	if len(computed) == 0 || order == "" {
+		return order
+	}
+	items := strings.Split(order, ",")
+	for i, item := range items {
+		parts := strings.Fields(item)
+		if len(parts) == 0 {
+			continue
+		}
+		if expr, ok := computed[parts[0]]; ok {
+			parts[0] = "(" + expr + ")"
+		}
+		items[i] = strings.Join(parts, " ")
+	}
+	return strings.Join(items, ", ")
+}
+
+// isColumnField: field yang benar-benar kolom tabel; field virtual `gorm:"-:migration"`
+// (mis. <relation>_count) tidak ikut di-select.
+func isColumnField(f *schema.Field) bool {
+	return f != nil && f.DBName != "" && !f.IgnoreMigration
+}
+
+// resolveColumnNames memvalidasi nama dari ?fields= / ?omit= terhadap schema dan mengembalikan
+// nama kolom DB. Nama tidak dikenal menghasilkan ErrInvalidField (strict) atau warning.
+func resolveColumnNames(sch *schema.Schema, names []string, param string, strict bool) ([]string, []string, error) {
+	var cols, warnings []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		f := sch.LookUpField(name)
+		if !isColumnField(f) {
+			if strict {
+				return nil, nil, newQueryError(ErrInvalidField, param, name, "")
+			}
+			warnings = append(warnings, fmt.Sprintf("%s %q does not exist and was ignored", param, name))
+			continue
+		}
+		cols = append(cols, f.DBName)
+	}
+	return cols, warnings, nil
+}
+
+// requiredColumns: kolom yang selalu ikut di-select agar identitas baris dan asosiasi tetap
+// utuh — primary key, kolom kunci di sisi parent untuk preload yang diminta, dan kolom soft delete.
+func requiredColumns(sch *schema.Schema, preloads []string) []string {
+	cols := append([]string{}, sch.PrimaryFieldDBNames...)
+	for _, p := range preloads {
+		if rel, ok := sch.Relationships.Relations[strings.SplitN(p, ".", 2)[0]]; ok {
+			cols = append(cols, relationKeyColumns(rel, sch)...)
+		}
+	}
+	if sd := softDeleteField(sch); sd != nil {
+		cols = append(cols, sd.DBName)
+	}
+	return cols
+}
+
+// selectableColumns me-resolve Options.SelectableColumns ke nama kolom DB (nil = semua kolom boleh).
+func selectableColumns(sch *schema.Schema, selectable []string) (map[string]bool, error) {
+	if selectable == nil {
+		return nil, nil
+	}
+	cols, _, err := resolveColumnNames(sch, selectable, "SelectableColumns", true)
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]bool{}
+	for _, c := range cols {
+		allowed[c] = true
+	}
+	return allowed, nil
+}
+
+// projection: hasil resolve ?fields= / ?omit= untuk select list
+type projection struct {
+	Columns  []string         // kolom model yang di-select
+	Computed []computedColumn // alias Options.ComputedColumns yang diminta
+	Implicit []string         // kolom kunci yang ditambahkan otomatis (Meta["implicitFields"])
+	Warnings []string         // nama kolom tidak dikenal yang di-drop
+}
+
+// projectColumns me-resolve ?fields=id,nama dan ?omit=deskripsi terhadap schema model.
+// Tanpa fields, basisnya semua kolom model (atau Options.SelectableColumns bila di-set). Kolom wajib
+// (requiredColumns) tidak bisa di-omit dan otomatis ditambahkan; daftarnya ada di Implicit.
+// Alias Options.ComputedColumns di fields dikembalikan terpisah.
+func projectColumns(sch *schema.Schema, fields, omit []string, preloads []string, opts Options) (projection, error) {
+	fields, extra := splitComputed(fields, opts.ComputedColumns)
+	requested, warnings, err := resolveColumnNames(sch, fields, "fields", opts.StrictFields)
+	if err != nil {
+		return projection{}, err
+	}
+	omitted, omitWarnings, err := resolveColumnNames(sch, omit, "omit", opts.StrictFields)
+	if err != nil {
+		return projection{}, err
+	}
+	warnings = append(warnings, omitWarnings...)
+	allowed, err := selectableColumns(sch, opts.SelectableColumns)
+	if err != nil {
+		return projection{}, err
+	}
+
+	required := requiredColumns(sch, preloads)
+	keep := map[string]bool{}
+	for _, c := range required {
+		keep[c] = true
+	}
+	if allowed != nil {
+		for _, c := range requested {
+			if !allowed[c] && !keep[c] {
+				return projection{}, newQueryError(ErrInvalidField, "fields", c, "is not selectable")
+			}
+		}
+	}
+	skip := map[string]bool{}
+	for _, c := range omitted {
+		if !keep[c] {
+			skip[c] = true
+		}
+	}
+
+	// explicit: kolom yang memang diminta klien (nil = semua kolom model)
+	base := requested
+	explicit := map[string]bool{}
+	if len(base) == 0 && len(extra) == 0 {
+		for _, f := range sch.Fields {
+			if isColumnField(f) && (allowed == nil || allowed[f.DBName] || keep[f.DBName]) {
+				base = append(base, f.DBName)
+			}
+		}
+		if allowed == nil {
+			explicit = nil
+		} else {
+			explicit = allowed
+		}
+	} else {
+		for _, c := range requested {
+			explicit[c] = true
+		}
+		base = append(base, required...)
+	}
+
+	seen := map[string]bool{}
+	out := projection{Computed: extra, Warnings: warnings}
+	for _, c := range base {
+		if !seen[c] && !skip[c] {
+			seen[c] = true
+			out.Columns = append(out.Columns, c)
+			if explicit != nil && !explicit[c] {
+				out.Implicit = append(out.Implicit, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+// distinctColumns me-resolve ?fields= untuk mode ?distinct=true. Hanya kolom di
+// Options.DistinctFields yang boleh dipakai (nil = tidak ada) karena DISTINCT pada baris lebar mahal.
+// Primary key sengaja tidak ditambahkan agar DISTINCT tetap bermakna.
+func distinctColumns(sch *schema.Schema, fields []string, opts Options) ([]string, error) {
+	cols, _, err := resolveColumnNames(sch, fields, "fields", true)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, newQueryError(ErrInvalidField, "distinct", "", "requires fields")
+	}
+	selectable, err := selectableColumns(sch, opts.SelectableColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cols {
+		if selectable != nil && !selectable[c] {
+			return nil, newQueryError(ErrInvalidField, "fields", c, "is not selectable")
+		}
+		ok := false
+		for _, a := range opts.DistinctFields {
+			if f := sch.LookUpField(a); f != nil && f.DBName == c {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return nil, newQueryError(ErrInvalidField, "fields", c, "is not allowed with distinct")
+		}
+	}
+	return cols, nil
+}
+
+// selectList menyusun daftar select: kolom ter-quote "table"."kolom" (atau "table".* bila tidak
+// ada proyeksi) ditambah computed column dan subquery count yang punya field di model.
+// nil bila tidak perlu Select.
+func selectList(db *gorm.DB, sch *schema.Schema, cols []string, computed []computedColumn, counts []relationCount) []string {
+	var list []string
+	for _, c := range cols {
+		list = append(list, db.Statement.Quote(sch.Table+"."+c))
+	}
+	for _, c := range computed {
+		list = append(list, fmt.Sprintf("(%s) AS %s", c.SQL, db.Statement.Quote(c.Alias)))
+	}
+	for _, c := range counts {
+		if !c.InModel {
+			continue
+		}
+		if len(list) == 0 {
+			list = append(list, db.Statement.Quote(sch.Table)+".*")
+		}
+		list = append(list, fmt.Sprintf("%s AS %s", c.SQL, db.Statement.Quote(c.Alias)))
+	}
+	return list
+}
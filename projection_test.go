@@ -0,0 +1,332 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestReadPaginatedFields(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	cases := []struct {
+		name    string
+		query   url.Values
+		strict  bool
+		err     error
+		warning string // potongan Meta["warnings"] ("" = tidak ada)
+	}{
+		{"requested columns", url.Values{"fields": {"kode,status"}}, false, nil, ""},
+		{"with preload", url.Values{"fields": {"kode,status"}, "preload": {"Gudang,Items"}}, false, nil, ""},
+		{"unknown dropped", url.Values{"fields": {"kode,status,foto"}}, false, nil, `"foto" does not exist`},
+		{"unknown strict", url.Values{"fields": {"kode,foto"}}, true, ErrInvalidField, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id", StrictFields: tc.strict})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				if len(rec.statements()) > 0 {
+					t.Fatalf("SQL for a rejected fields=: %v", rec.statements())
+				}
+				return
+			}
+			if len(rec.matching("SELECT `orders`.`kode`,`orders`.`status`,`orders`.`id`")) != 1 {
+				t.Fatalf("no projected query:\n%s", strings.Join(rec.statements(), "\n"))
+			}
+			for _, o := range res.Data {
+				// PK selalu ikut, kolom lain kosong; FK gudang_id ikut untuk preload Gudang
+				if o.ID == 0 || o.Kode == "" || o.Status == "" || o.Telepon != "" || !o.CreatedAt.IsZero() {
+					t.Fatalf("projection: %+v", o)
+				}
+				if tc.query.Get("preload") != "" && (o.GudangID == 0 || o.Gudang == nil || len(o.Items) != 1) {
+					t.Fatalf("order %s: preloads not assembled: %+v", o.Kode, o)
+				}
+			}
+			warnings := fmt.Sprint(res.Meta["warnings"])
+			if (tc.warning == "") != (res.Meta["warnings"] == nil) || !strings.Contains(warnings, tc.warning) {
+				t.Fatalf("warnings %s, want %q", warnings, tc.warning)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedOmit(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 1)
+	cases := []struct {
+		name    string
+		query   url.Values
+		absent  []string // kolom yang tidak boleh di-select query utama
+		present []string // kolom yang tetap di-select
+	}{
+		{"heavy column", url.Values{"omit": {"telepon"}}, []string{"`telepon`"}, []string{"`kode`", "`status`", "`id`"}},
+		{"primary key and soft delete kept", url.Values{"omit": {"id,deleted_at,status"}}, []string{"`status`"}, []string{"`id`", "`deleted_at`"}},
+		{"fk kept for preload", url.Values{"omit": {"gudang_id,telepon"}, "preload": {"Gudang"}}, []string{"`telepon`"}, []string{"`gudang_id`"}},
+		{"fk omitted without preload", url.Values{"omit": {"gudang_id"}}, []string{"`gudang_id`"}, []string{"`kode`"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sqls := rec.matching("FROM `orders` WHERE")
+			var main string
+			for _, s := range sqls {
+				if strings.HasPrefix(s, "SELECT `") {
+					main = s
+				}
+			}
+			if main == "" {
+				t.Fatalf("no projected query:\n%s", strings.Join(rec.statements(), "\n"))
+			}
+			selectList := main[:strings.Index(main, " FROM ")]
+			for _, c := range tc.absent {
+				if strings.Contains(selectList, c) {
+					t.Fatalf("%s selected: %s", c, selectList)
+				}
+			}
+			for _, c := range tc.present {
+				if !strings.Contains(selectList, c) {
+					t.Fatalf("%s not selected: %s", c, selectList)
+				}
+			}
+			if tc.query.Get("preload") == "Gudang" {
+				for _, o := range res.Data {
+					if o.Gudang == nil {
+						t.Fatalf("order %s: Gudang not assembled with gudang_id omitted", o.Kode)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReadPaginatedOmitUnknown(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 1, 0)
+	res, err := ReadPaginated(url.Values{"omit": {"foto_base64"}}, db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings, _ := res.Meta["warnings"].([]string)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `omit "foto_base64" does not exist`) {
+		t.Fatalf("warnings = %v", res.Meta["warnings"])
+	}
+	_, err = ReadPaginated(url.Values{"omit": {"foto_base64"}}, db.Model(&Order{}), &Order{}, Options{StrictFields: true})
+	var qe *QueryError
+	if !errors.Is(err, ErrInvalidField) || !errors.As(err, &qe) || qe.Param != "omit" {
+		t.Fatalf("err = %v, want ErrInvalidField on omit", err)
+	}
+}
+
+func TestReadPaginatedDistinct(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	allowed := Options{AllowDistinct: true, DistinctFields: []string{"status", "gudang_id"}}
+	cases := []struct {
+		name    string
+		query   url.Values
+		opts    Options
+		err     error
+		rows    int
+		total   int64
+		warning string
+	}{
+		{"one column", url.Values{"fields": {"status"}, "distinct": {"true"}}, allowed, nil, 2, 2, ""},
+		{"paginated", url.Values{"fields": {"status"}, "distinct": {"true"}, "pageSize": {"1"}}, allowed, nil, 1, 2, ""},
+		{"tuples", url.Values{"fields": {"status,gudang_id"}, "distinct": {"true"}}, allowed, nil, 2, 2, ""},
+		{"not enabled", url.Values{"fields": {"status"}, "distinct": {"true"}}, Options{}, nil, 5, 5, "distinct is not enabled"},
+		{"column not allowed", url.Values{"fields": {"kode"}, "distinct": {"true"}}, allowed, ErrInvalidField, 0, 0, ""},
+		{"no DistinctFields", url.Values{"fields": {"status"}, "distinct": {"true"}}, Options{AllowDistinct: true}, ErrInvalidField, 0, 0, ""},
+		{"without fields", url.Values{"distinct": {"true"}}, allowed, ErrInvalidField, 0, 0, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			p, _ := res.Meta["pagination"].(map[string]interface{})
+			if len(res.Data) != tc.rows || p["total"] != tc.total {
+				t.Fatalf("%d rows, total %v; want %d, %d", len(res.Data), p["total"], tc.rows, tc.total)
+			}
+			if tc.opts.AllowDistinct {
+				// count menghitung tuple unik lewat subquery; baris tanpa primary key
+				if len(rec.matching("magicrest_distinct")) != 1 || len(rec.matching("SELECT DISTINCT")) != 2 {
+					t.Fatalf("SQL:\n%s", strings.Join(rec.statements(), "\n"))
+				}
+				for _, o := range res.Data {
+					if o.ID != 0 || o.Status == "" {
+						t.Fatalf("distinct row %+v", o)
+					}
+				}
+			}
+			if w := fmt.Sprint(res.Meta["warnings"]); !strings.Contains(w, tc.warning) || (tc.warning == "") != (res.Meta["warnings"] == nil) {
+				t.Fatalf("warnings %s, want %q", w, tc.warning)
+			}
+		})
+	}
+}
+
+// ItemHitung: item dengan field virtual untuk computed column "ganda"
+type ItemHitung struct {
+	ID     uint   `json:"id"`
+	Nama   string `json:"nama"`
+	Jumlah int    `json:"jumlah"`
+	Ganda  int    `json:"ganda" gorm:"->;-:migration"`
+}
+
+func (ItemHitung) TableName() string { return "items" }
+
+func TestReadPaginatedComputedColumns(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 1, 3)
+	opts := Options{
+		OrderBy:           "id",
+		ComputedColumns:   map[string]string{"ganda": "jumlah * 2"},
+		DefaultFieldTypes: map[string]string{"ganda": "int"},
+	}
+	cases := []struct {
+		name  string
+		query url.Values
+		want  string // nama:ganda per row
+	}{
+		{"selected via fields", url.Values{"fields": {"nama,ganda"}}, "[item-1-1:2 item-1-2:4 item-1-3:6]"},
+		{"not selected by default", url.Values{}, "[item-1-1:0 item-1-2:0 item-1-3:0]"},
+		{"order", url.Values{"fields": {"nama,ganda"}, "order": {"ganda desc"}}, "[item-1-3:6 item-1-2:4 item-1-1:2]"},
+		{"filter", url.Values{"fields": {"nama,ganda"}, "filter[ganda]": {"4,6"}}, "[item-1-2:4 item-1-3:6]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&ItemHitung{}), &ItemHitung{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, it := range res.Data {
+				got = append(got, fmt.Sprintf("%s:%d", it.Nama, it.Ganda))
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+			if selected := len(rec.matching("(jumlah * 2) AS `ganda`")) > 0; selected != (tc.query.Get("fields") != "") {
+				t.Fatalf("computed selected %v:\n%s", selected, strings.Join(rec.statements(), "\n"))
+			}
+		})
+	}
+
+	conflict := opts
+	conflict.ComputedColumns = map[string]string{"jumlah": "1"}
+	if _, err := ReadPaginated(url.Values{}, db.Model(&ItemHitung{}), &ItemHitung{}, conflict); !errors.Is(err, ErrComputedColumnConflict) {
+		t.Fatalf("err = %v, want ErrComputedColumnConflict", err)
+	}
+}
+
+func TestReadPaginatedSelectableColumns(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	selectable := Options{OrderBy: "id", SelectableColumns: []string{"kode", "status"},
+		AllowDistinct: true, DistinctFields: []string{"status", "telepon"}, AllowGroupBy: true}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		err   error
+	}{
+		{"default select", url.Values{}, selectable, nil},
+		{"fields inside whitelist", url.Values{"fields": {"kode"}}, selectable, nil},
+		{"preload key kept", url.Values{"preload": {"Gudang"}}, selectable, nil},
+		{"fields outside whitelist", url.Values{"fields": {"kode,telepon"}}, selectable, ErrInvalidField},
+		{"distinct outside whitelist", url.Values{"fields": {"telepon"}, "distinct": {"true"}}, selectable, ErrInvalidField},
+		{"groupby outside whitelist", url.Values{"groupby": {"telepon"}}, selectable, ErrInvalidField},
+		{"nil selects all", url.Values{}, Options{OrderBy: "id"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			star := len(rec.matching("SELECT * FROM `orders`")) > 0
+			if star != (tc.opts.SelectableColumns == nil) {
+				t.Fatalf("SELECT *: %v\n%s", star, strings.Join(rec.statements(), "\n"))
+			}
+			for _, o := range res.Data {
+				if o.ID == 0 || o.Kode == "" || (o.Telepon != "") != (tc.opts.SelectableColumns == nil) {
+					t.Fatalf("row %+v", o)
+				}
+				if tc.query.Get("preload") != "" && o.Gudang == nil {
+					t.Fatalf("order %s: Gudang not loaded", o.Kode)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPaginatedFieldsImplicitKeys(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 2)
+	cases := []struct {
+		name     string
+		query    url.Values
+		implicit []string
+	}{
+		{"primary key and soft delete", url.Values{"fields": {"kode"}}, []string{"id", "deleted_at"}},
+		{"requested key not implicit", url.Values{"fields": {"id,kode"}}, []string{"deleted_at"}},
+		{"belongs-to fk on parent", url.Values{"fields": {"kode"}, "preload": {"Gudang"}}, []string{"id", "gudang_id", "deleted_at"}},
+		{"has-many fk on child", url.Values{"fields": {"kode"}, "preload": {"Items"}}, []string{"id", "deleted_at"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			implicit, _ := res.Meta["implicitFields"].([]string)
+			if strings.Join(implicit, ",") != strings.Join(tc.implicit, ",") {
+				t.Fatalf("implicitFields = %v, want %v", implicit, tc.implicit)
+			}
+			for _, o := range res.Data {
+				if o.ID == 0 || o.Kode == "" || o.Status != "" {
+					t.Fatalf("projection: %+v", o)
+				}
+				switch tc.query.Get("preload") {
+				case "Gudang":
+					if o.Gudang == nil || o.GudangID == 0 {
+						t.Fatalf("order %s: belongs-to not assembled", o.Kode)
+					}
+				case "Items":
+					if len(o.Items) != 2 {
+						t.Fatalf("order %s: %d items, want 2", o.Kode, len(o.Items))
+					}
+				}
+			}
+		})
+	}
+
+	// tanpa fields= tidak ada yang implisit
+	res, err := ReadPaginated(url.Values{"preload": {"Gudang"}}, db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Meta["implicitFields"]; ok {
+		t.Fatalf("implicitFields without fields=: %v", res.Meta["implicitFields"])
+	}
+}
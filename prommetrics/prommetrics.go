@@ -0,0 +1,114 @@
+// Package prommetrics: magicrest.Recorder di atas Prometheus client_golang. Modul terpisah (go.mod sendiri) agar
+// client Prometheus tidak ikut ke dependency graph pemakai core.
+//
+//	opts.Metrics = prommetrics.New(prometheus.DefaultRegisterer, prommetrics.Config{}) // atau prommetrics.Install()
+//
+// p95 latensi list per resource:
+//
+//	histogram_quantile(0.95, sum by (model, le) (rate(magicrest_query_duration_seconds_bucket{phase="list"}[5m])))
+package prommetrics
+
+import (
+	"errors"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config: nama dan bucket metrik; nilai kosong = default
+type Config struct {
+	Namespace       string            // prefix nama metrik (default "magicrest")
+	DurationBuckets []float64         // bucket durasi dalam detik (default prometheus.DefBuckets)
+	RowBuckets      []float64         // bucket jumlah row (default 1, 10, 50, 100, 500, 1000, 5000, 10000, 50000)
+	ConstLabels     prometheus.Labels // label tetap untuk semua metrik, e.g. {"service": "gudang"}
+}
+
+// defaultRowBuckets: sampai HardRowLimit bawaan
+var defaultRowBuckets = []float64{1, 10, 50, 100, 500, 1000, 5000, 10000, 50000}
+
+// Recorder: magicrest.Recorder yang menulis ke collector Prometheus
+//
+//	<ns>_query_duration_seconds{model,phase}  histogram durasi operasi (list, create, ...) dan query (data, count, ...)
+//	<ns>_query_errors_total{model,phase}      counter operasi / query yang gagal
+//	<ns>_rejected_total{model,code}           counter request yang ditolak (invalid_filter, validation_failed, ...)
+//	<ns>_rows{model}                          histogram row hasil list, export dan write bulk
+type Recorder struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rejected *prometheus.CounterVec
+	rows     *prometheus.HistogramVec
+}
+
+// New mendaftarkan collector ke reg (nil = prometheus.DefaultRegisterer). Collector yang sudah terdaftar dengan
+// nama yang sama dipakai ulang, jadi New / Install boleh dipanggil lebih dari sekali.
+func New(reg prometheus.Registerer, cfg Config) *Recorder {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "magicrest"
+	}
+	if cfg.DurationBuckets == nil {
+		cfg.DurationBuckets = prometheus.DefBuckets
+	}
+	if cfg.RowBuckets == nil {
+		cfg.RowBuckets = defaultRowBuckets
+	}
+	return &Recorder{
+		duration: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace, Name: "query_duration_seconds", ConstLabels: cfg.ConstLabels, Buckets: cfg.DurationBuckets,
+			Help: "Duration of magicrest operations (list, export, create, ...) and their queries (data, count, ...).",
+		}, []string{"model", "phase"})),
+		errors: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace, Name: "query_errors_total", ConstLabels: cfg.ConstLabels,
+			Help: "magicrest operations and queries that failed with a database or timeout error.",
+		}, []string{"model", "phase"})),
+		rejected: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace, Name: "rejected_total", ConstLabels: cfg.ConstLabels,
+			Help: "magicrest requests rejected with a stable error code (invalid_filter, validation_failed, ...).",
+		}, []string{"model", "code"})),
+		rows: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace, Name: "rows", ConstLabels: cfg.ConstLabels, Buckets: cfg.RowBuckets,
+			Help: "Rows returned by lists and exports or affected by bulk writes.",
+		}, []string{"model"})),
+	}
+}
+
+// Install mengisi magicrest.DefaultMetrics dengan Recorder di prometheus.DefaultRegisterer, sehingga semua
+// Options / WriteOptions tanpa Metrics ikut dilaporkan
+func Install() {
+	magicrest.DefaultMetrics = New(nil, Config{})
+}
+
+// register: c, atau collector yang sudah terdaftar dengan nama yang sama
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+// ObserveQuery: magicrest.Recorder
+func (r *Recorder) ObserveQuery(model, phase string, d time.Duration, err bool) {
+	r.duration.WithLabelValues(model, phase).Observe(d.Seconds())
+	if err {
+		r.errors.WithLabelValues(model, phase).Inc()
+	}
+}
+
+// ObserveRows: magicrest.Recorder
+func (r *Recorder) ObserveRows(model string, n int) {
+	r.rows.WithLabelValues(model).Observe(float64(n))
+}
+
+// ObserveRejected: magicrest.Recorder
+func (r *Recorder) ObserveRejected(model, code string) {
+	r.rejected.WithLabelValues(model, code).Inc()
+}
@@ -0,0 +1,101 @@
+package prommetrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ magicrest.Recorder = (*Recorder)(nil)
+
+// gather: "nama{label=nilai,...} nilai" per seri (histogram: jumlah sampel), urut
+func gather(t *testing.T, reg *prometheus.Registry) string {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+"="+l.GetValue())
+			}
+			var v float64
+			switch f.GetType() {
+			case dto.MetricType_COUNTER:
+				v = m.GetCounter().GetValue()
+			case dto.MetricType_HISTOGRAM:
+				v = float64(m.GetHistogram().GetSampleCount())
+			}
+			lines = append(lines, fmt.Sprintf("%s{%s} %g", f.GetName(), strings.Join(labels, ","), v))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func TestRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := New(reg, Config{Namespace: "uji", ConstLabels: prometheus.Labels{"service": "gudang"}})
+	r.ObserveQuery("Order", magicrest.OpList, 20*time.Millisecond, false)
+	r.ObserveQuery("Order", magicrest.OpList, time.Second, true)
+	r.ObserveQuery("Order", magicrest.QueryLabelCount, time.Millisecond, false)
+	r.ObserveRows("Order", 25)
+	r.ObserveRejected("Order", "invalid_filter")
+	r.ObserveRejected("Order", "invalid_filter")
+
+	want := strings.Join([]string{
+		"uji_query_duration_seconds{model=Order,phase=count,service=gudang} 1",
+		"uji_query_duration_seconds{model=Order,phase=list,service=gudang} 2",
+		"uji_query_errors_total{model=Order,phase=list,service=gudang} 1",
+		"uji_rejected_total{code=invalid_filter,model=Order,service=gudang} 2",
+		"uji_rows{model=Order,service=gudang} 1",
+	}, "\n")
+	if got := gather(t, reg); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// New kedua dengan nama yang sama memakai collector yang sudah terdaftar
+	New(reg, Config{Namespace: "uji", ConstLabels: prometheus.Labels{"service": "gudang"}}).ObserveRows("Order", 3)
+	if got := gather(t, reg); !strings.Contains(got, "uji_rows{model=Order,service=gudang} 2") {
+		t.Fatalf("after second New:\n%s", got)
+	}
+}
+
+func TestRecorderBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := New(reg, Config{DurationBuckets: []float64{0.1, 1}})
+	r.ObserveQuery("Order", magicrest.OpCreate, 50*time.Millisecond, false)
+	r.ObserveRows("Order", 7)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets := map[string]int{}
+	for _, f := range families {
+		if !strings.HasPrefix(f.GetName(), "magicrest_") {
+			t.Fatalf("default namespace: %s", f.GetName())
+		}
+		buckets[f.GetName()] = len(f.GetMetric()[0].GetHistogram().GetBucket())
+	}
+	if buckets["magicrest_query_duration_seconds"] != 2 || buckets["magicrest_rows"] != len(defaultRowBuckets) {
+		t.Fatalf("buckets %v", buckets)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	t.Cleanup(func() { magicrest.DefaultMetrics = nil })
+	Install()
+	Install() // collector di DefaultRegisterer dipakai ulang, tidak panic
+	if _, ok := magicrest.DefaultMetrics.(*Recorder); !ok {
+		t.Fatalf("DefaultMetrics = %T", magicrest.DefaultMetrics)
+	}
+}
@@ -0,0 +1,283 @@
+package magicrest
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filter: satu filter[field]=value hasil ParseQuery
+type Filter struct {
+	Field  string
+	Op     string        // "eq" atau "in" (nilai dipisah koma)
+	Values []interface{} // nilai ter-parse sesuai Type
+	Type   string        // "uuid", "int", "bool", "datetime", "date" atau "string"
+}
+
+// fieldNamePattern: nama field dari klien (filter[...], ?order=, ?groupby=) — identifier biasa atau "Relasi.kolom" bertingkat
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// SortField: satu item dari ?order=nama asc,id desc
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// QueryParams: url.Values yang sudah di-parse dan diberi tipe (dipakai BuildQuery, juga untuk
+// logging, validasi, atau cache key).
+type QueryParams struct {
+	Page      int
+	PageSize  int
+	Search    string
+	Filters   []Filter
+	Preloads  []string
+	Order     string // ?order= apa adanya ("" = pakai Options.OrderBy)
+	Sort      []SortField
+	GroupBy   []string // hanya bila Options.AllowGroupBy
+	Fields    []string
+	Omit      []string
+	Distinct  bool
+	WithCount []string
+	Defaults  []string // nilai default yang dipakai: "page", "pageSize", "maxPageSize", "order"
+}
+
+// legacyFieldTypes: tipe filter bawaan versi lama, hanya dipakai bila Options.LegacyFieldTypes
+var legacyFieldTypes = map[string]string{
+	"id":        "uuid",
+	"status":    "string",
+	"jumlah":    "int",
+	"gudang_id": "uuid",
+}
+
+// validFieldType: tipe yang dikenali parseTypedValue
+func validFieldType(t string) bool {
+	switch t {
+	case "uuid", "int", "bool", "datetime", "date", "string":
+		return true
+	}
+	return false
+}
+
+// fieldTypes menggabungkan Options.DefaultFieldTypes dengan tipe bawaan lama bila diminta
+// (map milik caller tidak diubah)
+func fieldTypes(opts Options) map[string]string {
+	out := map[string]string{}
+	if opts.LegacyFieldTypes {
+		for k, v := range legacyFieldTypes {
+			out[k] = v
+		}
+	}
+	for k, v := range opts.DefaultFieldTypes {
+		out[k] = v
+	}
+	return out
+}
+
+// filterType: tipe filter field tanpa menyalin map — DefaultFieldTypes, lalu tipe schema model (model, dari
+// AutoFieldTypes), lalu tipe bawaan lama bila LegacyFieldTypes; "" = string
+func filterType(opts Options, model map[string]string, field string) string {
+	if t, ok := opts.DefaultFieldTypes[field]; ok {
+		return t
+	}
+	if t, ok := model[field]; ok {
+		return t
+	}
+	if opts.LegacyFieldTypes {
+		return legacyFieldTypes[field]
+	}
+	return ""
+}
+
+// ParseQuery mem-parse page, pageSize, search, filter[field], preload, order, groupby, fields, omit,
+// distinct dan with_count dari url.Values. Nilai filter yang tidak sesuai tipenya menghasilkan ErrInvalidFilter.
+func ParseQuery(query url.Values, opts Options) (QueryParams, error) {
+	return ParseQuerySource(FromURLValues(query), opts)
+}
+
+// ParseQuerySource: ParseQuery untuk QuerySource apa pun.
+func ParseQuerySource(query QuerySource, opts Options) (QueryParams, error) {
+	return parseQuerySource(query, opts, nil)
+}
+
+// parseQuerySource: ParseQuerySource dengan tipe filter dari schema model (AutoFieldTypes, lihat filterType)
+func parseQuerySource(query QuerySource, opts Options, modelTypes map[string]string) (QueryParams, error) {
+	p := QueryParams{Page: opts.DefaultPage, PageSize: opts.DefaultPageSize}
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = 10
+	}
+	if pi, err := strconv.Atoi(query.Get("page")); err == nil && pi > 0 {
+		p.Page = pi
+	} else if v := query.Get("page"); v != "" && opts.StrictQuery {
+		return p, newQueryError(ErrPageOutOfRange, "page", v, "must be a positive integer")
+	} else {
+		p.Defaults = append(p.Defaults, "page")
+	}
+	if psi, err := strconv.Atoi(query.Get("pageSize")); err == nil && psi > 0 {
+		p.PageSize = psi
+	} else if v := query.Get("pageSize"); v != "" && opts.StrictQuery {
+		return p, newQueryError(ErrPageOutOfRange, "pageSize", v, "must be a positive integer")
+	} else {
+		p.Defaults = append(p.Defaults, "pageSize")
+	}
+	if opts.MaxPageSize > 0 && p.PageSize > opts.MaxPageSize {
+		if opts.StrictQuery {
+			return p, newQueryError(ErrPageSizeTooLarge, "pageSize", strconv.Itoa(p.PageSize), fmt.Sprintf("exceeds %d", opts.MaxPageSize))
+		}
+		p.PageSize = opts.MaxPageSize
+		p.Defaults = append(p.Defaults, "maxPageSize")
+	}
+	p.Search = query.Get("search")
+
+	// 🔹 filter[field]=value / filter[field]=a,b
+	var invalid []*QueryError
+	for key, vals := range query.Values() {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		field := key[7 : len(key)-1]
+		if field == "" {
+			continue
+		}
+		if _, computed := opts.ComputedColumns[field]; !computed && !fieldNamePattern.MatchString(field) {
+			invalid = append(invalid, &QueryError{Kind: ErrInvalidFilter, Param: key, Reason: "is not a valid field name",
+				Code: "invalid_field_name"})
+			continue
+		}
+		if opts.AllowedFilterFields != nil && !containsString(opts.AllowedFilterFields, field) {
+			return p, newQueryError(ErrUnknownFilterField, key, "", "is not an allowed filter")
+		}
+		f := Filter{Field: field, Op: "eq", Type: filterType(opts, modelTypes, field)}
+		raw := []string{vals[0]}
+		if strings.Contains(vals[0], ",") {
+			f.Op = "in"
+			raw = splitValues(vals[0])
+		}
+		for _, v := range raw {
+			tv, err := parseTypedValue(f.Type, v)
+			if err != nil {
+				invalid = append(invalid, &QueryError{Kind: ErrInvalidFilter, Param: key, Value: v,
+					Reason: "is not a valid " + f.Type, Code: "invalid_" + f.Type})
+				continue
+			}
+			f.Values = append(f.Values, tv)
+		}
+		if len(f.Values) > 0 {
+			p.Filters = append(p.Filters, f)
+		}
+	}
+	if len(invalid) > 0 {
+		return p, joinQueryErrors(invalid)
+	}
+	sort.Slice(p.Filters, func(i, j int) bool { return p.Filters[i].Field < p.Filters[j].Field })
+
+	p.Preloads = splitPreloads(query.Get("preload"))
+
+	p.Order = query.Get("order")
+	if p.Order == "" {
+		p.Defaults = append(p.Defaults, "order")
+	}
+	if err := checkOrder("order", p.Order, opts.ComputedColumns, opts.AllowedOrderFields); err != nil {
+		return p, err
+	}
+	p.Sort = parseSort(p.Order)
+	if opts.AllowGroupBy && query.Get("groupby") != "" {
+		p.GroupBy = nonEmpty(splitValues(query.Get("groupby")))
+		for _, g := range p.GroupBy {
+			if !fieldNamePattern.MatchString(g) {
+				return p, newQueryError(ErrInvalidField, "groupby", g, "is not a valid field name")
+			}
+			// groupby juga menentukan urutan dan projection, jadi ikut whitelist order
+			if err := checkOrder("groupby", g, nil, opts.AllowedOrderFields); err != nil {
+				return p, err
+			}
+		}
+	}
+	p.Fields = nonEmpty(splitValues(query.Get("fields")))
+	p.Omit = nonEmpty(splitValues(query.Get("omit")))
+	p.Distinct = query.Get("distinct") == "true"
+	p.WithCount = splitPreloads(query.Get("with_count"))
+	return p, nil
+}
+
+// checkTagDriven menolak filter dan order di luar kolom bertag filterable / sortable
+// (Options.TagDrivenConfig). Alias Options.ComputedColumns didefinisikan server sehingga tetap boleh.
+func checkTagDriven(p QueryParams, cfg ModelConfig, opts Options) error {
+	for _, f := range p.Filters {
+		if _, ok := opts.ComputedColumns[f.Field]; !ok && !containsString(cfg.FilterFields, f.Field) {
+			return newQueryError(ErrInvalidFilter, "filter["+f.Field+"]", "", "is not filterable")
+		}
+	}
+	for _, s := range p.Sort {
+		if _, ok := opts.ComputedColumns[s.Field]; !ok && !containsString(cfg.SortFields, s.Field) {
+			return newQueryError(ErrInvalidOrder, "order", s.Field, "is not sortable")
+		}
+	}
+	return nil
+}
+
+// checkOrder: setiap item order (parameter param) berbentuk "field [asc|desc]" dengan field identifier (fieldNamePattern) atau alias
+// ComputedColumns, dan ada di allowed bila allowed tidak nil (Options.AllowedOrderFields)
+func checkOrder(param, order string, computed map[string]string, allowed []string) error {
+	for _, item := range strings.Split(order, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) > 2 || len(parts) == 2 && !isDirection(parts[1]) {
+			return newQueryError(ErrInvalidOrder, param, strings.TrimSpace(item), "is not a valid sort")
+		}
+		if _, ok := computed[parts[0]]; !ok && !fieldNamePattern.MatchString(parts[0]) {
+			return newQueryError(ErrInvalidOrder, param, parts[0], "is not a valid field name")
+		}
+		if allowed != nil && !containsString(allowed, parts[0]) {
+			if len(allowed) == 0 {
+				return newQueryError(ErrInvalidOrder, param, parts[0], "is not sortable, sorting is disabled")
+			}
+			return newQueryError(ErrInvalidOrder, param, parts[0], "is not sortable, allowed: "+strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// containsString: true bila v ada di list
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSort memecah "nama asc, id desc" menjadi []SortField (arah default asc)
+func parseSort(order string) []SortField {
+	var out []SortField
+	for _, item := range strings.Split(order, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 {
+			continue
+		}
+		out = append(out, SortField{
+			Field: parts[0],
+			Desc:  len(parts) > 1 && strings.EqualFold(parts[1], "desc"),
+		})
+	}
+	return out
+}
+
+// nonEmpty membuang string kosong hasil splitValues
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	opts := Options{DefaultPageSize: 20, DefaultFieldTypes: map[string]string{"jumlah": "int", "status": "string"}}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  string // hasil check
+		check func(QueryParams) string
+	}{
+		{"defaults", url.Values{}, opts, "1 20",
+			func(p QueryParams) string { return fmt.Sprint(p.Page, " ", p.PageSize) }},
+		{"invalid page ignored", url.Values{"page": {"-2"}, "pageSize": {"abc"}}, opts, "1 20",
+			func(p QueryParams) string { return fmt.Sprint(p.Page, " ", p.PageSize) }},
+		{"page and search", url.Values{"page": {"3"}, "pageSize": {"5"}, "search": {"semen"}}, opts, "3 5 semen",
+			func(p QueryParams) string { return fmt.Sprint(p.Page, " ", p.PageSize, " ", p.Search) }},
+		{"typed filters sorted by field", url.Values{"filter[status]": {"aktif"}, "filter[jumlah]": {"1,2"}}, opts,
+			"[{Field:jumlah Op:in Values:[1 2] Type:int} {Field:status Op:eq Values:[aktif] Type:string}]",
+			func(p QueryParams) string { return fmt.Sprintf("%+v", p.Filters) }},
+		{"preload and sort", url.Values{"preload": {"Items.Produk,Gudang"}, "order": {"kode desc, id"}}, opts,
+			"[Items.Produk Gudang] kode desc, id [{kode true} {id false}]",
+			func(p QueryParams) string { return fmt.Sprint(p.Preloads, " ", p.Order, " ", p.Sort) }},
+		{"groupby ignored when not allowed", url.Values{"groupby": {"status"}}, opts, "[]",
+			func(p QueryParams) string { return fmt.Sprint(p.GroupBy) }},
+		{"groupby", url.Values{"groupby": {"status, gudang_id"}}, Options{AllowGroupBy: true}, "[status gudang_id]",
+			func(p QueryParams) string { return fmt.Sprint(p.GroupBy) }},
+		{"fields omit distinct with_count", url.Values{"fields": {"id,,kode"}, "omit": {"telepon"}, "distinct": {"true"}, "with_count": {"Items"}}, opts,
+			"[id kode] [telepon] true [Items]",
+			func(p QueryParams) string {
+				return fmt.Sprint(p.Fields, " ", p.Omit, " ", p.Distinct, " ", p.WithCount)
+			}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParseQuery(tc.query, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.check(p); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := ParseQuery(url.Values{"filter[jumlah]": {"1,dua"}}, opts); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("err = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestParseQueryRejectsHostileFilterFields(t *testing.T) {
+	cases := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{"eq", "filter[id) OR 1=1 --]", "x"},
+		{"in", "filter[id) OR 1=1 --]", "x,y"},
+		{"dotted", "filter[Gudang.kode) OR (1=1]", "x"},
+		{"dotted in", "filter[Gudang.kode = kode OR 1]", "a,b"},
+		{"statement", "filter[a.b;DROP TABLE orders]", "x"},
+		{"comment", "filter[status/**/]", "x"},
+		{"quote", "filter[nama\"]", "x"},
+		{"backtick", "filter[`nama`]", "x"},
+		{"leading digit", "filter[1abc]", "x"},
+		{"trailing dot", "filter[Gudang.]", "x"},
+		{"space", "filter[nama asc]", "x"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseQuery(url.Values{tc.key: {tc.val}}, Options{})
+			if !errors.Is(err, ErrInvalidFilter) {
+				t.Fatalf("err = %v, want ErrInvalidFilter", err)
+			}
+			details := DetailsFromError(err)
+			if len(details) != 1 || details[0].Code != "invalid_field_name" {
+				t.Fatalf("details = %+v, want one invalid_field_name", details)
+			}
+		})
+	}
+}
+
+func TestParseQueryAcceptsFieldNames(t *testing.T) {
+	opts := Options{ComputedColumns: map[string]string{"sisa-stok": "jumlah - 1"}}
+	for _, field := range []string{"status", "_id", "gudang_id", "Gudang.kode", "Items.Produk.nama", "sisa-stok"} {
+		p, err := ParseQuery(url.Values{"filter[" + field + "]": {"x"}}, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", field, err)
+		}
+		if len(p.Filters) != 1 || p.Filters[0].Field != field {
+			t.Fatalf("%s: filters = %+v", field, p.Filters)
+		}
+	}
+}
+
+func TestParseQueryRejectsHostileGroupBy(t *testing.T) {
+	opts := Options{AllowGroupBy: true}
+	for _, groupby := range []string{
+		"status, (select 1) as x --",
+		"status; DROP TABLE orders",
+		"MAX(id)",
+		"status desc",
+	} {
+		_, err := ParseQuery(url.Values{"groupby": {groupby}}, opts)
+		if !errors.Is(err, ErrInvalidField) {
+			t.Fatalf("%q: err = %v, want ErrInvalidField", groupby, err)
+		}
+	}
+	p, err := ParseQuery(url.Values{"groupby": {"status, gudang_id,"}}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.GroupBy) != 2 || p.GroupBy[0] != "status" || p.GroupBy[1] != "gudang_id" {
+		t.Fatalf("GroupBy = %v", p.GroupBy)
+	}
+}
+
+func TestParseQueryOrderValidation(t *testing.T) {
+	opts := Options{ComputedColumns: map[string]string{"sisa": "jumlah - 1"}}
+	for _, order := range []string{
+		"id; DROP TABLE orders",
+		"(CASE WHEN 1=1 THEN id END)",
+		"id sideways",
+		"id desc nulls",
+		"nama, id) --",
+		"MAX(created_at) desc",
+	} {
+		if _, err := ParseQuery(url.Values{"order": {order}}, opts); !errors.Is(err, ErrInvalidOrder) {
+			t.Fatalf("%q: err = %v, want ErrInvalidOrder", order, err)
+		}
+	}
+	for _, order := range []string{"id", "nama asc, id DESC", "Gudang.nama desc", "sisa", " id , "} {
+		if _, err := ParseQuery(url.Values{"order": {order}}, opts); err != nil {
+			t.Fatalf("%q: %v", order, err)
+		}
+	}
+}
+
+func TestParseQueryAllowedOrderFields(t *testing.T) {
+	cases := []struct {
+		name    string
+		allowed []string
+		query   url.Values
+		wantErr string // "" = diterima
+	}{
+		{"nil allows all", nil, url.Values{"order": {"nama"}}, ""},
+		{"listed", []string{"id", "Gudang.nama"}, url.Values{"order": {"Gudang.nama desc, id"}}, ""},
+		{"not listed", []string{"id", "created_at"}, url.Values{"order": {"id, nama"}}, "order=nama is not sortable, allowed: id, created_at"},
+		{"empty denies all", []string{}, url.Values{"order": {"id"}}, "sorting is disabled"},
+		{"empty without order", []string{}, url.Values{}, ""},
+		{"groupby listed", []string{"status"}, url.Values{"groupby": {"status"}}, ""},
+		{"groupby not listed", []string{"status"}, url.Values{"groupby": {"status,gudang_id"}}, "groupby=gudang_id is not sortable"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseQuery(tc.query, Options{AllowGroupBy: true, AllowedOrderFields: tc.allowed})
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOrder) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want ErrInvalidOrder containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package magicrest
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEntry: satu read yang berhasil untuk Options.Audit — siapa membaca apa; identitas pemanggil diambil hook
+// dari ctx. Tidak memegang referensi ke row hasil.
+type AuditEntry struct {
+	Resource  string        // nama model, e.g. "Pelanggan"
+	Operation string        // OpList, OpReadOne, OpExport (juga StreamList) atau OpReadAll
+	Key       string        // ReadOne: id, ReadOneBy: "kolom=nilai" (kosong untuk operasi lain)
+	Filters   []AuditFilter // filter[...] dari query, urut per field
+	Search    string        // ?search= ("" bila kosong atau di-redact)
+	Sort      string        // ORDER BY efektif
+	Page      int           // 0 untuk ReadOne, export dan ReadAll
+	PageSize  int
+	Rows      int           // jumlah row yang dikembalikan / ditulis
+	Duration  time.Duration // dari awal operasi sampai row terakhir
+}
+
+// AuditFilter: satu filter[field]; Values nil dan Redacted true bila field ada di Options.AuditRedactFields
+type AuditFilter struct {
+	Field    string
+	Op       string        // "eq" atau "in"
+	Values   []interface{} // nilai ter-parse
+	Redacted bool
+}
+
+// AuditFunc: hook Options.Audit, dipanggil sinkron setelah read berhasil (read yang gagal tidak dilaporkan)
+type AuditFunc func(ctx context.Context, entry AuditEntry)
+
+// readAudit: Options.Audit untuk satu read; nil = tidak aktif
+type readAudit struct {
+	fn    AuditFunc
+	entry AuditEntry
+	start time.Time
+}
+
+// newReadAudit: entry dari info dengan nilai filter (dan search) di AuditRedactFields dibuang; nil bila Audit nil
+func newReadAudit[T any](opts Options, op string, start time.Time, info QueryInfo, key string) *readAudit {
+	if opts.Audit == nil {
+		return nil
+	}
+	e := AuditEntry{Resource: modelName[T](), Operation: op, Key: key, Search: info.Search, Sort: info.Order,
+		Page: info.Page, PageSize: info.PageSize}
+	if op != OpList {
+		e.Page, e.PageSize = 0, 0
+	}
+	if containsString(opts.AuditRedactFields, "search") {
+		e.Search = ""
+	}
+	for _, f := range info.Params.Filters {
+		af := AuditFilter{Field: f.Field, Op: f.Op, Values: f.Values}
+		if containsString(opts.AuditRedactFields, f.Field) {
+			af.Values, af.Redacted = nil, true
+		}
+		e.Filters = append(e.Filters, af)
+	}
+	return &readAudit{fn: opts.Audit, entry: e, start: start}
+}
+
+// done memanggil hook bila err nil
+func (a *readAudit) done(ctx context.Context, rows int, err error) {
+	if a == nil || err != nil {
+		return
+	}
+	e := a.entry
+	e.Rows, e.Duration = rows, time.Since(a.start)
+	a.fn(ctx, e)
+}
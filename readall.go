@@ -0,0 +1,99 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReadAll memanggil fn per batch (batchSize row, default 500) untuk semua row hasil pipeline BuildQuery
+// (Scopes, filter, search, preload, masks, TransformItem) tanpa pagination, untuk proses server-side dengan
+// filter yang sama dengan request list. Batch diambil lewat FindInBatches: keyset pada primary key
+// (WHERE pk > terakhir ORDER BY pk), sehingga row tidak terlewat atau terulang walau ada insert/delete
+// bersamaan — ?order= / Options.OrderBy tidak dipakai. Berhenti pada error fn atau ctx dibatalkan;
+// mengembalikan jumlah row yang sudah diproses fn. groupby / distinct -> ErrInvalidField. Dengan
+// Options.ReadAllMaxRows, batch yang membuat total melewatinya tidak diberikan ke fn -> ErrTooManyRows.
+func ReadAll[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, batchSize int, fn func(batch []T) error) (n int, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpReadAll)
+	defer func() { op.end(n, err) }()
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatch
+	}
+	db = readDB(ctx, db, FromURLValues(query), opts)
+	q, info, err := BuildQuery(query, db.Model(modelPtr), modelPtr, opts)
+	if err != nil {
+		return 0, err
+	}
+	if info.Distinct || len(info.GroupBy) > 0 {
+		return 0, fmt.Errorf("%w: ReadAll needs whole rows, not groupby / distinct", ErrInvalidField)
+	}
+	delete(q.Statement.Clauses, "ORDER BY") // FindInBatches mengurutkan per primary key
+	info.Order = ""
+
+	var batch []T
+	err = q.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.ReadAllMaxRows > 0 && n+len(batch) > opts.ReadAllMaxRows {
+			return fmt.Errorf("%w: more than %d rows, narrow the filters", ErrTooManyRows, opts.ReadAllMaxRows)
+		}
+		applyMasks(ctx, batch, opts)
+		if opts.TransformItem != nil {
+			for i := range batch {
+				opts.TransformItem(n+i, &batch[i])
+			}
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		n += len(batch)
+		return nil
+	}).Error
+	newReadAudit[T](opts, OpReadAll, start, info, "").done(ctx, n, err)
+	return n, err
+}
+
+// ReadStream: ReadAll sebagai channel. Row dikirim satu per satu lewat channel tanpa buffer (backpressure:
+// batch berikutnya baru di-query setelah row sebelumnya diterima). Goroutine producer berhenti dan menutup
+// kedua channel saat semua row terkirim, query gagal atau ctx dibatalkan; error (termasuk ctx.Err()) dikirim
+// ke channel error (buffer 1) sebelum ditutup. Pemanggil WAJIB membaca rows sampai tertutup atau
+// membatalkan ctx — berhenti membaca tanpa cancel membuat goroutine producer menunggu selamanya.
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	defer cancel()
+//	rows, errc := magicrest.ReadStream(ctx, query, db, &Barang{}, opts, 500)
+//	for b := range rows { ... }
+//	if err := <-errc; err != nil { ... }
+func ReadStream[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, batchSize int) (<-chan T, <-chan error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rows := make(chan T)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(rows)
+		_, err := ReadAll(ctx, query, db, modelPtr, opts, batchSize, func(batch []T) error {
+			for i := range batch {
+				select {
+				case rows <- batch[i]:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return rows, errc
+}
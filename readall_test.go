@@ -0,0 +1,169 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// waitGoroutines: tunggu jumlah goroutine kembali ke baseline (producer selesai), gagal setelah 2 detik
+func waitGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines, baseline %d:\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReadStreamCancelMidStream(t *testing.T) {
+	cases := []struct {
+		name  string
+		drain bool // terus membaca rows setelah cancel
+	}{
+		{"drain after cancel", true},
+		{"abandon after cancel", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			seedOrders(t, db, 20, 0)
+			rdb, rec := recordSQL(db)
+			baseline := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			rows, errc := ReadStream(ctx, url.Values{}, rdb.Model(&Order{}), &Order{}, Options{}, 3)
+			got := 0
+			for range rows {
+				if got++; got == 4 { // di tengah batch kedua
+					break
+				}
+			}
+			cancel()
+			queries := len(rec.matching("FROM `orders`"))
+			if tc.drain {
+				for range rows {
+					got++
+				}
+			}
+			if err := <-errc; !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			waitGoroutines(t, baseline)
+			if _, open := <-rows; open {
+				t.Fatal("rows still open")
+			}
+			// paling banyak sisa batch yang sedang dikirim; tidak ada batch baru setelah cancel
+			if got > 6 {
+				t.Fatalf("%d rows received after cancel at row 4", got)
+			}
+			if n := len(rec.matching("FROM `orders`")); n != queries {
+				t.Fatalf("%d queries after cancel (had %d)", n-queries, queries)
+			}
+		})
+	}
+}
+
+func TestReadStreamComplete(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 7, 0)
+	baseline := runtime.NumGoroutine()
+	rows, errc := ReadStream(context.Background(), url.Values{"filter[status]": {"aktif"}}, db.Model(&Order{}), &Order{}, Options{}, 2)
+	var kodes []string
+	for o := range rows {
+		kodes = append(kodes, o.Kode)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if len(kodes) != 4 || kodes[0] != "ORD-01" || kodes[3] != "ORD-07" {
+		t.Fatalf("rows %v", kodes)
+	}
+	waitGoroutines(t, baseline)
+}
+
+func TestReadAllCancelStopsCallback(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 10, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	n, err := ReadAll(ctx, url.Values{}, db.Model(&Order{}), &Order{}, Options{}, 3, func(batch []Order) error {
+		if calls++; calls == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || calls != 2 || n != 6 {
+		t.Fatalf("err %v, %d calls, %d rows: want context.Canceled after 2 calls", err, calls, n)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 7, 0)
+	stop := errors.New("stop")
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		fail  int    // batch ke-n (1-based) membuat fn gagal, 0 = tidak pernah
+		want  string // kode per batch yang diterima fn
+		n     int
+		err   error
+	}{
+		{"filtered, order ignored", url.Values{"filter[status]": {"aktif"}, "order": {"id desc"}}, Options{}, 0, "[[ORD-01 ORD-03] [ORD-05 ORD-07]]", 4, nil},
+		{"last partial batch", url.Values{}, Options{}, 0, "[[ORD-01 ORD-02] [ORD-03 ORD-04] [ORD-05 ORD-06] [ORD-07]]", 7, nil},
+		{"fn error stops", url.Values{}, Options{}, 2, "[[ORD-01 ORD-02] [ORD-03 ORD-04]]", 2, stop},
+		{"groupby rejected", url.Values{"groupby": {"status"}}, Options{AllowGroupBy: true}, 0, "[]", 0, ErrInvalidField},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got [][]string
+			n, err := ReadAll(context.Background(), tc.query, db, &Order{}, tc.opts, 2, func(batch []Order) error {
+				var kodes []string
+				for _, o := range batch {
+					kodes = append(kodes, o.Kode)
+				}
+				if got = append(got, kodes); len(got) == tc.fail {
+					return stop
+				}
+				return nil
+			})
+			if n != tc.n || !errors.Is(err, tc.err) {
+				t.Fatalf("n = %d, err %v; want %d, %v", n, err, tc.n, tc.err)
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("batches %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadAllCancel(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 6, 0)
+	rdb, rec := recordSQL(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches := 0
+	n, err := ReadAll(ctx, url.Values{}, rdb, &Order{}, Options{}, 2, func([]Order) error {
+		batches++
+		cancel()
+		return nil
+	})
+	if n != 2 || batches != 1 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("n = %d, %d batches, err %v", n, batches, err)
+	}
+	if q := rec.matching("FROM `orders`"); len(q) > 2 {
+		t.Fatalf("queries after cancel: %v", q)
+	}
+}
@@ -2,27 +2,109 @@
 package magicrest
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 // Options mengontrol behaviour fungsi ReadPaginated
 type Options struct {
 	SearchField       string
-	OrderBy           string // fallback order if not provided
+	SearchFields      []string // pencarian OR di beberapa kolom, e.g. {"nama", "kode"} (ditambah SearchField bila di-set)
+	OrderBy           string   // fallback order if not provided
 	PreloadFields     []string
 	DefaultFieldTypes map[string]string // e.g. "id":"uuid", "status":"string"
+	AutoFieldTypes    bool              // isi tipe filter dari schema model (FieldTypesFromModel), DefaultFieldTypes tetap menang
+	LegacyFieldTypes  bool              // pakai tipe bawaan lama (id, status, jumlah, gudang_id)
+	TagDrivenConfig   bool              // filter/order/search hanya untuk kolom bertag `magicrest:"filterable,sortable,searchable"`
+	EchoQuery         bool              // tampilkan query yang dipahami server di Meta["query"]
+	IncludeStats      bool              // durasi query data / count dan jumlah row di Meta["stats"] (QueryStats); jangan aktifkan bila timing tidak boleh publik
+	AllowDebugQuery   bool              // izinkan ?debug=1 untuk Meta["query"] per request
+	Debug             bool              // sertakan SQL data dan count di Meta["sql"]
+	AllowDebugSQL     bool              // izinkan ?debug=sql untuk Meta["sql"] per request (tidak pernah aktif tanpa opt-in ini)
 	DefaultPage       int
 	DefaultPageSize   int
+	MaxPageSize       int // batas atas ?pageSize= (0 = tanpa batas)
 	AllowGroupBy      bool
+	AllowedPreloads   []string            // whitelist ?preload= (nil = semua boleh, slice kosong = tolak semua), mendukung "Items.Product"
+	PreloadPolicy     PreloadPolicy       // perlakuan preload di luar whitelist: reject (default) atau drop + warning
+	MaxPreloadDepth   int                 // kedalaman maksimum path preload dari query (default 3)
+	MaxPreloads       int                 // jumlah maksimum preload dari query (default 10)
+	PreloadSelects    map[string][]string // kolom default per preload, e.g. "Items": {"id","nama"} (FK otomatis ikut)
+	PreloadLimits     map[string]int      // maksimum child per parent untuk preload has-one/has-many, e.g. "Items": 5
+	PreloadMergeMode  PreloadMergeMode    // PreloadReplace (default), PreloadMerge (direkomendasikan) atau PreloadServerOnly
+	PreloadStrategy   map[string]Strategy // StrategyJoin untuk relasi belongs-to/has-one (satu query), default StrategyPreload
+	WithCounts        []string            // relasi yang dihitung sebagai <relation>_count (ditambah ?with_count=)
+	AutoAllowPreloads bool                // tambahkan DiscoverRelations[T]() ke whitelist AllowedPreloads
+	AllowDistinct     bool                // izinkan ?fields=status&distinct=true
+	DistinctFields    []string            // kolom yang boleh dipakai dengan distinct (nil = tidak ada)
+	StrictFields      bool                // ?fields= / ?omit= dengan kolom tidak dikenal -> ErrInvalidField (default: di-drop + warning)
+	StrictQuery       bool                // page/pageSize tidak valid, filter kolom tidak dikenal, dll -> error (default: fallback diam-diam)
+	RequireUnique     bool                // ReadFirst: lebih dari satu row cocok -> ErrMultipleResults
+	ComputedColumns   map[string]string   // alias -> ekspresi SQL, e.g. "sisa_stok": "jumlah - reserved" (via ?fields=, order, filter)
+	SelectableColumns []string            // whitelist kolom yang boleh di-select (default Select bila di-set, nil = semua / *)
+	MaskFields        MaskFunc            // dipanggil per item (pointer ke T) sebelum Result dikembalikan
+	MaskedColumns     map[string][]string // role -> kolom (nama json) yang dikosongkan, e.g. "kasir": {"telepon", "pelanggan.alamat"}
+	MaskRole          RoleFunc            // role pemanggil untuk MaskedColumns (dari context request)
+	Scopes            []Scope             // tenant, default filter, join: diterapkan berurutan sebelum filter dinamis (ikut count)
+	BeforeQuery       BeforeQueryFunc     // hook sebelum filter dinamis (otorisasi), error = request dibatalkan
+	AfterQuery        AfterQueryFunc      // hook setelah Result lengkap (post-process, metrics)
+	TransformItem     TransformItemFunc   // tweak per item (pointer ke T) setelah pagination dan preload
+	TransformResult   TransformResultFunc // tambah info ke Meta (server time, flags, agregat lain)
+	Envelope          EnvelopeConfig      // key response handler bawaan, default {"data": ..., "meta": ...}
+	ETag              bool                // ListHandlerHTTP / adapter: ETag halaman list dan 304 untuk If-None-Match
+	LastModified      bool                // ListHandlerHTTP / adapter: Last-Modified dari MAX(ModifiedColumn), 304 untuk If-Modified-Since
+	ModifiedColumn    string              // kolom untuk ETag / LastModified (default field autoUpdateTime / updated_at)
+	StreamAbove       int                 // ListHandlerHTTP: streaming (StreamListHandler) bila pageSize melebihi nilai ini (0 = tidak pernah)
+	StreamBatchSize   int                 // jumlah row per query saat streaming (default 500)
+	QueryTimeout      time.Duration       // batas waktu query list/count (deadline context; Postgres juga SET LOCAL statement_timeout), lewat -> ErrQueryTimeout
+	RangeHeader       bool                // ListHandlerHTTP / adapter: Range: items=0-49 -> 206 + Content-Range, 416 bila di luar total
+	NegotiateContent  bool                // ListHandlerHTTP / adapter: Accept text/csv / application/x-ndjson -> export tanpa pagination
+	ExportMaxRows     int                 // batas row export (0 = tanpa batas), lebih -> ErrExportTooLarge
+	HardRowLimit      int                 // batas row satu halaman di memori (0 = HardRowLimit paket, negatif = tanpa batas), lebih -> ErrTooManyRows
+	ReadAllMaxRows    int                 // batas total row ReadAll / ReadStream (0 = tanpa batas), lebih -> ErrTooManyRows
+	ResourceType      string              // type resource JSON:API / nama _embedded HAL, default nama tabel
+	SelfLinkTemplate  string              // link self item HAL, e.g. "/api/barang/{id}" (default "<path list>/{id}")
+	ResultCache       ResultCache         // cache hasil list per query kanonik (NewMemoryCache, Redis), hit -> Meta["cached"]
+	ResultCacheTTL    time.Duration       // umur entry ResultCache (default 1 menit)
+	ResultCacheDepth  int                 // hanya page <= nilai ini yang di-cache (0 = semua halaman)
+	ResultCacheKey    CacheKeyFunc        // bagian key per pemanggil (tenant, role); wajib bila ada Scopes / masking / BeforeQuery / ReadDBResolver
+	ReadDB            *gorm.DB            // replica untuk list, count, ReadOne dan export (hanya koneksinya dipakai; nil = db yang dikirim)
+	ReadDBResolver    ReadDBFunc          // replica per request, menang atas ReadDB
+	AllowStrongReads  bool                // izinkan ?consistency=strong: baca dari db yang dikirim (primary) walau ReadDB di-set
+	Tracer            Tracer              // span per fase list (parse, count, data, with_count), nil = DefaultTracer; adapter OpenTelemetry: modul oteltrace
+	Audit             AuditFunc           // dipanggil setelah setiap read berhasil (list, ReadOne, export, ReadAll): model, filter, sort, jumlah row, durasi
+	AuditRedactFields []string            // filter (dan "search") yang nilainya tidak dikirim ke Audit karena berisi data pribadi, e.g. {"email", "telepon"}
+	Logger            *slog.Logger        // debug: query yang di-parse dan durasi list; warn: pageSize dipotong, parameter diabaikan, query ditolak (nil = tanpa log)
+	Metrics           Recorder            // durasi per operasi dan query, jumlah row dan penolakan, nil = DefaultMetrics; Prometheus: modul prommetrics
+	SQLComment        SQLCommentFunc      // tag dari context untuk komentar di akhir SELECT, e.g. /* app=gudang, request_id=.., resource=Barang */ (pg_stat_activity)
+	UseIndex          []string            // index hint untuk query data dan count (MySQL USE INDEX, SQL Server WITH (INDEX)), dialect lain diabaikan
+
+	SlowQueryThreshold time.Duration // OnSlowQuery dipanggil untuk query selama ini atau lebih (0 = semua query)
+	OnSlowQuery        SlowQueryFunc // hook per query (data, count, with_count, ...), label di SlowQueryInfo.Label
+
+	// whitelist field filter[...] dari klien, termasuk alias ComputedColumns dan PageRequest.Filter
+	// (nil = semua kolom boleh, slice kosong = tolak semua filter), lainnya -> ErrUnknownFilterField
+	AllowedFilterFields []string
+	// whitelist kolom ?order= dan ?groupby= dari klien, termasuk "Relasi.kolom" dan alias ComputedColumns (nil =
+	// semua boleh, slice kosong = tolak semua), lainnya -> ErrInvalidOrder. Options.OrderBy tidak terkena whitelist.
+	AllowedOrderFields []string
+
+	compiled *CompiledOptions // di-set CompileOptions: konfigurasi turunan model sudah di-resolve dan divalidasi
 }
 
+// Scope sama dengan fungsi untuk db.Scopes (alias, jadi func(*gorm.DB) *gorm.DB biasa bisa langsung dipakai)
+type Scope = func(*gorm.DB) *gorm.DB
+
 // Result meta dan data yang dikembalikan
 type Result[T any] struct {
 	Data []T
@@ -32,220 +114,495 @@ type Result[T any] struct {
 // ErrInvalidFilter digunakan bila ada filter tidak valid
 var ErrInvalidFilter = errors.New("invalid filter value")
 
-// ReadPaginated: core function yang tidak bergantung gin.
-// - query: url.Values (bisa dari request.URL.Query())
-// - db: *gorm.DB (sudah di-set model, joins, etc jika perlu dari caller)
-// - modelPtr: pointer ke slice/struct model seperti &models.YourModel{} (digunakan untuk scanning)
-// Mengembalikan data (slice T), meta (dengan pagination), dan error.
-func ReadPaginated[T any](query url.Values, db *gorm.DB, modelPtr *T, opts Options) (Result[T], error) {
-	// defaults
-	page := opts.DefaultPage
-	if page <= 0 {
-		page = 1
-	}
-	pageSize := opts.DefaultPageSize
-	if pageSize <= 0 {
-		pageSize = 10
+// ErrInvalidOrder digunakan bila ?order= memakai kolom yang tidak boleh diurutkan
+var ErrInvalidOrder = errors.New("invalid order")
+
+// QueryInfo menjelaskan apa yang diterapkan BuildQuery ke query builder.
+type QueryInfo struct {
+	Page           int
+	PageSize       int
+	Search         string
+	SearchFields   []string               // kolom yang dipakai ?search=
+	Order          string                 // klausa ORDER BY efektif
+	Filters        map[string]interface{} // filter[field] -> nilai ter-parse ([]interface{} untuk IN)
+	Preloads       []string
+	Fields         []string // kolom yang di-select (nil = semua)
+	Distinct       bool
+	GroupBy        []string
+	ImplicitFields []string
+	Warnings       []string
+	Params         QueryParams // hasil ParseQuery
+
+	schema *schema.Schema
+	counts []relationCount
+}
+
+// BuildQuery menerapkan semua yang dilakukan ReadPaginated (filter, search, preload, fields, group,
+// order) kecuali Limit/Offset/Find, lalu mengembalikan builder-nya untuk dipakai ulang (export,
+// subquery EXISTS, count khusus, dll) beserta QueryInfo.
+func BuildQuery[T any](query url.Values, db *gorm.DB, modelPtr *T, opts Options) (*gorm.DB, QueryInfo, error) {
+	return BuildQuerySource[T](FromURLValues(query), db, modelPtr, opts)
+}
+
+// BuildQuerySource: BuildQuery untuk QuerySource apa pun (gin.Context, gRPC, fixture, QueryBuilder).
+func BuildQuerySource[T any](query QuerySource, db *gorm.DB, modelPtr *T, opts Options) (*gorm.DB, QueryInfo, error) {
+	q, info, err := buildQuerySource[T](query, db, modelPtr, opts)
+	if opts.Logger != nil {
+		logBuild(db.Statement.Context, opts, modelName[T](), query, info, err)
 	}
+	return q, info, err
+}
 
-	if p := query.Get("page"); p != "" {
-		if pi, err := strconv.Atoi(p); err == nil && pi > 0 {
-			page = pi
+// buildQuerySource: pipeline BuildQuerySource, tanpa Options.Logger
+func buildQuerySource[T any](query QuerySource, db *gorm.DB, modelPtr *T, opts Options) (*gorm.DB, QueryInfo, error) {
+	// tipe dari schema dibaca dari cache per model, tanpa menyalin map tiap request
+	var modelTypes map[string]string
+	if opts.AutoFieldTypes {
+		modelTypes = modelFieldTypes[T]()
+	}
+	params, err := parseQuerySource(query, opts, modelTypes)
+	if err != nil {
+		return nil, QueryInfo{}, err
+	}
+	if opts.TagDrivenConfig {
+		cfg := ConfigFromModel[T]()
+		if err := checkTagDriven(params, cfg, opts); err != nil {
+			return nil, QueryInfo{}, err
+		}
+		if len(opts.SearchFields) == 0 {
+			opts.SearchFields = cfg.SearchFields
 		}
 	}
-	if ps := query.Get("pageSize"); ps != "" {
-		if psi, err := strconv.Atoi(ps); err == nil && psi > 0 {
-			pageSize = psi
+
+	// 🔹 Scopes dari Options lebih dulu agar filter dari query tidak bisa melewatinya
+	for _, scope := range opts.Scopes {
+		db = scope(db)
+	}
+	if len(opts.UseIndex) > 0 {
+		db = db.Clauses(indexHint{names: opts.UseIndex})
+	}
+	db = withSQLComment(db, opts, modelName[T]())
+	if opts.BeforeQuery != nil {
+		if db, err = opts.BeforeQuery(db.Statement.Context, db, params); err != nil {
+			return nil, QueryInfo{}, err
 		}
 	}
 
-	search := query.Get("search")
-	invalidFilter := false
+	// 🔹 JOIN relasi untuk filter / search / order "Relasi.kolom", satu kali per relasi
+	joins, err := newJoinRegistry(db, modelPtr, relationRefs(params, opts, params.Search))
+	if err != nil {
+		return nil, QueryInfo{}, err
+	}
 
-	// if no custom default provided, use sensible defaults
-	defaultAllowed := map[string]string{
-		"id":        "uuid",
-		"status":    "string",
-		"jumlah":    "int",
-		"gudang_id": "uuid",
+	// 🔹 Dynamic filters: filter[field]=value
+	var sch *schema.Schema
+	if len(params.Filters) > 0 {
+		if sch, err = parseSchema(db, modelPtr); err != nil {
+			return nil, QueryInfo{}, err
+		}
 	}
-	// merge defaults
-	if opts.DefaultFieldTypes == nil {
-		opts.DefaultFieldTypes = defaultAllowed
-	} else {
-		for k, v := range defaultAllowed {
-			if _, ok := opts.DefaultFieldTypes[k]; !ok {
-				opts.DefaultFieldTypes[k] = v
+	if opts.StrictQuery {
+		for _, f := range params.Filters {
+			if _, ok := opts.ComputedColumns[f.Field]; !ok && !isColumnField(sch.LookUpField(f.Field)) && !joins.known(f.Field) {
+				return nil, QueryInfo{}, newQueryError(ErrUnknownFilterField, "filter["+f.Field+"]", "", "")
 			}
 		}
 	}
+	filters := map[string]interface{}{}
+	for _, f := range params.Filters {
+		column, vars := filterExpr(sch, joins, f.Field)
+		if expr, ok := opts.ComputedColumns[f.Field]; ok {
+			column, vars = "("+expr+")", nil
+		}
+		if f.Op == "in" {
+			db = db.Where(fmt.Sprintf("%s IN ?", column), append(vars, f.Values)...)
+			filters[f.Field] = f.Values
+		} else {
+			db = db.Where(fmt.Sprintf("%s = ?", column), append(vars, f.Values[0])...)
+			filters[f.Field] = f.Values[0]
+		}
+	}
 
-	// 🔹 Dynamic filters: filter[field]=value
-	for key, vals := range query {
-		if strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]") {
-			field := key[7 : len(key)-1]
-			if field == "" {
-				continue
-			}
-			value := vals[0]
-			fieldType := opts.DefaultFieldTypes[field]
+	// 🔹 Distinct (?fields=status&distinct=true, hanya bila opts.AllowDistinct)
+	var warnings []string
+	distinct := params.Distinct
+	if distinct && !opts.AllowDistinct {
+		warnings = append(warnings, "distinct is not enabled for this resource and was ignored")
+		distinct = false
+	}
 
-			if strings.Contains(value, ",") {
-				parts := strings.Split(value, ",")
-				for i := range parts {
-					parts[i] = strings.TrimSpace(parts[i])
-				}
-				switch fieldType {
-				case "int":
-					var ints []int
-					for _, v := range parts {
-						iv, err := strconv.Atoi(v)
-						if err != nil {
-							invalidFilter = true
-							continue
-						}
-						ints = append(ints, iv)
-					}
-					if len(ints) > 0 {
-						db = db.Where(fmt.Sprintf("%s IN ?", field), ints)
-					}
-				case "uuid":
-					var uuids []string
-					for _, v := range parts {
-						if _, err := uuid.Parse(v); err != nil {
-							invalidFilter = true
-							continue
-						}
-						uuids = append(uuids, v)
-					}
-					if len(uuids) > 0 {
-						db = db.Where(fmt.Sprintf("%s IN ?", field), uuids)
-					}
-				default:
-					db = db.Where(fmt.Sprintf("%s IN ?", field), parts)
-				}
-			} else {
-				switch fieldType {
-				case "int":
-					iv, err := strconv.Atoi(value)
-					if err != nil {
-						invalidFilter = true
-						continue
-					}
-					db = db.Where(fmt.Sprintf("%s = ?", field), iv)
-				case "uuid":
-					if _, err := uuid.Parse(value); err != nil {
-						invalidFilter = true
-						continue
-					}
-					db = db.Where(fmt.Sprintf("%s = ?", field), value)
-				default:
-					db = db.Where(fmt.Sprintf("%s = ?", field), value)
-				}
-			}
+	// 🔹 Preload (from query ?preload=A,B(id,nama) / preload[Rel][field]=value or from opts)
+	// Baris DISTINCT tidak punya primary key sehingga preload tidak dipakai.
+	var preloads []string
+	if !distinct {
+		if opts.AutoAllowPreloads {
+			opts.AllowedPreloads = append(append([]string{}, opts.AllowedPreloads...), modelRelations[T]()...)
 		}
+		var dropped []string
+		if db, preloads, dropped, err = applyPreloads(db, modelPtr, query, opts); err != nil {
+			return nil, QueryInfo{}, err
+		}
+		warnings = append(warnings, dropped...)
 	}
 
-	if invalidFilter {
-		return Result[T]{Data: []T{}, Meta: map[string]interface{}{}}, ErrInvalidFilter
+	// 🔹 Search
+	search := params.Search
+	// "Relasi.kolom" (belongs-to/has-one) di-join otomatis; alias lain (e.g. "g.nama") butuh JOIN dari caller
+	if opts.SearchField != "" && search != "" && len(opts.SearchFields) == 0 {
+		column, vars := joins.expr(opts.SearchField)
+		db = db.Where(fmt.Sprintf("%s ILIKE ?", column), append(vars, "%"+search+"%")...)
+	}
+	if len(opts.SearchFields) > 0 && search != "" {
+		// beberapa kolom sekaligus: a ILIKE ? OR b ILIKE ? (gorm membungkusnya dengan kurung)
+		fields := opts.SearchFields
+		if opts.SearchField != "" {
+			fields = append([]string{opts.SearchField}, fields...)
+		}
+		conds := make([]string, len(fields))
+		var args []interface{}
+		for i, f := range fields {
+			column, vars := joins.expr(f)
+			conds[i] = fmt.Sprintf("%s ILIKE ?", column)
+			args = append(append(args, vars...), "%"+search+"%")
+		}
+		db = db.Where(strings.Join(conds, " OR "), args...)
 	}
 
-	// 🔹 Preload (from query ?preload=A,B or from opts)
-	if preloadQuery := query.Get("preload"); preloadQuery != "" {
-		fields := strings.Split(preloadQuery, ",")
-		for _, f := range fields {
-			if f = strings.TrimSpace(f); f != "" {
-				db = db.Preload(f)
+	// 🔹 Sparse fieldsets (?fields=id,nama / ?omit=deskripsi) dan relation counts (?with_count=Items or opts)
+	var columns []string
+	var computed []computedColumn
+	var implicit []string
+	var counts []relationCount
+	grouping := len(params.GroupBy) > 0
+	wantCounts := !distinct && (len(opts.WithCounts) > 0 || len(params.WithCount) > 0)
+	project := len(params.Fields) > 0 || len(params.Omit) > 0 || (opts.SelectableColumns != nil && !grouping)
+	if project || wantCounts || distinct || grouping && opts.SelectableColumns != nil || len(opts.ComputedColumns) > 0 {
+		if sch, err = parseSchema(db, modelPtr); err != nil {
+			return nil, QueryInfo{}, err
+		}
+		if !compiledFor[T](opts) {
+			if err = checkComputedColumns(sch, opts.ComputedColumns); err != nil {
+				return nil, QueryInfo{}, err
 			}
 		}
-	} else {
-		for _, f := range opts.PreloadFields {
-			db = db.Preload(f)
+	}
+	if distinct {
+		if columns, err = distinctColumns(sch, params.Fields, opts); err != nil {
+			return nil, QueryInfo{}, err
+		}
+	} else if project {
+		p, err := projectColumns(sch, params.Fields, params.Omit, preloads, opts)
+		if err != nil {
+			return nil, QueryInfo{}, err
 		}
+		columns, computed, implicit = p.Columns, p.Computed, p.Implicit
+		warnings = append(warnings, p.Warnings...)
 	}
-
-	// 🔹 Search
-	if opts.SearchField != "" && search != "" {
-		if strings.Contains(opts.SearchField, ".") {
-			parts := strings.Split(opts.SearchField, ".")
-			if len(parts) == 2 {
-				relation := parts[0]
-				field := parts[1]
-				// Note: caller should be responsible untuk JOIN alias yang benar bila perlu.
-				db = db.Where(fmt.Sprintf("%s.%s ILIKE ?", relation, field), "%"+search+"%")
-			} else {
-				db = db.Where(fmt.Sprintf("%s ILIKE ?", opts.SearchField), "%"+search+"%")
+	if wantCounts {
+		if counts, err = resolveWithCounts(db, sch, params.WithCount, opts); err != nil {
+			return nil, QueryInfo{}, err
+		}
+	}
+	if grouping && opts.SelectableColumns != nil {
+		allowed, err := selectableColumns(sch, opts.SelectableColumns)
+		if err != nil {
+			return nil, QueryInfo{}, err
+		}
+		for _, g := range params.GroupBy {
+			if f := sch.LookUpField(g); f == nil || !allowed[f.DBName] {
+				return nil, QueryInfo{}, newQueryError(ErrInvalidField, "groupby", g, "is not selectable")
 			}
+		}
+	}
+	if grouping && (len(columns) > 0 || len(computed) > 0 || len(counts) > 0) {
+		warnings = append(warnings, "fields, omit, distinct and with_count are ignored when groupby is used")
+		columns, computed, implicit, counts, distinct = nil, nil, nil, nil, false
+	}
+	if sel := selectList(db, sch, columns, computed, counts); len(sel) > 0 {
+		if distinct {
+			db = db.Distinct(sel)
 		} else {
-			db = db.Where(fmt.Sprintf("%s ILIKE ?", opts.SearchField), "%"+search+"%")
+			db = db.Select(sel)
 		}
 	}
 
 	// 🔹 Group by (opsional)
-	if opts.AllowGroupBy {
-		if gq := query.Get("groupby"); gq != "" {
-			fields := strings.Split(gq, ",")
-			for i := range fields {
-				fields[i] = strings.TrimSpace(fields[i])
-			}
-			groupExpr := strings.Join(fields, ", ")
-			db = db.Select(fmt.Sprintf("%s, MAX(created_at) as created_at", groupExpr)).
-				Group(groupExpr).
-				Order("MAX(created_at) desc")
+	if grouping {
+		// kolom sudah lolos fieldNamePattern, tetap di-quote sesuai dialect
+		quoted := make([]string, len(params.GroupBy))
+		for i, g := range params.GroupBy {
+			quoted[i] = db.Statement.Quote(g)
 		}
+		groupExpr := strings.Join(quoted, ", ")
+		db = db.Select(fmt.Sprintf("%s, MAX(created_at) as created_at", groupExpr)).
+			Group(groupExpr).
+			Order("MAX(created_at) desc")
 	}
 
 	// 🔹 Order by
 	orderBy := opts.OrderBy
-	if qOrder := query.Get("order"); qOrder != "" {
-		orderBy = qOrder
+	if distinct {
+		// ORDER BY pada DISTINCT harus memakai kolom yang di-select
+		orderBy = strings.Join(selectList(db, sch, columns, nil, nil), ", ")
+	}
+	if params.Order != "" {
+		orderBy = params.Order
+	} else if !distinct && opts.OrderBy != "" {
+		if err := checkOrder("order", opts.OrderBy, opts.ComputedColumns, nil); err != nil {
+			return nil, QueryInfo{}, fmt.Errorf("%w: OrderBy: %v", ErrInvalidConfig, err)
+		}
 	}
 	if orderBy != "" {
-		db = db.Order(orderBy)
+		orderBy = computedOrder(orderBy, opts.ComputedColumns)
 	} else {
-		db = db.Order("created_at desc")
+		orderBy = "created_at desc"
 	}
+	db = joins.apply(db.Order(joins.order(orderBy)))
 
-	// 🔹 Paginate (menggunakan helper PaginateGeneric)
-	data, pagination, err := PaginateGeneric[T](db, modelPtr, page, pageSize)
+	return db, QueryInfo{
+		Page:           params.Page,
+		PageSize:       params.PageSize,
+		Search:         search,
+		SearchFields:   searchFields(opts),
+		Order:          orderBy,
+		Filters:        filters,
+		Preloads:       preloads,
+		Fields:         columns,
+		Distinct:       distinct,
+		GroupBy:        params.GroupBy,
+		ImplicitFields: implicit,
+		Warnings:       warnings,
+		Params:         params,
+		schema:         sch,
+		counts:         counts,
+	}, nil
+}
+
+// ReadPaginated: core function yang tidak bergantung gin.
+// - query: url.Values (bisa dari request.URL.Query())
+// - db: *gorm.DB (sudah di-set model, joins, etc jika perlu dari caller)
+// - modelPtr: pointer ke slice/struct model seperti &models.YourModel{} (digunakan untuk scanning)
+// Mengembalikan data (slice T), meta (dengan pagination), dan error.
+// Context yang sudah di-set lewat db.WithContext tetap dipakai; lihat juga ReadPaginatedCtx.
+func ReadPaginated[T any](query url.Values, db *gorm.DB, modelPtr *T, opts Options) (Result[T], error) {
+	return ReadPaginatedCtx[T](db.Statement.Context, query, db, modelPtr, opts)
+}
+
+// ReadPaginatedCtx: ReadPaginated dengan context request, sehingga cancel/deadline sampai ke
+// database untuk count, find, preload dan query tambahan (with_count).
+func ReadPaginatedCtx[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options) (Result[T], error) {
+	return ReadPaginatedSource[T](ctx, FromURLValues(query), db, modelPtr, opts)
+}
+
+// ReadPaginatedSource: ReadPaginatedCtx dengan parameter dari QuerySource, bukan url.Values.
+func ReadPaginatedSource[T any](ctx context.Context, query QuerySource, db *gorm.DB, modelPtr *T, opts Options) (Result[T], error) {
+	return readList[T](ctx, query, db, modelPtr, opts, nil)
+}
+
+// readList: pipeline ReadPaginatedSource; rng != nil mengganti page/pageSize dengan jendela item (ReadRange)
+func readList[T any](ctx context.Context, query QuerySource, db *gorm.DB, modelPtr *T, opts Options, rng *itemRange) (res Result[T], err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db = readDB(ctx, db, query, opts)
+	if opts.QueryTimeout > 0 {
+		var res Result[T]
+		err := withQueryTimeout(ctx, db, opts.QueryTimeout, func(ctx context.Context, tx *gorm.DB) error {
+			inner := opts
+			inner.QueryTimeout = 0
+			var err error
+			res, err = readList[T](ctx, query, tx, modelPtr, inner, rng)
+			return err
+		})
+		return res, err
+	}
+	start := time.Now()
+	ctx, span := startSpan(ctx, opts, "list", TraceAttr{Key: TraceAttrModel, Value: modelName[T]()})
+	op := startOp[T](opts.Metrics, OpList)
+	var info QueryInfo
+	defer func() {
+		endListSpan(span, res, err)
+		op.end(len(res.Data), err)
+		if opts.Logger != nil {
+			logList(ctx, opts.Logger, info, res, start, err)
+		}
+		newReadAudit[T](opts, OpList, start, info, "").done(ctx, len(res.Data), err)
+	}()
+	db = db.WithContext(ctx)
+	_, parse := startSpan(ctx, opts, "parse")
+	db, info, err = BuildQuerySource[T](query, db, modelPtr, opts)
+	parse.End(err)
 	if err != nil {
-		return Result[T]{}, err
+		return Result[T]{Data: []T{}, Meta: map[string]interface{}{}}, err
 	}
+	span.SetAttributes(TraceAttr{Key: TraceAttrPage, Value: info.Page}, TraceAttr{Key: TraceAttrPageSize, Value: info.PageSize},
+		TraceAttr{Key: TraceAttrFilters, Value: filterNames(info.Params)})
 
-	return Result[T]{
-		Data: data,
-		Meta: map[string]interface{}{"pagination": pagination},
-	}, nil
+	cacheKey := ""
+	if opts.ResultCache != nil && rng == nil {
+		if err := checkResultCacheKey(opts); err != nil {
+			return Result[T]{Data: []T{}, Meta: map[string]interface{}{}}, err
+		}
+		if cacheKey = listCacheKey[T](ctx, query, info.Page, info.PageSize, opts); cacheKey != "" {
+			if res, ok := cachedResult[T](ctx, opts.ResultCache, cacheKey, info.Page, info.PageSize); ok {
+				if opts.IncludeStats {
+					res.Meta["stats"] = QueryStats{Rows: len(res.Data)}
+				}
+				return finishList(ctx, res, info, opts, start)
+			}
+		}
+	}
+
+	// 🔹 Paginate (menggunakan helper PaginateGeneric)
+	slow := newSlowQuery(opts, info.Params, modelName[T]())
+	var data []T
+	var pagination map[string]interface{}
+	if rng != nil {
+		if data, pagination, err = rangeGeneric[T](ctx, db, *rng, info.PageSize, opts.MaxPageSize, hardRowLimit(opts), slow); err != nil {
+			return Result[T]{Data: []T{}, Meta: errorMeta(pagination)}, err
+		}
+	} else if data, pagination, err = paginate[T](ctx, db, info.Page, info.PageSize, hardRowLimit(opts), slow); err != nil {
+		return Result[T]{Data: []T{}, Meta: errorMeta(pagination)}, err
+	}
+	if pc, _ := pagination["pageCount"].(int); rng == nil && opts.StrictQuery && info.Page > 1 && info.Page > pc {
+		return Result[T]{Data: []T{}, Meta: map[string]interface{}{"pagination": pagination}},
+			newQueryError(ErrPageOutOfRange, "page", strconv.Itoa(info.Page), fmt.Sprintf("exceeds pageCount %d", pc))
+	}
+
+	applyMasks(ctx, data, opts)
+	if opts.TransformItem != nil {
+		for i := range data {
+			opts.TransformItem(i, &data[i])
+		}
+	}
+
+	meta := map[string]interface{}{"pagination": pagination}
+	if len(info.counts) > 0 {
+		extra, err := loadCountsMeta(db, info.schema, data, info.counts, slow)
+		if err != nil {
+			return Result[T]{Data: []T{}, Meta: meta}, err
+		}
+		if extra != nil {
+			meta["counts"] = extra
+		}
+	}
+	if len(info.ImplicitFields) > 0 {
+		meta["implicitFields"] = info.ImplicitFields
+	}
+	if len(info.Warnings) > 0 {
+		meta["warnings"] = info.Warnings
+	}
+
+	if opts.EchoQuery || (opts.AllowDebugQuery && query.Get("debug") == "1") {
+		meta["query"] = echoQuery(info)
+	}
+	if opts.Debug || (opts.AllowDebugSQL && query.Get("debug") == "sql") {
+		meta["sql"] = captureSQL[T](db, info.Page, info.PageSize)
+	}
+	if cacheKey != "" {
+		storeResult(ctx, opts, cacheKey, data, meta)
+	}
+	if opts.IncludeStats { // setelah storeResult: durasi tidak ikut di-cache
+		meta["stats"] = slow.times.stats(len(data))
+	}
+	return finishList(ctx, Result[T]{Data: data, Meta: meta}, info, opts, start)
+}
+
+// errorMeta: Meta list yang gagal setelah parse — pagination bila count sudah berhasil, selalu map (bukan nil)
+func errorMeta(pagination map[string]interface{}) map[string]interface{} {
+	if pagination == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"pagination": pagination}
+}
+
+// finishList: TransformResult dan AfterQuery, untuk hasil query maupun hasil dari ResultCache
+func finishList[T any](ctx context.Context, res Result[T], info QueryInfo, opts Options, start time.Time) (Result[T], error) {
+	if opts.TransformResult != nil {
+		opts.TransformResult(res.Meta)
+	}
+	if opts.AfterQuery != nil {
+		if err := opts.AfterQuery(ctx, info.Params, &res, time.Since(start)); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
 }
 
 // ReadPaginatedFromGin: wrapper nyaman untuk pemakai Gin.
-// Caller tetap bertanggung jawab mengirim response HTTP.
+// Caller tetap bertanggung jawab mengirim response HTTP; ginrest.ListHandler sudah melakukan semuanya.
 func ReadPaginatedFromGin[T any](ctxQuery url.Values, db *gorm.DB, modelPtr *T, opts Options) (Result[T], error) {
 	return ReadPaginated[T](ctxQuery, db, modelPtr, opts)
 }
 
+// ReadPaginatedInto: ReadPaginated lalu memetakan tiap baris model T ke DTO D lewat mapFn.
+// Query tetap dibangun dari model; Meta dipertahankan apa adanya.
+func ReadPaginatedInto[T any, D any](query url.Values, db *gorm.DB, modelPtr *T, opts Options, mapFn func(T) D) (Result[D], error) {
+	res, err := ReadPaginated[T](query, db, modelPtr, opts)
+	if err != nil {
+		return Result[D]{Data: []D{}, Meta: res.Meta}, err
+	}
+	return MapResult(res, func(item T) (D, error) { return mapFn(item), nil })
+}
+
+// MapResult memetakan Data Result[T] ke Result[D] dengan Meta yang sama (method generic tidak
+// didukung Go, jadi bentuknya fungsi). Error dari fn menghentikan pemetaan dengan index barisnya.
+func MapResult[T any, D any](r Result[T], fn func(T) (D, error)) (Result[D], error) {
+	out := make([]D, 0, len(r.Data))
+	for i, item := range r.Data {
+		d, err := fn(item)
+		if err != nil {
+			return Result[D]{Data: []D{}, Meta: r.Meta}, fmt.Errorf("map result index %d: %w", i, err)
+		}
+		out = append(out, d)
+	}
+	return Result[D]{Data: out, Meta: r.Meta}, nil
+}
+
 // PaginateGeneric: contoh implementasi paginate sederhana.
 // modelPtr: pointer ke slice model tipe T (e.g. &[]models.User{})
 // Mengembalikan []T dan map pagination.
 func PaginateGeneric[T any](db *gorm.DB, modelPtr *T, page, pageSize int) ([]T, map[string]interface{}, error) {
+	return PaginateGenericCtx[T](db.Statement.Context, db, modelPtr, page, pageSize)
+}
+
+// PaginateGenericCtx: PaginateGeneric dengan context untuk query count dan find.
+// Halaman lebih dari HardRowLimit row -> ErrTooManyRows. Query dilaporkan ke DefaultMetrics / DefaultTracer.
+func PaginateGenericCtx[T any](ctx context.Context, db *gorm.DB, modelPtr *T, page, pageSize int) ([]T, map[string]interface{}, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return paginate[T](ctx, db, page, pageSize, HardRowLimit, newSlowQuery(Options{}, QueryParams{}, modelName[T]()))
+}
+
+// paginate: PaginateGenericCtx dengan batas row hard (0 = tanpa batas) dan pengukuran Options.OnSlowQuery
+// (slow nil = tidak diukur)
+func paginate[T any](ctx context.Context, db *gorm.DB, page, pageSize, hard int, slow *slowQuery) ([]T, map[string]interface{}, error) {
+	db = db.WithContext(ctx)
 	// count total
 	var total int64
-	countDB := db.Session(&gorm.Session{}) // copy session
-	if err := countDB.Count(&total).Error; err != nil {
+	if err := slow.run(QueryLabelCount, countQuery(db), func(tx *gorm.DB) *gorm.DB { return tx.Count(&total) }); err != nil {
 		return nil, nil, err
 	}
 
 	// apply limit offset and find
-	offset := (page - 1) * pageSize
-	findDB := db.Limit(pageSize).Offset(offset)
 	out := new([]T)
-	if err := findDB.Find(out).Error; err != nil {
+	q := pageQuery(db, page, pageSize).Limit(cappedLimit(pageSize, hard))
+	if err := slow.run(QueryLabelData, q, func(tx *gorm.DB) *gorm.DB { return tx.Find(out) }); err != nil {
 		return nil, nil, err
 	}
+	if err := checkRowLimit(len(*out), hard); err != nil {
+		return nil, paginationMeta(total, page, pageSize), err
+	}
 
-	// build pagination meta
+	// convert *[]T to []T
+	return *out, paginationMeta(total, page, pageSize), nil
+}
+
+// paginationMeta: Meta["pagination"] dari total row
+func paginationMeta(total int64, page, pageSize int) map[string]interface{} {
 	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
-	pagination := map[string]interface{}{
+	return map[string]interface{}{
 		"page":      page,
 		"pageSize":  pageSize,
 		"pageCount": totalPages,
@@ -253,7 +610,52 @@ func PaginateGeneric[T any](db *gorm.DB, modelPtr *T, page, pageSize int) ([]T,
 		"hasNext":   page < totalPages,
 		"hasPrev":   page > 1 && totalPages > 0,
 	}
+}
 
-	// convert *[]T to []T
-	return *out, pagination, nil
+// countQuery: salinan query untuk menghitung total (dipakai juga oleh capture SQL debug)
+func countQuery(db *gorm.DB) *gorm.DB {
+	if db.Statement.Distinct {
+		// DISTINCT multi kolom: hitung tuple unik lewat subquery agar pageCount benar
+		return db.Session(&gorm.Session{NewDB: true}).Table("(?) AS magicrest_distinct", db.Session(&gorm.Session{}))
+	}
+	return db.Session(&gorm.Session{}) // copy session
+}
+
+// pageQuery: query data satu halaman (limit/offset)
+func pageQuery(db *gorm.DB, page, pageSize int) *gorm.DB {
+	return db.Limit(pageSize).Offset((page - 1) * pageSize)
+}
+
+// splitValues memecah "a, b,c" menjadi []string{"a","b","c"} (spasi di-trim)
+func splitValues(value string) []string {
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// parseTypedValue mengkonversi nilai string sesuai tipe field ("int", "uuid", "bool", "datetime",
+// "date", selain itu string apa adanya).
+func parseTypedValue(fieldType, value string) (interface{}, error) {
+	switch fieldType {
+	case "int":
+		return strconv.Atoi(value)
+	case "bool":
+		return strconv.ParseBool(value)
+	case "datetime":
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02", value)
+	case "date":
+		return time.Parse("2006-01-02", value)
+	case "uuid":
+		if _, err := uuid.Parse(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
 }
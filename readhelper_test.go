@@ -0,0 +1,320 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestReadPaginatedHostileFieldsNeverReachSQL(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+	cases := []struct {
+		name  string
+		query url.Values
+		want  error
+	}{
+		{"filter eq", url.Values{"filter[id) OR 1=1 --]": {"1"}}, ErrInvalidFilter},
+		{"filter in", url.Values{"filter[id) OR 1=1 --]": {"1,2"}}, ErrInvalidFilter},
+		{"filter dotted", url.Values{"filter[Gudang.kode) OR (1=1]": {"GD-01"}}, ErrInvalidFilter},
+		{"preload condition", url.Values{"preload[Items][nama) OR 1=1 --]": {"x"}}, ErrInvalidPreload},
+		{"groupby", url.Values{"groupby": {"status, (select 1) as x --"}}, ErrInvalidField},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			_, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id", AllowGroupBy: true})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if sql := rec.statements(); len(sql) > 0 {
+				t.Fatalf("SQL executed for a rejected request: %v", sql)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedQuotesFilterColumns(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 2)
+	cases := []struct {
+		name  string
+		query url.Values
+		sql   string
+		rows  int
+	}{
+		{"eq", url.Values{"filter[status]": {"aktif"}}, "`status` = \"aktif\"", 2},
+		{"in", url.Values{"filter[status]": {"aktif,selesai"}}, "`status` IN (\"aktif\",\"selesai\")", 4},
+		{"dotted", url.Values{"filter[Gudang.kode]": {"GD-01"}}, "`Gudang`.`kode` = \"GD-01\"", 2},
+		{"preload condition", url.Values{"preload[Items][jumlah]": {"1"}}, "`jumlah` = 1", 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id", AllowGroupBy: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.matching(tc.sql)) == 0 {
+				t.Fatalf("no SQL contains %s:\n%s", tc.sql, strings.Join(rec.statements(), "\n"))
+			}
+			if len(res.Data) != tc.rows {
+				t.Fatalf("rows = %d, want %d", len(res.Data), tc.rows)
+			}
+		})
+	}
+}
+
+func TestBuildQueryQuotesGroupBy(t *testing.T) {
+	db := newTestDB(t)
+	q, _, err := BuildQuery(url.Values{"groupby": {"status,gudang_id"}}, db.Model(&Order{}), &Order{}, Options{AllowGroupBy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := q.Session(&gorm.Session{DryRun: true}).Find(&[]Order{}).Statement
+	sql := stmt.SQL.String()
+	for _, want := range []string{"SELECT `status`, `gudang_id`, MAX(created_at)", "GROUP BY `status`, `gudang_id`"} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("SQL %q does not contain %q", sql, want)
+		}
+	}
+}
+
+func TestReadPaginatedServerOrderBy(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+
+	// Options.OrderBy tidak terkena AllowedOrderFields
+	res, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, Options{OrderBy: "kode desc", AllowedOrderFields: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Data[0].Kode != "ORD-03" {
+		t.Fatalf("first = %s, want ORD-03", res.Data[0].Kode)
+	}
+
+	// tetapi tetap harus berupa identifier
+	rdb, rec := recordSQL(db)
+	_, err = ReadPaginated(url.Values{}, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id; DROP TABLE orders"})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("err = %v, want ErrInvalidConfig", err)
+	}
+	if sql := rec.matching("SELECT * FROM"); len(sql) > 0 {
+		t.Fatalf("data query executed: %v", sql)
+	}
+	if err := (Options{OrderBy: "MAX(id)"}).Validate(&Order{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestReadPaginatedCtxCancel(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+
+	// dibatalkan sebelum mulai: tidak ada query yang berhasil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ReadPaginatedCtx(ctx, url.Values{}, db.Model(&Order{}), &Order{}, Options{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	// dibatalkan di tengah: query ke-n membatalkan context request sebelum dijalankan
+	for _, tc := range []struct {
+		name  string
+		at    int // 1 = count, 2 = find, 3 = preload Items
+		query url.Values
+	}{
+		{"count", 1, url.Values{}},
+		{"find", 2, url.Values{}},
+		{"preload", 3, url.Values{"preload": {"Items"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			n := 0
+			cdb := db.Session(&gorm.Session{NewDB: true})
+			cdb.Callback().Query().Before("gorm:query").Register("test:cancel_"+tc.name, func(tx *gorm.DB) {
+				if n++; n == tc.at {
+					cancel()
+				}
+			})
+			defer cdb.Callback().Query().Remove("test:cancel_" + tc.name)
+			_, err := ReadPaginatedCtx(ctx, tc.query, cdb.Model(&Order{}), &Order{}, Options{})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			if n != tc.at {
+				t.Fatalf("%d queries ran after cancel at query %d", n-tc.at, tc.at)
+			}
+		})
+	}
+}
+
+func TestReadPaginatedScopes(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 10, 0) // gudang 1: ORD-01,03,05,07,09
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", 1) }
+	aktif := func(db *gorm.DB) *gorm.DB { return db.Where("status = ?", "aktif") }
+	cases := []struct {
+		name   string
+		scopes []Scope
+		query  url.Values
+		total  int64
+		kodes  []string
+	}{
+		{"tenant", []Scope{tenant}, url.Values{"pageSize": {"2"}}, 5, []string{"ORD-01", "ORD-03"}},
+		{"second page", []Scope{tenant}, url.Values{"pageSize": {"2"}, "page": {"3"}}, 5, []string{"ORD-09"}},
+		{"filter cannot bypass", []Scope{tenant}, url.Values{"filter[gudang_id]": {"2"}}, 0, []string{}},
+		{"scopes in order", []Scope{tenant, aktif}, url.Values{}, 5, []string{"ORD-01", "ORD-03", "ORD-05", "ORD-07", "ORD-09"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			opts := Options{OrderBy: "kode", Scopes: tc.scopes}
+			res, err := ReadPaginated(tc.query, rdb.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total := res.Meta["pagination"].(map[string]interface{})["total"].(int64); total != tc.total {
+				t.Fatalf("total = %d, want %d", total, tc.total)
+			}
+			kodes := []string{}
+			for _, o := range res.Data {
+				kodes = append(kodes, o.Kode)
+			}
+			if strings.Join(kodes, ",") != strings.Join(tc.kodes, ",") {
+				t.Fatalf("kodes = %v, want %v", kodes, tc.kodes)
+			}
+			// count ikut scope
+			if len(rec.matching("SELECT count(*) FROM `orders` WHERE gudang_id = 1")) != 1 {
+				t.Fatalf("count without scope:\n%s", strings.Join(rec.statements(), "\n"))
+			}
+		})
+	}
+}
+
+// search memakai ILIKE (Postgres), jadi dicek lewat SQL DryRun: scope tetap di depan dan tidak bisa di-OR-kan
+func TestBuildQueryScopesWithSearch(t *testing.T) {
+	db := newTestDB(t)
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", 1) }
+	opts := Options{SearchFields: []string{"kode", "telepon"}, Scopes: []Scope{tenant}}
+	q, _, err := BuildQuery(url.Values{"search": {"ORD"}}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := q.Session(&gorm.Session{DryRun: true}).Find(&[]Order{}).Statement.SQL.String()
+	want := "WHERE gudang_id = ? AND (kode ILIKE ? OR telepon ILIKE ?)"
+	if !strings.Contains(sql, want) {
+		t.Fatalf("SQL %q does not contain %q", sql, want)
+	}
+}
+
+// OrderDTO: bentuk response yang tidak membuka model gorm
+type OrderDTO struct {
+	Kode   string
+	Gudang string
+}
+
+func TestReadPaginatedInto(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	toDTO := func(o Order) OrderDTO { return OrderDTO{Kode: o.Kode, Gudang: o.Gudang.Kode} }
+	res, err := ReadPaginatedInto(url.Values{"pageSize": {"2"}}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id", PreloadFields: []string{"Gudang"}}, toDTO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 2 || res.Data[0] != (OrderDTO{"ORD-01", "GD-01"}) || res.Data[1] != (OrderDTO{"ORD-02", "GD-02"}) {
+		t.Fatalf("data %+v", res.Data)
+	}
+	if p, _ := res.Meta["pagination"].(map[string]interface{}); p["total"] != int64(3) || p["pageCount"] != 2 {
+		t.Fatalf("meta %v", res.Meta)
+	}
+
+	res, err = ReadPaginatedInto(url.Values{"preload": {"Itemz"}}, db.Model(&Order{}), &Order{}, Options{}, toDTO)
+	if !errors.Is(err, ErrInvalidPreload) || res.Data == nil || len(res.Data) != 0 {
+		t.Fatalf("data %v, err %v: want an empty page and ErrInvalidPreload", res.Data, err)
+	}
+}
+
+func TestMapResult(t *testing.T) {
+	src := Result[Order]{Data: []Order{{Kode: "ORD-01"}, {Kode: ""}, {Kode: "ORD-03"}}, Meta: map[string]interface{}{"pagination": "p"}}
+	errKosong := errors.New("kode kosong")
+	calls := 0
+	res, err := MapResult(src, func(o Order) (string, error) {
+		calls++
+		if o.Kode == "" {
+			return "", errKosong
+		}
+		return o.Kode, nil
+	})
+	if !errors.Is(err, errKosong) || !strings.Contains(err.Error(), "index 1") || calls != 2 {
+		t.Fatalf("err = %v after %d calls, want errKosong at index 1", err, calls)
+	}
+	if len(res.Data) != 0 || res.Meta["pagination"] != "p" {
+		t.Fatalf("result on error %+v", res)
+	}
+
+	src.Data[1].Kode = "ORD-02"
+	res, err = MapResult(src, func(o Order) (string, error) { return o.Kode, nil })
+	if err != nil || strings.Join(res.Data, ",") != "ORD-01,ORD-02,ORD-03" || res.Meta["pagination"] != "p" {
+		t.Fatalf("%+v, err %v", res, err)
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 1)
+	rdb, rec := recordSQL(db)
+	query := url.Values{"filter[status]": {"aktif,selesai"}, "preload": {"Items"}, "page": {"2"}, "pageSize": {"3"}}
+	q, info, err := BuildQuery(query, rdb.Model(&Order{}), &Order{}, Options{DefaultFieldTypes: map[string]string{"status": "string"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// builder belum dieksekusi
+	if n := len(rec.statements()); n != 0 {
+		t.Fatalf("%d statements before use: %v", n, rec.statements())
+	}
+	if info.Page != 2 || info.PageSize != 3 || info.Order != "created_at desc" ||
+		fmt.Sprint(info.Filters) != "map[status:[aktif selesai]]" || fmt.Sprint(info.Preloads) != "[Items]" {
+		t.Fatalf("info %+v", info)
+	}
+
+	// builder dipakai ulang dengan filter yang sama
+	var kode []string
+	if err := q.Where("gudang_id = ?", 1).Pluck("kode", &kode).Error; err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(kode) != "[ORD-03 ORD-01]" {
+		t.Fatalf("kode %v", kode)
+	}
+
+	_, _, err = BuildQuery(url.Values{"preload": {"Itemz"}}, db.Model(&Order{}), &Order{}, Options{})
+	if !errors.Is(err, ErrInvalidPreload) {
+		t.Fatalf("err = %v, want ErrInvalidPreload", err)
+	}
+}
+
+// ReadPaginated = BuildQuery + paginate: hasil sama dengan Limit/Offset manual pada builder
+func TestReadPaginatedMatchesBuildQuery(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	query := url.Values{"filter[status]": {"aktif"}, "order": {"kode desc"}, "pageSize": {"2"}, "page": {"1"}}
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, info, err := BuildQuery(query, db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manual []Order
+	q.Offset((info.Page - 1) * info.PageSize).Limit(info.PageSize).Find(&manual)
+	if len(res.Data) != 2 || len(manual) != 2 || res.Data[0].Kode != manual[0].Kode || res.Data[1].Kode != manual[1].Kode || res.Data[0].Kode != "ORD-05" {
+		t.Fatalf("ReadPaginated %v, BuildQuery %v", res.Data, manual)
+	}
+}
@@ -0,0 +1,182 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidID digunakan bila id tidak sesuai tipe primary key (mis. bukan UUID untuk PK uuid)
+var ErrInvalidID = errors.New("invalid id")
+
+// ErrMultipleResults digunakan ReadFirst dengan Options.RequireUnique bila lebih dari satu row cocok
+var ErrMultipleResults = errors.New("multiple results")
+
+// ReadOne mengambil satu row berdasarkan primary key. id divalidasi terhadap tipe PK (uuid / int,
+// ErrInvalidID), preload memakai Options.PreloadFields dan ?preload= dari query (whitelist tetap berlaku),
+// Scopes, masking dan TransformItem sama seperti list. Row tidak ada -> ErrNotFound (404).
+func ReadOne[T any](ctx context.Context, query url.Values, db *gorm.DB, id any, opts Options) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpReadOne)
+	defer func() { op.end(-1, err) }()
+	db = readDB(ctx, db, FromURLValues(query), opts)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, fmt.Errorf("model %s has no single primary key", sch.Name)
+	}
+	if id, err = parseID(pk, id); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprint(id)
+	out, err = readSingle[T](ctx, query, db.Where(db.Statement.Quote(sch.Table+"."+pk.DBName)+" = ?", id), sch, opts, key)
+	newReadAudit[T](opts, OpReadOne, start, QueryInfo{}, key).done(ctx, 1, err)
+	return out, err
+}
+
+// parseID memvalidasi id string terhadap tipe primary key ("uuid" / "int"); nilai non-string diteruskan
+func parseID(pk *schema.Field, id any) (any, error) {
+	s, ok := id.(string)
+	if !ok {
+		return id, nil
+	}
+	typ := schemaFieldType(pk)
+	if typ != "uuid" && typ != "int" {
+		return s, nil
+	}
+	v, err := parseTypedValue(typ, s)
+	if err != nil {
+		return nil, &QueryError{Kind: ErrInvalidID, Param: "id", Value: s, Reason: "is not a valid " + typ, Code: "invalid_" + typ}
+	}
+	return v, nil
+}
+
+// readSingle menerapkan Scopes dan preload ke db (sudah berisi kondisi), mengambil row pertama lalu
+// menjalankan masking dan TransformItem. key hanya untuk pesan ErrNotFound.
+func readSingle[T any](ctx context.Context, query url.Values, db *gorm.DB, sch *schema.Schema, opts Options, key string) (*T, error) {
+	for _, scope := range opts.Scopes {
+		db = scope(db)
+	}
+	db, _, _, err := applyPreloads(db, new(T), FromURLValues(query), opts)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0, 1)
+	if err := withSQLComment(db, opts, modelName[T]()).Limit(1).Find(&out).Error; err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, sch.Name, key)
+	}
+	applyMasks(ctx, out, opts)
+	if opts.TransformItem != nil {
+		opts.TransformItem(0, &out[0])
+	}
+	return &out[0], nil
+}
+
+// ReadFirst menjalankan pipeline filter/search/order yang sama dengan ReadPaginated dan mengembalikan row
+// pertama (ErrNotFound bila kosong), untuk endpoint "get by business key" seperti ?filter[sku]=X.
+// Dengan Options.RequireUnique diambil dua row dan lebih dari satu menghasilkan ErrMultipleResults.
+func ReadFirst[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options) (res *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpReadOne)
+	defer func() { op.end(-1, err) }()
+	q, info, err := BuildQuery(query, readDB(ctx, db, FromURLValues(query), opts), modelPtr, opts)
+	if err != nil {
+		return nil, err
+	}
+	limit := 1
+	if opts.RequireUnique {
+		limit = 2
+	}
+	out := make([]T, 0, limit)
+	if err := q.Limit(limit).Find(&out).Error; err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: no row matches the query", ErrNotFound)
+	}
+	if len(out) > 1 {
+		return nil, fmt.Errorf("%w: more than one row matches the query", ErrMultipleResults)
+	}
+	applyMasks(ctx, out, opts)
+	if opts.TransformItem != nil {
+		opts.TransformItem(0, &out[0])
+	}
+	newReadAudit[T](opts, OpReadOne, start, info, "").done(ctx, 1, nil)
+	return &out[0], nil
+}
+
+// ReadOneBy mengambil satu row berdasarkan kolom unik selain primary key (slug, kode). field divalidasi
+// terhadap schema (ErrInvalidField) dan value di-parse sesuai tipenya (Options.DefaultFieldTypes atau tipe
+// dari schema, ErrInvalidID bila tidak cocok). Preload Options.PreloadFields; tidak ada -> ErrNotFound.
+func ReadOneBy[T any](ctx context.Context, db *gorm.DB, field string, value string, opts Options) (*T, error) {
+	return ReadOneByKeys[T](ctx, db, map[string]string{field: value}, opts)
+}
+
+// ReadOneByKeys: ReadOneBy untuk natural key komposit, e.g. {"gudang_id": g, "kode": "BRS"}.
+func ReadOneByKeys[T any](ctx context.Context, db *gorm.DB, keys map[string]string, opts Options) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpReadOne)
+	defer func() { op.end(-1, err) }()
+	db = readDB(ctx, db, nil, opts)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no key given", ErrInvalidField)
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := fieldTypes(opts)
+	var desc, audited []string // audited: desc tanpa nilai kolom di AuditRedactFields
+	for _, name := range names {
+		f := sch.LookUpField(name)
+		if !isColumnField(f) {
+			return nil, newQueryError(ErrInvalidField, name, "", "is not a field of "+sch.Name)
+		}
+		typ := types[f.DBName]
+		if typ == "" {
+			typ = schemaFieldType(f)
+		}
+		v, err := parseTypedValue(typ, keys[name])
+		if err != nil {
+			return nil, &QueryError{Kind: ErrInvalidID, Param: name, Value: keys[name], Reason: "is not a valid " + typ, Code: "invalid_" + typ}
+		}
+		db = db.Where(db.Statement.Quote(sch.Table+"."+f.DBName)+" = ?", v)
+		desc = append(desc, f.DBName+"="+keys[name])
+		if containsString(opts.AuditRedactFields, f.DBName) {
+			audited = append(audited, f.DBName+"=[redacted]")
+		} else {
+			audited = append(audited, desc[len(desc)-1])
+		}
+	}
+	out, err = readSingle[T](ctx, nil, db, sch, opts, strings.Join(desc, ","))
+	newReadAudit[T](opts, OpReadOne, start, QueryInfo{}, strings.Join(audited, ",")).done(ctx, 1, err)
+	return out, err
+}
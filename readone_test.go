@@ -0,0 +1,195 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Pelanggan: primary key uuid
+type Pelanggan struct {
+	ID   uuid.UUID `json:"id" gorm:"type:text;primaryKey"`
+	Nama string    `json:"nama"`
+}
+
+var pelangganID = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+// newReadOneDB: tiga order (PK int, dua item per order) dan dua pelanggan (PK uuid)
+func newReadOneDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Pelanggan{}); err != nil {
+		t.Fatal(err)
+	}
+	seedOrders(t, db, 3, 2)
+	db.Create(&[]Pelanggan{{ID: pelangganID, Nama: "Andi"}, {ID: uuid.New(), Nama: "Rina"}})
+	return db
+}
+
+func TestReadOne(t *testing.T) {
+	order := func(db *gorm.DB, id any) (string, error) {
+		o, err := ReadOne[Order](context.Background(), nil, db, id, Options{})
+		if err != nil {
+			return "", err
+		}
+		return o.Kode, nil
+	}
+	pelanggan := func(db *gorm.DB, id any) (string, error) {
+		p, err := ReadOne[Pelanggan](context.Background(), nil, db, id, Options{})
+		if err != nil {
+			return "", err
+		}
+		return p.Nama, nil
+	}
+	cases := []struct {
+		name string
+		read func(db *gorm.DB, id any) (string, error)
+		id   any
+		want string // kode order / nama pelanggan
+		err  error
+	}{
+		{"int pk", order, "2", "ORD-02", nil},
+		{"int pk typed", order, uint(3), "ORD-03", nil},
+		{"int pk not found", order, "99", "", ErrNotFound},
+		{"int pk invalid", order, "abc", "", ErrInvalidID},
+		{"uuid pk", pelanggan, pelangganID.String(), "Andi", nil},
+		{"uuid pk typed", pelanggan, pelangganID, "Andi", nil},
+		{"uuid pk not found", pelanggan, uuid.NewString(), "", ErrNotFound},
+		{"uuid pk invalid", pelanggan, "12", "", ErrInvalidID},
+	}
+	db := newReadOneDB(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			got, err := tc.read(rdb, tc.id)
+			if !errors.Is(err, tc.err) || got != tc.want {
+				t.Fatalf("got %q, err %v; want %q, %v", got, err, tc.want, tc.err)
+			}
+			switch {
+			case errors.Is(err, ErrInvalidID):
+				var qe *QueryError
+				if !errors.As(err, &qe) || qe.Param != "id" || StatusForError(err) != 400 || len(rec.statements()) > 0 {
+					t.Fatalf("err %v, SQL %v: want a 400 QueryError for id before any query", err, rec.statements())
+				}
+			case errors.Is(err, ErrNotFound):
+				if StatusForError(err) != 404 {
+					t.Fatalf("status %d for %v", StatusForError(err), err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadOnePreloadScopesAndMasks(t *testing.T) {
+	db := newReadOneDB(t)
+	opts := Options{
+		AllowedPreloads: []string{"Items"},
+		Scopes:          []Scope{func(db *gorm.DB) *gorm.DB { return db.Where("status = ?", "aktif") }},
+		MaskRole:        func(context.Context) string { return "kasir" },
+		MaskedColumns:   map[string][]string{"kasir": {"telepon"}},
+	}
+	o, err := ReadOne[Order](context.Background(), url.Values{"preload": {"Items"}}, db, "1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Items) != 2 || o.Telepon != "" {
+		t.Fatalf("order %+v: want two items and a masked telepon", o)
+	}
+	// ORD-02 berstatus selesai: di luar Scopes sama dengan tidak ada
+	if _, err := ReadOne[Order](context.Background(), nil, db, "2", opts); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound outside Scopes", err)
+	}
+	if _, err := ReadOne[Order](context.Background(), url.Values{"preload": {"Gudang"}}, db, "1", opts); !errors.Is(err, ErrPreloadNotAllowed) {
+		t.Fatalf("err = %v, want ErrPreloadNotAllowed", err)
+	}
+}
+
+func TestReadFirst(t *testing.T) {
+	db := newReadOneDB(t)
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  string // kode, kosong bila error
+		err   error
+	}{
+		{"business key", url.Values{"filter[kode]": {"ORD-02"}}, Options{}, "ORD-02", nil},
+		{"first by order", url.Values{"filter[status]": {"aktif"}, "order": {"kode desc"}}, Options{}, "ORD-03", nil},
+		{"unique match", url.Values{"filter[kode]": {"ORD-02"}}, Options{RequireUnique: true}, "ORD-02", nil},
+		{"not unique", url.Values{"filter[status]": {"aktif"}}, Options{RequireUnique: true}, "", ErrMultipleResults},
+		{"no match", url.Values{"filter[kode]": {"ORD-99"}}, Options{}, "", ErrNotFound},
+		{"invalid query", url.Values{"preload": {"Itemz"}}, Options{}, "", ErrInvalidPreload},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.PreloadFields = []string{"Items"}
+			o, err := ReadFirst(context.Background(), tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				if o != nil {
+					t.Fatalf("row %+v returned with error", o)
+				}
+				return
+			}
+			if o.Kode != tc.want || len(o.Items) != 2 {
+				t.Fatalf("got %s with %d items, want %s", o.Kode, len(o.Items), tc.want)
+			}
+		})
+	}
+	if _, err := ReadFirst(context.Background(), url.Values{"filter[status]": {"aktif"}}, db.Model(&Order{}), &Order{}, Options{RequireUnique: true}); StatusForError(err) != 409 {
+		t.Fatalf("ErrMultipleResults status %d, want 409", StatusForError(err))
+	}
+
+	o, err := ReadFirst(context.Background(), url.Values{"filter[kode]": {"ORD-01"}}, db.Model(&Order{}), &Order{},
+		Options{TransformItem: func(i int, item any) { item.(*Order).Kode += "*" }})
+	if err != nil || o.Kode != "ORD-01*" {
+		t.Fatalf("TransformItem: %+v, err %v", o, err)
+	}
+}
+
+func TestReadOneBy(t *testing.T) {
+	db := newReadOneDB(t)
+	opts := Options{PreloadFields: []string{"Gudang"}}
+	o, err := ReadOneBy[Order](context.Background(), db, "kode", "ORD-02", opts)
+	if err != nil || o.Kode != "ORD-02" || o.Gudang == nil || o.Gudang.Kode != "GD-02" {
+		t.Fatalf("by kode: %+v, err %v", o, err)
+	}
+	// natural key komposit, nama field Go atau kolom, nilai di-parse sesuai tipe kolom
+	o, err = ReadOneByKeys[Order](context.Background(), db, map[string]string{"GudangID": "1", "status": "aktif", "kode": "ORD-03"}, opts)
+	if err != nil || o.Kode != "ORD-03" {
+		t.Fatalf("by keys: %+v, err %v", o, err)
+	}
+	if p, err := ReadOneBy[Pelanggan](context.Background(), db, "id", pelangganID.String(), Options{}); err != nil || p.Nama != "Andi" {
+		t.Fatalf("by uuid: %+v, err %v", p, err)
+	}
+
+	cases := []struct {
+		name   string
+		keys   map[string]string
+		want   error
+		status int
+	}{
+		{"unknown field", map[string]string{"alamat": "x"}, ErrInvalidField, 400},
+		{"items relation", map[string]string{"Items": "1"}, ErrInvalidField, 400},
+		{"invalid int", map[string]string{"gudang_id": "satu"}, ErrInvalidID, 400},
+		{"no keys", map[string]string{}, ErrInvalidField, 400},
+		{"no match", map[string]string{"kode": "ORD-01", "gudang_id": "2"}, ErrNotFound, 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadOneByKeys[Order](context.Background(), db, tc.keys, opts)
+			if !errors.Is(err, tc.want) || StatusForError(err) != tc.status {
+				t.Fatalf("err = %v (%d), want %v (%d)", err, StatusForError(err), tc.want, tc.status)
+			}
+		})
+	}
+	if _, err := ReadOneBy[Pelanggan](context.Background(), db, "id", "bukan-uuid", Options{}); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("invalid uuid: err = %v, want ErrInvalidID", err)
+	}
+}
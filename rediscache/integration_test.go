@@ -0,0 +1,54 @@
+//go:build integration
+
+package rediscache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestClusterInvalidatePrefix butuh Redis Cluster sungguhan (CROSSSLOT tidak ditiru miniredis):
+//
+//	REDIS_CLUSTER_ADDRS=127.0.0.1:7000,127.0.0.1:7001,127.0.0.1:7002 go test -tags integration ./...
+func TestClusterInvalidatePrefix(t *testing.T) {
+	addrs := os.Getenv("REDIS_CLUSTER_ADDRS")
+	if addrs == "" {
+		t.Skip("REDIS_CLUSTER_ADDRS not set")
+	}
+	ctx := context.Background()
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: strings.Split(addrs, ",")})
+	defer client.Close()
+	namespace := fmt.Sprintf("magicrest-it-%d:", time.Now().UnixNano())
+	cache := New(client, Config{Namespace: namespace})
+
+	// cukup banyak key agar tersebar ke semua master dan ke banyak slot per master
+	var orders, gudang []string
+	for i := 0; i < 200; i++ {
+		orders = append(orders, fmt.Sprintf("app.Order:%03d", i))
+		gudang = append(gudang, fmt.Sprintf("app.Gudang:%03d", i))
+	}
+	for _, key := range append(orders, gudang...) {
+		cache.Set(ctx, key, []byte("x"), time.Minute)
+	}
+	defer cache.InvalidatePrefix(ctx, "")
+
+	if err := cache.InvalidatePrefix(ctx, "app.Order:"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range orders {
+		if _, ok := cache.Get(ctx, key); ok {
+			t.Fatalf("%s survived", key)
+		}
+	}
+	for _, key := range gudang {
+		if _, ok := cache.Get(ctx, key); !ok {
+			t.Fatalf("%s was deleted", key)
+		}
+	}
+}
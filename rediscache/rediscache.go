@@ -0,0 +1,139 @@
+// Package rediscache: magicrest.ResultCache di Redis (go-redis v9). Modul terpisah (go.mod sendiri) agar
+// go-redis dan msgpack tidak ikut ke dependency graph pemakai lain. Key = Namespace + key magicrest
+// ("<package path>.<Model>:..."), jadi InvalidatePrefix(CacheKeyPrefix[T]()) hanya menyentuh entry satu resource.
+package rediscache
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/redis/go-redis/v9"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// defaultNamespace: awalan key bila Config.Namespace kosong
+const defaultNamespace = "magicrest:"
+
+// scanCount: petunjuk COUNT untuk SCAN (key per pipeline DEL) di InvalidatePrefix
+const scanCount = 500
+
+// Config: pengaturan Cache
+type Config struct {
+	Namespace string               // awalan semua key (default "magicrest:"), e.g. "app1:list:"
+	Codec     magicrest.CacheCodec // serialisasi entry (default magicrest.GobCodec, atau MsgpackCodec)
+}
+
+// Cache: magicrest.ResultCache, PrefixInvalidator dan CodecCache di atas redis.UniversalClient
+// (*redis.Client, *redis.ClusterClient, *redis.Ring)
+type Cache struct {
+	client    redis.UniversalClient
+	namespace string
+	codec     magicrest.CacheCodec
+}
+
+// New: Cache di atas client dengan cfg
+func New(client redis.UniversalClient, cfg Config) *Cache {
+	if cfg.Namespace == "" {
+		cfg.Namespace = defaultNamespace
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = magicrest.GobCodec
+	}
+	return &Cache{client: client, namespace: cfg.Namespace, codec: cfg.Codec}
+}
+
+// Get: GET <namespace><key>. Key tidak ada atau Redis error = miss (query berjalan seperti tanpa cache).
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	val, err := c.client.Get(ctx, c.namespace+key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// Set: SET <namespace><key> dengan TTL. Error Redis diabaikan — entry hanya tidak tersimpan.
+func (c *Cache) Set(ctx context.Context, key string, val []byte, ttl time.Duration) {
+	c.client.Set(ctx, c.namespace+key, val, ttl)
+}
+
+// Codec: codec entry (magicrest.CodecCache)
+func (c *Cache) Codec() magicrest.CacheCodec {
+	return c.codec
+}
+
+// InvalidatePrefix menghapus semua key <namespace><prefix>* lewat SCAN + DEL (bukan KEYS, agar Redis tidak
+// terblokir). SCAN hanya melihat key satu node, jadi di cluster setiap master dan di Ring setiap shard di-scan
+// sendiri-sendiri.
+func (c *Cache) InvalidatePrefix(ctx context.Context, prefix string) error {
+	pattern := escapePattern(c.namespace+prefix) + "*"
+	switch client := c.client.(type) {
+	case *redis.ClusterClient:
+		return client.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return deleteMatching(ctx, node, pattern)
+		})
+	case *redis.Ring:
+		return client.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+			return deleteMatching(ctx, shard, pattern)
+		})
+	}
+	return deleteMatching(ctx, c.client, pattern)
+}
+
+// deleteMatching: SCAN MATCH pattern lalu DEL per key dalam satu pipeline per halaman scan. DEL banyak key
+// sekaligus ditolak cluster (CROSSSLOT) bila key-nya berada di slot berbeda, walau di node yang sama.
+func deleteMatching(ctx context.Context, client redis.Cmdable, pattern string) error {
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.Del(ctx, key)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// escapePattern meng-escape karakter glob Redis (* ? [ ] \) agar prefix dicocokkan apa adanya
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MsgpackCodec: magicrest.CacheCodec msgpack — entry lebih kecil dari JSON. Field dinamai menurut nama Go
+// (bukan tag json) agar field `json:"-"` seperti DeletedAt tetap tersimpan.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	return dec.Decode(v)
+}
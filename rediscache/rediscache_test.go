@@ -0,0 +1,191 @@
+package rediscache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// delRecorder: hook go-redis yang mencatat jumlah key setiap DEL
+type delRecorder struct {
+	mu   sync.Mutex
+	dels []int
+}
+
+func (r *delRecorder) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (r *delRecorder) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		r.record(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+func (r *delRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			r.record(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func (r *delRecorder) record(cmd redis.Cmder) {
+	if cmd.Name() == "del" {
+		r.mu.Lock()
+		r.dels = append(r.dels, len(cmd.Args())-1)
+		r.mu.Unlock()
+	}
+}
+
+// multiKeyDels: jumlah DEL dengan lebih dari satu key (CROSSSLOT di cluster)
+func (r *delRecorder) multiKeyDels() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, keys := range r.dels {
+		if keys > 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestInvalidatePrefix(t *testing.T) {
+	cases := []struct {
+		name    string
+		servers int
+		client  func(addrs []string, rec *delRecorder) redis.UniversalClient
+	}{
+		{"client", 1, func(addrs []string, rec *delRecorder) redis.UniversalClient {
+			c := redis.NewClient(&redis.Options{Addr: addrs[0]})
+			c.AddHook(rec)
+			return c
+		}},
+		// miniredis menjawab CLUSTER SLOTS sebagai cluster satu node yang memegang semua slot
+		{"cluster", 1, func(addrs []string, rec *delRecorder) redis.UniversalClient {
+			return redis.NewClusterClient(&redis.ClusterOptions{Addrs: addrs, NewClient: func(opt *redis.Options) *redis.Client {
+				c := redis.NewClient(opt)
+				c.AddHook(rec)
+				return c
+			}})
+		}},
+		{"ring", 2, func(addrs []string, rec *delRecorder) redis.UniversalClient {
+			shards := map[string]string{}
+			for i, addr := range addrs {
+				shards[fmt.Sprintf("shard%d", i)] = addr
+			}
+			return redis.NewRing(&redis.RingOptions{Addrs: shards, NewClient: func(opt *redis.Options) *redis.Client {
+				c := redis.NewClient(opt)
+				c.AddHook(rec)
+				return c
+			}})
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var servers []*miniredis.Miniredis
+			var addrs []string
+			for i := 0; i < tc.servers; i++ {
+				s := miniredis.RunT(t)
+				servers = append(servers, s)
+				addrs = append(addrs, s.Addr())
+			}
+			rec := &delRecorder{}
+			client := tc.client(addrs, rec)
+			defer client.Close()
+			cache := New(client, Config{Namespace: "toko:"})
+			for i := 0; i < 40; i++ {
+				cache.Set(ctx, fmt.Sprintf("app.Order:%02d", i), []byte("x"), time.Minute)
+				cache.Set(ctx, fmt.Sprintf("app.Gudang:%02d", i), []byte("x"), time.Minute)
+			}
+			for _, s := range servers {
+				if len(s.Keys()) == 0 {
+					t.Fatalf("server %s has no keys: test does not cover every shard", s.Addr())
+				}
+			}
+
+			if err := cache.InvalidatePrefix(ctx, "app.Order:"); err != nil {
+				t.Fatal(err)
+			}
+			gudang := 0
+			for _, s := range servers {
+				for _, key := range s.Keys() {
+					if strings.HasPrefix(key, "toko:app.Order:") {
+						t.Fatalf("%s survived on %s", key, s.Addr())
+					}
+					gudang++
+				}
+			}
+			if gudang != 40 {
+				t.Fatalf("%d Gudang keys left, want 40", gudang)
+			}
+			if n := rec.multiKeyDels(); n > 0 {
+				t.Fatalf("%d multi-key DEL commands (CROSSSLOT in a cluster)", n)
+			}
+		})
+	}
+}
+
+func TestCacheGetSet(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	cache := New(client, Config{})
+	cache.Set(ctx, "app.Order:1", []byte("isi"), time.Second)
+	if !s.Exists("magicrest:app.Order:1") {
+		t.Fatalf("keys %v: want default namespace", s.Keys())
+	}
+	if val, ok := cache.Get(ctx, "app.Order:1"); !ok || string(val) != "isi" {
+		t.Fatalf("Get = %q, %v", val, ok)
+	}
+	s.FastForward(2 * time.Second)
+	if _, ok := cache.Get(ctx, "app.Order:1"); ok {
+		t.Fatal("expired entry returned")
+	}
+
+	// Redis mati = miss, bukan error
+	s.Close()
+	if _, ok := cache.Get(ctx, "app.Order:2"); ok {
+		t.Fatal("hit from a closed server")
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	if got, want := escapePattern(`app.Item[T]:*?\`), `app.Item\[T\]:\*\?\\`; got != want {
+		t.Fatalf("escapePattern = %q, want %q", got, want)
+	}
+}
+
+func TestCodecsKeepHiddenFields(t *testing.T) {
+	type akun struct {
+		Nama  string `json:"nama"`
+		Sandi string `json:"-"`
+	}
+	codecs := map[string]magicrest.CacheCodec{
+		"default": New(redis.NewClient(&redis.Options{}), Config{}).Codec(), // client tidak pernah terhubung
+		"msgpack": MsgpackCodec{},
+	}
+	for name, codec := range codecs {
+		raw, err := codec.Marshal([]akun{{Nama: "budi", Sandi: "rahasia"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []akun
+		if err := codec.Unmarshal(raw, &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != 1 || out[0].Sandi != "rahasia" {
+			t.Fatalf("%s: %+v", name, out)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateResource digunakan bila nama resource sudah terdaftar di Registry
+var ErrDuplicateResource = errors.New("resource already registered")
+
+// ErrUnknownResource digunakan bila nama resource tidak ada di Registry
+var ErrUnknownResource = errors.New("unknown resource")
+
+// Resource: satu resource yang terdaftar (model + Options)
+type Resource struct {
+	Name    string
+	Model   any          // pointer ke model, e.g. &Barang{}
+	Type    reflect.Type // tipe model (bukan pointer)
+	Options Options
+}
+
+// Registry menyimpan konfigurasi resource agar cukup didefinisikan sekali lalu dipakai dari handler,
+// export dan endpoint deskripsi schema. Aman dibaca bersamaan saat request.
+type Registry struct {
+	mu        sync.RWMutex
+	resources map[string]Resource
+}
+
+// NewRegistry membuat Registry kosong.
+func NewRegistry() *Registry {
+	return &Registry{resources: map[string]Resource{}}
+}
+
+// Register mendaftarkan model T dengan nama tertentu. Nama yang sama dua kali menghasilkan ErrDuplicateResource.
+// (fungsi, bukan method, karena method Go tidak bisa punya type parameter)
+func Register[T any](r *Registry, name string, opts Options) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.resources == nil {
+		r.resources = map[string]Resource{}
+	}
+	if _, ok := r.resources[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateResource, name)
+	}
+	r.resources[name] = Resource{
+		Name:    name,
+		Model:   new(T),
+		Type:    reflect.TypeOf((*T)(nil)).Elem(),
+		Options: opts,
+	}
+	return nil
+}
+
+// Lookup mengambil resource berdasarkan nama.
+func (r *Registry) Lookup(name string) (Resource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res, ok := r.resources[name]
+	return res, ok
+}
+
+// Names mengembalikan semua nama resource (urut alfabet).
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.resources))
+	for name := range r.resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OptionsFor mengambil Options resource T dari registry, memastikan tipe modelnya cocok.
+func OptionsFor[T any](r *Registry, name string) (Options, error) {
+	res, ok := r.Lookup(name)
+	if !ok {
+		return Options{}, fmt.Errorf("%w: %s", ErrUnknownResource, name)
+	}
+	if want := reflect.TypeOf((*T)(nil)).Elem(); res.Type != want {
+		return Options{}, fmt.Errorf("%w: %s is registered for %s, not %s", ErrUnknownResource, name, res.Type, want)
+	}
+	return res.Options, nil
+}
@@ -0,0 +1,76 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if err := Register[Order](r, "orders", Options{OrderBy: "id"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register[Gudang](r, "gudang", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register[Item](r, "orders", Options{}); !errors.Is(err, ErrDuplicateResource) {
+		t.Fatalf("duplicate: err = %v, want ErrDuplicateResource", err)
+	}
+
+	res, ok := r.Lookup("orders")
+	if !ok || res.Name != "orders" || res.Type != reflect.TypeOf(Order{}) || res.Options.OrderBy != "id" {
+		t.Fatalf("Lookup = %+v, %v", res, ok)
+	}
+	if _, ok := res.Model.(*Order); !ok {
+		t.Fatalf("Model %T, want *Order", res.Model)
+	}
+	if _, ok := r.Lookup("items"); ok {
+		t.Fatal("Lookup of unregistered name")
+	}
+	if got := fmt.Sprint(r.Names()); got != "[gudang orders]" {
+		t.Fatalf("Names = %s", got)
+	}
+
+	if opts, err := OptionsFor[Order](r, "orders"); err != nil || opts.OrderBy != "id" {
+		t.Fatalf("OptionsFor = %+v, %v", opts, err)
+	}
+	if _, err := OptionsFor[Item](r, "orders"); !errors.Is(err, ErrUnknownResource) {
+		t.Fatalf("wrong model: err = %v, want ErrUnknownResource", err)
+	}
+	if _, err := OptionsFor[Item](r, "items"); !errors.Is(err, ErrUnknownResource) {
+		t.Fatalf("unknown name: err = %v, want ErrUnknownResource", err)
+	}
+
+	// Registry zero value juga bisa dipakai
+	var zero Registry
+	if err := Register[Order](&zero, "orders", Options{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// jalankan dengan -race: Lookup bersamaan dengan Register
+func TestRegistryConcurrentLookup(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := Register[Order](r, fmt.Sprintf("orders-%d", i), Options{}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			r.Lookup("orders-0")
+			r.Names()
+		}()
+	}
+	wg.Wait()
+	if len(r.Names()) != 8 {
+		t.Fatalf("Names = %v", r.Names())
+	}
+}
@@ -0,0 +1,33 @@
+package magicrest
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ReadDBFunc: koneksi baca per request (Options.ReadDBResolver), e.g. memilih replica per region / round robin.
+// nil = Options.ReadDB atau db yang dikirim.
+type ReadDBFunc func(ctx context.Context) *gorm.DB
+
+// readDB: db untuk query baca — kondisi, model dan Scopes dari db yang dikirim tetap dipakai, hanya koneksinya
+// (ConnPool) diganti replica dari Options.ReadDBResolver / ReadDB. Satu pilihan per request, jadi count dan data
+// membaca replica yang sama. db tetap dipakai bila tidak ada replica, db sedang dalam transaksi, atau
+// ?consistency=strong dengan Options.AllowStrongReads (read-your-writes setelah write ke primary).
+func readDB(ctx context.Context, db *gorm.DB, query QuerySource, opts Options) *gorm.DB {
+	db = db.WithContext(ctx)
+	replica := opts.ReadDB
+	if opts.ReadDBResolver != nil {
+		if r := opts.ReadDBResolver(ctx); r != nil {
+			replica = r
+		}
+	}
+	if replica == nil || (opts.AllowStrongReads && query != nil && query.Get("consistency") == "strong") {
+		return db
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return db
+	}
+	db.Statement.ConnPool = replica.Statement.ConnPool
+	return db
+}
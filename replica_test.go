@@ -0,0 +1,86 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// replicaDBs: primary dengan 3 order dan replica yang tertinggal (2 order)
+func replicaDBs(t *testing.T) (primary, replica *gorm.DB) {
+	primary, replica = newTestDB(t), newTestDB(t)
+	seedOrders(t, primary, 3, 0)
+	seedOrders(t, replica, 2, 0)
+	return primary, replica
+}
+
+func TestReadDBRouting(t *testing.T) {
+	primary, replica := replicaDBs(t)
+	strong := url.Values{"consistency": {"strong"}}
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		want  int64 // total = jumlah row: count dan data dari koneksi yang sama
+	}{
+		{"no replica", url.Values{}, Options{}, 3},
+		{"ReadDB", url.Values{}, Options{ReadDB: replica}, 2},
+		{"resolver wins", url.Values{}, Options{ReadDB: primary, ReadDBResolver: func(context.Context) *gorm.DB { return replica }}, 2},
+		{"resolver nil falls back to ReadDB", url.Values{}, Options{ReadDB: replica, ReadDBResolver: func(context.Context) *gorm.DB { return nil }}, 2},
+		{"strong read", strong, Options{ReadDB: replica, AllowStrongReads: true}, 3},
+		{"strong read not allowed", strong, Options{ReadDB: replica}, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginatedCtx(context.Background(), tc.query, primary.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := res.Meta["pagination"].(map[string]interface{})
+			if p["total"] != tc.want || int64(len(res.Data)) != tc.want {
+				t.Fatalf("total %v, %d rows; want %d", p["total"], len(res.Data), tc.want)
+			}
+			meta, err := CountList[Order](context.Background(), FromURLValues(tc.query), primary.Model(&Order{}), tc.opts)
+			if err != nil || meta["total"] != tc.want {
+				t.Fatalf("CountList %v, err %v", meta, err)
+			}
+		})
+	}
+}
+
+func TestReadDBSingleRowAndBatches(t *testing.T) {
+	primary, replica := replicaDBs(t)
+	opts := Options{ReadDB: replica, AllowStrongReads: true}
+	if _, err := ReadOne[Order](context.Background(), url.Values{}, primary, 3, opts); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ReadOne from replica: err = %v, want ErrNotFound", err)
+	}
+	if o, err := ReadOne[Order](context.Background(), url.Values{"consistency": {"strong"}}, primary, 3, opts); err != nil || o.Kode != "ORD-03" {
+		t.Fatalf("strong ReadOne: %+v, err %v", o, err)
+	}
+	n, err := ReadAll(context.Background(), url.Values{}, primary, &Order{}, opts, 10, func([]Order) error { return nil })
+	if err != nil || n != 2 {
+		t.Fatalf("ReadAll: n = %d, err %v", n, err)
+	}
+}
+
+// di dalam transaksi caller semua baca tetap di transaksi itu (read-your-writes)
+func TestReadDBInsideTransaction(t *testing.T) {
+	primary, replica := replicaDBs(t)
+	err := primary.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&Order{Kode: "TX-1"}).Error; err != nil {
+			return err
+		}
+		res, err := ReadPaginated(url.Values{}, tx.Model(&Order{}), &Order{}, Options{OrderBy: "id", ReadDB: replica})
+		if err != nil || len(res.Data) != 4 {
+			t.Fatalf("%d rows, err %v; want the transaction's 4", len(res.Data), err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
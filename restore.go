@@ -0,0 +1,76 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotDeleted digunakan RestoreGeneric bila row ada tetapi tidak sedang di-soft delete
+var ErrNotDeleted = errors.New("record is not deleted")
+
+// RestoreGeneric mengembalikan row id yang di-soft delete (deleted_at = NULL) lalu mengembalikannya dengan
+// WriteOptions.PreloadFields. Row tidak ada (termasuk di luar Scopes) -> ErrNotFound, row tidak
+// sedang terhapus -> ErrNotDeleted. Model tanpa gorm.DeletedAt menghasilkan ErrInvalidConfig.
+func RestoreGeneric[T any](ctx context.Context, db *gorm.DB, id any, opts WriteOptions) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpRestore)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	sd := softDeleteField(sch)
+	if sd == nil {
+		return nil, fmt.Errorf("%w: model %s has no gorm.DeletedAt field", ErrInvalidConfig, sch.Name)
+	}
+	row, err := findByPK[T](db.Unscoped(), sch, id, opts.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	deletedAt, _ := sd.ValueOf(ctx, reflect.ValueOf(row).Elem())
+	if da, ok := deletedAt.(gorm.DeletedAt); !ok || !da.Valid {
+		return nil, fmt.Errorf("%w: %s %v", ErrNotDeleted, sch.Name, id)
+	}
+	if err := db.Unscoped().Model(row).Update(sd.DBName, nil).Error; err != nil {
+		return nil, err
+	}
+	invalidateItems(ctx, db, opts, []T{*row})
+	return refetch(db, row, opts)
+}
+
+// BulkRestoreByQuery mengembalikan semua row terhapus yang cocok dengan filter/search query, dengan
+// pengaman yang sama seperti BulkDeleteByQuery (AllowDeleteAll, MaxAffected, DryRun).
+// Mengembalikan jumlah row (yang akan) dikembalikan.
+func BulkRestoreByQuery[T any](ctx context.Context, query url.Values, db *gorm.DB, modelPtr *T, opts Options, write WriteOptions) (n int64, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](write.Metrics, OpBulkRestore)
+	defer func() { op.end(int(n), err) }()
+	sch, err := parseSchema(db, modelPtr)
+	if err != nil {
+		return 0, err
+	}
+	sd := softDeleteField(sch)
+	if sd == nil {
+		return 0, fmt.Errorf("%w: model %s has no gorm.DeletedAt field", ErrInvalidConfig, sch.Name)
+	}
+	db = db.Unscoped().Where(db.Statement.Quote(sch.Table+"."+sd.DBName) + " IS NOT NULL")
+	q, total, err := bulkQuery(ctx, query, db, modelPtr, opts, write)
+	if err != nil || write.DryRun || total == 0 {
+		return total, err
+	}
+	res := q.Select(sd.DBName).Updates(map[string]interface{}{sd.DBName: nil})
+	if res.Error == nil && res.RowsAffected > 0 {
+		invalidateWrite[T](ctx, db, write)
+	}
+	return res.RowsAffected, res.Error
+}
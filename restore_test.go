@@ -0,0 +1,88 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRestoreGeneric(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 3, 0)
+	db.Delete(&Order{}, orders[0].ID)
+	db.Delete(&Order{}, orders[1].ID)
+
+	restored, err := RestoreGeneric[Order](context.Background(), db, orders[0].ID, WriteOptions{PreloadFields: []string{"Gudang"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ID != orders[0].ID || restored.DeletedAt.Valid || restored.Gudang == nil {
+		t.Fatalf("restored %+v", restored)
+	}
+	var visible int64
+	db.Model(&Order{}).Count(&visible)
+	if visible != 2 {
+		t.Fatalf("%d visible rows, want 2", visible)
+	}
+
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", orders[0].GudangID) }
+	cases := []struct {
+		name   string
+		id     uint
+		opts   WriteOptions
+		want   error
+		status int
+	}{
+		{"not deleted", orders[2].ID, WriteOptions{}, ErrNotDeleted, 409},
+		{"not found", 99, WriteOptions{}, ErrNotFound, 404},
+		{"outside scope", orders[1].ID, WriteOptions{Scopes: []Scope{tenant}}, ErrNotFound, 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := RestoreGeneric[Order](context.Background(), db, tc.id, tc.opts)
+			if !errors.Is(err, tc.want) || StatusForError(err) != tc.status {
+				t.Fatalf("err = %v (%d), want %v (%d)", err, StatusForError(err), tc.want, tc.status)
+			}
+		})
+	}
+	if _, err := RestoreGeneric[Gudang](context.Background(), db, 1, WriteOptions{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("model without DeletedAt: err = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestBulkRestoreByQuery(t *testing.T) {
+	cases := []struct {
+		name     string
+		query    url.Values
+		write    WriteOptions
+		want     error
+		n        int64
+		restored int64
+	}{
+		{"filter", url.Values{"filter[status]": {"aktif"}}, WriteOptions{}, nil, 2, 2},
+		{"dry run", url.Values{"filter[status]": {"aktif"}}, WriteOptions{DryRun: true}, nil, 2, 0},
+		{"no conditions", url.Values{}, WriteOptions{}, ErrMissingConditions, 0, 0},
+		{"allow all", url.Values{}, WriteOptions{AllowDeleteAll: true}, nil, 3, 3},
+		{"max affected", url.Values{"filter[status]": {"aktif"}}, WriteOptions{MaxAffected: 1}, ErrTooManyAffected, 2, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			seedOrders(t, db, 4, 0)
+			// ORD-01..03 terhapus, ORD-04 tidak: hanya row terhapus yang dihitung
+			db.Where("kode <> ?", "ORD-04").Delete(&Order{})
+			n, err := BulkRestoreByQuery(context.Background(), tc.query, db.Model(&Order{}), &Order{}, Options{}, tc.write)
+			if !errors.Is(err, tc.want) || n != tc.n {
+				t.Fatalf("%d rows, err %v; want %d, %v", n, err, tc.n, tc.want)
+			}
+			var visible int64
+			db.Model(&Order{}).Count(&visible)
+			if visible != 1+tc.restored {
+				t.Fatalf("%d visible rows, want %d", visible, 1+tc.restored)
+			}
+		})
+	}
+}
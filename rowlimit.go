@@ -0,0 +1,42 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HardRowLimit: batas paket untuk jumlah row satu halaman yang dimuat ke memori (ReadPaginated, PaginateGeneric,
+// ReadRange) — jaring pengaman bila DefaultPageSize / MaxPageSize salah konfigurasi. Options.HardRowLimit
+// menimpanya per resource. 0 = tanpa batas. Ubah sekali saat init, bukan per request.
+var HardRowLimit = 50000
+
+// ErrTooManyRows: satu halaman melebihi HardRowLimit / Options.HardRowLimit, atau ReadAll melebihi
+// Options.ReadAllMaxRows
+var ErrTooManyRows = errors.New("too many rows")
+
+// hardRowLimit: batas efektif untuk opts (Options.HardRowLimit, 0 = HardRowLimit paket, negatif = tanpa batas)
+func hardRowLimit(opts Options) int {
+	switch {
+	case opts.HardRowLimit < 0:
+		return 0
+	case opts.HardRowLimit > 0:
+		return opts.HardRowLimit
+	}
+	return HardRowLimit
+}
+
+// cappedLimit: LIMIT query data; di atas batas cukup hard+1 row untuk tahu batasnya terlewati
+func cappedLimit(limit, hard int) int {
+	if hard > 0 && limit > hard {
+		return hard + 1
+	}
+	return limit
+}
+
+// checkRowLimit: ErrTooManyRows bila n melewati batas hard
+func checkRowLimit(n, hard int) error {
+	if hard > 0 && n > hard {
+		return fmt.Errorf("%w: more than %d rows in one page, use a smaller pageSize or StreamList / ReadAll", ErrTooManyRows, hard)
+	}
+	return nil
+}
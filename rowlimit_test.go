@@ -0,0 +1,80 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestReadPaginatedHardRowLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		hard  int
+		limit string // LIMIT query data
+		err   error
+	}{
+		{"page above limit", 3, "LIMIT 4", ErrTooManyRows},
+		{"page at limit", 5, "LIMIT 5", nil},
+		{"negative = unlimited", -1, "LIMIT 5", nil},
+		{"package default", 0, "LIMIT 5", nil},
+	}
+	db := newTestDB(t)
+	seedOrders(t, db, 6, 0)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			res, err := ReadPaginated(url.Values{"pageSize": {"5"}}, rdb.Model(&Order{}), &Order{}, Options{OrderBy: "id", HardRowLimit: tc.hard})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if len(rec.matching(tc.limit)) != 1 {
+				t.Fatalf("no %s in %v", tc.limit, rec.statements())
+			}
+			p, _ := res.Meta["pagination"].(map[string]interface{})
+			if res.Data == nil || p == nil || p["total"] != int64(6) {
+				t.Fatalf("data %v, meta %v: want a non-nil page and pagination also on error", res.Data, res.Meta)
+			}
+			if tc.err != nil && (len(res.Data) != 0 || StatusForError(err) != 400 || errorCode(err) != "too_many_rows") {
+				t.Fatalf("data %d rows, status %d, code %q", len(res.Data), StatusForError(err), errorCode(err))
+			}
+		})
+	}
+}
+
+func TestHardRowLimitPackageDefault(t *testing.T) {
+	old := HardRowLimit
+	HardRowLimit = 2
+	t.Cleanup(func() { HardRowLimit = old })
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 0)
+	if _, _, err := PaginateGeneric(db.Model(&Order{}), &Order{}, 1, 3); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("PaginateGeneric: err = %v, want ErrTooManyRows", err)
+	}
+	if _, err := ReadRange[Order](context.Background(), FromURLValues(url.Values{}), db, Options{OrderBy: "id"}, 0, 2); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("ReadRange: err = %v, want ErrTooManyRows", err)
+	}
+	if res, err := ReadPaginated(url.Values{"pageSize": {"3"}}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id", HardRowLimit: 10}); err != nil || len(res.Data) != 3 {
+		t.Fatalf("Options.HardRowLimit override: %d rows, err %v", len(res.Data), err)
+	}
+}
+
+func TestReadAllMaxRows(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	cases := []struct {
+		max  int
+		want int // row yang diberikan ke fn
+		err  error
+	}{
+		{0, 5, nil},
+		{5, 5, nil},
+		{3, 2, ErrTooManyRows}, // batch kedua (row 3-4) melewati batas, tidak diberikan
+	}
+	for _, tc := range cases {
+		n, err := ReadAll(context.Background(), url.Values{}, db, &Order{}, Options{ReadAllMaxRows: tc.max}, 2, func([]Order) error { return nil })
+		if n != tc.want || !errors.Is(err, tc.err) {
+			t.Fatalf("max %d: n = %d, err %v; want %d, %v", tc.max, n, err, tc.want, tc.err)
+		}
+	}
+}
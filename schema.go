@@ -0,0 +1,180 @@
+package magicrest
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// modelSchemaCache dipakai helper yang tidak menerima *gorm.DB (DiscoverRelations dkk)
+var modelSchemaCache sync.Map
+
+// parseModelSchema mem-parse schema model tanpa *gorm.DB (naming strategy default gorm)
+func parseModelSchema(model interface{}) (*schema.Schema, error) {
+	return schema.Parse(model, &modelSchemaCache, schema.NamingStrategy{})
+}
+
+// parseMagicTag membaca tag `magicrest:"nopreload,type:date"` menjadi map
+// (entry tanpa nilai disimpan sebagai "")
+func parseMagicTag(tag reflect.StructTag) map[string]string {
+	out := map[string]string{}
+	for _, item := range strings.Split(tag.Get("magicrest"), ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(item, ":")
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+// DiscoverRelations: daftar relasi level pertama model T (nama field Go, urut alfabet)
+// hasil parsing schema gorm. Relasi dengan tag `magicrest:"nopreload"` dilewati.
+func DiscoverRelations[T any]() []string {
+	return append([]string(nil), modelRelations[T]()...)
+}
+
+// relationsCache: hasil DiscoverRelations per tipe model
+var relationsCache sync.Map
+
+// modelRelations: DiscoverRelations yang di-memoize per tipe model (slice bersama, jangan diubah)
+func modelRelations[T any]() []string {
+	key := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := relationsCache.Load(key); ok {
+		return cached.([]string)
+	}
+	var names []string
+	if sch, err := parseModelSchema(new(T)); err == nil {
+		for name, rel := range sch.Relationships.Relations {
+			if _, skip := parseMagicTag(rel.Field.Tag)["nopreload"]; skip {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	cached, _ := relationsCache.LoadOrStore(key, names)
+	return cached.([]string)
+}
+
+// parseSchema mem-parse schema gorm dari model memakai naming strategy dan cache milik db.
+func parseSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// schemaFieldType memetakan field schema ke tipe filter ("uuid", "int", "bool", "datetime", "string").
+// Tag `magicrest:"type:date"` menimpa hasil deteksi.
+func schemaFieldType(f *schema.Field) string {
+	if t := parseMagicTag(f.Tag)["type"]; t != "" {
+		return t
+	}
+	t := f.FieldType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(uuid.UUID{}) || f.DataType == "uuid" {
+		return "uuid"
+	}
+	if t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(gorm.DeletedAt{}) {
+		return "datetime"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Bool:
+		return "bool"
+	}
+	return "string"
+}
+
+// fieldTypesCache: hasil FieldTypesFromModel per tipe model
+var fieldTypesCache sync.Map
+
+// FieldTypesFromModel: tipe filter per kolom DB model T hasil parsing schema gorm
+// (uuid.UUID -> "uuid", int -> "int", bool -> "bool", time.Time -> "datetime", selain itu "string"),
+// bisa ditimpa dengan tag `magicrest:"type:date"`. Hasil di-memoize per tipe model.
+func FieldTypesFromModel[T any]() map[string]string {
+	out := map[string]string{}
+	for k, v := range modelFieldTypes[T]() {
+		out[k] = v
+	}
+	return out
+}
+
+// modelFieldTypes: FieldTypesFromModel tanpa salinan (map bersama, jangan diubah)
+func modelFieldTypes[T any]() map[string]string {
+	key := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := fieldTypesCache.Load(key); ok {
+		return cached.(map[string]string)
+	}
+	types := map[string]string{}
+	if sch, err := parseModelSchema(new(T)); err == nil {
+		for _, f := range sch.Fields {
+			if isColumnField(f) {
+				types[f.DBName] = schemaFieldType(f)
+			}
+		}
+	}
+	cached, _ := fieldTypesCache.LoadOrStore(key, types)
+	return cached.(map[string]string)
+}
+
+// softDeleteField mengembalikan field gorm.DeletedAt milik schema (nil bila model tidak soft delete)
+func softDeleteField(sch *schema.Schema) *schema.Field {
+	for _, f := range sch.Fields {
+		if f.DBName != "" && f.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return f
+		}
+	}
+	return nil
+}
+
+// ModelConfig: konfigurasi yang dikumpulkan dari tag `magicrest:"filterable,sortable,searchable"` (nama kolom DB)
+type ModelConfig struct {
+	FilterFields []string
+	SortFields   []string
+	SearchFields []string
+}
+
+// modelConfigCache: hasil ConfigFromModel per tipe model
+var modelConfigCache sync.Map
+
+// ConfigFromModel mengumpulkan kolom bertag filterable, sortable dan searchable dari model T
+// (urut sesuai deklarasi field). Dipakai otomatis bila Options.TagDrivenConfig.
+func ConfigFromModel[T any]() ModelConfig {
+	key := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := modelConfigCache.Load(key); ok {
+		return cached.(ModelConfig)
+	}
+	var cfg ModelConfig
+	if sch, err := parseModelSchema(new(T)); err == nil {
+		for _, f := range sch.Fields {
+			if !isColumnField(f) {
+				continue
+			}
+			tag := parseMagicTag(f.Tag)
+			if _, ok := tag["filterable"]; ok {
+				cfg.FilterFields = append(cfg.FilterFields, f.DBName)
+			}
+			if _, ok := tag["sortable"]; ok {
+				cfg.SortFields = append(cfg.SortFields, f.DBName)
+			}
+			if _, ok := tag["searchable"]; ok {
+				cfg.SearchFields = append(cfg.SearchFields, f.DBName)
+			}
+		}
+	}
+	modelConfigCache.Store(key, cfg)
+	return cfg
+}
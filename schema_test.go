@@ -0,0 +1,206 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Retur: relasi Order ditandai nopreload, Gudang boleh di-preload
+type Retur struct {
+	ID       uint    `json:"id"`
+	OrderID  uint    `json:"order_id"`
+	Order    *Order  `json:"order,omitempty" magicrest:"nopreload"`
+	GudangID uint    `json:"gudang_id"`
+	Gudang   *Gudang `json:"gudang,omitempty"`
+	Alasan   string  `json:"alasan"`
+}
+
+func TestDiscoverRelations(t *testing.T) {
+	if got := fmt.Sprint(DiscoverRelations[Order]()); got != "[Gudang Items]" {
+		t.Fatalf("Order: %s", got)
+	}
+	if got := fmt.Sprint(DiscoverRelations[Retur]()); got != "[Gudang]" {
+		t.Fatalf("Retur (Order nopreload): %s", got)
+	}
+	if got := DiscoverRelations[Gudang](); len(got) != 0 {
+		t.Fatalf("Gudang: %v", got)
+	}
+}
+
+func TestReadPaginatedAutoAllowPreloads(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Retur{}); err != nil {
+		t.Fatal(err)
+	}
+	orders := seedOrders(t, db, 1, 1)
+	db.Create(&Retur{OrderID: orders[0].ID, GudangID: orders[0].GudangID, Alasan: "rusak"})
+	cases := []struct {
+		name    string
+		preload string
+		opts    Options
+		want    error
+	}{
+		{"discovered relation", "Items,Gudang", Options{AutoAllowPreloads: true}, nil},
+		{"added to empty whitelist", "Items", Options{AutoAllowPreloads: true, AllowedPreloads: []string{}}, nil},
+		{"added to explicit whitelist", "Items.Produk,Gudang", Options{AutoAllowPreloads: true, AllowedPreloads: []string{"Items.Produk"}}, nil},
+		{"first level only", "Items.Produk", Options{AutoAllowPreloads: true}, ErrPreloadNotAllowed},
+		{"unknown relation", "Pelanggan", Options{AutoAllowPreloads: true}, ErrPreloadNotAllowed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(url.Values{"preload": {tc.preload}}, db.Model(&Order{}), &Order{}, tc.opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if err == nil && (len(res.Data) != 1 || len(res.Data[0].Items) != 1) {
+				t.Fatalf("data %+v", res.Data)
+			}
+		})
+	}
+
+	opts := Options{OrderBy: "id", AutoAllowPreloads: true}
+	if _, err := ReadPaginated(url.Values{"preload": {"Order"}}, db.Model(&Retur{}), &Retur{}, opts); !errors.Is(err, ErrPreloadNotAllowed) {
+		t.Fatalf("nopreload relation: err = %v, want ErrPreloadNotAllowed", err)
+	}
+	res, err := ReadPaginated(url.Values{"preload": {"Gudang"}}, db.Model(&Retur{}), &Retur{}, opts)
+	if err != nil || len(res.Data) != 1 || res.Data[0].Gudang == nil {
+		t.Fatalf("Retur with Gudang: %+v, err %v", res.Data, err)
+	}
+}
+
+// Jadwal: tipe filter dari schema, Tanggal (string) ditandai type:date
+type Jadwal struct {
+	ID        uint      `json:"id"`
+	Aktif     bool      `json:"aktif"`
+	Kapasitas int       `json:"kapasitas"`
+	Tanggal   string    `json:"tanggal" magicrest:"type:date"`
+	Mulai     time.Time `json:"mulai"`
+	Catatan   *string   `json:"catatan"`
+}
+
+func TestFieldTypesFromModel(t *testing.T) {
+	want := "map[aktif:bool catatan:string id:int kapasitas:int mulai:datetime tanggal:date]"
+	types := FieldTypesFromModel[Jadwal]()
+	if fmt.Sprint(types) != want {
+		t.Fatalf("got %v, want %s", types, want)
+	}
+	// hasil memoize tidak ikut berubah bila map milik caller diubah
+	types["aktif"] = "string"
+	if got := fmt.Sprint(FieldTypesFromModel[Jadwal]()); got != want {
+		t.Fatalf("after caller change: %s", got)
+	}
+}
+
+func TestReadPaginatedAutoFieldTypes(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Jadwal{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Jadwal{{Aktif: true, Kapasitas: 10, Tanggal: "2024-01-01"}, {Kapasitas: 20, Tanggal: "2024-01-02"}})
+	cases := []struct {
+		name  string
+		query url.Values
+		opts  Options
+		rows  int
+		err   error
+	}{
+		{"bool", url.Values{"filter[aktif]": {"true"}}, Options{AutoFieldTypes: true}, 1, nil},
+		{"invalid bool", url.Values{"filter[aktif]": {"ya"}}, Options{AutoFieldTypes: true}, 0, ErrInvalidFilter},
+		{"int in", url.Values{"filter[kapasitas]": {"10,20"}}, Options{AutoFieldTypes: true}, 2, nil},
+		{"invalid int", url.Values{"filter[kapasitas]": {"banyak"}}, Options{AutoFieldTypes: true}, 0, ErrInvalidFilter},
+		{"invalid date tag", url.Values{"filter[tanggal]": {"2024-13-01"}}, Options{AutoFieldTypes: true}, 0, ErrInvalidFilter},
+		{"DefaultFieldTypes wins", url.Values{"filter[kapasitas]": {"banyak"}},
+			Options{AutoFieldTypes: true, DefaultFieldTypes: map[string]string{"kapasitas": "string"}}, 0, nil},
+		{"untyped without AutoFieldTypes", url.Values{"filter[kapasitas]": {"banyak"}}, Options{}, 0, nil},
+		{"legacy id uuid", url.Values{"filter[id]": {"1"}}, Options{LegacyFieldTypes: true}, 0, ErrInvalidFilter},
+		{"no legacy by default", url.Values{"filter[id]": {"1"}}, Options{}, 1, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Jadwal{}), &Jadwal{}, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if len(res.Data) != tc.rows {
+				t.Fatalf("rows = %d, want %d", len(res.Data), tc.rows)
+			}
+		})
+	}
+}
+
+// Kontak: konfigurasi filter, order dan search dari tag
+type Kontak struct {
+	ID      uint   `json:"id" magicrest:"sortable"`
+	Nama    string `json:"nama" magicrest:"filterable,sortable,searchable"`
+	Kota    string `json:"kota" magicrest:"filterable"`
+	Email   string `json:"email" magicrest:"searchable"`
+	Catatan string `json:"catatan"`
+}
+
+func TestConfigFromModel(t *testing.T) {
+	cfg := ConfigFromModel[Kontak]()
+	if got := fmt.Sprintf("%+v", cfg); got != "{FilterFields:[nama kota] SortFields:[id nama] SearchFields:[nama email]}" {
+		t.Fatalf("got %s", got)
+	}
+	if cfg := ConfigFromModel[Gudang](); cfg.FilterFields != nil || cfg.SortFields != nil || cfg.SearchFields != nil {
+		t.Fatalf("untagged model: %+v", cfg)
+	}
+}
+
+func TestReadPaginatedTagDrivenConfig(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Kontak{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&[]Kontak{{Nama: "Budi", Kota: "Bandung"}, {Nama: "Ani", Kota: "Bogor"}})
+	opts := Options{TagDrivenConfig: true, OrderBy: "id", ComputedColumns: map[string]string{"inisial": "substr(nama, 1, 1)"}}
+	cases := []struct {
+		name  string
+		query url.Values
+		want  string // nama, urut
+		err   error
+	}{
+		{"filterable", url.Values{"filter[kota]": {"Bogor"}}, "[Ani]", nil},
+		{"sortable", url.Values{"order": {"nama"}}, "[Ani Budi]", nil},
+		{"computed alias", url.Values{"order": {"inisial desc"}}, "[Budi Ani]", nil},
+		{"not filterable", url.Values{"filter[catatan]": {"x"}}, "[]", ErrInvalidFilter},
+		{"not sortable", url.Values{"order": {"kota"}}, "[]", ErrInvalidOrder},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ReadPaginated(tc.query, db.Model(&Kontak{}), &Kontak{}, opts)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			var got []string
+			for _, p := range res.Data {
+				got = append(got, p.Nama)
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+		})
+	}
+
+	// tanpa TagDrivenConfig semua kolom boleh
+	if _, err := ReadPaginated(url.Values{"filter[catatan]": {"x"}, "order": {"kota"}}, db.Model(&Kontak{}), &Kontak{}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	// SearchFields dari tag (ILIKE, jadi dicek lewat SQL DryRun)
+	q, _, err := BuildQuery(url.Values{"search": {"bu"}}, db.Model(&Kontak{}), &Kontak{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := q.Session(&gorm.Session{DryRun: true}).Find(&[]Kontak{}).Statement.SQL.String()
+	if !strings.Contains(sql, "nama ILIKE ? OR email ILIKE ?") {
+		t.Fatalf("SQL %q without tag search fields", sql)
+	}
+}
@@ -0,0 +1,116 @@
+package magicrest
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// label query untuk SlowQueryInfo.Label
+const (
+	QueryLabelData         = "data"          // query halaman dan preload-nya (masing-masing dilaporkan) / batch streaming dan export
+	QueryLabelCount        = "count"         // total untuk pagination, CountList dan export
+	QueryLabelWithCount    = "with_count"    // query tambahan ?with_count= untuk Meta["counts"]
+	QueryLabelLastModified = "last_modified" // MAX(updated_at) untuk Options.LastModified
+)
+
+// SlowQueryInfo: satu query yang melewati Options.SlowQueryThreshold. Tidak memegang referensi ke hasil query.
+type SlowQueryInfo struct {
+	Label    string        // QueryLabelData, QueryLabelCount, ...
+	Duration time.Duration // waktu eksekusi
+	SQL      string        // SQL yang dijalankan, nilai bind tertanam seperti log gorm (ParamsFilter logger dihormati)
+	Rows     int64         // jumlah row hasil (RowsAffected, -1 bila driver tidak melaporkan)
+	Params   QueryParams   // query yang di-parse dari request
+}
+
+// SlowQueryFunc: hook Options.OnSlowQuery
+type SlowQueryFunc func(ctx context.Context, info SlowQueryInfo)
+
+// slowQuery mengukur query berlabel satu request untuk Options.OnSlowQuery, span Options.Tracer dan
+// Options.Metrics; nil = tidak aktif
+type slowQuery struct {
+	threshold time.Duration
+	fn        SlowQueryFunc
+	params    QueryParams
+	tracer    Tracer
+	metrics   Recorder
+	model     string
+	times     *queryTimes // Options.IncludeStats
+}
+
+// queryLabelKey: key context untuk label query yang sedang berjalan (dibaca slowQueryLogger)
+type queryLabelKey struct{}
+
+// newSlowQuery: nil bila Options.OnSlowQuery, Tracer (atau DefaultTracer), Metrics (atau DefaultMetrics) dan
+// IncludeStats tidak di-set
+func newSlowQuery(opts Options, params QueryParams, model string) *slowQuery {
+	tracer, metrics := tracerFor(opts), metricsFor(opts.Metrics)
+	if opts.OnSlowQuery == nil && tracer == nil && metrics == nil && !opts.IncludeStats {
+		return nil
+	}
+	s := &slowQuery{threshold: opts.SlowQueryThreshold, fn: opts.OnSlowQuery, params: params, tracer: tracer, metrics: metrics, model: model}
+	if opts.IncludeStats {
+		s.times = &queryTimes{}
+	}
+	return s
+}
+
+// run menjalankan exec pada q. Dengan Tracer, query dibungkus span "magicrest.<label>". Dengan OnSlowQuery, q
+// diberi label di context dan logger pembungkus: gorm mengosongkan Statement.SQL setelah eksekusi, jadi SQL dan
+// durasi diambil dari Logger.Trace (juga untuk preload). Dengan Metrics / IncludeStats, durasi exec dicatat per label.
+func (s *slowQuery) run(label string, q *gorm.DB, exec func(tx *gorm.DB) *gorm.DB) error {
+	if s == nil {
+		return exec(q).Error
+	}
+	ctx := q.Statement.Context
+	var span Span = noopSpan{}
+	if s.tracer != nil {
+		ctx, span = s.tracer.Start(ctx, "magicrest."+label)
+	}
+	session := &gorm.Session{Context: ctx}
+	if s.fn != nil {
+		session.Context = context.WithValue(ctx, queryLabelKey{}, label)
+		session.Logger = slowQueryLogger{Interface: q.Logger, slow: s}
+	}
+	start := time.Now()
+	tx := exec(q.Session(session))
+	d := time.Since(start)
+	if s.metrics != nil {
+		s.metrics.ObserveQuery(s.model, label, d, tx.Error != nil)
+	}
+	if s.times != nil {
+		s.times.add(label, d)
+	}
+	span.SetAttributes(TraceAttr{Key: TraceAttrRows, Value: tx.RowsAffected})
+	span.End(tx.Error)
+	return tx.Error
+}
+
+// slowQueryLogger meneruskan semua ke logger asli lalu melaporkan query berlabel yang melewati threshold
+type slowQueryLogger struct {
+	logger.Interface
+	slow *slowQuery
+}
+
+func (l slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return slowQueryLogger{Interface: l.Interface.LogMode(level), slow: l.slow}
+}
+
+func (l slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	d := time.Since(begin)
+	l.Interface.Trace(ctx, begin, fc, err)
+	if label, ok := ctx.Value(queryLabelKey{}).(string); ok && d >= l.slow.threshold {
+		sql, rows := fc()
+		l.slow.fn(ctx, SlowQueryInfo{Label: label, Duration: d, SQL: sql, Rows: rows, Params: l.slow.params})
+	}
+}
+
+// ParamsFilter: filter nilai bind milik logger asli (e.g. ParameterizedQueries) tetap berlaku
+func (l slowQueryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if f, ok := l.Interface.(gorm.ParamsFilter); ok {
+		return f.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
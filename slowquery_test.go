@@ -0,0 +1,65 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOnSlowQuery(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 4, 1)
+	query := url.Values{"filter[status]": {"aktif"}, "preload": {"Items"}, "with_count": {"Items"}}
+	cases := []struct {
+		name      string
+		threshold time.Duration
+		hook      bool
+		want      string // label per query, urut
+	}{
+		{"every query", 0, true, "[count data data with_count]"},
+		{"below threshold", time.Hour, true, "[]"},
+		{"no hook", 0, false, "[]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var infos []SlowQueryInfo
+			opts := Options{OrderBy: "id", SlowQueryThreshold: tc.threshold}
+			if tc.hook {
+				opts.OnSlowQuery = func(_ context.Context, info SlowQueryInfo) {
+					mu.Lock()
+					infos = append(infos, info)
+					mu.Unlock()
+				}
+			}
+			res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, opts)
+			if err != nil || len(res.Data) != 2 {
+				t.Fatalf("%d rows, err %v", len(res.Data), err)
+			}
+			var labels []string
+			for _, info := range infos {
+				labels = append(labels, info.Label)
+			}
+			if fmt.Sprint(labels) != tc.want {
+				t.Fatalf("labels %v, want %s", labels, tc.want)
+			}
+			for _, info := range infos {
+				if info.SQL == "" || info.Duration < 0 || len(info.Params.Filters) != 1 || info.Params.Filters[0].Field != "status" {
+					t.Fatalf("info %+v", info)
+				}
+			}
+			if len(infos) == 4 {
+				// preload selesai (dan dilaporkan) sebelum query halaman
+				count, preload, data := infos[0], infos[1], infos[2]
+				if !strings.Contains(count.SQL, "count(*)") || !strings.Contains(preload.SQL, "FROM `items`") ||
+					!strings.Contains(data.SQL, "FROM `orders`") || data.Rows != 2 {
+					t.Fatalf("count %+v\npreload %+v\ndata %+v", count, preload, data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package magicrest
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// QuerySource: sumber parameter query (url.Values, gin.Context, request gRPC, fixture test, ...)
+type QuerySource interface {
+	Get(key string) string
+	Values() map[string][]string
+}
+
+// valuesSource membungkus map[string][]string / url.Values sebagai QuerySource
+type valuesSource map[string][]string
+
+func (v valuesSource) Get(key string) string       { return url.Values(v).Get(key) }
+func (v valuesSource) Values() map[string][]string { return v }
+
+// FromURLValues membungkus url.Values sebagai QuerySource
+func FromURLValues(query url.Values) QuerySource {
+	return valuesSource(query)
+}
+
+// FromMap membungkus map[string][]string (mis. dari payload message) sebagai QuerySource
+func FromMap(m map[string][]string) QuerySource {
+	return valuesSource(m)
+}
+
+// QueryBuilder menyusun QuerySource tanpa string query, e.g.
+// NewQuery().Page(2).Filter("status", "active").Order("created_at desc")
+type QueryBuilder struct {
+	values url.Values
+}
+
+// NewQuery membuat QueryBuilder kosong.
+func NewQuery() *QueryBuilder {
+	return &QueryBuilder{values: url.Values{}}
+}
+
+// Set mengisi parameter apa adanya (untuk parameter yang tidak punya method khusus).
+func (b *QueryBuilder) Set(key, value string) *QueryBuilder {
+	b.values.Set(key, value)
+	return b
+}
+
+// Page mengisi ?page=
+func (b *QueryBuilder) Page(page int) *QueryBuilder { return b.Set("page", strconv.Itoa(page)) }
+
+// PageSize mengisi ?pageSize=
+func (b *QueryBuilder) PageSize(size int) *QueryBuilder { return b.Set("pageSize", strconv.Itoa(size)) }
+
+// Search mengisi ?search=
+func (b *QueryBuilder) Search(term string) *QueryBuilder { return b.Set("search", term) }
+
+// Order mengisi ?order=
+func (b *QueryBuilder) Order(order string) *QueryBuilder { return b.Set("order", order) }
+
+// Preload mengisi ?preload=
+func (b *QueryBuilder) Preload(preload string) *QueryBuilder { return b.Set("preload", preload) }
+
+// Filter mengisi filter[field]=value (beberapa nilai digabung koma menjadi IN)
+func (b *QueryBuilder) Filter(field string, values ...string) *QueryBuilder {
+	return b.Set("filter["+field+"]", strings.Join(values, ","))
+}
+
+// Get implements QuerySource.
+func (b *QueryBuilder) Get(key string) string { return b.values.Get(key) }
+
+// Values implements QuerySource.
+func (b *QueryBuilder) Values() map[string][]string { return b.values }
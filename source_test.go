@@ -0,0 +1,68 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+// listRequest: QuerySource dari struct request (mis. pesan gRPC)
+type listRequest struct {
+	Status string
+	Page   int
+}
+
+func (r listRequest) Get(key string) string { return url.Values(r.Values()).Get(key) }
+
+func (r listRequest) Values() map[string][]string {
+	return map[string][]string{"filter[status]": {r.Status}, "page": {fmt.Sprint(r.Page)}, "pageSize": {"1"}}
+}
+
+func TestQueryBuilder(t *testing.T) {
+	q := NewQuery().Page(2).PageSize(5).Search("semen").Order("id desc").Preload("Items").
+		Filter("status", "aktif", "selesai").Set("fields", "id,kode")
+	want := url.Values{
+		"page": {"2"}, "pageSize": {"5"}, "search": {"semen"}, "order": {"id desc"}, "preload": {"Items"},
+		"filter[status]": {"aktif,selesai"}, "fields": {"id,kode"},
+	}
+	if fmt.Sprint(q.Values()) != fmt.Sprint(map[string][]string(want)) || q.Get("filter[status]") != "aktif,selesai" {
+		t.Fatalf("values %v", q.Values())
+	}
+	if q.Get("distinct") != "" {
+		t.Fatalf("unset key = %q", q.Get("distinct"))
+	}
+}
+
+func TestReadPaginatedSource(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	opts := Options{OrderBy: "id"}
+	sources := []struct {
+		name string
+		src  QuerySource
+	}{
+		{"url.Values", FromURLValues(url.Values{"filter[status]": {"aktif"}, "page": {"2"}, "pageSize": {"1"}})},
+		{"map", FromMap(map[string][]string{"filter[status]": {"aktif"}, "page": {"2"}, "pageSize": {"1"}})},
+		{"builder", NewQuery().Filter("status", "aktif").Page(2).PageSize(1)},
+		{"custom", listRequest{Status: "aktif", Page: 2}},
+	}
+	for _, tc := range sources {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ReadPaginatedSource(context.Background(), tc.src, db.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Data) != 1 || res.Data[0].Kode != "ORD-03" {
+				t.Fatalf("data %v", res.Data)
+			}
+			if p := res.Meta["pagination"].(map[string]interface{}); p["total"] != int64(3) {
+				t.Fatalf("pagination %v", p)
+			}
+			params, err := ParseQuerySource(tc.src, opts)
+			if err != nil || params.Page != 2 || len(params.Filters) != 1 || params.Filters[0].Field != "status" {
+				t.Fatalf("params %+v, err %v", params, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package magicrest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLCommentFunc: tag komentar SQL dari context request untuk Options.SQLComment, e.g.
+// {"app": "gudang", "request_id": id}. Key / nilai kosong dilewati.
+type SQLCommentFunc func(ctx context.Context) map[string]string
+
+// sqlComment menambahkan /* key=value, ... */ di akhir SELECT (query data, count, ReadOne) lewat builder clause FOR,
+// yang selalu ditulis terakhir oleh callback query gorm. Locking (FOR UPDATE) dari caller tetap ditulis lebih dulu;
+// UPDATE / DELETE tidak punya clause FOR sehingga tidak berubah.
+type sqlComment struct {
+	text string
+}
+
+// Build: komentar ditulis oleh buildFor (db.Clauses memanggil ModifyStatement)
+func (c sqlComment) Build(clause.Builder) {}
+
+func (c sqlComment) ModifyStatement(stmt *gorm.Statement) {
+	cl := stmt.Clauses["FOR"]
+	cl.Name = "FOR"
+	cl.Builder = c.buildFor
+	stmt.Clauses["FOR"] = cl
+}
+
+// buildFor: Locking milik clause FOR (bila ada) lalu komentarnya
+func (c sqlComment) buildFor(cl clause.Clause, builder clause.Builder) {
+	if cl.Expression != nil {
+		cl.Builder = nil
+		cl.Build(builder)
+		builder.WriteByte(' ')
+	}
+	builder.WriteString("/* " + c.text + " */")
+}
+
+// withSQLComment: db dengan komentar dari opts.SQLComment (ditambah resource=<model> bila extractor tidak
+// mengisinya); db apa adanya bila SQLComment nil atau tidak ada tag
+func withSQLComment(db *gorm.DB, opts Options, model string) *gorm.DB {
+	if opts.SQLComment == nil {
+		return db
+	}
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tags := opts.SQLComment(ctx)
+	if _, ok := tags["resource"]; !ok {
+		tags = mergeTags(tags, "resource", model)
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		key, value := sanitizeComment(k), sanitizeComment(tags[k])
+		if key != "" && value != "" {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	if len(parts) == 0 {
+		return db
+	}
+	return db.Clauses(sqlComment{text: strings.Join(parts, ", ")})
+}
+
+// mergeTags: salinan tags dengan key=value (map milik extractor tidak diubah)
+func mergeTags(tags map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// sanitizeComment: teks yang aman di dalam /* */ — tanpa penutup dan pembuka komentar (Postgres mengenal komentar
+// bersarang), tanda ? (dihitung sebagai placeholder oleh interpolasi driver MySQL) dan karakter kontrol
+func sanitizeComment(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '?' || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	for strings.Contains(s, "*/") || strings.Contains(s, "/*") {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, "*/", ""), "/*", "")
+	}
+	return strings.TrimSpace(s)
+}
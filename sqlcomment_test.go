@@ -0,0 +1,80 @@
+package magicrest
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+// requestTags: extractor uji — app tetap, request_id dari context
+func requestTags(ctx context.Context) map[string]string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return map[string]string{"app": "gudang", "request_id": id}
+}
+
+func TestReadPaginatedSQLComment(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+	cases := []struct {
+		name      string
+		requestID string
+		extractor SQLCommentFunc
+		want      string // "" = tanpa komentar
+	}{
+		{"request id", "req-42", requestTags, "/* app=gudang, request_id=req-42, resource=Order */"},
+		{"injection", "x */ ; DROP TABLE orders; /* y?", requestTags, "/* app=gudang, request_id=x  ; DROP TABLE orders;  y, resource=Order */"},
+		{"empty value skipped", "", requestTags, "/* app=gudang, resource=Order */"},
+		{"resource from extractor", "", func(context.Context) map[string]string { return map[string]string{"resource": "pesanan"} }, "/* resource=pesanan */"},
+		{"off", "req-42", nil, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			ctx := context.WithValue(context.Background(), requestIDKey{}, tc.requestID)
+			opts := Options{OrderBy: "id", SQLComment: tc.extractor, PreloadFields: []string{"Items"}}
+			res, err := ReadPaginatedCtx(ctx, url.Values{}, rdb.Model(&Order{}), &Order{}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Data) != 3 {
+				t.Fatalf("rows = %d", len(res.Data))
+			}
+			stmts := rec.matching("FROM `orders`")
+			if len(stmts) != 2 {
+				t.Fatalf("statements %v, want count and data", rec.statements())
+			}
+			for _, sql := range stmts {
+				if tc.want == "" {
+					if strings.Contains(sql, "/*") {
+						t.Fatalf("comment without SQLComment: %s", sql)
+					}
+					continue
+				}
+				if !strings.HasSuffix(sql, tc.want) || strings.Count(sql, "*/") != 1 {
+					t.Fatalf("SQL %s\nwant suffix %s", sql, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeComment(t *testing.T) {
+	cases := map[string]string{
+		"req-1":           "req-1",
+		"a */ b":          "a  b",
+		"**//":            "",
+		"*/*/":            "",
+		"a /* nested":     "a  nested",
+		"id=?":            "id=",
+		"line\nbreak\x00": "linebreak",
+		"  spasi  ":       "spasi",
+	}
+	for in, want := range cases {
+		if got := sanitizeComment(in); got != want {
+			t.Errorf("sanitizeComment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package magicrest
+
+import (
+	"math"
+	"time"
+)
+
+// QueryStats: Meta["stats"] (Options.IncludeStats). Durasi diukur di sekitar panggilan database saja — hook,
+// masking dan encode tidak ikut; hasil dari ResultCache berdurasi 0.
+type QueryStats struct {
+	DurationMs          float64 `json:"durationMs"`                    // query data, termasuk preload-nya
+	Rows                int     `json:"rows"`                          // jumlah item di Data
+	CountDurationMs     float64 `json:"countDurationMs"`               // query total untuk pagination
+	WithCountDurationMs float64 `json:"withCountDurationMs,omitempty"` // query ?with_count= / Options.WithCounts
+}
+
+// queryTimes: durasi per label query satu request untuk QueryStats
+type queryTimes struct {
+	data, count, withCount time.Duration
+}
+
+func (t *queryTimes) add(label string, d time.Duration) {
+	switch label {
+	case QueryLabelData:
+		t.data += d
+	case QueryLabelCount:
+		t.count += d
+	case QueryLabelWithCount:
+		t.withCount += d
+	}
+}
+
+// stats: QueryStats untuk rows item
+func (t *queryTimes) stats(rows int) QueryStats {
+	return QueryStats{DurationMs: millis(t.data), Rows: rows, CountDurationMs: millis(t.count), WithCountDurationMs: millis(t.withCount)}
+}
+
+// millis: d dalam milidetik, dibulatkan ke 0.01
+func millis(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
@@ -0,0 +1,103 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slowOrdersDB: setiap query ke orders ditahan 5ms sebelum dijalankan, agar durasi di QueryStats bisa diuji
+func slowOrdersDB(t *testing.T) *gorm.DB {
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 1)
+	db.Callback().Query().Before("gorm:query").Register("test:slow_orders", func(db *gorm.DB) {
+		if db.Statement.Table == "orders" {
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+	return db
+}
+
+func TestIncludeStats(t *testing.T) {
+	db := slowOrdersDB(t)
+	cases := []struct {
+		name      string
+		query     url.Values
+		opts      Options
+		rows      int
+		withCount bool
+	}{
+		{"list", url.Values{"pageSize": {"2"}}, Options{IncludeStats: true}, 2, false},
+		{"with_count", url.Values{"with_count": {"Items"}, "preload": {"Items"}}, Options{IncludeStats: true}, 3, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.OrderBy = "id"
+			res, err := ReadPaginated(tc.query, db.Model(&Order{}), &Order{}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, ok := res.Meta["stats"].(QueryStats)
+			if !ok || st.Rows != tc.rows || st.DurationMs < 5 || st.CountDurationMs < 5 {
+				t.Fatalf("stats %#v", res.Meta["stats"])
+			}
+			// with_count menghitung items (tanpa jeda), jadi cukup dicek ada tidaknya di JSON
+			b, _ := json.Marshal(st)
+			if strings.Contains(string(b), "withCountDurationMs") != (tc.withCount && st.WithCountDurationMs > 0) {
+				t.Fatalf("json %s", b)
+			}
+			if typed := res.Typed().Meta; typed.Stats == nil || typed.Stats.Rows != tc.rows {
+				t.Fatalf("typed meta %+v", typed)
+			}
+		})
+	}
+
+	res, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id"})
+	if err != nil || res.Meta["stats"] != nil {
+		t.Fatalf("stats without IncludeStats: %v, err %v", res.Meta, err)
+	}
+}
+
+// hasil dari ResultCache: durasi 0, stats tidak ikut disimpan di cache
+func TestIncludeStatsCached(t *testing.T) {
+	db := slowOrdersDB(t)
+	cache := NewMemoryCache(10)
+	opts := Options{OrderBy: "id", ResultCache: cache, IncludeStats: true}
+	miss, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hit, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := miss.Meta["stats"].(QueryStats); st.DurationMs < 5 {
+		t.Fatalf("miss stats %+v", st)
+	}
+	if st := hit.Meta["stats"]; hit.Meta["cached"] != true || st != (QueryStats{Rows: 3}) {
+		t.Fatalf("hit meta %v", hit.Meta)
+	}
+	// cache tanpa IncludeStats: entry yang sama, tanpa stats
+	plain, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id", ResultCache: cache})
+	if err != nil || plain.Meta["cached"] != true || plain.Meta["stats"] != nil {
+		t.Fatalf("plain meta %v, err %v", plain.Meta, err)
+	}
+}
+
+func TestMillis(t *testing.T) {
+	cases := map[time.Duration]float64{
+		0:                       0,
+		1234 * time.Microsecond: 1.23,
+		1235 * time.Microsecond: 1.24,
+		2 * time.Second:         2000,
+	}
+	for d, want := range cases {
+		if got := millis(d); got != want {
+			t.Fatalf("millis(%v) = %v, want %v", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package magicrest
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// StatusForError memetakan error dari package ini ke HTTP status:
+// query tidak valid (termasuk ErrInvalidCursor) -> 400, record tidak ada -> 404, unique violation / ErrNotDeleted / ErrMultipleResults -> 409,
+// ErrMissingActor -> 401, ErrStaleRecord -> 412, dialect tidak didukung -> 501,
+// lainnya -> 500.
+// Error yang dibungkus (fmt.Errorf("%w"), errors.Join) tetap dikenali.
+func StatusForError(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict), errors.Is(err, ErrNotDeleted), errors.Is(err, ErrMultipleResults):
+		return http.StatusConflict
+	case errors.Is(err, ErrMissingActor):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrStaleRecord):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrQueryTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, ErrRangeNotSatisfiable):
+		return http.StatusRequestedRangeNotSatisfiable
+	case errors.Is(err, ErrUnsupportedDialect):
+		return http.StatusNotImplemented
+	case errorCode(err) != "":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError menulis err sebagai JSON {"error": "...", "errors": [ValidationDetail...]} dengan status dari
+// StatusForError. Pesan error 500 tidak dikirim ke klien (hanya teks status).
+func WriteError(w http.ResponseWriter, err error) {
+	WriteErrorLocale(w, err, "")
+}
+
+// WriteErrorLocale: WriteError dengan ValidationDetail.Message dalam locale tertentu
+// (e.g. dari LocaleFromRequest(r)).
+func WriteErrorLocale(w http.ResponseWriter, err error, locale string) {
+	status, body := ErrorResponse(err, locale)
+	writeJSON(w, status, body)
+}
+
+// ErrorResponse: status dan body error yang ditulis WriteErrorLocale, untuk adapter framework yang
+// menulis JSON sendiri. Pesan error 500 diganti teks status. Bentuk lain: EnvelopeConfig.ErrorBody.
+func ErrorResponse(err error, locale string) (int, map[string]interface{}) {
+	return EnvelopeConfig{}.ErrorBody(err, locale)
+}
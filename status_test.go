@@ -0,0 +1,84 @@
+package magicrest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestStatusForError(t *testing.T) {
+	pageErr := newQueryError(ErrPageOutOfRange, "page", "9", "exceeds pageCount 2")
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"invalid filter", newQueryError(ErrInvalidFilter, "filter[jumlah]", "x", ""), http.StatusBadRequest},
+		{"invalid order", newQueryError(ErrInvalidOrder, "order", "x", ""), http.StatusBadRequest},
+		{"invalid preload", fmt.Errorf("%w: unknown relation", ErrInvalidPreload), http.StatusBadRequest},
+		{"too many preloads", fmt.Errorf("%w: %w: 11", ErrInvalidPreload, ErrTooManyPreloads), http.StatusBadRequest},
+		{"invalid cursor", newQueryError(ErrInvalidCursor, "cursor", "x", ""), http.StatusBadRequest},
+		{"page size", newQueryError(ErrPageSizeTooLarge, "pageSize", "500", ""), http.StatusBadRequest},
+		{"page out of range", pageErr, http.StatusBadRequest},
+		{"range", fmt.Errorf("%w: items=90-99", ErrRangeNotSatisfiable), http.StatusRequestedRangeNotSatisfiable},
+		{"gorm not found", gorm.ErrRecordNotFound, http.StatusNotFound},
+		{"not found", ErrNotFound, http.StatusNotFound},
+		{"conflict", ErrConflict, http.StatusConflict},
+		{"stale", ErrStaleRecord, http.StatusPreconditionFailed},
+		{"missing actor", ErrMissingActor, http.StatusUnauthorized},
+		{"timeout", ErrQueryTimeout, http.StatusGatewayTimeout},
+		{"unsupported dialect", newQueryError(ErrUnsupportedDialect, "preload", "Items", ""), http.StatusNotImplemented},
+		{"wrapped", fmt.Errorf("list barang: %w", gorm.ErrRecordNotFound), http.StatusNotFound},
+		{"joined", errors.Join(newQueryError(ErrInvalidFilter, "filter[a]", "x", ""), newQueryError(ErrInvalidOrder, "order", "y", "")), http.StatusBadRequest},
+		{"other", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := StatusForError(tc.err); got != tc.want {
+				t.Fatalf("StatusForError(%v) = %d, want %d", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	db := newTestDB(t)
+	_, err := ParseQuery(url.Values{"filter[id]": {"abc"}}, Options{DefaultFieldTypes: map[string]string{"id": "int"}})
+	cases := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+		details int
+	}{
+		{"query error", err, http.StatusBadRequest, err.Error(), 1},
+		{"not found", db.First(&Order{}).Error, http.StatusNotFound, "record not found", 0},
+		{"internal hidden", errors.New("dial tcp 10.0.0.5:5432: refused"), http.StatusInternalServerError, "Internal Server Error", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteError(rec, tc.err)
+			var body struct {
+				Error  string             `json:"error"`
+				Errors []ValidationDetail `json:"errors"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tc.status || body.Error != tc.message || len(body.Errors) != tc.details {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" && ct != "application/json; charset=utf-8" {
+				t.Fatalf("Content-Type %q", ct)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package magicrest
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// defaultStreamBatch: jumlah row per query saat streaming (Options.StreamBatchSize)
+const defaultStreamBatch = 500
+
+// listStream: query dan meta yang sudah siap sebelum byte pertama ditulis
+type listStream[T any] struct {
+	ctx    context.Context
+	db     *gorm.DB
+	opts   Options
+	offset int
+	limit  int // jumlah row maksimum mulai offset
+	meta   map[string]interface{}
+	order  streamOrder // tiebreaker primary key dan keyset paging antar batch
+	slow   *slowQuery  // Options.OnSlowQuery, per batch
+	op     *metricOp   // Options.Metrics: OpExport dari persiapan sampai each selesai
+	audit  *readAudit  // Options.Audit: dipanggil setelah each selesai tanpa error
+}
+
+// newListStream menjalankan semua yang bisa gagal dengan error API (parse, validasi, count, strict page)
+// sebelum response dimulai, sehingga handler masih bisa mengirim status error.
+func newListStream[T any](ctx context.Context, query QuerySource, db *gorm.DB, opts Options) (s *listStream[T], err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	op := startOp[T](opts.Metrics, OpExport)
+	defer func() {
+		if err != nil {
+			op.end(-1, err)
+		}
+	}()
+	db = readDB(ctx, db, query, opts)
+	q, info, err := BuildQuerySource[T](query, db.Model(new(T)), new(T), opts)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	slow := newSlowQuery(opts, info.Params, modelName[T]())
+	if err := slow.run(QueryLabelCount, countQuery(q), func(tx *gorm.DB) *gorm.DB { return tx.Count(&total) }); err != nil {
+		return nil, err
+	}
+	pagination := paginationMeta(total, info.Page, info.PageSize)
+	if pc, _ := pagination["pageCount"].(int); opts.StrictQuery && info.Page > 1 && info.Page > pc {
+		return nil, newQueryError(ErrPageOutOfRange, "page", strconv.Itoa(info.Page), fmt.Sprintf("exceeds pageCount %d", pc))
+	}
+
+	// meta dihitung di depan; counts per relasi butuh data sehingga tidak tersedia saat streaming
+	meta := map[string]interface{}{"pagination": pagination}
+	if len(info.ImplicitFields) > 0 {
+		meta["implicitFields"] = info.ImplicitFields
+	}
+	if len(info.Warnings) > 0 {
+		meta["warnings"] = info.Warnings
+	}
+	if opts.EchoQuery || (opts.AllowDebugQuery && query.Get("debug") == "1") {
+		meta["query"] = echoQuery(info)
+	}
+	if opts.TransformResult != nil {
+		opts.TransformResult(meta)
+	}
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	offset := (info.Page - 1) * info.PageSize
+	limit := min(int64(info.PageSize), max(total-int64(offset), 0))
+	return &listStream[T]{ctx: ctx, db: q, opts: opts, offset: offset, limit: int(limit), meta: meta,
+		order: newStreamOrder(sch, info), slow: slow, op: op, audit: newReadAudit[T](opts, OpExport, start, info, "")}, nil
+}
+
+// each meng-query row per Options.StreamBatchSize (default 500) dengan ORDER BY dari query ditambah primary key
+// sebagai tiebreaker, lalu menerapkan mask dan TransformItem sebelum memanggil fn per batch. Batch pertama mulai
+// dari offset halaman; batch berikutnya memakai keyset (WHERE setelah row terakhir) bila streamOrder mengizinkan,
+// selain itu Offset — urutannya tetap deterministik sehingga tidak ada row yang terulang atau terlewat.
+func (s *listStream[T]) each(fn func(rows []T) error) (err error) {
+	n := 0
+	defer func() {
+		s.op.end(n, err)
+		s.audit.done(s.ctx, n, err)
+	}()
+	batch := s.opts.StreamBatchSize
+	if batch <= 0 {
+		batch = defaultStreamBatch
+	}
+	q := s.db
+	if s.order.tiebreak {
+		q = q.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}})
+	}
+	// Session: tiap batch mulai dari query dasar, kondisi keyset batch sebelumnya tidak menumpuk
+	q = q.Session(&gorm.Session{})
+	var after clause.Expression // nil = Offset(s.offset + n)
+	for n < s.limit {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		limit := min(batch, s.limit-n)
+		// Offset selalu di-set (-1 = hapus): q bisa membawa Offset halaman dari buildQuery
+		off := s.offset + n
+		if after != nil {
+			off = -1
+		}
+		tx := q.Limit(limit).Offset(off)
+		if after != nil {
+			tx = tx.Where(after)
+		}
+		var rows []T
+		if err := s.slow.run(QueryLabelData, tx, func(tx *gorm.DB) *gorm.DB { return tx.Find(&rows) }); err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			// posisi keyset dari row asli, sebelum mask / TransformItem mengubahnya
+			after = s.order.after(s.ctx, reflect.ValueOf(&rows[len(rows)-1]).Elem())
+		}
+		applyMasks(s.ctx, rows, s.opts)
+		if s.opts.TransformItem != nil {
+			for i := range rows {
+				s.opts.TransformItem(n+i, &rows[i])
+			}
+		}
+		n += len(rows)
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < limit {
+			break
+		}
+	}
+	return nil
+}
+
+// streamOrder: urutan stream. ORDER BY efektif selalu ditutup primary key (tiebreak) agar row dengan nilai sort
+// yang sama punya urutan tetap antar batch. keys (kolom sort + primary key) hanya diisi bila setiap kolom sort
+// adalah kolom model yang ikut di-select dan tidak pernah NULL — primary key, NOT NULL, autoCreateTime /
+// autoUpdateTime — karena perbandingan keyset dengan NULL melewatkan row. Kolom relasi, ComputedColumns, ekspresi
+// atau kolom nullable memakai Offset. DISTINCT / GROUP BY tidak punya primary key: tanpa tiebreak dan keyset.
+type streamOrder struct {
+	tiebreak bool
+	keys     []seekKey
+}
+
+// seekKey: satu kolom keyset
+type seekKey struct {
+	field *schema.Field
+	desc  bool
+}
+
+func newStreamOrder(sch *schema.Schema, info QueryInfo) streamOrder {
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil || info.Distinct || len(info.GroupBy) > 0 {
+		return streamOrder{}
+	}
+	o := streamOrder{tiebreak: true}
+	seekable := true
+	for _, item := range parseSort(info.Order) {
+		f := sch.LookUpField(item.Field)
+		if !isColumnField(f) || !(f.PrimaryKey || f.NotNull || f.AutoCreateTime > 0 || f.AutoUpdateTime > 0) ||
+			info.Fields != nil && !containsString(info.Fields, f.DBName) {
+			seekable = false
+			continue
+		}
+		o.keys = append(o.keys, seekKey{field: f, desc: item.Desc})
+		if f == pk {
+			o.tiebreak = false
+		}
+	}
+	if o.tiebreak {
+		o.keys = append(o.keys, seekKey{field: pk})
+	}
+	if !seekable {
+		o.keys = nil
+	}
+	return o
+}
+
+// after: kondisi keyset untuk row setelah rv — (a > ?) OR (a = ? AND b < ?) OR ... sesuai arah tiap kolom;
+// nil (kembali ke Offset) bila tanpa keys atau ada nilai NULL
+func (o streamOrder) after(ctx context.Context, rv reflect.Value) clause.Expression {
+	if len(o.keys) == 0 {
+		return nil
+	}
+	values := make([]interface{}, len(o.keys))
+	for i, k := range o.keys {
+		v, _ := k.field.ValueOf(ctx, rv)
+		if isNullValue(v) {
+			return nil
+		}
+		values[i] = v
+	}
+	ors := make([]clause.Expression, len(o.keys))
+	for i, k := range o.keys {
+		and := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, clause.Eq{Column: o.keys[j].column(), Value: values[j]})
+		}
+		if k.desc {
+			and = append(and, clause.Lt{Column: k.column(), Value: values[i]})
+		} else {
+			and = append(and, clause.Gt{Column: k.column(), Value: values[i]})
+		}
+		ors[i] = clause.And(and...)
+	}
+	return clause.Or(ors...)
+}
+
+func (k seekKey) column() clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: k.field.DBName}
+}
+
+// isNullValue: v ditulis sebagai NULL (nil, pointer nil, atau driver.Valuer yang menghasilkan nil)
+func isNullValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return true
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		return err != nil || val == nil
+	}
+	return false
+}
+
+// writeTo menulis pembuka envelope, item per batch (Flush tiap batch bila w http.Flusher), lalu meta.
+func (s *listStream[T]) writeTo(w io.Writer) error {
+	env := s.opts.Envelope
+	flat := env.DataKey == "-"
+	if flat {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+	} else {
+		key, _ := json.Marshal(keyOr(env.DataKey, "data"))
+		if _, err := fmt.Fprintf(w, "{%s:[", key); err != nil {
+			return err
+		}
+	}
+
+	// Encoder memakai ulang buffer-nya; newline setelah item adalah whitespace JSON yang valid
+	enc := json.NewEncoder(w)
+	first := true
+	err := s.each(func(rows []T) error {
+		for i := range rows {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := enc.Encode(rows[i]); err != nil {
+				return err
+			}
+		}
+		flush(w)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if flat {
+		_, err := io.WriteString(w, "]")
+		return err
+	}
+	if env.MetaKey == "-" {
+		_, err := io.WriteString(w, "]}")
+		return err
+	}
+	key, _ := json.Marshal(keyOr(env.MetaKey, "meta"))
+	meta, err := json.Marshal(env.metaValue(s.meta))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "],%s:%s}", key, meta)
+	return err
+}
+
+// flush mengirim yang sudah ditulis ke klien bila w mendukung http.Flusher (atau Flush() error, e.g. bufio.Writer)
+func flush(w io.Writer) {
+	switch f := w.(type) {
+	case http.Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		_ = f.Flush()
+	}
+}
+
+// StreamList menulis list ke w tanpa menampung satu halaman penuh di memori: item di-query per
+// Options.StreamBatchSize (default 500) dan di-encode satu per satu di dalam envelope, meta di akhir.
+// Error parse/validasi/count dikembalikan sebelum ada byte yang ditulis; error setelahnya membuat
+// JSON terpotong. Meta["counts"], Meta["sql"] dan AfterQuery tidak tersedia saat streaming.
+func StreamList[T any](ctx context.Context, w io.Writer, query QuerySource, db *gorm.DB, opts Options) error {
+	s, err := newListStream[T](ctx, query, db, opts)
+	if err != nil {
+		return err
+	}
+	return s.writeTo(w)
+}
+
+// StreamListHandler: ListHandlerHTTP versi streaming (tanpa Content-Length, ETag tidak dihitung)
+func StreamListHandler[T any](db *gorm.DB, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, err := newListStream[T](r.Context(), FromURLValues(r.URL.Query()), db, opts)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		// status sudah terkirim: error di tengah stream hanya bisa memotong response
+		_ = s.writeTo(w)
+	}
+}
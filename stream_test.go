@@ -0,0 +1,177 @@
+package magicrest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamKodes: kode order dari output StreamList, gagal bila JSON tidak valid
+func streamKodes(t testing.TB, out []byte) []string {
+	t.Helper()
+	var body struct {
+		Data []Order                `json:"data"`
+		Meta map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal(out, &body); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	kodes := make([]string, len(body.Data))
+	for i, o := range body.Data {
+		kodes[i] = o.Kode
+	}
+	return kodes
+}
+
+func TestStreamListStableAcrossBatches(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 11, 0)
+	// semua created_at sama: tanpa tiebreaker urutan antar batch tidak terdefinisi
+	db.Model(&Order{}).Where("1 = 1").Update("created_at", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	cases := []struct {
+		name  string
+		query url.Values
+		sql   string // potongan SQL batch kedua
+	}{
+		{"default order keyset", url.Values{}, "`orders`.`created_at` < "},
+		{"primary key keyset", url.Values{"order": {"id desc"}}, "`orders`.`id` < "},
+		{"nullable column offset", url.Values{"order": {"telepon"}}, "OFFSET 3"},
+		{"second page", url.Values{"page": {"2"}, "pageSize": {"5"}}, "`orders`.`created_at` < "},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, rec := recordSQL(db)
+			var buf bytes.Buffer
+			opts := Options{DefaultPageSize: 100, StreamBatchSize: 3}
+			if err := StreamList[Order](context.Background(), &buf, FromURLValues(tc.query), rdb.Model(&Order{}), opts); err != nil {
+				t.Fatal(err)
+			}
+			want := 11
+			if tc.query.Get("page") == "2" {
+				want = 5
+			}
+			kodes := streamKodes(t, buf.Bytes())
+			seen := map[string]bool{}
+			for _, k := range kodes {
+				if seen[k] {
+					t.Fatalf("duplicate %s in %v", k, kodes)
+				}
+				seen[k] = true
+			}
+			if len(kodes) != want {
+				t.Fatalf("got %d rows %v, want %d", len(kodes), kodes, want)
+			}
+			if len(rec.matching(tc.sql)) == 0 {
+				t.Fatalf("no SQL contains %q:\n%s", tc.sql, strings.Join(rec.statements(), "\n"))
+			}
+			for _, sql := range rec.matching(" OR ") {
+				if strings.Count(sql, " OR ") > 1 {
+					t.Fatalf("keyset conditions accumulate across batches: %s", sql)
+				}
+			}
+			if len(rec.matching("`orders`.`id`")) == 0 {
+				t.Fatalf("ORDER BY lacks primary key tiebreaker:\n%s", strings.Join(rec.statements(), "\n"))
+			}
+		})
+	}
+}
+
+func TestStreamListMatchesReadPaginated(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 9, 0)
+	query := url.Values{"order": {"status, created_at desc"}, "pageSize": {"9"}}
+	res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := StreamList[Order](context.Background(), &buf, FromURLValues(query), db.Model(&Order{}), Options{StreamBatchSize: 2}); err != nil {
+		t.Fatal(err)
+	}
+	kodes := streamKodes(t, buf.Bytes())
+	if len(kodes) != len(res.Data) {
+		t.Fatalf("stream %d rows, paginated %d", len(kodes), len(res.Data))
+	}
+	for i, o := range res.Data {
+		if kodes[i] != o.Kode {
+			t.Fatalf("row %d: stream %s, paginated %s", i, kodes[i], o.Kode)
+		}
+	}
+}
+
+func TestStreamListEnvelopes(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 5, 0)
+	cases := []struct {
+		name string
+		env  EnvelopeConfig
+		want string // prefix body
+	}{
+		{"default", EnvelopeConfig{}, `{"data":[`},
+		{"custom keys", EnvelopeConfig{DataKey: "items", MetaKey: "info"}, `{"items":[`},
+		{"without meta", EnvelopeConfig{MetaKey: "-"}, `{"data":[`},
+		{"flat", EnvelopeConfig{DataKey: "-"}, `[`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := Options{StreamBatchSize: 2, Envelope: tc.env}
+			if err := StreamList[Order](context.Background(), &buf, FromURLValues(url.Values{}), db.Model(&Order{}), opts); err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(buf.Bytes()) || !strings.HasPrefix(buf.String(), tc.want) {
+				t.Fatalf("body %s", buf.String())
+			}
+		})
+	}
+
+	// tanpa row tetap JSON valid dengan data []
+	var buf bytes.Buffer
+	query := FromURLValues(url.Values{"filter[status]": {"batal"}})
+	if err := StreamList[Order](context.Background(), &buf, query, db.Model(&Order{}), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if kodes := streamKodes(t, buf.Bytes()); len(kodes) != 0 || !strings.HasPrefix(buf.String(), `{"data":[]`) {
+		t.Fatalf("body %s", buf.String())
+	}
+}
+
+// BenchmarkStreamList vs BenchmarkStreamListBuffered: total alokasi (B/op) sebanding, tetapi streaming hanya
+// menahan satu batch (StreamBatchSize row) sekaligus, sedangkan versi buffered menampung seluruh halaman dan
+// hasil marshal-nya bersamaan.
+func BenchmarkStreamList(b *testing.B) {
+	db := newTestDB(b)
+	seedOrders(b, db, 2000, 0)
+	query := FromURLValues(url.Values{"pageSize": {"2000"}})
+	opts := Options{StreamBatchSize: 200}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := StreamList[Order](context.Background(), io.Discard, query, db.Model(&Order{}), opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamListBuffered(b *testing.B) {
+	db := newTestDB(b)
+	seedOrders(b, db, 2000, 0)
+	query := url.Values{"pageSize": {"2000"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := ReadPaginated(query, db.Model(&Order{}), &Order{}, Options{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := json.Marshal(res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrQueryTimeout: query list melewati Options.QueryTimeout (deadline context atau statement_timeout Postgres)
+var ErrQueryTimeout = errors.New("query timeout")
+
+// pgQueryCanceled: SQLSTATE query_canceled, dipakai Postgres untuk statement_timeout
+const pgQueryCanceled = "57014"
+
+// withQueryTimeout menjalankan fn dengan context ber-deadline timeout. Di Postgres fn berjalan di dalam
+// transaksi dengan SET LOCAL statement_timeout agar batasnya tidak ikut ke koneksi lain di pool. Di dalam
+// transaksi caller (SAVEPOINT) SET LOCAL berlaku sampai transaksi luar selesai, jadi nilai sebelumnya
+// dipulihkan setelah fn; bila fn gagal ROLLBACK TO SAVEPOINT sudah memulihkannya.
+func withQueryTimeout(ctx context.Context, db *gorm.DB, timeout time.Duration, fn func(ctx context.Context, db *gorm.DB) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var err error
+	if db.Dialector.Name() == "postgres" {
+		_, nested := db.Statement.ConnPool.(gorm.TxCommitter)
+		err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var prev string
+			if nested {
+				if err := tx.Raw("SELECT current_setting('statement_timeout')").Scan(&prev).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())).Error; err != nil {
+				return err
+			}
+			if err := fn(ctx, tx); err != nil || !nested {
+				return err
+			}
+			return tx.Exec("SELECT set_config('statement_timeout', ?, true)", prev).Error
+		})
+	} else {
+		err = fn(ctx, db.WithContext(ctx))
+	}
+	if isTimeout(ctx, err) {
+		return fmt.Errorf("%w after %s: %w", ErrQueryTimeout, timeout, err)
+	}
+	return err
+}
+
+// isTimeout: err berasal dari deadline ctx atau statement_timeout (SQLSTATE 57014)
+func isTimeout(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, ErrQueryTimeout) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return true
+	}
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == pgQueryCanceled
+}
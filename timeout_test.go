@@ -0,0 +1,160 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestReadPaginatedQueryTimeout(t *testing.T) {
+	expired := func() (context.Context, context.CancelFunc) {
+		return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	}
+	cancelled := func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, cancel
+	}
+	background := func() (context.Context, context.CancelFunc) { return context.Background(), func() {} }
+	cases := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		timeout time.Duration
+		want    error
+	}{
+		{"expired request deadline", expired, time.Minute, ErrQueryTimeout},
+		{"timeout already over", background, time.Nanosecond, ErrQueryTimeout},
+		{"cancelled is not a timeout", cancelled, time.Minute, context.Canceled},
+		{"within timeout", background, time.Minute, nil},
+	}
+	db := newTestDB(t)
+	seedOrders(t, db, 3, 0)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			res, err := ReadPaginatedCtx(ctx, url.Values{}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id", QueryTimeout: tc.timeout})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if tc.want == ErrQueryTimeout && (StatusForError(err) != 504 || errors.Is(err, context.Canceled)) {
+				t.Fatalf("status %d for %v", StatusForError(err), err)
+			}
+			if tc.want == context.Canceled && errors.Is(err, ErrQueryTimeout) {
+				t.Fatalf("cancel reported as timeout: %v", err)
+			}
+			if tc.want == nil && len(res.Data) != 3 {
+				t.Fatalf("rows = %d", len(res.Data))
+			}
+		})
+	}
+}
+
+func TestQueryTimeoutInsideTransact(t *testing.T) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	err := Transact(context.Background(), db, func(tx *Tx) error {
+		ctx, cancel := context.WithDeadline(tx.Context(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err := ReadPaginatedCtx(ctx, url.Values{}, tx.DB.Model(&Order{}), &Order{}, Options{OrderBy: "id", QueryTimeout: time.Minute})
+		if !errors.Is(err, ErrQueryTimeout) {
+			t.Fatalf("err = %v, want ErrQueryTimeout", err)
+		}
+		// deadline list tidak ikut ke transaksi: tulis dan baca berikutnya tetap jalan
+		if _, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{}); err != nil {
+			return err
+		}
+		res, err := ReadPaginatedCtx(tx.Context(), url.Values{}, tx.DB.Model(&Order{}), &Order{}, Options{OrderBy: "id", QueryTimeout: time.Minute})
+		if err != nil || len(res.Data) != 3 {
+			t.Fatalf("list after timeout: %d rows, err %v", len(res.Data), err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int64
+	db.Model(&Order{}).Count(&n)
+	if n != 3 {
+		t.Fatalf("%d rows after commit, want 3", n)
+	}
+}
+
+// pgSettingsDB: SQLite yang mengaku postgres; SET / current_setting / set_config statement_timeout dicatat ke
+// recorder lalu diganti query SQLite yang setara, agar urutan statement di transaksi bisa diuji
+func pgSettingsDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	db := newTestDB(t)
+	seedOrders(t, db, 2, 0)
+	db.Config.Dialector = namedDialector{Dialector: db.Dialector, name: "postgres"}
+	db, rec := recordSQL(db)
+	rewrite := func(db *gorm.DB) {
+		sql := db.Statement.SQL.String()
+		if !strings.Contains(sql, "statement_timeout") {
+			return
+		}
+		rec.mu.Lock()
+		rec.sql = append(rec.sql, sql)
+		rec.mu.Unlock()
+		db.Statement.SQL.Reset()
+		db.Statement.SQL.WriteString("SELECT '30s'")
+		db.Statement.Vars = nil
+	}
+	db.Callback().Raw().Before("gorm:raw").Register("test:pg_settings", rewrite)
+	db.Callback().Row().Before("gorm:row").Register("test:pg_settings", rewrite)
+	return db, rec
+}
+
+// Postgres: SET LOCAL statement_timeout di transaksi sendiri, di dalam transaksi caller nilai lama dipulihkan
+func TestQueryTimeoutPostgresStatements(t *testing.T) {
+	cases := []struct {
+		name   string
+		nested bool
+	}{
+		{"own transaction", false},
+		{"inside caller transaction", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, rec := pgSettingsDB(t)
+			read := func(db *gorm.DB) error {
+				res, err := ReadPaginated(url.Values{}, db.Model(&Order{}), &Order{}, Options{OrderBy: "id", QueryTimeout: 1500 * time.Millisecond})
+				if err == nil && len(res.Data) != 2 {
+					t.Fatalf("rows = %d", len(res.Data))
+				}
+				return err
+			}
+			var err error
+			if tc.nested {
+				err = db.Transaction(read)
+			} else {
+				err = read(db)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sql := range rec.statements() {
+				switch {
+				case strings.Contains(sql, "statement_timeout"):
+					got = append(got, sql)
+				case strings.Contains(sql, "count(*)"):
+					got = append(got, "count")
+				case strings.HasPrefix(sql, "SELECT * FROM `orders`"):
+					got = append(got, "data")
+				}
+			}
+			want := []string{"SET LOCAL statement_timeout = 1500", "count", "data"}
+			if tc.nested {
+				want = []string{"SELECT current_setting('statement_timeout')", "SET LOCAL statement_timeout = 1500", "count", "data", "SELECT set_config('statement_timeout', ?, true)"}
+			}
+			if strings.Join(got, "; ") != strings.Join(want, "; ") {
+				t.Fatalf("statements:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package magicrest
+
+import (
+	"context"
+)
+
+// Tracer: tracing opsional per request (Options.Tracer / DefaultTracer). Core tidak bergantung pada
+// OpenTelemetry; modul oteltrace menyediakan adapter-nya.
+type Tracer interface {
+	// Start membuat span name sebagai child span di ctx dan mengembalikan ctx yang membawanya
+	Start(ctx context.Context, name string, attrs ...TraceAttr) (context.Context, Span)
+}
+
+// Span: satu fase request (list, parse, count, data, with_count, ...)
+type Span interface {
+	SetAttributes(attrs ...TraceAttr)
+	End(err error) // err != nil -> status span error
+}
+
+// TraceAttr: atribut span; Value berupa string, bool, int, int64, float64 atau []string
+type TraceAttr struct {
+	Key   string
+	Value interface{}
+}
+
+// DefaultTracer dipakai bila Options.Tracer nil (nil = tanpa tracing). oteltrace.Install() mengisinya dengan
+// provider global OpenTelemetry. Set sekali saat init.
+var DefaultTracer Tracer
+
+// atribut span magicrest
+const (
+	TraceAttrModel       = "magicrest.model"        // nama model, e.g. "Barang"
+	TraceAttrPage        = "magicrest.page"         // halaman (span list)
+	TraceAttrPageSize    = "magicrest.page_size"    // ukuran halaman (span list)
+	TraceAttrFilters     = "magicrest.filters"      // nama field filter[...] (tanpa nilai)
+	TraceAttrResultCount = "magicrest.result_count" // jumlah item di Data (span list)
+	TraceAttrTotal       = "magicrest.total"        // total row untuk pagination (span list)
+	TraceAttrCached      = "magicrest.cached"       // hasil dari Options.ResultCache (span list)
+	TraceAttrRows        = "magicrest.rows"         // row hasil query (span count, data, with_count, ...)
+)
+
+// tracerFor: Options.Tracer, atau DefaultTracer
+func tracerFor(opts Options) Tracer {
+	if opts.Tracer != nil {
+		return opts.Tracer
+	}
+	return DefaultTracer
+}
+
+// startSpan: span "magicrest.<phase>" bila ada Tracer, selain itu span kosong dan ctx apa adanya
+func startSpan(ctx context.Context, opts Options, phase string, attrs ...TraceAttr) (context.Context, Span) {
+	t := tracerFor(opts)
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	return t.Start(ctx, "magicrest."+phase, attrs...)
+}
+
+// filterNames: nama field filter untuk TraceAttrFilters, nilainya tidak ikut ke trace
+func filterNames(params QueryParams) []string {
+	names := make([]string, len(params.Filters))
+	for i, f := range params.Filters {
+		names[i] = f.Field
+	}
+	return names
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...TraceAttr) {}
+func (noopSpan) End(error)                  {}
+
+// endListSpan: jumlah item, total dan status cache hasil list, lalu menutup span
+func endListSpan[T any](span Span, res Result[T], err error) {
+	attrs := []TraceAttr{{Key: TraceAttrResultCount, Value: len(res.Data)}}
+	if p, ok := res.Meta["pagination"].(map[string]interface{}); ok {
+		if total, ok := p["total"].(int64); ok {
+			attrs = append(attrs, TraceAttr{Key: TraceAttrTotal, Value: total})
+		}
+	}
+	if cached, ok := res.Meta["cached"].(bool); ok {
+		attrs = append(attrs, TraceAttr{Key: TraceAttrCached, Value: cached})
+	}
+	span.SetAttributes(attrs...)
+	span.End(err)
+}
@@ -0,0 +1,50 @@
+package magicrest
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Tx: transaksi yang sedang berjalan untuk Transact. Tx.DB adalah *gorm.DB yang terikat ke transaksi
+// sehingga semua helper generik (CreateGeneric, UpdateGeneric, BulkCreateGeneric, ReadOne, ...) bisa
+// dipanggil apa adanya: magicrest.CreateGeneric(tx.Context(), tx.DB, &item, opts).
+type Tx struct {
+	*gorm.DB
+	ctx context.Context
+}
+
+// Context: context milik transaksi
+func (t *Tx) Context() context.Context { return t.ctx }
+
+// Transact menjalankan fn sebagai nested transaction (SAVEPOINT): error dari fn hanya me-rollback
+// pekerjaan fn, transaksi luar tetap bisa lanjut.
+func (t *Tx) Transact(fn func(tx *Tx) error) error {
+	return t.DB.Transaction(func(db *gorm.DB) error {
+		return fn(&Tx{DB: db, ctx: t.ctx})
+	})
+}
+
+// Transact menjalankan fn dalam satu transaksi database: commit bila fn mengembalikan nil, rollback
+// bila error atau panic (panic diteruskan setelah rollback). Helper bulk / upsert yang membuka
+// transaksi sendiri otomatis memakai SAVEPOINT di dalam tx. WriteOptions.Invalidator dari helper tulis
+// yang dipanggil dengan tx.DB (atau tx.Context()) baru dijalankan setelah commit; rollback = cache tidak
+// disentuh. Transaksi yang dibuka sendiri dengan db.Transaction tidak dikenali: invalidasinya langsung jalan.
+func Transact(ctx context.Context, db *gorm.DB, fn func(tx *Tx) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pending := &pendingInvalidations{}
+	txCtx := context.WithValue(ctx, pendingKey{}, pending)
+	err := db.Session(&gorm.Session{NewDB: true}).WithContext(txCtx).Transaction(func(db *gorm.DB) error {
+		if key, ok := txPoolKey(db); ok {
+			pendingByTx.Store(key, pending)
+			defer pendingByTx.Delete(key)
+		}
+		return fn(&Tx{DB: db, ctx: txCtx})
+	})
+	if err == nil {
+		pending.run(ctx)
+	}
+	return err
+}
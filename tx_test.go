@@ -0,0 +1,239 @@
+package magicrest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// invalidationLog: Invalidator uji yang mencatat resource setiap invalidasi
+type invalidationLog struct {
+	mu   sync.Mutex
+	invs []string
+}
+
+func (l *invalidationLog) Invalidate(_ context.Context, inv Invalidation) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.invs = append(l.invs, inv.Resource)
+	return nil
+}
+
+func (l *invalidationLog) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.invs)
+}
+
+func TestTransact(t *testing.T) {
+	errBatal := errors.New("batal")
+	cases := []struct {
+		name   string
+		fn     func(tx *Tx) error
+		err    error
+		panics bool
+		kode   string // kode order tersimpan
+		items  int64
+	}{
+		{"commit header and lines", func(tx *Tx) error {
+			o, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{})
+			if err != nil {
+				return err
+			}
+			_, err = BulkCreateGeneric(tx.Context(), tx.DB, []Item{{OrderID: o.ID, Nama: "a", Jumlah: 1}, {OrderID: o.ID, Nama: "b", Jumlah: 2}}, WriteOptions{})
+			return err
+		}, nil, false, "[TX-1]", 2},
+		{"rollback on error", func(tx *Tx) error {
+			if _, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{}); err != nil {
+				return err
+			}
+			return errBatal
+		}, errBatal, false, "[]", 0},
+		{"rollback on failing helper", func(tx *Tx) error {
+			if _, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{}); err != nil {
+				return err
+			}
+			_, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{})
+			return err
+		}, ErrConflict, false, "[]", 0},
+		{"rollback on panic", func(tx *Tx) error {
+			if _, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{}); err != nil {
+				return err
+			}
+			panic("stok minus")
+		}, nil, true, "[]", 0},
+		{"nested savepoint rollback", func(tx *Tx) error {
+			if _, err := CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-1"}, WriteOptions{}); err != nil {
+				return err
+			}
+			err := tx.Transact(func(inner *Tx) error {
+				if _, err := CreateGeneric(inner.Context(), inner.DB, &Order{Kode: "TX-2"}, WriteOptions{}); err != nil {
+					return err
+				}
+				return errBatal
+			})
+			if !errors.Is(err, errBatal) {
+				return fmt.Errorf("nested: %v", err)
+			}
+			_, err = CreateGeneric(tx.Context(), tx.DB, &Order{Kode: "TX-3"}, WriteOptions{})
+			return err
+		}, nil, false, "[TX-1 TX-3]", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tc.panics {
+						t.Fatalf("recover() = %v", r)
+					}
+				}()
+				if err := Transact(context.Background(), db, tc.fn); !errors.Is(err, tc.err) {
+					t.Fatalf("err = %v, want %v", err, tc.err)
+				}
+			}()
+			var kode []string
+			db.Model(&Order{}).Order("id").Pluck("kode", &kode)
+			var items int64
+			db.Model(&Item{}).Count(&items)
+			if fmt.Sprint(kode) != tc.kode || items != tc.items {
+				t.Fatalf("orders %v, %d items; want %s, %d", kode, items, tc.kode, tc.items)
+			}
+		})
+	}
+}
+
+func TestTransactInvalidations(t *testing.T) {
+	errBatal := errors.New("batal")
+	cases := []struct {
+		name     string
+		ctx      func(tx *Tx) context.Context // ctx yang diberikan ke helper tulis
+		fail     error                        // error dari fn (nil = commit)
+		panics   bool
+		want     int // invalidasi setelah Transact selesai
+		wantRows int64
+	}{
+		{"commit with tx context", (*Tx).Context, nil, false, 3, 2},
+		{"commit with request context", func(*Tx) context.Context { return context.Background() }, nil, false, 3, 2},
+		{"rollback with tx context", (*Tx).Context, errBatal, false, 0, 0},
+		{"rollback with request context", func(*Tx) context.Context { return context.Background() }, errBatal, false, 0, 0},
+		{"panic", func(*Tx) context.Context { return context.Background() }, nil, true, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			inv := &invalidationLog{}
+			opts := WriteOptions{Invalidator: inv}
+			run := func() error {
+				return Transact(context.Background(), db, func(tx *Tx) error {
+					ctx := tc.ctx(tx)
+					o, err := CreateGeneric(ctx, tx.DB, &Order{Kode: "TX-1"}, opts)
+					if err != nil {
+						return err
+					}
+					if _, err := BulkCreateGeneric(ctx, tx.DB, []Order{{Kode: "TX-2"}, {Kode: "TX-3"}}, opts); err != nil {
+						return err
+					}
+					if err := DeleteGeneric[Order](ctx, tx.DB, o.ID, opts); err != nil {
+						return err
+					}
+					if n := inv.count(); n != 0 {
+						return fmt.Errorf("%d invalidations before commit", n)
+					}
+					if tc.panics {
+						panic("gagal")
+					}
+					return tc.fail
+				})
+			}
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); r != nil && !tc.panics {
+						panic(r)
+					}
+				}()
+				err = run()
+			}()
+			if !errors.Is(err, tc.fail) {
+				t.Fatalf("err = %v, want %v", err, tc.fail)
+			}
+			if n := inv.count(); n != tc.want {
+				t.Fatalf("%d invalidations, want %d", n, tc.want)
+			}
+			var rows int64
+			db.Model(&Order{}).Count(&rows)
+			if rows != tc.wantRows {
+				t.Fatalf("%d rows, want %d", rows, tc.wantRows)
+			}
+		})
+	}
+}
+
+func TestInvalidateOutsideTransact(t *testing.T) {
+	db := newTestDB(t)
+	inv := &invalidationLog{}
+	opts := WriteOptions{Invalidator: inv}
+
+	// transaksi sendiri (bukan Transact): tidak dikenali, invalidasi langsung jalan
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if _, err := CreateGeneric(context.Background(), tx, &Order{Kode: "OWN-1"}, opts); err != nil {
+			return err
+		}
+		if inv.count() != 1 {
+			t.Fatalf("%d invalidations inside db.Transaction, want 1", inv.count())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// setelah Transact selesai, db yang sama tidak lagi dianggap di dalam transaksi
+	if err := Transact(context.Background(), db, func(*Tx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateGeneric(context.Background(), db, &Order{Kode: "OWN-2"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	if inv.count() != 2 {
+		t.Fatalf("%d invalidations, want 2", inv.count())
+	}
+}
+
+func TestInvalidationErrors(t *testing.T) {
+	errCache := errors.New("redis down")
+	failing := InvalidatorFunc(func(context.Context, Invalidation) error { return errCache })
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	// tanpa OnInvalidateError: satu baris error di WriteOptions.Logger, write tetap berhasil
+	db := newTestDB(t)
+	if _, err := CreateGeneric(context.Background(), db, &Order{Kode: "INV-1"}, WriteOptions{Invalidator: failing, Logger: logger}); err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+	for _, want := range []string{"level=ERROR", `msg="magicrest: cache invalidation failed"`, "model=Order", "redis down"} {
+		if !strings.Contains(line, want) || strings.Count(line, "\n") != 1 {
+			t.Fatalf("log %q: want one line with %s", line, want)
+		}
+	}
+
+	// OnInvalidateError menggantikan Logger
+	buf.Reset()
+	var got error
+	opts := WriteOptions{Invalidator: failing, Logger: logger, OnInvalidateError: func(_ context.Context, err error) { got = err }}
+	if _, err := CreateGeneric(context.Background(), db, &Order{Kode: "INV-2"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(got, errCache) || buf.Len() > 0 {
+		t.Fatalf("OnInvalidateError got %v, log %q", got, buf.String())
+	}
+}
@@ -0,0 +1,162 @@
+package magicrest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// UpsertGeneric meng-insert payload atau, bila bentrok pada conflictColumns (boleh komposit, harus punya
+// unique index), meng-update updateColumns (nil = semua kolom yang boleh ditulis selain conflictColumns,
+// kosong = DO NOTHING). Mengembalikan row terbaru dan apakah row baru di-insert: di Postgres dari
+// RETURNING (xmax = 0), di dialect lain dari pengecekan sebelum upsert dalam transaksi yang sama.
+// Nama kolom divalidasi terhadap schema (ErrInvalidConfig).
+func UpsertGeneric[T any](ctx context.Context, db *gorm.DB, payload *T, conflictColumns []string, updateColumns []string, opts WriteOptions) (out *T, created bool, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpUpsert)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	sch, err := parseSchema(db, payload)
+	if err != nil {
+		return nil, false, err
+	}
+	conflict, err := upsertColumns(sch, "conflictColumns", conflictColumns)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(conflict) == 0 {
+		return nil, false, fmt.Errorf("%w: conflictColumns is empty", ErrInvalidConfig)
+	}
+	var update []string
+	if updateColumns == nil {
+		cols, err := writableColumns(sch, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, c := range cols {
+			if !containsString(conflict, c) {
+				update = append(update, c)
+			}
+		}
+	} else if update, err = upsertColumns(sch, "updateColumns", updateColumns); err != nil {
+		return nil, false, err
+	}
+	if err := setAuditOnCreate(ctx, db, payload, opts); err != nil {
+		return nil, false, err
+	}
+	if len(update) > 0 {
+		// updated_by dan updated_at ikut di-update bila row sudah ada; created_by tetap milik insert pertama
+		audit, err := newAuditColumns(ctx, sch, opts, false)
+		if err != nil {
+			return nil, false, err
+		}
+		fields := audit.fields()
+		for _, f := range sch.Fields {
+			if f.AutoUpdateTime > 0 && isColumnField(f) {
+				fields = append(fields, f)
+			}
+		}
+		for _, f := range fields {
+			if !containsString(update, f.DBName) {
+				update = append(update, f.DBName)
+			}
+		}
+	}
+	if err := runValidation(ctx, db, payload, opts); err != nil {
+		return nil, false, err
+	}
+
+	onConflict := clause.OnConflict{DoNothing: len(update) == 0}
+	for _, c := range conflict {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: c})
+	}
+	if len(update) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(update)
+	}
+
+	// kondisi kolom konflik dari payload, untuk pre-check dan mengambil ulang row
+	rv := reflect.ValueOf(payload).Elem()
+	keyed := func(tx *gorm.DB) *gorm.DB {
+		for _, c := range conflict {
+			v, _ := sch.LookUpField(c).ValueOf(ctx, rv)
+			tx = tx.Where(db.Statement.Quote(sch.Table+"."+c)+" = ?", v)
+		}
+		return tx
+	}
+
+	var inserted bool
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			inserted, err = upsertReturningInserted(tx, payload, onConflict)
+			return err
+		}
+		var n int64
+		if err := keyed(tx.Model(new(T))).Count(&n).Error; err != nil {
+			return err
+		}
+		inserted = n == 0
+		return tx.Clauses(onConflict).Create(payload).Error
+	})
+	if err != nil {
+		return nil, false, translateWriteError(err)
+	}
+
+	tx := keyed(db.Model(new(T)))
+	for _, scope := range opts.Scopes {
+		tx = scope(tx)
+	}
+	for _, p := range opts.PreloadFields {
+		tx = tx.Preload(p)
+	}
+	out = new(T)
+	if err := tx.First(out).Error; err != nil {
+		invalidateWrite[T](ctx, db, opts)
+		return nil, false, err
+	}
+	invalidateItems(ctx, db, opts, []T{*out})
+	return out, inserted, nil
+}
+
+// upsertReturningInserted menjalankan INSERT ... ON CONFLICT ... RETURNING (xmax = 0) di Postgres.
+// SQL disusun dengan DryRun lalu dieksekusi langsung karena gorm membuang kolom RETURNING yang bukan
+// field model. Tanpa baris hasil (DO NOTHING) berarti tidak ada yang di-insert.
+func upsertReturningInserted(tx *gorm.DB, payload interface{}, onConflict clause.OnConflict) (bool, error) {
+	stmt := tx.Session(&gorm.Session{DryRun: true}).Clauses(onConflict, clause.Returning{
+		Columns: []clause.Column{{Name: "(xmax = 0) AS magicrest_inserted", Raw: true}},
+	}).Create(payload).Statement
+	if stmt.Error != nil {
+		return false, stmt.Error
+	}
+	rows, err := tx.Statement.ConnPool.QueryContext(tx.Statement.Context, stmt.SQL.String(), stmt.Vars...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	var inserted bool
+	if err := rows.Scan(&inserted); err != nil {
+		return false, err
+	}
+	return inserted, rows.Err()
+}
+
+// upsertColumns me-resolve nama kolom konflik / update ke nama kolom DB
+func upsertColumns(sch *schema.Schema, option string, names []string) ([]string, error) {
+	var cols []string
+	for _, name := range names {
+		f := sch.LookUpField(name)
+		if !isColumnField(f) {
+			return nil, fmt.Errorf("%w: %s: unknown column %q on %s", ErrInvalidConfig, option, name, sch.Name)
+		}
+		cols = append(cols, f.DBName)
+	}
+	return cols, nil
+}
@@ -0,0 +1,87 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Persediaan: unique komposit (gudang_id, produk_id)
+type Persediaan struct {
+	ID       uint   `json:"id"`
+	GudangID uint   `json:"gudang_id" gorm:"uniqueIndex:idx_stok"`
+	ProdukID uint   `json:"produk_id" gorm:"uniqueIndex:idx_stok"`
+	Jumlah   int    `json:"jumlah"`
+	Catatan  string `json:"catatan"`
+}
+
+func TestUpsertGeneric(t *testing.T) {
+	cases := []struct {
+		name     string
+		payload  Persediaan
+		update   []string
+		inserted bool
+		want     string // gudang/produk/jumlah/catatan per row, urut id
+	}{
+		{"insert", Persediaan{GudangID: 1, ProdukID: 2, Jumlah: 5}, nil, true, "[1/1/10/awal 1/2/5/]"},
+		{"update listed columns", Persediaan{GudangID: 1, ProdukID: 1, Jumlah: 7, Catatan: "baru"}, []string{"jumlah"}, false, "[1/1/7/awal]"},
+		{"update all writable", Persediaan{GudangID: 1, ProdukID: 1, Jumlah: 7, Catatan: "baru"}, nil, false, "[1/1/7/baru]"},
+		{"do nothing", Persediaan{GudangID: 1, ProdukID: 1, Jumlah: 7}, []string{}, false, "[1/1/10/awal]"},
+		{"composite key differs", Persediaan{GudangID: 2, ProdukID: 1, Jumlah: 3}, []string{"jumlah"}, true, "[1/1/10/awal 2/1/3/]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if err := db.AutoMigrate(&Persediaan{}); err != nil {
+				t.Fatal(err)
+			}
+			db.Create(&Persediaan{GudangID: 1, ProdukID: 1, Jumlah: 10, Catatan: "awal"})
+			out, inserted, err := UpsertGeneric(context.Background(), db, &tc.payload, []string{"gudang_id", "ProdukID"}, tc.update, WriteOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inserted != tc.inserted || out.GudangID != tc.payload.GudangID || out.ProdukID != tc.payload.ProdukID || out.ID == 0 {
+				t.Fatalf("inserted %v, row %+v", inserted, out)
+			}
+			var rows []Persediaan
+			db.Order("id").Find(&rows)
+			var got []string
+			for _, s := range rows {
+				got = append(got, fmt.Sprintf("%d/%d/%d/%s", s.GudangID, s.ProdukID, s.Jumlah, s.Catatan))
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("rows %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpsertGenericInvalidColumns(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Persediaan{}); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name     string
+		conflict []string
+		update   []string
+	}{
+		{"no conflict columns", nil, nil},
+		{"unknown conflict column", []string{"gudang_id", "rak"}, nil},
+		{"unknown update column", []string{"gudang_id", "produk_id"}, []string{"harga"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := UpsertGeneric(context.Background(), db, &Persediaan{GudangID: 1, ProdukID: 1}, tc.conflict, tc.update, WriteOptions{})
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("err = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+	var n int64
+	db.Model(&Persediaan{}).Count(&n)
+	if n != 0 {
+		t.Fatalf("%d rows written", n)
+	}
+}
@@ -0,0 +1,127 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ErrValidation membungkus error dari Validatable, ValidatableWithDB dan WriteOptions.Validate
+var ErrValidation = errors.New("validation failed")
+
+// Validatable: model yang memvalidasi dirinya sendiri; dipanggil helper tulis setelah payload di-bind
+// dan sebelum menulis.
+type Validatable interface {
+	Validate(ctx context.Context) error
+}
+
+// ValidatableWithDB: seperti Validatable untuk pengecekan yang butuh query (mis. keunikan).
+type ValidatableWithDB interface {
+	ValidateWithDB(ctx context.Context, db *gorm.DB) error
+}
+
+// DetailedError: error validasi yang membawa detail per field; DetailsFromError memakainya apa adanya.
+type DetailedError interface {
+	error
+	ValidationDetails() []ValidationDetail
+}
+
+// ValidationErrors: implementasi DetailedError siap pakai untuk Validate(), e.g.
+// return magicrest.ValidationErrors{{Field: "nama", Code: "required", Message: "nama wajib diisi"}}
+type ValidationErrors []ValidationDetail
+
+func (v ValidationErrors) Error() string {
+	parts := make([]string, len(v))
+	for i, d := range v {
+		msg := d.Message
+		if msg == "" {
+			msg = d.Code
+		}
+		if d.Field != "" {
+			msg = d.Field + ": " + msg
+		}
+		parts[i] = msg
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (v ValidationErrors) ValidationDetails() []ValidationDetail { return v }
+
+// runValidation menjalankan WriteOptions.Validator, Validatable, ValidatableWithDB lalu WriteOptions.Validate
+// pada item (pointer ke T). Error dibungkus ErrValidation (400) agar bisa dibedakan dari error database.
+func runValidation(ctx context.Context, db *gorm.DB, item any, opts WriteOptions) error {
+	var err error
+	if opts.Validator != nil {
+		err = translateValidator(item, opts.Validator.StructCtx(ctx, item))
+	}
+	if v, ok := item.(Validatable); ok && err == nil {
+		err = v.Validate(ctx)
+	}
+	if v, ok := item.(ValidatableWithDB); ok && err == nil {
+		err = v.ValidateWithDB(ctx, db)
+	}
+	if opts.Validate != nil && err == nil {
+		err = opts.Validate(ctx, db, item)
+	}
+	if err == nil || errors.Is(err, ErrValidation) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrValidation, err)
+}
+
+// translateValidator mengubah validator.ValidationErrors menjadi ValidationErrors dengan nama field dari
+// tag json (path bertitik untuk struct bersarang, e.g. "items[0].nama"); Message diisi dari katalog locale.
+func translateValidator(item any, err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	root := reflect.TypeOf(item)
+	out := make(ValidationErrors, 0, len(verrs))
+	for _, fe := range verrs {
+		d := ValidationDetail{Field: jsonPath(root, fe.StructNamespace()), Code: fe.Tag(), Param: fe.Param()}
+		if v := fe.Value(); v != nil && !reflect.ValueOf(v).IsZero() {
+			d.Value = fmt.Sprint(v)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// jsonPath mengubah namespace validator "Barang.Items[0].NamaBarang" menjadi "items[0].nama_barang"
+// memakai tag json tiap field (segmen yang tidak ditemukan dibiarkan apa adanya).
+func jsonPath(t reflect.Type, namespace string) string {
+	segs := strings.Split(namespace, ".")
+	if len(segs) > 1 {
+		segs = segs[1:] // nama tipe root
+	}
+	for i, seg := range segs {
+		name, index := seg, ""
+		if j := strings.IndexByte(seg, '['); j >= 0 {
+			name, index = seg[:j], seg[j:]
+		}
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			t = nil
+			continue
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			t = nil
+			continue
+		}
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+		segs[i] = name + index
+		t = f.Type
+	}
+	return strings.Join(segs, ".")
+}
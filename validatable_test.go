@@ -0,0 +1,230 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Barang: Validatable (nama wajib, harga tidak negatif) dan ValidatableWithDB (kode unik)
+type Barang struct {
+	ID    uint   `json:"id"`
+	Kode  string `json:"kode"`
+	Nama  string `json:"nama"`
+	Harga int    `json:"harga"`
+}
+
+func (b *Barang) Validate(context.Context) error {
+	var errs ValidationErrors
+	if b.Nama == "" {
+		errs = append(errs, ValidationDetail{Field: "nama", Code: "required"})
+	}
+	if b.Harga < 0 {
+		errs = append(errs, ValidationDetail{Field: "harga", Code: "min", Param: "0", Message: "harga tidak boleh negatif"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (b *Barang) ValidateWithDB(_ context.Context, db *gorm.DB) error {
+	var n int64
+	db.Model(&Barang{}).Where("kode = ? AND id <> ?", b.Kode, b.ID).Count(&n)
+	if n > 0 {
+		return ValidationErrors{{Field: "kode", Code: "unique", Value: b.Kode}}
+	}
+	return nil
+}
+
+func newBarangDB(t *testing.T) (*gorm.DB, Barang) {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Barang{}); err != nil {
+		t.Fatal(err)
+	}
+	b := Barang{Kode: "BRG-1", Nama: "Semen", Harga: 50}
+	db.Create(&b)
+	db.Create(&Barang{Kode: "BRG-2", Nama: "Pasir", Harga: 20})
+	return db, b
+}
+
+func TestWriteHelpersRunValidatable(t *testing.T) {
+	type write func(db *gorm.DB, id uint) error
+	create := func(b Barang) write {
+		return func(db *gorm.DB, _ uint) error {
+			_, err := CreateGeneric(context.Background(), db, &b, WriteOptions{})
+			return err
+		}
+	}
+	update := func(b Barang) write {
+		return func(db *gorm.DB, id uint) error {
+			_, err := UpdateGeneric(context.Background(), db, id, &b, WriteOptions{})
+			return err
+		}
+	}
+	patch := func(p map[string]interface{}) write {
+		return func(db *gorm.DB, id uint) error {
+			_, err := PatchGeneric[Barang](context.Background(), db, id, p, WriteOptions{})
+			return err
+		}
+	}
+	cases := []struct {
+		name    string
+		write   write
+		details []string // "field/code", nil = berhasil
+	}{
+		{"create", create(Barang{Kode: "BRG-3", Nama: "Bata", Harga: 5}), nil},
+		{"create missing nama", create(Barang{Kode: "BRG-3", Harga: -1}), []string{"nama/required", "harga/min"}},
+		{"create duplicate kode", create(Barang{Kode: "BRG-2", Nama: "Bata"}), []string{"kode/unique"}},
+		{"update", update(Barang{Kode: "BRG-1", Nama: "Semen Putih", Harga: 60}), nil},
+		{"update missing nama", update(Barang{Kode: "BRG-1", Harga: 60}), []string{"nama/required"}},
+		{"update duplicate kode", update(Barang{Kode: "BRG-2", Nama: "Semen"}), []string{"kode/unique"}},
+		{"patch", patch(map[string]interface{}{"harga": 70}), nil},
+		{"patch negative harga", patch(map[string]interface{}{"harga": -5}), []string{"harga/min"}},
+		{"patch empty nama", patch(map[string]interface{}{"nama": ""}), []string{"nama/required"}},
+		{"patch duplicate kode", patch(map[string]interface{}{"kode": "BRG-2"}), []string{"kode/unique"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, b := newBarangDB(t)
+			rdb, rec := recordSQL(db)
+			err := tc.write(rdb, b.ID)
+			if tc.details == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || StatusForError(err) != 400 {
+				t.Fatalf("err = %v, want ErrValidation (400)", err)
+			}
+			var got []string
+			for _, d := range DetailsFromError(err) {
+				if d.Message == "" {
+					t.Fatalf("detail without message: %+v", d)
+				}
+				got = append(got, d.Field+"/"+d.Code)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.details) {
+				t.Fatalf("details %v, want %v", got, tc.details)
+			}
+			for _, sql := range rec.statements() {
+				// hanya baca (row lama, cek keunikan), tidak ada INSERT / UPDATE
+				if !strings.HasPrefix(sql, "SELECT") {
+					t.Fatalf("write after failed validation: %s", sql)
+				}
+			}
+			var stored []Barang
+			db.Order("id").Find(&stored)
+			if len(stored) != 2 || stored[0] != b {
+				t.Fatalf("rows changed: %+v", stored)
+			}
+		})
+	}
+}
+
+func TestValidationOrder(t *testing.T) {
+	db, b := newBarangDB(t)
+	var calls []string
+	opts := WriteOptions{Validate: func(context.Context, *gorm.DB, any) error {
+		calls = append(calls, "options")
+		return errors.New("stok habis")
+	}}
+	// Validatable gagal: ValidatableWithDB dan WriteOptions.Validate tidak dipanggil
+	if _, err := CreateGeneric(context.Background(), db, &Barang{Kode: "BRG-2"}, opts); len(DetailsFromError(err)) != 1 || len(calls) != 0 {
+		t.Fatalf("err = %v, calls %v", err, calls)
+	}
+	// model valid: WriteOptions.Validate terakhir, error biasa tetap dibungkus ErrValidation
+	_, err := PatchGeneric[Barang](context.Background(), db, b.ID, map[string]interface{}{"harga": 1}, opts)
+	if !errors.Is(err, ErrValidation) || len(calls) != 1 {
+		t.Fatalf("err = %v, calls %v", err, calls)
+	}
+}
+
+// Pengiriman: tag validate (bersarang lewat dive) dan aturan struct-level berat_gudang
+type Pengiriman struct {
+	ID     uint         `json:"id"`
+	Tujuan string       `json:"tujuan" validate:"required,max=10"`
+	Berat  int          `json:"berat_kg" validate:"gte=1"`
+	Baris  []BarisKirim `json:"baris" validate:"dive" gorm:"-"`
+}
+
+type BarisKirim struct {
+	NamaBarang string `json:"nama_barang" validate:"required"`
+}
+
+func TestWriteHelpersRunValidator(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&Pengiriman{}); err != nil {
+		t.Fatal(err)
+	}
+	v := validator.New()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		if p := sl.Current().Interface().(Pengiriman); p.Tujuan == "Gudang" && p.Berat > 100 {
+			sl.ReportError(p.Berat, "Berat", "Berat", "berat_gudang", "")
+		}
+	}, Pengiriman{})
+	opts := WriteOptions{Validator: v}
+	cases := []struct {
+		name    string
+		item    Pengiriman
+		details string // field/code/param/value: message
+	}{
+		{"valid", Pengiriman{Tujuan: "Bandung", Berat: 5, Baris: []BarisKirim{{NamaBarang: "Semen"}}}, ""},
+		{"tags", Pengiriman{Tujuan: "Kota Bandung Barat"}, "[tujuan/max/10/Kota Bandung Barat: tujuan must be at most 10 berat_kg/gte/1/: berat_kg must be at least 1]"},
+		{"nested dive", Pengiriman{Tujuan: "Bogor", Berat: 1, Baris: []BarisKirim{{NamaBarang: "Pasir"}, {}}},
+			"[baris[1].nama_barang/required//: baris[1].nama_barang is required]"},
+		{"struct level", Pengiriman{Tujuan: "Gudang", Berat: 150}, "[berat_kg/berat_gudang//150: berat_kg is invalid]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CreateGeneric(context.Background(), db, &tc.item, opts)
+			if tc.details == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || StatusForError(err) != 400 {
+				t.Fatalf("err = %v, want ErrValidation (400)", err)
+			}
+			var got []string
+			for _, d := range DetailsFromError(err) {
+				got = append(got, fmt.Sprintf("%s/%s/%s/%s: %s", d.Field, d.Code, d.Param, d.Value, d.Message))
+			}
+			if fmt.Sprint(got) != tc.details {
+				t.Fatalf("details %v, want %s", got, tc.details)
+			}
+		})
+	}
+	var n int64
+	db.Model(&Pengiriman{}).Count(&n)
+	if n != 1 {
+		t.Fatalf("%d rows stored, want only the valid one", n)
+	}
+
+	// pesan dari katalog locale, {param} ikut diisi
+	_, err := CreateGeneric(context.Background(), db, &Pengiriman{Tujuan: "Kota Bandung Barat", Berat: 1}, opts)
+	if got := DetailsFromErrorLocale(err, "id"); len(got) != 1 || got[0].Message != "tujuan maksimal 10" {
+		t.Fatalf("id details %+v", got)
+	}
+}
+
+func TestValidatorRunsFirst(t *testing.T) {
+	db, _ := newBarangDB(t)
+	// Validator gagal: Validatable (nama wajib) tidak ikut dilaporkan
+	v := validator.New()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		sl.ReportError(sl.Current().Interface().(Barang).Kode, "Kode", "Kode", "startswith", "BRG-")
+	}, Barang{})
+	_, err := CreateGeneric(context.Background(), db, &Barang{Kode: "X-1"}, WriteOptions{Validator: v})
+	if got := DetailsFromError(err); len(got) != 1 || got[0].Field != "kode" || got[0].Param != "BRG-" {
+		t.Fatalf("details %+v", got)
+	}
+}
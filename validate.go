@@ -0,0 +1,138 @@
+package magicrest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidConfig digunakan Options.Validate untuk setiap konfigurasi yang tidak cocok dengan model
+var ErrInvalidConfig = errors.New("invalid options")
+
+// Validate mem-parse schema gorm modelPtr lalu mencocokkan semua kolom, relasi dan ekspresi yang
+// dikonfigurasi di Options. Semua masalah dikembalikan sekaligus (errors.Join) sehingga bisa
+// dipanggil di init atau unit test, bukan meledak sebagai error database saat request.
+func (o Options) Validate(modelPtr any) error {
+	sch, err := parseModelSchema(modelPtr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	var errs []error
+	report := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...))
+	}
+	column := func(option, name string) {
+		if !configColumnExists(sch, name, o.ComputedColumns) {
+			report("%s: unknown column %q on %s", option, name, sch.Name)
+		}
+	}
+	relation := func(option, path string) *schema.Relationship {
+		rel, err := resolvePreloadPath(sch, path)
+		if err != nil {
+			report("%s: unknown relation %q on %s", option, path, sch.Name)
+		}
+		return rel
+	}
+
+	if o.SearchField != "" {
+		column("SearchField", o.SearchField)
+	}
+	for _, f := range o.SearchFields {
+		column("SearchFields", f)
+	}
+	if err := checkOrder("order", o.OrderBy, o.ComputedColumns, nil); err != nil {
+		report("OrderBy: %v", err)
+	} else {
+		for _, item := range parseSort(o.OrderBy) {
+			column("OrderBy", item.Field)
+		}
+	}
+	for _, f := range o.AllowedOrderFields {
+		column("AllowedOrderFields", f)
+	}
+	for _, f := range o.AllowedFilterFields {
+		column("AllowedFilterFields", f)
+	}
+	for _, p := range o.PreloadFields {
+		relation("PreloadFields", p)
+	}
+	for _, p := range o.AllowedPreloads {
+		relation("AllowedPreloads", p)
+	}
+	for path, cols := range o.PreloadSelects {
+		if rel := relation("PreloadSelects", path); rel != nil {
+			for _, c := range cols {
+				if f := rel.FieldSchema.LookUpField(c); !isColumnField(f) {
+					report("PreloadSelects: unknown column %q on %s", c, path)
+				}
+			}
+		}
+	}
+	for path, limit := range o.PreloadLimits {
+		if rel := relation("PreloadLimits", path); rel != nil && limit <= 0 {
+			report("PreloadLimits: limit for %s must be positive", path)
+		}
+	}
+	for path, strategy := range o.PreloadStrategy {
+		if relation("PreloadStrategy", path) != nil && strategy == StrategyJoin && !joinable(sch, path) {
+			report("PreloadStrategy: %s is not a belongs-to/has-one relation and cannot be joined", path)
+		}
+	}
+	for _, n := range o.WithCounts {
+		rel, ok := sch.Relationships.Relations[n]
+		if !ok {
+			report("WithCounts: unknown relation %q on %s", n, sch.Name)
+		} else if rel.Type == schema.BelongsTo {
+			report("WithCounts: %s is a belongs-to relation", n)
+		}
+	}
+	for _, f := range o.DistinctFields {
+		column("DistinctFields", f)
+	}
+	for _, f := range o.SelectableColumns {
+		column("SelectableColumns", f)
+	}
+	if err := checkComputedColumns(sch, o.ComputedColumns); err != nil {
+		report("ComputedColumns: %v", err)
+	}
+	for field, t := range o.DefaultFieldTypes {
+		if !validFieldType(t) {
+			report("DefaultFieldTypes: unknown type %q for %s", t, field)
+		}
+	}
+	if err := o.checkConflicts(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkResultCacheKey(o); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// configColumnExists mengecek nama kolom dari konfigurasi: kolom model, alias computed, atau
+// "relasi.kolom" / "tabel.kolom". Prefix yang tidak dikenal (alias JOIN milik caller) tidak bisa
+// dicek dan dianggap valid.
+func configColumnExists(sch *schema.Schema, name string, computed map[string]string) bool {
+	if _, ok := computed[name]; ok {
+		return true
+	}
+	prefix, col, dotted := strings.Cut(name, ".")
+	if !dotted {
+		return isColumnField(sch.LookUpField(name))
+	}
+	if prefix == sch.Table {
+		return isColumnField(sch.LookUpField(col))
+	}
+	if (&joinRegistry{sch: sch}).known(name) {
+		return true // relasi bertingkat yang di-join otomatis, e.g. "Gudang.Kota.nama"
+	}
+	for relName, rel := range sch.Relationships.Relations {
+		if strings.EqualFold(relName, prefix) || rel.FieldSchema.Table == prefix {
+			return isColumnField(rel.FieldSchema.LookUpField(col))
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package magicrest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{
+		SearchFields:      []string{"kode", "Gudang.kode", "orders.telepon"},
+		OrderBy:           "created_at desc, id",
+		PreloadFields:     []string{"Gudang"},
+		AllowedPreloads:   []string{"Items", "Items.Produk"},
+		PreloadSelects:    map[string][]string{"Items": {"nama"}},
+		PreloadLimits:     map[string]int{"Items": 5},
+		PreloadStrategy:   map[string]Strategy{"Gudang": StrategyJoin},
+		WithCounts:        []string{"Items"},
+		AllowDistinct:     true,
+		DistinctFields:    []string{"status"},
+		ComputedColumns:   map[string]string{"kode_status": "kode || status"},
+		SelectableColumns: []string{"id", "kode", "kode_status"},
+		DefaultFieldTypes: map[string]string{"gudang_id": "int"},
+	}
+	if err := valid.Validate(&Order{}); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := Options{
+		SearchFields:      []string{"nama"},
+		OrderBy:           "dibuat desc",
+		PreloadFields:     []string{"Pelanggan"},
+		AllowedPreloads:   []string{"Items.Kategori"},
+		PreloadSelects:    map[string][]string{"Items": {"harga"}},
+		PreloadLimits:     map[string]int{"Items": 0},
+		PreloadStrategy:   map[string]Strategy{"Items": StrategyJoin},
+		WithCounts:        []string{"Gudang"},
+		DistinctFields:    []string{"status"},
+		SelectableColumns: []string{"alamat"},
+		DefaultFieldTypes: map[string]string{"jumlah": "angka"},
+	}
+	err := invalid.Validate(&Order{})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("err = %v, want ErrInvalidConfig", err)
+	}
+	// semua masalah dilaporkan sekaligus
+	for _, want := range []string{
+		`SearchFields: unknown column "nama" on Order`,
+		`OrderBy: unknown column "dibuat" on Order`,
+		`PreloadFields: unknown relation "Pelanggan" on Order`,
+		`AllowedPreloads: unknown relation "Items.Kategori" on Order`,
+		`PreloadSelects: unknown column "harga" on Items`,
+		"PreloadLimits: limit for Items must be positive",
+		"PreloadStrategy: Items is not a belongs-to/has-one relation and cannot be joined",
+		"WithCounts: Gudang is a belongs-to relation",
+		`SelectableColumns: unknown column "alamat" on Order`,
+		`DefaultFieldTypes: unknown type "angka" for jumlah`,
+		"DistinctFields is set but AllowDistinct is false",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+
+	if err := (Options{}).Validate("orders"); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("not a model: err = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,531 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrConflict digunakan bila insert/update melanggar unique constraint (lihat ConflictError)
+var ErrConflict = errors.New("conflict")
+
+// ErrInvalidPatch digunakan PatchGeneric untuk key yang tidak dikenal, tidak boleh ditulis atau nilainya salah tipe
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ErrInvalidBody digunakan handler bawaan bila body request bukan JSON yang bisa dibaca
+var ErrInvalidBody = errors.New("invalid request body")
+
+// ErrNotFound digunakan helper tulis/baca satu record bila row dengan id tersebut tidak ada
+var ErrNotFound = errors.New("not found")
+
+// ConflictError: ErrConflict dengan nama constraint dari database. SQLite tidak menyebut nama
+// constraint sehingga Constraint berisi kolomnya, e.g. "barangs.kode".
+type ConflictError struct {
+	Constraint string
+	Err        error // error asli dari driver
+}
+
+func (e *ConflictError) Error() string {
+	if e.Constraint == "" {
+		return ErrConflict.Error()
+	}
+	return ErrConflict.Error() + ": " + e.Constraint
+}
+
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+// ValidateFunc: validasi sebelum menulis; item adalah pointer ke T. Error = penulisan dibatalkan.
+type ValidateFunc func(ctx context.Context, db *gorm.DB, item any) error
+
+// WriteOptions: konfigurasi helper tulis (CreateGeneric, ...)
+type WriteOptions struct {
+	PreloadFields []string     // preload untuk row yang dikembalikan (samakan dengan Options.PreloadFields)
+	Scopes        []Scope      // tenant, default filter: diterapkan saat row diambil ulang
+	Validate      ValidateFunc // dipanggil sebelum insert/update (setelah Validatable / ValidatableWithDB model)
+
+	Validator *validator.Validate // tag `validate:"required,max=100"` dicek paling awal (nil = dilewati)
+
+	WritableColumns  []string // whitelist kolom yang boleh ditulis UpdateGeneric (nil = semua)
+	ProtectedColumns []string // kolom yang tidak pernah ditulis dari payload, e.g. "tenant_id" (PK dan created_at selalu)
+
+	VersionColumn   string    // optimistic locking: kolom versi integer, dicek di WHERE dan dinaikkan tiap update
+	UnmodifiedSince time.Time // optimistic locking: tolak bila row berubah setelah waktu ini (If-Unmodified-Since)
+	UpdatedAtColumn string    // kolom untuk UnmodifiedSince (default "updated_at")
+
+	Envelope EnvelopeConfig // key response handler tulis bawaan (ginrest.CreateHandler, ...), default {"data": ...}
+
+	ActorFromContext ActorFunc // kolom audit: id user dari context untuk created_by / updated_by (nil = tidak diisi)
+	CreatedByColumn  string    // default "created_by", diisi saat insert
+	UpdatedByColumn  string    // default "updated_by", diisi saat insert dan update
+
+	HardDelete    bool    // DeleteGeneric: hapus permanen (Unscoped) walau model punya gorm.DeletedAt
+	Preconditions []Scope // DeleteGeneric: syarat tambahan, e.g. status = 'draft'; row ada tapi tidak cocok -> ErrConflict
+
+	BatchSize  int        // BulkCreateGeneric: ukuran batch CreateInBatches (default 100)
+	BulkPolicy BulkPolicy // BulkAllOrNothing (default) atau BulkBestEffort
+
+	AllowDeleteAll bool  // bulk by query: izinkan tanpa filter/search (semua row dalam Scopes)
+	MaxAffected    int64 // bulk by query: batas jumlah row, dihitung dulu (0 = tanpa batas)
+	DryRun         bool  // bulk by query: hanya kembalikan jumlah row yang akan terkena
+
+	Invalidator       Invalidator                          // cache: dipanggil setelah write ter-commit, e.g. CacheInvalidator(cache)
+	InvalidateScope   InvalidateScope                      // InvalidateResource (default) atau InvalidateRows
+	OnInvalidateError func(ctx context.Context, err error) // error Invalidator (default: Logger); write tetap berhasil
+	Logger            *slog.Logger                         // error Invalidator tanpa OnInvalidateError (nil = slog.Default())
+
+	Metrics Recorder // durasi per operasi write dan penolakan (validasi, konflik), nil = DefaultMetrics
+}
+
+// uniqueViolation: pola pesan unique violation per driver (nama constraint di group 1)
+var uniqueViolation = []*regexp.Regexp{
+	regexp.MustCompile(`unique constraint "([^"]+)"`),                       // postgres
+	regexp.MustCompile(`Duplicate entry .* for key '([^']+)'`),              // mysql
+	regexp.MustCompile(`UNIQUE constraint failed: ([^\s,]+(?:, [^\s,]+)*)`), // sqlite
+	regexp.MustCompile(`(?:UNIQUE KEY constraint|unique index) '([^']+)'`),  // sqlserver
+}
+
+// translateWriteError mengubah unique violation dari driver menjadi *ConflictError
+func translateWriteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, re := range uniqueViolation {
+		if m := re.FindStringSubmatch(err.Error()); m != nil {
+			return &ConflictError{Constraint: m[1], Err: err}
+		}
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return &ConflictError{Err: err}
+	}
+	return err
+}
+
+// CreateGeneric mengisi kolom audit (WriteOptions.ActorFromContext), menjalankan validasi, meng-insert payload, lalu mengambil ulang row
+// dengan WriteOptions.PreloadFields sehingga response sama dengan GET berikutnya.
+// Unique violation dikembalikan sebagai *ConflictError (errors.Is(err, ErrConflict)).
+func CreateGeneric[T any](ctx context.Context, db *gorm.DB, payload *T, opts WriteOptions) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpCreate)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	if err := setAuditOnCreate(ctx, db, payload, opts); err != nil {
+		return nil, err
+	}
+	if err := runValidation(ctx, db, payload, opts); err != nil {
+		return nil, err
+	}
+	if err := db.Create(payload).Error; err != nil {
+		return nil, translateWriteError(err)
+	}
+	invalidateItems(ctx, db, opts, []T{*payload})
+	return refetch(db, payload, opts)
+}
+
+// refetch mengambil ulang row berdasarkan primary key item, dengan scopes dan preload
+func refetch[T any](db *gorm.DB, item *T, opts WriteOptions) (*T, error) {
+	sch, err := parseSchema(db, item)
+	if err != nil {
+		return nil, err
+	}
+	if len(sch.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("model %s has no primary key", sch.Name)
+	}
+	tx := db.Model(new(T))
+	rv := reflect.ValueOf(item).Elem()
+	for _, pk := range sch.PrimaryFields {
+		v, zero := pk.ValueOf(db.Statement.Context, rv)
+		if zero {
+			return nil, fmt.Errorf("model %s: primary key %s is empty after write", sch.Name, pk.Name)
+		}
+		tx = tx.Where(db.Statement.Quote(sch.Table+"."+pk.DBName)+" = ?", v)
+	}
+	for _, scope := range opts.Scopes {
+		tx = scope(tx)
+	}
+	for _, p := range opts.PreloadFields {
+		tx = tx.Preload(p)
+	}
+	out := new(T)
+	if err := tx.First(out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateGeneric mengganti seluruh kolom yang boleh ditulis pada row id dengan nilai payload (full replace:
+// nilai zero ikut ditulis, berbeda dengan Updates gorm biasa). Primary key, created_at dan
+// WriteOptions.ProtectedColumns tidak pernah ditimpa. Row yang tidak ada (termasuk di luar Scopes)
+// menghasilkan ErrNotFound. Dengan WriteOptions.VersionColumn versi di payload harus sama dengan di database
+// (lalu dinaikkan), dengan UnmodifiedSince row tidak boleh berubah sejak waktu itu; gagal -> ErrStaleRecord.
+// Mengembalikan row terbaru dengan WriteOptions.PreloadFields.
+func UpdateGeneric[T any](ctx context.Context, db *gorm.DB, id any, payload *T, opts WriteOptions) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpUpdate)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	sch, err := parseSchema(db, payload)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := findByPK[T](db, sch, id, opts.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := writableColumns(sch, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// primary key dari row yang ada, bukan dari payload
+	rv, ev := reflect.ValueOf(payload).Elem(), reflect.ValueOf(existing).Elem()
+	for _, pk := range sch.PrimaryFields {
+		v, _ := pk.ValueOf(ctx, ev)
+		if err := pk.Set(ctx, rv, v); err != nil {
+			return nil, err
+		}
+	}
+	lock, err := newLockPrecondition(sch, opts)
+	if err != nil {
+		return nil, err
+	}
+	if lock.version != nil {
+		if err := lock.setVersion(ctx, rv); err != nil {
+			return nil, err
+		}
+		cols = append(cols, lock.version.DBName)
+	}
+	audit, err := newAuditColumns(ctx, sch, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	if createdBy, _ := auditFields(sch, opts); createdBy != nil {
+		// created_by tidak ditulis; samakan payload dengan row yang ada untuk validasi
+		v, _ := createdBy.ValueOf(ctx, ev)
+		if err := createdBy.Set(ctx, rv, v); err != nil {
+			return nil, err
+		}
+	}
+	auditCols, err := audit.set(ctx, rv)
+	if err != nil {
+		return nil, err
+	}
+	cols = append(cols, auditCols...)
+	if err := runValidation(ctx, db, payload, opts); err != nil {
+		return nil, err
+	}
+	res := lock.apply(db.Model(existing), sch).Select(cols).Omit(clause.Associations).Updates(payload)
+	if res.Error != nil {
+		return nil, translateWriteError(res.Error)
+	}
+	if lock.active() && res.RowsAffected == 0 {
+		return nil, staleError(sch, id)
+	}
+	invalidateItems(ctx, db, opts, []T{*existing})
+	return refetch(db, existing, opts)
+}
+
+// findByPK mengambil row dengan primary key id (model dengan satu primary key), dengan scopes.
+// id string (dari URL) divalidasi terhadap tipe PK seperti ReadOne (ErrInvalidID).
+func findByPK[T any](db *gorm.DB, sch *schema.Schema, id any, scopes []Scope) (*T, error) {
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, fmt.Errorf("model %s has no single primary key", sch.Name)
+	}
+	id, err := parseID(pk, id)
+	if err != nil {
+		return nil, err
+	}
+	tx := db.Model(new(T)).Where(db.Statement.Quote(sch.Table+"."+pk.DBName)+" = ?", id)
+	for _, scope := range scopes {
+		tx = scope(tx)
+	}
+	out := new(T)
+	if err := tx.First(out).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s %v", ErrNotFound, sch.Name, id)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// writableColumns: kolom yang boleh ditulis dari payload — kolom model yang updatable, tanpa primary key,
+// kolom autoCreateTime / autoUpdateTime (diisi gorm, bukan klien: updated_at dipakai UnmodifiedSince) dan
+// ProtectedColumns, dibatasi WritableColumns bila di-set.
+func writableColumns(sch *schema.Schema, opts WriteOptions) ([]string, error) {
+	resolve := func(option string, names []string) (map[string]bool, error) {
+		if names == nil {
+			return nil, nil
+		}
+		out := map[string]bool{}
+		for _, name := range names {
+			f := sch.LookUpField(name)
+			if !isColumnField(f) {
+				return nil, fmt.Errorf("%w: %s: unknown column %q on %s", ErrInvalidConfig, option, name, sch.Name)
+			}
+			out[f.DBName] = true
+		}
+		return out, nil
+	}
+	allowed, err := resolve("WritableColumns", opts.WritableColumns)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := resolve("ProtectedColumns", opts.ProtectedColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := managedColumns(sch, opts)
+
+	var cols []string
+	for _, f := range sch.Fields {
+		if !isColumnField(f) || !f.Updatable || f.PrimaryKey || f.AutoCreateTime > 0 || f.AutoUpdateTime > 0 || protected[f.DBName] || managed[f] {
+			continue
+		}
+		if allowed != nil && !allowed[f.DBName] {
+			continue
+		}
+		cols = append(cols, f.DBName)
+	}
+	return cols, nil
+}
+
+// managedColumns: kolom yang diisi helper sendiri (VersionColumn, kolom audit), tidak pernah dari payload
+func managedColumns(sch *schema.Schema, opts WriteOptions) map[*schema.Field]bool {
+	out := map[*schema.Field]bool{}
+	if opts.VersionColumn != "" {
+		if f := sch.LookUpField(opts.VersionColumn); f != nil {
+			out[f] = true
+		}
+	}
+	createdBy, updatedBy := auditFields(sch, opts)
+	for _, f := range []*schema.Field{createdBy, updatedBy} {
+		if f != nil {
+			out[f] = true
+		}
+	}
+	return out
+}
+
+// PatchGeneric mengubah sebagian kolom row id dari objek JSON (key = nama json, field Go atau kolom DB).
+// Setiap key dicek terhadap schema dan kolom yang boleh ditulis (WritableColumns / ProtectedColumns), nilainya
+// di-parse dengan tipe yang sama seperti filter (uuid, int, bool, date). Semua key yang salah dilaporkan sekaligus
+// sebagai *QueryError (ErrInvalidPatch) yang bisa dibongkar DetailsFromError. Dengan WriteOptions.VersionColumn
+// patch wajib berisi versi saat ini (dinaikkan oleh update); precondition gagal -> ErrStaleRecord. Mengembalikan row terbaru.
+func PatchGeneric[T any](ctx context.Context, db *gorm.DB, id any, patch map[string]interface{}, opts WriteOptions) (out *T, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpPatch)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return nil, err
+	}
+	lock, err := newLockPrecondition(sch, opts)
+	if err != nil {
+		return nil, err
+	}
+	if lock.version != nil {
+		if patch, err = lock.patchVersion(sch, patch); err != nil {
+			return nil, err
+		}
+	}
+	updates, fields, err := patchColumns(sch, patch, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := findByPK[T](db, sch, id, opts.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 && !lock.active() {
+		return refetch(db, existing, opts)
+	}
+	audit, err := newAuditColumns(ctx, sch, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	// validasi melihat row setelah patch diterapkan
+	merged := *existing
+	rv := reflect.ValueOf(&merged).Elem()
+	for col, v := range updates {
+		if err := fields[col].Set(ctx, rv, v); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := audit.set(ctx, rv); err != nil {
+		return nil, err
+	}
+	if err := runValidation(ctx, db, &merged, opts); err != nil {
+		return nil, err
+	}
+	audit.assign(updates)
+	if lock.version != nil {
+		updates[lock.version.DBName] = gorm.Expr(db.Statement.Quote(lock.version.DBName)+" + ?", 1)
+	}
+	res := lock.apply(db.Model(existing), sch).Omit(clause.Associations).Updates(updates)
+	if res.Error != nil {
+		return nil, translateWriteError(res.Error)
+	}
+	if lock.active() && res.RowsAffected == 0 {
+		return nil, staleError(sch, id)
+	}
+	invalidateItems(ctx, db, opts, []T{*existing})
+	return refetch(db, existing, opts)
+}
+
+// patchColumns memvalidasi key patch terhadap schema dan kolom yang boleh ditulis lalu mem-parse nilainya.
+// Hasil: nilai per kolom DB dan field-nya; semua key yang salah digabung dalam satu error (ErrInvalidPatch).
+func patchColumns(sch *schema.Schema, patch map[string]interface{}, opts WriteOptions) (map[string]interface{}, map[string]*schema.Field, error) {
+	cols, err := writableColumns(sch, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	writable := map[string]bool{}
+	for _, c := range cols {
+		writable[c] = true
+	}
+
+	updates := map[string]interface{}{}
+	fields := map[string]*schema.Field{}
+	var invalid []*QueryError
+	for key, raw := range patch {
+		f := lookUpPatchField(sch, key)
+		switch {
+		case !isColumnField(f):
+			invalid = append(invalid, &QueryError{Kind: ErrInvalidPatch, Param: key, Reason: "is not a field", Code: "unknown_field"})
+		case !writable[f.DBName]:
+			invalid = append(invalid, &QueryError{Kind: ErrInvalidPatch, Param: key, Reason: "is not writable", Code: "protected_field"})
+		default:
+			v, err := patchValue(schemaFieldType(f), raw)
+			if err != nil {
+				typ := schemaFieldType(f)
+				invalid = append(invalid, &QueryError{Kind: ErrInvalidPatch, Param: key, Value: fmt.Sprint(raw),
+					Reason: "is not a valid " + typ, Code: "invalid_" + typ})
+				continue
+			}
+			updates[f.DBName] = v
+			fields[f.DBName] = f
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, nil, joinQueryErrors(invalid)
+	}
+	return updates, fields, nil
+}
+
+// lookUpPatchField mencari field dari key patch: nama kolom / field Go, lalu nama di tag json
+func lookUpPatchField(sch *schema.Schema, key string) *schema.Field {
+	if f := sch.LookUpField(key); f != nil {
+		return f
+	}
+	for _, f := range sch.Fields {
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// patchValue mengubah nilai JSON ke tipe kolom; string di-parse seperti nilai filter, angka JSON
+// (float64) untuk kolom int harus bulat. nil = NULL.
+func patchValue(fieldType string, raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return parseTypedValue(fieldType, v)
+	case float64:
+		switch fieldType {
+		case "int":
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int64(v), nil
+		case "string":
+			return v, nil
+		}
+	case bool:
+		if fieldType == "bool" || fieldType == "string" {
+			return v, nil
+		}
+	default:
+		if fieldType == "string" {
+			return v, nil
+		}
+		if rv := reflect.ValueOf(v); fieldType == "int" && rv.CanInt() {
+			return rv.Int(), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %T for %s", raw, fieldType)
+}
+
+// DeleteGeneric menghapus row id: soft delete bila model punya gorm.DeletedAt, permanen bila
+// WriteOptions.HardDelete. Tidak ada row (termasuk di luar Scopes) -> ErrNotFound; row ada tapi
+// tidak memenuhi WriteOptions.Preconditions -> ErrConflict (409, bukan 404).
+func DeleteGeneric[T any](ctx context.Context, db *gorm.DB, id any, opts WriteOptions) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := startOp[T](opts.Metrics, OpDelete)
+	defer func() { op.end(-1, err) }()
+	db = db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	if opts.HardDelete {
+		db = db.Unscoped()
+	}
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return fmt.Errorf("model %s has no single primary key", sch.Name)
+	}
+	if id, err = parseID(pk, id); err != nil {
+		return err
+	}
+
+	tx := db.Where(db.Statement.Quote(sch.Table+"."+pk.DBName)+" = ?", id)
+	for _, scope := range opts.Scopes {
+		tx = scope(tx)
+	}
+	for _, cond := range opts.Preconditions {
+		tx = cond(tx)
+	}
+	res := tx.Delete(new(T))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		invalidateWrite[T](ctx, db, opts, id)
+		return nil
+	}
+	if len(opts.Preconditions) == 0 {
+		return fmt.Errorf("%w: %s %v", ErrNotFound, sch.Name, id)
+	}
+	if _, err := findByPK[T](db, sch, id, opts.Scopes); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s %v does not match the delete precondition", ErrConflict, sch.Name, id)
+}
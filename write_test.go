@@ -0,0 +1,284 @@
+package magicrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestCreateGeneric(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 1, 0)
+	opts := WriteOptions{PreloadFields: []string{"Gudang", "Items"}}
+
+	created, err := CreateGeneric(context.Background(), db, &Order{Kode: "ORD-02", Status: "aktif", GudangID: orders[0].GudangID,
+		Items: []Item{{Nama: "semen", Jumlah: 2}}}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// response sama dengan GET berikutnya: preload terisi, kolom dari database (created_at)
+	if created.ID == 0 || created.Gudang == nil || created.Gudang.Kode != "GD-01" || len(created.Items) != 1 || created.CreatedAt.IsZero() {
+		t.Fatalf("created %+v", created)
+	}
+
+	_, err = CreateGeneric(context.Background(), db, &Order{Kode: "ORD-01"}, opts)
+	var conflict *ConflictError
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &conflict) || conflict.Constraint != "orders.kode" || StatusForError(err) != 409 {
+		t.Fatalf("duplicate kode: err = %v", err)
+	}
+
+	errStok := errors.New("stok habis")
+	validate := WriteOptions{Validate: func(_ context.Context, _ *gorm.DB, item any) error {
+		if item.(*Order).Kode == "ORD-03" {
+			return errStok
+		}
+		return nil
+	}}
+	if _, err := CreateGeneric(context.Background(), db, &Order{Kode: "ORD-03"}, validate); !errors.Is(err, errStok) {
+		t.Fatalf("err = %v, want Validate error", err)
+	}
+	var n int64
+	db.Model(&Order{}).Count(&n)
+	if n != 2 {
+		t.Fatalf("%d orders, want 2 (no insert after failed validation or conflict)", n)
+	}
+}
+
+func TestTranslateWriteError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string // Constraint, "-" = bukan konflik
+	}{
+		{"postgres", errors.New(`ERROR: duplicate key value violates unique constraint "orders_kode_key" (SQLSTATE 23505)`), "orders_kode_key"},
+		{"mysql", errors.New(`Error 1062 (23000): Duplicate entry 'ORD-01' for key 'orders.idx_kode'`), "orders.idx_kode"},
+		{"sqlite", errors.New(`UNIQUE constraint failed: orders.kode`), "orders.kode"},
+		{"sqlite composite", errors.New(`UNIQUE constraint failed: items.order_id, items.nama`), "items.order_id, items.nama"},
+		{"sqlserver", errors.New(`mssql: Cannot insert duplicate key row in object 'dbo.orders' with unique index 'ux_orders_kode'.`), "ux_orders_kode"},
+		{"gorm translated", gorm.ErrDuplicatedKey, ""},
+		{"other", errors.New("connection refused"), "-"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateWriteError(tc.err)
+			var conflict *ConflictError
+			if tc.want == "-" {
+				if err != tc.err {
+					t.Fatalf("err = %v, want unchanged", err)
+				}
+				return
+			}
+			if !errors.As(err, &conflict) || conflict.Constraint != tc.want || !errors.Is(err, tc.err) {
+				t.Fatalf("err = %#v, want constraint %q", err, tc.want)
+			}
+		})
+	}
+	if translateWriteError(nil) != nil {
+		t.Fatal("nil error translated")
+	}
+}
+
+func TestUpdateGeneric(t *testing.T) {
+	payload := func() *Order {
+		// ID dan created_at dari payload diabaikan, Telepon kosong (zero value) tetap ditulis
+		return &Order{ID: 99, Kode: "ORD-01B", Status: "selesai", GudangID: 2, CreatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+	}
+	cases := []struct {
+		name string
+		opts WriteOptions
+		want string // kode/status/telepon/gudang_id setelah update
+	}{
+		{"full replace", WriteOptions{}, "ORD-01B/selesai//2"},
+		{"protected column", WriteOptions{ProtectedColumns: []string{"telepon"}}, "ORD-01B/selesai/0812/2"},
+		{"writable columns", WriteOptions{WritableColumns: []string{"status"}}, "ORD-01/selesai/0812/1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			o := seedOrders(t, db, 1, 0)[0]
+			tc.opts.PreloadFields = []string{"Gudang"}
+			updated, err := UpdateGeneric(context.Background(), db, o.ID, payload(), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stored Order
+			db.First(&stored, o.ID)
+			got := fmt.Sprintf("%s/%s/%s/%d", stored.Kode, stored.Status, stored.Telepon, stored.GudangID)
+			if got != tc.want || updated.ID != o.ID || updated.Gudang == nil || updated.Gudang.ID != stored.GudangID {
+				t.Fatalf("stored %s, want %s; returned %+v", got, tc.want, updated)
+			}
+			if !stored.CreatedAt.Equal(o.CreatedAt) || !stored.UpdatedAt.After(o.UpdatedAt) {
+				t.Fatalf("created_at %v (was %v), updated_at %v (was %v)", stored.CreatedAt, o.CreatedAt, stored.UpdatedAt, o.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestUpdateGenericErrors(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 2, 0)
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", orders[0].GudangID) }
+	cases := []struct {
+		name string
+		id   uint
+		opts WriteOptions
+		want error
+	}{
+		{"not found", 99, WriteOptions{}, ErrNotFound},
+		{"outside scope", orders[1].ID, WriteOptions{Scopes: []Scope{tenant}}, ErrNotFound},
+		{"unknown writable column", orders[0].ID, WriteOptions{WritableColumns: []string{"alamat"}}, ErrInvalidConfig},
+		{"duplicate kode", orders[0].ID, WriteOptions{}, ErrConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UpdateGeneric(context.Background(), db, tc.id, &Order{Kode: orders[1].Kode}, tc.opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+	if _, err := UpdateGeneric(context.Background(), db, 99, &Order{}, WriteOptions{}); StatusForError(err) != 404 {
+		t.Fatalf("status %d, want 404", StatusForError(err))
+	}
+}
+
+func TestPatchGeneric(t *testing.T) {
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 2, 0)
+	o := orders[0]
+	// key boleh nama json, field Go atau kolom; angka JSON (float64) dan string di-parse sesuai tipe kolom
+	patch := map[string]interface{}{"status": "batal", "GudangID": float64(orders[1].GudangID), "telepon": "0813"}
+	patched, err := PatchGeneric[Order](context.Background(), db, o.ID, patch, WriteOptions{PreloadFields: []string{"Gudang"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched.Status != "batal" || patched.Telepon != "0813" || patched.Gudang == nil || patched.Gudang.Kode != "GD-02" || patched.Kode != o.Kode {
+		t.Fatalf("patched %+v", patched)
+	}
+	if _, err := PatchGeneric[Order](context.Background(), db, o.ID, map[string]interface{}{"gudang_id": "1"}, WriteOptions{}); err != nil {
+		t.Fatalf("int from string: %v", err)
+	}
+
+	// semua key yang salah dilaporkan sekaligus, urut nama
+	bad := map[string]interface{}{"alamat": "x", "id": float64(5), "gudang_id": 1.5, "created_at": "2020-01-01", "status": "ok"}
+	_, err = PatchGeneric[Order](context.Background(), db, o.ID, bad, WriteOptions{})
+	if !errors.Is(err, ErrInvalidPatch) || StatusForError(err) != 400 {
+		t.Fatalf("err = %v, want ErrInvalidPatch", err)
+	}
+	var got []string
+	for _, d := range DetailsFromError(err) {
+		got = append(got, d.Field+"/"+d.Code)
+	}
+	if want := "[alamat/unknown_field created_at/protected_field gudang_id/invalid_int id/protected_field]"; fmt.Sprint(got) != want {
+		t.Fatalf("details %v, want %s", got, want)
+	}
+	var stored Order
+	db.First(&stored, o.ID)
+	if stored.Status != "batal" {
+		t.Fatalf("row changed by rejected patch: %+v", stored)
+	}
+
+	cases := []struct {
+		name  string
+		id    uint
+		patch map[string]interface{}
+		opts  WriteOptions
+		want  error
+	}{
+		{"not found", 99, map[string]interface{}{"status": "x"}, WriteOptions{}, ErrNotFound},
+		{"protected column", o.ID, map[string]interface{}{"telepon": "x"}, WriteOptions{ProtectedColumns: []string{"telepon"}}, ErrInvalidPatch},
+		{"outside writable columns", o.ID, map[string]interface{}{"kode": "x"}, WriteOptions{WritableColumns: []string{"status"}}, ErrInvalidPatch},
+		{"duplicate kode", o.ID, map[string]interface{}{"kode": orders[1].Kode}, WriteOptions{}, ErrConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := PatchGeneric[Order](context.Background(), db, tc.id, tc.patch, tc.opts); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPatchValue(t *testing.T) {
+	cases := []struct {
+		typ  string
+		raw  interface{}
+		want string // "%T %v", "error" = ditolak
+	}{
+		{"int", float64(3), "int64 3"},
+		{"int", 2.5, "error"},
+		{"int", "7", "int 7"},
+		{"int", true, "error"},
+		{"string", float64(3), "float64 3"},
+		{"bool", true, "bool true"},
+		{"bool", "false", "bool false"},
+		{"uuid", "bukan-uuid", "error"},
+		{"date", "2024-02-30", "error"},
+		{"string", nil, "<nil> <nil>"},
+	}
+	for _, tc := range cases {
+		v, err := patchValue(tc.typ, tc.raw)
+		got := fmt.Sprintf("%T %v", v, v)
+		if err != nil {
+			got = "error"
+		}
+		if got != tc.want {
+			t.Fatalf("patchValue(%s, %#v) = %s, want %s", tc.typ, tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestDeleteGeneric(t *testing.T) {
+	aktif := func(db *gorm.DB) *gorm.DB { return db.Where("status = ?", "aktif") }
+	cases := []struct {
+		name string
+		row  int // index order yang dihapus (ORD-01 aktif/GD-01, ORD-02 selesai/GD-02), -1 = id tidak ada
+		opts WriteOptions
+		want error
+		left string // row terlihat / termasuk soft-deleted setelahnya
+	}{
+		{"soft delete", 0, WriteOptions{}, nil, "1/2"},
+		{"hard delete", 0, WriteOptions{HardDelete: true}, nil, "1/1"},
+		{"not found", -1, WriteOptions{}, ErrNotFound, "2/2"},
+		{"precondition met", 0, WriteOptions{Preconditions: []Scope{aktif}}, nil, "1/2"},
+		{"precondition failed", 1, WriteOptions{Preconditions: []Scope{aktif}}, ErrConflict, "2/2"},
+		{"precondition on missing row", -1, WriteOptions{Preconditions: []Scope{aktif}}, ErrNotFound, "2/2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			orders := seedOrders(t, db, 2, 0)
+			id := uint(99)
+			if tc.row >= 0 {
+				id = orders[tc.row].ID
+			}
+			if err := DeleteGeneric[Order](context.Background(), db, id, tc.opts); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			var visible, all int64
+			db.Model(&Order{}).Count(&visible)
+			db.Unscoped().Model(&Order{}).Count(&all)
+			if got := fmt.Sprintf("%d/%d", visible, all); got != tc.left {
+				t.Fatalf("rows %s, want %s", got, tc.left)
+			}
+		})
+	}
+
+	// row di luar Scopes dianggap tidak ada; soft-deleted tidak bisa dihapus lagi
+	db := newTestDB(t)
+	orders := seedOrders(t, db, 2, 0)
+	tenant := func(db *gorm.DB) *gorm.DB { return db.Where("gudang_id = ?", orders[0].GudangID) }
+	if err := DeleteGeneric[Order](context.Background(), db, orders[1].ID, WriteOptions{Scopes: []Scope{tenant}}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("outside scope: err = %v, want ErrNotFound", err)
+	}
+	if err := DeleteGeneric[Order](context.Background(), db, orders[0].ID, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteGeneric[Order](context.Background(), db, orders[0].ID, WriteOptions{}); StatusForError(err) != 404 {
+		t.Fatalf("second delete: err = %v, want 404", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package xlsxexport menulis export magicrest sebagai file Excel (.xlsx). Modul terpisah (go.mod sendiri)
+// agar excelize tidak ikut ke dependency graph pemakai lain. Query, filter, batas row dan kolom sama
+// dengan magicrest.ExportCSV (magicrest.PrepareExport); row ditulis per batch lewat StreamWriter excelize.
+package xlsxexport
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// ContentType: media type file .xlsx
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// format angka Excel default untuk kolom bertipe "date" dan "datetime"
+var defaultFormats = map[string]string{"date": "yyyy-mm-dd", "datetime": "yyyy-mm-dd hh:mm:ss"}
+
+// layoutTokens: token layout Go -> kode format angka Excel (yang panjang lebih dulu)
+var layoutTokens = strings.NewReplacer(
+	"2006", "yyyy", "January", "mmmm", "Monday", "dddd", "Jan", "mmm", "Mon", "ddd",
+	"01", "mm", "02", "dd", "06", "yy", "15", "hh", "03", "hh", "04", "mm", "05", "ss", "PM", "AM/PM",
+)
+
+// ExportXLSX menulis semua row hasil filter/search/order (tanpa pagination) sebagai .xlsx ke w: baris
+// pertama header (cfg.Headers / tag export, default nama json), kolom int/float sebagai angka, date/datetime
+// sebagai tanggal Excel (format dari layout Format kolom, e.g. format=date:02-01-2006 -> dd-mm-yyyy), bool sebagai boolean dan sisanya (uuid, string) sebagai teks. Row di-query per batch
+// seperti ExportCSV; lebih dari cfg.MaxRows / Options.ExportMaxRows -> ErrExportTooLarge sebelum ada
+// yang ditulis.
+func ExportXLSX[T any](ctx context.Context, w io.Writer, query url.Values, db *gorm.DB, modelPtr *T, opts magicrest.Options, cfg magicrest.ExportConfig) error {
+	exp, err := magicrest.PrepareExport[T](ctx, magicrest.FromURLValues(query), db, opts, cfg)
+	if err != nil {
+		return err
+	}
+	return Write(w, exp, cfg.SheetName)
+}
+
+// Write menulis exp sebagai .xlsx dengan satu sheet bernama sheet ("" = "Sheet1")
+func Write[T any](w io.Writer, exp *magicrest.Export[T], sheet string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	if sheet == "" {
+		sheet = "Sheet1"
+	} else if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	cols := exp.Columns()
+	styles := map[string]int{}          // format angka -> style
+	colStyles := make([]int, len(cols)) // style tanggal per kolom (0 = bukan tanggal)
+	row := make([]interface{}, len(cols))
+	for i, c := range cols {
+		row[i] = excelize.Cell{StyleID: headerStyle, Value: c.Header}
+		numFmt, isDate := defaultFormats[c.Type]
+		if !isDate {
+			continue
+		}
+		if c.Format != "" {
+			numFmt = layoutTokens.Replace(c.Format)
+		}
+		if _, ok := styles[numFmt]; !ok {
+			if styles[numFmt], err = f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt}); err != nil {
+				return err
+			}
+		}
+		colStyles[i] = styles[numFmt]
+	}
+	if err := sw.SetRow("A1", row); err != nil {
+		return err
+	}
+	n := 1
+	err = exp.EachRecord(func(record []interface{}) error {
+		n++
+		for i, v := range record {
+			if t, ok := v.(time.Time); ok && colStyles[i] != 0 {
+				v = excelize.Cell{StyleID: colStyles[i], Value: t}
+			}
+			row[i] = v
+		}
+		cell, err := excelize.CoordinatesToCellName(1, n)
+		if err != nil {
+			return err
+		}
+		return sw.SetRow(cell, row)
+	})
+	if err != nil {
+		return err
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// Handler: GET export .xlsx untuk model T (ExportXLSX) dengan Content-Disposition attachment
+// (cfg.Filename, default "export.xlsx"); error sebelum streaming dijawab seperti ListHandlerHTTP.
+func Handler[T any](db *gorm.DB, opts magicrest.Options, cfg magicrest.ExportConfig) http.HandlerFunc {
+	filename := cfg.Filename
+	if filename == "" {
+		filename = "export.xlsx"
+	}
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	return func(w http.ResponseWriter, r *http.Request) {
+		exp, err := magicrest.PrepareExport[T](r.Context(), magicrest.FromURLValues(r.URL.Query()), db, opts, cfg)
+		if err != nil {
+			opts.Envelope.WriteError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Content-Disposition", disposition)
+		w.WriteHeader(http.StatusOK)
+		_ = Write(w, exp, cfg.SheetName)
+	}
+}
@@ -0,0 +1,176 @@
+package xlsxexport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	magicrest "github.com/Jupriadi/magic-rest"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Pengiriman struct {
+	ID       uint      `json:"id"`
+	Resi     string    `json:"resi"`
+	Penerima uuid.UUID `json:"penerima" gorm:"type:text"`
+	Berat    float64   `json:"berat"`
+	Selesai  bool      `json:"selesai"`
+	Dikirim  time.Time `json:"dikirim"`
+	Tanggal  time.Time `json:"tanggal" export:"header=Tgl Kirim,format=date:02-01-2006"`
+	Catatan  string    `json:"catatan" export:"-"`
+}
+
+var base = time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+
+func newTestDB(t *testing.T, n int) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&Pengiriman{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= n; i++ {
+		db.Create(&Pengiriman{
+			Resi:     fmt.Sprintf("%05d", i), // nol di depan harus tetap ada
+			Penerima: uuid.NewSHA1(uuid.NameSpaceOID, []byte{byte(i)}),
+			Berat:    float64(i) + 0.5,
+			Selesai:  i%2 == 0,
+			Dikirim:  base.Add(time.Duration(i) * time.Hour),
+			Tanggal:  base.AddDate(0, 0, i),
+		})
+	}
+	return db
+}
+
+// readBack: isi sheet hasil export, header di rows[0]
+func readBack(t *testing.T, raw []byte, sheet string) (*excelize.File, [][]string) {
+	t.Helper()
+	f, err := excelize.OpenReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatalf("sheet %q: %v (sheets %v)", sheet, err, f.GetSheetList())
+	}
+	return f, rows
+}
+
+func TestExportXLSX(t *testing.T) {
+	cases := []struct {
+		name    string
+		query   url.Values
+		cfg     magicrest.ExportConfig
+		sheet   string
+		headers []string
+		rows    int
+	}{
+		{"default", url.Values{}, magicrest.ExportConfig{BatchSize: 2}, "Sheet1",
+			[]string{"id", "resi", "penerima", "berat", "selesai", "dikirim", "Tgl Kirim"}, 5},
+		{"config", url.Values{"filter[selesai]": {"true"}},
+			magicrest.ExportConfig{Columns: []string{"tanggal", "resi", "penerima"}, Headers: map[string]string{"resi": "No. Resi"}, SheetName: "Kiriman"},
+			"Kiriman", []string{"Tgl Kirim", "No. Resi", "penerima"}, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t, 5)
+			var buf bytes.Buffer
+			opts := magicrest.Options{OrderBy: "id", AutoFieldTypes: true}
+			if err := ExportXLSX(context.Background(), &buf, tc.query, db, &Pengiriman{}, opts, tc.cfg); err != nil {
+				t.Fatal(err)
+			}
+			f, rows := readBack(t, buf.Bytes(), tc.sheet)
+			if len(rows) != tc.rows+1 {
+				t.Fatalf("%d rows, want header + %d", len(rows), tc.rows)
+			}
+			if fmt.Sprint(rows[0]) != fmt.Sprint(tc.headers) {
+				t.Fatalf("headers %v, want %v", rows[0], tc.headers)
+			}
+			var stored []Pengiriman
+			db.Scopes(func(q *gorm.DB) *gorm.DB {
+				if tc.query.Has("filter[selesai]") {
+					q = q.Where("selesai = ?", true)
+				}
+				return q
+			}).Order("id").Find(&stored)
+			for i, p := range stored {
+				for j, h := range tc.headers {
+					cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+					assertCell(t, f, tc.sheet, cell, h, rows[i+1][j], p)
+				}
+			}
+		})
+	}
+}
+
+// assertCell: nilai dan tipe sel cell (kolom header h) round-trip ke field p
+func assertCell(t *testing.T, f *excelize.File, sheet, cell, h, raw string, p Pengiriman) {
+	t.Helper()
+	typ, _ := f.GetCellType(sheet, cell)
+	isText := typ == excelize.CellTypeInlineString || typ == excelize.CellTypeSharedString
+	switch h {
+	case "resi", "No. Resi":
+		if raw != p.Resi || !isText {
+			t.Fatalf("%s = %q (type %v), want text %q", cell, raw, typ, p.Resi)
+		}
+	case "penerima":
+		if id, err := uuid.Parse(raw); err != nil || id != p.Penerima || !isText {
+			t.Fatalf("%s = %q (type %v), want text %s", cell, raw, typ, p.Penerima)
+		}
+	case "berat":
+		if raw != fmt.Sprint(p.Berat) || isText {
+			t.Fatalf("%s = %q (type %v), want number %v", cell, raw, typ, p.Berat)
+		}
+	case "selesai":
+		want := "0"
+		if p.Selesai {
+			want = "1"
+		}
+		if raw != want || typ != excelize.CellTypeBool {
+			t.Fatalf("%s = %q (type %v), want bool %v", cell, raw, typ, p.Selesai)
+		}
+	case "dikirim", "Tgl Kirim":
+		want := p.Dikirim
+		if h == "Tgl Kirim" {
+			want = p.Tanggal
+		}
+		if isText {
+			t.Fatalf("%s = %q is text, want an Excel date", cell, raw)
+		}
+		var serial float64
+		if _, err := fmt.Sscan(raw, &serial); err != nil {
+			t.Fatalf("%s = %q: %v", cell, raw, err)
+		}
+		got, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%s = %v (%q), want %v", cell, got, raw, want)
+		}
+		formatted, _ := f.GetCellValue(sheet, cell)
+		if h == "Tgl Kirim" && formatted != want.Format("02-01-2006") {
+			t.Fatalf("%s displayed %q, want %q", cell, formatted, want.Format("02-01-2006"))
+		}
+	}
+}
+
+func TestExportXLSXMaxRows(t *testing.T) {
+	db := newTestDB(t, 5)
+	var buf bytes.Buffer
+	err := ExportXLSX(context.Background(), &buf, url.Values{}, db, &Pengiriman{}, magicrest.Options{}, magicrest.ExportConfig{MaxRows: 4})
+	if !errors.Is(err, magicrest.ErrExportTooLarge) || buf.Len() != 0 {
+		t.Fatalf("err = %v, %d bytes written", err, buf.Len())
+	}
+}